@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mallbots/modules/currency/application/dto"
+	"mallbots/modules/currency/domain/interfaces"
+	currencyDi "mallbots/modules/currency/infrastructure/di"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/common"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	sctx "github.com/phathdt/service-context"
+	"github.com/spf13/cobra"
+)
+
+var ratesCmd = &cobra.Command{
+	Use:   "rates",
+	Short: "Manage exchange rates against the base currency",
+}
+
+var ratesLoadCmd = &cobra.Command{
+	Use:   "load <file.csv>",
+	Short: "Publish the rates of a currency,rate CSV file",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := sctx.GlobalLogger().GetLogger("rates")
+
+		source, _ := cmd.Flags().GetString("source")
+		if source == "" {
+			source = args[0]
+		}
+
+		file, err := os.Open(args[0])
+		if err != nil {
+			logger.Fatal(err)
+		}
+		defer file.Close()
+
+		lines, err := readRates(file)
+		if err != nil {
+			logger.Fatalf("Failed to read %s: %v", args[0], err)
+		}
+
+		service := newCurrencyService(logger)
+		rates, err := service.LoadRates(context.Background(), &dto.LoadRatesRequest{Source: source, Rates: lines})
+		if err != nil {
+			logger.Fatalf("Failed to load rates: %v", err)
+		}
+
+		printRates(rates)
+	},
+}
+
+var ratesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print the current rate of every currency",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := sctx.GlobalLogger().GetLogger("rates")
+
+		rates, err := newCurrencyService(logger).ListRates(context.Background())
+		if err != nil {
+			logger.Fatalf("Failed to list rates: %v", err)
+		}
+
+		printRates(rates)
+	},
+}
+
+func init() {
+	ratesLoadCmd.Flags().String("source", "", "where the rates come from, the file name by default")
+	ratesCmd.AddCommand(ratesLoadCmd, ratesListCmd)
+}
+
+func newCurrencyService(logger sctx.Logger) interfaces.CurrencyService {
+	sc := newServiceCtx()
+	if err := sc.Load(); err != nil {
+		logger.Fatal(err)
+	}
+
+	dbPool := sc.MustGet(common.KeyPgx).(pgxc.PgxComp).GetConn()
+
+	service, err := currencyDi.InitializeCurrencyService(dbPool)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	return service
+}
+
+// readRates reads currency,rate records, with or without a header
+func readRates(r io.Reader) ([]dto.RateLine, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []dto.RateLine
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(record[0], "currency") {
+			continue
+		}
+		lines = append(lines, dto.RateLine{Currency: record[0], Rate: record[1]})
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("no rates")
+	}
+
+	return lines, nil
+}
+
+func printRates(rates []*dto.ExchangeRateResponse) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tBASE\tCURRENCY\tRATE\tSOURCE\tCREATED AT")
+	for _, rate := range rates {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", rate.ID, rate.Base, rate.Currency, rate.Rate, rate.Source, rate.CreatedAt.Format(time.RFC3339))
+	}
+	w.Flush()
+}
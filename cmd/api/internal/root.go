@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"mallbots/plugins/eventbus"
+	"mallbots/plugins/payment/fake"
 	"mallbots/plugins/pgxc"
+	"mallbots/plugins/scheduler"
 	"mallbots/plugins/tokenprovider/jwt"
 	"mallbots/shared/common"
 	"mallbots/shared/config"
@@ -10,6 +13,7 @@ import (
 	"time"
 
 	sctx "github.com/phathdt/service-context"
+	"github.com/phathdt/service-context/component/redisc"
 
 	"github.com/spf13/cobra"
 )
@@ -19,11 +23,22 @@ const (
 )
 
 func newServiceCtx() sctx.ServiceContext {
-	return sctx.NewServiceContext(
+	opts := []sctx.Option{
 		sctx.WithName(serviceName),
 		sctx.WithComponent(pgxc.New(common.KeyPgx, "")),
 		sctx.WithComponent(jwt.New(common.KeyJwt)),
-	)
+		sctx.WithComponent(eventbus.New(common.KeyEventBus)),
+		sctx.WithComponent(eventbus.New(common.KeyOutboxBus)),
+		sctx.WithComponent(fake.New(common.KeyPayment)),
+		sctx.WithComponent(scheduler.New(common.KeyScheduler, common.KeyPgx)),
+	}
+
+	// Redis is optional; without it idempotency keys are kept in Postgres
+	if os.Getenv("REDIS_URI") != "" {
+		opts = append(opts, sctx.WithComponent(redisc.New(common.KeyCompRedis)))
+	}
+
+	return sctx.NewServiceContext(opts...)
 }
 
 var rootCmd = &cobra.Command{
@@ -50,6 +65,7 @@ var rootCmd = &cobra.Command{
 
 func Execute() {
 	rootCmd.AddCommand(outEnvCmd)
+	rootCmd.AddCommand(ratesCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
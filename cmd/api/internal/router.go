@@ -1,24 +1,36 @@
 package cmd
 
 import (
+	"context"
 	"log"
 	"log/slog"
 	cartDi "mallbots/modules/cart/infrastructure/di"
+	currencyDi "mallbots/modules/currency/infrastructure/di"
+	orderInterfaces "mallbots/modules/order/domain/interfaces"
 	orderDi "mallbots/modules/order/infrastructure/di"
 	productDi "mallbots/modules/product/infrastructure/di"
+	promotionDi "mallbots/modules/promotion/infrastructure/di"
 	userDi "mallbots/modules/user/infrastructure/di"
+	"mallbots/plugins/eventbus"
+	"mallbots/plugins/idempotency"
+	"mallbots/plugins/outbox"
+	"mallbots/plugins/payment"
 	"mallbots/plugins/pgxc"
+	"mallbots/plugins/scheduler"
 	"mallbots/plugins/tokenprovider"
 	"mallbots/shared/common"
 	"mallbots/shared/config"
 	middleware2 "mallbots/shared/middleware"
 	"os"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/jackc/pgx/v5/pgxpool"
 	sctx "github.com/phathdt/service-context"
 	"github.com/phathdt/service-context/component/fiberc/middleware"
+	"github.com/phathdt/service-context/component/redisc"
 	slogfiber "github.com/samber/slog-fiber"
 )
 
@@ -27,26 +39,95 @@ func StartRouter(sc sctx.ServiceContext, cfg *config.Config) {
 
 	tokenProvider := sc.MustGet(common.KeyJwt).(tokenprovider.Provider)
 
+	eventBus := sc.MustGet(common.KeyEventBus).(eventbus.Bus)
+
+	paymentProvider := sc.MustGet(common.KeyPayment).(payment.PaymentProvider)
+
+	idempotencyStore := idempotency.NewPostgresStore(dbPool)
+	if redisComp, ok := sc.Get(common.KeyCompRedis); ok {
+		idempotencyStore = idempotency.NewRedisStore(redisComp.(redisc.RedisComponent).GetClient())
+	}
+	idempotent := middleware2.Idempotency(idempotencyStore)
+
 	productHandler, err := productDi.InitializeProductHandler(dbPool)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	inventoryHandler, err := productDi.InitializeInventoryHandler(dbPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	orderSubscriber, err := productDi.InitializeOrderSubscriber(dbPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	orderSubscriber.Register(eventBus)
+
+	couponSubscriber, err := promotionDi.InitializeOrderSubscriber(dbPool, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	couponSubscriber.Register(eventBus)
+
+	currencyHandler, err := currencyDi.InitializeCurrencyHandler(dbPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	userHandler, err := userDi.InitializeUserHandler(dbPool, tokenProvider)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	cartHandler, err := cartDi.InitializeCartHandler(dbPool)
+	addressHandler, err := userDi.InitializeAddressHandler(dbPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cartHandler, err := cartDi.InitializeCartHandler(dbPool, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	promotionHandler, err := promotionDi.InitializePromotionHandler(dbPool, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	orderHandler, err := orderDi.InitializeOrderHandler(dbPool, eventBus, cfg, paymentProvider)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	refundHandler, err := orderDi.InitializeRefundHandler(dbPool, paymentProvider)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	orderHandler, err := orderDi.InitializeOrderHandler(dbPool)
+	shipmentHandler, err := orderDi.InitializeShipmentHandler(dbPool)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	invoiceHandler, err := orderDi.InitializeInvoiceHandler(dbPool, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	paymentHandler, err := orderDi.InitializePaymentHandler(dbPool, eventBus, cfg, paymentProvider)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	orderExpiryService, err := orderDi.InitializeOrderExpiryService(dbPool, eventBus, cfg, paymentProvider)
+	if err != nil {
+		log.Fatal(err)
+	}
+	scheduleAutoCancel(sc, cfg.AutoCancel, orderExpiryService)
+	scheduleOutboxRelay(sc, dbPool, cfg.Outbox)
+
 	app := fiber.New(fiber.Config{BodyLimit: 100 * 1024 * 1024})
 
 	app.Use(slogfiber.New(slog.New(slog.NewTextHandler(os.Stdout, nil))))
@@ -59,30 +140,129 @@ func StartRouter(sc sctx.ServiceContext, cfg *config.Config) {
 	// Setup routes
 	app.Get("/v1/products", productHandler.GetProducts)
 	app.Get("/v1/products/:id", productHandler.GetProduct)
+	app.Get("/v1/exchange-rates", currencyHandler.ListRates)
 
 	// User routes
 	app.Post("/v1/auth/register", userHandler.Register)
 	app.Post("/v1/auth/login", userHandler.Login)
 
+	// Payment provider callbacks, authenticated by their signature
+	app.Post("/v1/payments/webhook", paymentHandler.HandleWebhook)
+
 	// Protected routes
 	app.Use(middleware2.RequiredAuth(sc))
 
 	app.Get("/v1/users/me", userHandler.GetProfile)
 
+	// Address book routes
+	app.Post("/v1/users/me/addresses", idempotent, addressHandler.CreateAddress)
+	app.Get("/v1/users/me/addresses", addressHandler.GetAddresses)
+	app.Get("/v1/users/me/addresses/:id", addressHandler.GetAddress)
+	app.Put("/v1/users/me/addresses/:id", idempotent, addressHandler.UpdateAddress)
+	app.Delete("/v1/users/me/addresses/:id", idempotent, addressHandler.DeleteAddress)
+
 	// Cart routes
-	app.Post("/v1/cart/items", cartHandler.AddItem)
-	app.Put("/v1/cart/items", cartHandler.UpdateQuantity)
-	app.Delete("/v1/cart/items/:productId", cartHandler.RemoveItem)
+	app.Post("/v1/cart/items", idempotent, cartHandler.AddItem)
+	app.Put("/v1/cart/items", idempotent, cartHandler.UpdateQuantity)
+	app.Delete("/v1/cart/items/:productId", idempotent, cartHandler.RemoveItem)
 	app.Get("/v1/cart/items", cartHandler.GetItems)
+	app.Post("/v1/cart/coupon", idempotent, promotionHandler.ApplyCoupon)
+	app.Delete("/v1/cart/coupon", idempotent, promotionHandler.RemoveCoupon)
+
+	// Shipping routes
+	app.Post("/v1/shipping/quote", orderHandler.QuoteShipping)
 
 	// Order routes
-	app.Post("/v1/orders", orderHandler.CreateOrder)
+	app.Post("/v1/orders", idempotent, orderHandler.CreateOrder)
 	app.Get("/v1/orders", orderHandler.GetUserOrders)
 	app.Get("/v1/orders/:id", orderHandler.GetOrder)
+	app.Get("/v1/orders/:id/timeline", orderHandler.GetOrderTimeline)
+	app.Post("/v1/orders/:id/cancel", idempotent, orderHandler.CancelOrder)
+	app.Post("/v1/orders/:id/reorder", idempotent, orderHandler.Reorder)
+	app.Post("/v1/orders/:id/refunds", idempotent, refundHandler.RequestRefund)
+	app.Get("/v1/orders/:id/refunds", refundHandler.GetOrderRefunds)
+	app.Get("/v1/orders/:id/invoice", invoiceHandler.GetInvoice)
+	app.Post("/v1/orders/:id/payment-intent", idempotent, paymentHandler.CreatePaymentIntent)
+
+	// Admin order routes
+	app.Patch("/v1/orders/:id/status", middleware2.RequiredRole(common.RoleAdmin), idempotent, orderHandler.UpdateOrderStatus)
+	app.Patch("/v1/orders/:id/payment-status", middleware2.RequiredRole(common.RoleAdmin), idempotent, orderHandler.UpdatePaymentStatus)
+	app.Post("/v1/orders/:id/payment/capture", middleware2.RequiredRole(common.RoleAdmin), paymentHandler.CapturePayment)
+	app.Post("/v1/orders/:id/shipments", middleware2.RequiredRole(common.RoleAdmin), shipmentHandler.CreateShipment)
+	app.Patch("/v1/shipments/:id", middleware2.RequiredRole(common.RoleAdmin), shipmentHandler.UpdateShipment)
+	app.Post("/v1/refunds/:id/approve", middleware2.RequiredRole(common.RoleAdmin), refundHandler.ApproveRefund)
+	app.Post("/v1/refunds/:id/reject", middleware2.RequiredRole(common.RoleAdmin), refundHandler.RejectRefund)
+	app.Post("/v1/refunds/:id/process", middleware2.RequiredRole(common.RoleAdmin), refundHandler.ProcessRefund)
+
+	// Admin promotion routes
+	app.Post("/v1/coupons", middleware2.RequiredRole(common.RoleAdmin), promotionHandler.CreateCoupon)
+	app.Get("/v1/coupons", middleware2.RequiredRole(common.RoleAdmin), promotionHandler.ListCoupons)
+
+	// Admin inventory routes
+	app.Get("/v1/products/:id/inventory", middleware2.RequiredRole(common.RoleAdmin), inventoryHandler.GetInventory)
+	app.Put("/v1/products/:id/inventory", middleware2.RequiredRole(common.RoleAdmin), inventoryHandler.SetStock)
 
 	_ = app.Listen(":4000")
 }
 
+// scheduleAutoCancel cancels unpaid orders in the background once a TTL is
+// configured. The scheduler runs it on one replica at a time.
+func scheduleAutoCancel(sc sctx.ServiceContext, cfg config.AutoCancelConfig, orderExpiryService orderInterfaces.OrderExpiryService) {
+	if cfg.TTL <= 0 && len(cfg.ProviderTTLs) == 0 {
+		return
+	}
+
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	logger := sc.Logger("auto-cancel")
+	jobs := sc.MustGet(common.KeyScheduler).(scheduler.Scheduler)
+	jobs.Schedule(scheduler.Job{
+		Name:     "cancel-unpaid-orders",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			cancelled, err := orderExpiryService.CancelExpiredOrders(ctx, time.Now())
+			if cancelled > 0 {
+				logger.Infof("cancelled %d unpaid orders", cancelled)
+			}
+			return err
+		},
+	})
+}
+
+// scheduleOutboxRelay delivers the events recorded in the outbox to the
+// in-process outbox bus and the sinks switched on in the configuration
+func scheduleOutboxRelay(sc sctx.ServiceContext, dbPool *pgxpool.Pool, cfg config.OutboxConfig) {
+	sinks := []outbox.Sink{outbox.NewBusSink(sc.MustGet(common.KeyOutboxBus).(eventbus.Bus))}
+	if cfg.NotifyChannel != "" {
+		sinks = append(sinks, outbox.NewNotifySink(dbPool, cfg.NotifyChannel))
+	}
+	for _, webhook := range cfg.Webhooks {
+		sinks = append(sinks, outbox.NewWebhookSink(webhook.URL, webhook.Secret, webhook.Timeout))
+	}
+
+	relay := outbox.NewRelay(dbPool, outbox.RelayOptions{
+		BatchSize:       cfg.BatchSize,
+		RetryBackoff:    cfg.RetryBackoff,
+		MaxRetryBackoff: cfg.MaxRetryBackoff,
+		Lease:           cfg.Lease,
+	}, sinks...)
+
+	interval := cfg.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	jobs := sc.MustGet(common.KeyScheduler).(scheduler.Scheduler)
+	jobs.Schedule(scheduler.Job{
+		Name:     "outbox-relay",
+		Interval: interval,
+		Run:      relay.Run,
+	})
+}
+
 func ping() fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		return ctx.Status(200).JSON(&fiber.Map{
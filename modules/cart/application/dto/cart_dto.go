@@ -1,13 +1,20 @@
 package dto
 
+import "mallbots/shared/money"
+
 type CartItemRequest struct {
 	ProductID int32 `json:"product_id" validate:"required"`
 	Quantity  int32 `json:"quantity" validate:"required,min=1"`
 }
 
 type CartItemResponse struct {
-	ID        int32   `json:"id"`
-	ProductID int32   `json:"product_id"`
-	Quantity  int32   `json:"quantity"`
-	Price     float64 `json:"price"`
+	ID        int32 `json:"id"`
+	ProductID int32 `json:"product_id"`
+	Quantity  int32 `json:"quantity"`
+	// Price is in Currency, the one asked for, converted from BasePrice when
+	// it is not the product's own
+	Price        money.Money `json:"price"`
+	Currency     string      `json:"currency"`
+	BasePrice    money.Money `json:"base_price"`
+	BaseCurrency string      `json:"base_currency"`
 }
@@ -4,23 +4,43 @@ import (
 	"context"
 	"mallbots/modules/cart/application/dto"
 	"mallbots/modules/cart/domain/entities"
+	"mallbots/modules/cart/domain/events"
 	"mallbots/modules/cart/domain/interfaces"
+	currencyInterfaces "mallbots/modules/currency/domain/interfaces"
 	productInterfaces "mallbots/modules/product/domain/interfaces"
+	ruleConstants "mallbots/modules/rules/domain/constants"
+	ruleEntities "mallbots/modules/rules/domain/entities"
+	ruleInterfaces "mallbots/modules/rules/domain/interfaces"
+	"mallbots/plugins/outbox"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/money"
 	"time"
 )
 
 type cartService struct {
 	cartRepo       interfaces.CartRepository
 	productService productInterfaces.ProductService
+	rules          ruleInterfaces.RuleEngine
+	currencies     currencyInterfaces.CurrencyService
+	txManager      pgxc.TxManager
+	outbox         outbox.Writer
 }
 
 func NewCartService(
 	cartRepo interfaces.CartRepository,
 	productService productInterfaces.ProductService,
+	rules ruleInterfaces.RuleEngine,
+	currencies currencyInterfaces.CurrencyService,
+	txManager pgxc.TxManager,
+	outbox outbox.Writer,
 ) interfaces.CartService {
 	return &cartService{
 		cartRepo:       cartRepo,
 		productService: productService,
+		rules:          rules,
+		currencies:     currencies,
+		txManager:      txManager,
+		outbox:         outbox,
 	}
 }
 
@@ -38,16 +58,19 @@ func (s *cartService) AddItem(ctx context.Context, userID int32, req *dto.CartIt
 		existingItem.Quantity += req.Quantity
 		existingItem.UpdatedAt = time.Now()
 
+		if err := s.checkRules(ctx, userID, existingItem.ProductID, existingItem.Quantity); err != nil {
+			return nil, err
+		}
+
 		if err := s.cartRepo.Update(ctx, existingItem); err != nil {
 			return nil, err
 		}
 
-		return &dto.CartItemResponse{
-			ID:        existingItem.ID,
-			ProductID: existingItem.ProductID,
-			Quantity:  existingItem.Quantity,
-			Price:     existingItem.Price,
-		}, nil
+		return toCartItemResponse(existingItem), nil
+	}
+
+	if err := s.checkRules(ctx, userID, req.ProductID, req.Quantity); err != nil {
+		return nil, err
 	}
 
 	// Create new cart item
@@ -65,12 +88,7 @@ func (s *cartService) AddItem(ctx context.Context, userID int32, req *dto.CartIt
 		return nil, err
 	}
 
-	return &dto.CartItemResponse{
-		ID:        newItem.ID,
-		ProductID: newItem.ProductID,
-		Quantity:  newItem.Quantity,
-		Price:     newItem.Price,
-	}, nil
+	return toCartItemResponse(newItem), nil
 }
 
 func (s *cartService) UpdateQuantity(ctx context.Context, userID int32, req *dto.CartItemRequest) (*dto.CartItemResponse, error) {
@@ -82,16 +100,15 @@ func (s *cartService) UpdateQuantity(ctx context.Context, userID int32, req *dto
 	item.Quantity = req.Quantity
 	item.UpdatedAt = time.Now()
 
+	if err := s.checkRules(ctx, userID, item.ProductID, item.Quantity); err != nil {
+		return nil, err
+	}
+
 	if err := s.cartRepo.Update(ctx, item); err != nil {
 		return nil, err
 	}
 
-	return &dto.CartItemResponse{
-		ID:        item.ID,
-		ProductID: item.ProductID,
-		Quantity:  item.Quantity,
-		Price:     item.Price,
-	}, nil
+	return toCartItemResponse(item), nil
 }
 
 func (s *cartService) RemoveItem(ctx context.Context, userID, productID int32) error {
@@ -106,17 +123,82 @@ func (s *cartService) GetItems(ctx context.Context, userID int32) ([]*dto.CartIt
 
 	var response []*dto.CartItemResponse
 	for _, item := range items {
-		response = append(response, &dto.CartItemResponse{
-			ID:        item.ID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-		})
+		response = append(response, toCartItemResponse(item))
 	}
 
 	return response, nil
 }
 
+func (s *cartService) GetItemsIn(ctx context.Context, userID int32, code string) ([]*dto.CartItemResponse, error) {
+	items, err := s.GetItems(ctx, userID)
+	if err != nil || code == "" {
+		return items, err
+	}
+
+	currency, err := s.currencies.ParseCurrency(code)
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := []money.Currency{currency}
+	for _, item := range items {
+		currencies = append(currencies, item.BasePrice.Currency())
+	}
+
+	exchange, err := s.currencies.Exchange(ctx, currencies...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		item.Price = exchange.Convert(item.BasePrice, currency)
+		item.Currency = currency.String()
+	}
+
+	return items, nil
+}
+
 func (s *cartService) RemoveAllItems(ctx context.Context, userID int32) error {
-	return s.cartRepo.DeleteAllByUser(ctx, userID)
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.cartRepo.DeleteAllByUser(ctx, userID); err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, &events.CartCleared{UserID: userID, ClearedAt: time.Now()})
+	})
+}
+
+// checkRules runs the order rules against the cart as it would be with the
+// product at the given quantity
+func (s *cartService) checkRules(ctx context.Context, userID, productID, quantity int32) error {
+	items, err := s.cartRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	check := ruleEntities.RuleCheck{
+		Stage:  ruleConstants.StageCart,
+		UserID: userID,
+		Lines:  []ruleEntities.RuleLine{{ProductID: productID, Quantity: quantity}},
+	}
+	for _, item := range items {
+		if item.ProductID == productID {
+			continue
+		}
+		check.Lines = append(check.Lines, ruleEntities.RuleLine{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+
+	return s.rules.Check(ctx, check)
+}
+
+func toCartItemResponse(item *entities.CartItem) *dto.CartItemResponse {
+	return &dto.CartItemResponse{
+		ID:           item.ID,
+		ProductID:    item.ProductID,
+		Quantity:     item.Quantity,
+		Price:        item.Price,
+		Currency:     item.Price.Currency().String(),
+		BasePrice:    item.Price,
+		BaseCurrency: item.Price.Currency().String(),
+	}
 }
@@ -5,7 +5,16 @@ import (
 	"errors"
 	"mallbots/modules/cart/application/dto"
 	"mallbots/modules/cart/domain/entities"
+	"mallbots/modules/cart/domain/events"
+	currencyServices "mallbots/modules/currency/application/services"
+	currencyEntities "mallbots/modules/currency/domain/entities"
 	productDto "mallbots/modules/product/application/dto"
+	ruleServices "mallbots/modules/rules/application/services"
+	"mallbots/plugins/outbox"
+	"mallbots/shared/config"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"net/http"
 	"testing"
 	"time"
 
@@ -70,6 +79,14 @@ func (m *MockProductService) GetProduct(ctx context.Context, id int32) (*product
 	return args.Get(0).(*productDto.ProductResponse), args.Error(1)
 }
 
+func (m *MockProductService) GetProductIn(ctx context.Context, id int32, currency string) (*productDto.ProductResponse, error) {
+	args := m.Called(ctx, id, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*productDto.ProductResponse), args.Error(1)
+}
+
 func (m *MockProductService) GetProducts(ctx context.Context, req *productDto.ProductListRequest, paging *core.Paging) ([]*productDto.ProductResponse, error) {
 	args := m.Called(ctx, req, paging)
 	if args.Get(0) == nil {
@@ -78,11 +95,56 @@ func (m *MockProductService) GetProducts(ctx context.Context, req *productDto.Pr
 	return args.Get(0).([]*productDto.ProductResponse), args.Error(1)
 }
 
+type MockExchangeRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateRepository) Create(ctx context.Context, rate *currencyEntities.ExchangeRate) (*currencyEntities.ExchangeRate, error) {
+	args := m.Called(ctx, rate)
+	return args.Get(0).(*currencyEntities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) GetLatest(ctx context.Context, currencies []money.Currency) ([]*currencyEntities.ExchangeRate, error) {
+	args := m.Called(ctx, currencies)
+	return args.Get(0).([]*currencyEntities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) ListLatest(ctx context.Context) ([]*currencyEntities.ExchangeRate, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*currencyEntities.ExchangeRate), args.Error(1)
+}
+
+type MockTxManager struct {
+	mock.Mock
+}
+
+func (m *MockTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.Called(ctx)
+	return fn(ctx)
+}
+
+type MockOutboxWriter struct {
+	mock.Mock
+}
+
+func (m *MockOutboxWriter) Add(ctx context.Context, events ...outbox.Event) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
 func TestCartService(t *testing.T) {
 	ctx := context.Background()
 	cartRepo := new(MockCartRepository)
 	productService := new(MockProductService)
-	cartService := NewCartService(cartRepo, productService)
+	rules, err := ruleServices.NewRuleEngine(&config.Config{
+		OrderRules: config.OrderRulesConfig{MaxQuantityPerProduct: 5},
+	})
+	require.NoError(t, err)
+	rateRepo := new(MockExchangeRateRepository)
+	txManager := new(MockTxManager)
+	txManager.On("WithTx", mock.Anything).Return()
+	outboxWriter := new(MockOutboxWriter)
+	cartService := NewCartService(cartRepo, productService, rules, currencyServices.NewCurrencyService(rateRepo, nil), txManager, outboxWriter)
 
 	t.Run("Add Item to Cart", func(t *testing.T) {
 		userID := int32(1)
@@ -94,17 +156,18 @@ func TestCartService(t *testing.T) {
 		// Mock product service response
 		productService.On("GetProduct", ctx, req.ProductID).Return(&productDto.ProductResponse{
 			ID:    1,
-			Price: 10.99,
+			Price: money.MustParse("10.99", money.USD),
 		}, nil)
 
 		// Mock repository calls
 		cartRepo.On("GetByUserAndProduct", ctx, userID, req.ProductID).Return(nil, errors.New("not found"))
+		cartRepo.On("GetByUser", ctx, userID).Return([]*entities.CartItem{}, nil).Once()
 		cartRepo.On("Create", ctx, mock.AnythingOfType("*entities.CartItem")).Return(&entities.CartItem{
 			ID:        1,
 			UserID:    userID,
 			ProductID: req.ProductID,
 			Quantity:  req.Quantity,
-			Price:     10.99,
+			Price:     money.MustParse("10.99", money.USD),
 		}, nil)
 
 		// Test add item
@@ -114,17 +177,45 @@ func TestCartService(t *testing.T) {
 		require.Equal(t, req.Quantity, response.Quantity)
 	})
 
+	t.Run("Add Item - Over Max Quantity Per Product", func(t *testing.T) {
+		userID := int32(2)
+		req := &dto.CartItemRequest{
+			ProductID: 3,
+			Quantity:  2,
+		}
+		existing := &entities.CartItem{ID: 5, UserID: userID, ProductID: 3, Quantity: 4, Price: money.MustParse("1.99", money.USD)}
+
+		productService.On("GetProduct", ctx, req.ProductID).Return(&productDto.ProductResponse{
+			ID:    3,
+			Price: money.MustParse("1.99", money.USD),
+		}, nil)
+		cartRepo.On("GetByUserAndProduct", ctx, userID, req.ProductID).Return(existing, nil)
+		cartRepo.On("GetByUser", ctx, userID).Return([]*entities.CartItem{existing}, nil)
+
+		_, err := cartService.AddItem(ctx, userID, req)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+		require.Equal(t, errorx.ErrMaximumOrderQuantityExceeded.Error(), appErr.Error())
+		cartRepo.AssertNotCalled(t, "Update", ctx, existing)
+	})
+
 	t.Run("Remove All Items from Cart", func(t *testing.T) {
 		userID := int32(1)
 
 		// Mock repository call
 		cartRepo.On("DeleteAllByUser", ctx, userID).Return(nil)
+		outboxWriter.On("Add", ctx, mock.MatchedBy(func(recorded []outbox.Event) bool {
+			return len(recorded) == 1 && recorded[0].(*events.CartCleared).UserID == userID
+		})).Return(nil)
 
 		// Test remove all items
 		err := cartService.RemoveAllItems(ctx, userID)
 		require.NoError(t, err)
 
 		cartRepo.AssertCalled(t, "DeleteAllByUser", ctx, userID)
+		outboxWriter.AssertExpectations(t)
 	})
 
 	t.Run("Get Cart Items", func(t *testing.T) {
@@ -135,7 +226,7 @@ func TestCartService(t *testing.T) {
 				UserID:    userID,
 				ProductID: 1,
 				Quantity:  2,
-				Price:     10.99,
+				Price:     money.MustParse("10.99", money.USD),
 				CreatedAt: time.Now(),
 				UpdatedAt: time.Now(),
 			},
@@ -144,7 +235,7 @@ func TestCartService(t *testing.T) {
 				UserID:    userID,
 				ProductID: 2,
 				Quantity:  1,
-				Price:     20.99,
+				Price:     money.MustParse("20.99", money.USD),
 				CreatedAt: time.Now(),
 				UpdatedAt: time.Now(),
 			},
@@ -162,4 +253,22 @@ func TestCartService(t *testing.T) {
 		require.Equal(t, mockItems[0].Quantity, items[0].Quantity)
 		require.Equal(t, mockItems[0].Price, items[0].Price)
 	})
+
+	t.Run("Get Cart Items In Another Currency", func(t *testing.T) {
+		userID := int32(3)
+		cartRepo.On("GetByUser", ctx, userID).Return([]*entities.CartItem{
+			{ID: 5, UserID: userID, ProductID: 1, Quantity: 2, Price: money.MustParse("10.99", money.USD)},
+		}, nil)
+		rateRepo.On("GetLatest", ctx, []money.Currency{money.VND}).Return([]*currencyEntities.ExchangeRate{
+			{ID: 1, Currency: money.VND, Rate: money.MustParseRate("25000")},
+		}, nil)
+
+		items, err := cartService.GetItemsIn(ctx, userID, "VND")
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		require.Equal(t, "VND", items[0].Currency)
+		require.Equal(t, "274750", items[0].Price.String())
+		require.Equal(t, "USD", items[0].BaseCurrency)
+		require.Equal(t, "10.99", items[0].BasePrice.String())
+	})
 }
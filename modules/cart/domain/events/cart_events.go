@@ -0,0 +1,18 @@
+package events
+
+import "time"
+
+const (
+	CartClearedEvent = "cart.cleared"
+)
+
+// CartCleared goes through the outbox once every item left the cart, e.g.
+// after they were ordered
+type CartCleared struct {
+	UserID    int32     `json:"user_id"`
+	ClearedAt time.Time `json:"cleared_at"`
+}
+
+func (e *CartCleared) EventName() string {
+	return CartClearedEvent
+}
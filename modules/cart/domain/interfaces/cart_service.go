@@ -10,5 +10,9 @@ type CartService interface {
 	UpdateQuantity(ctx context.Context, userID int32, req *dto.CartItemRequest) (*dto.CartItemResponse, error)
 	RemoveItem(ctx context.Context, userID, productID int32) error
 	RemoveAllItems(ctx context.Context, userID int32) error
+	// GetItems returns the cart priced in the currency of each product
 	GetItems(ctx context.Context, userID int32) ([]*dto.CartItemResponse, error)
+	// GetItemsIn returns the cart priced in the currency, the currency of each
+	// product when it is empty
+	GetItemsIn(ctx context.Context, userID int32, currency string) ([]*dto.CartItemResponse, error)
 }
@@ -6,22 +6,33 @@ import (
 	"mallbots/modules/cart/application/services"
 	"mallbots/modules/cart/infrastructure/repositories"
 	"mallbots/modules/cart/infrastructure/rest"
+	currencyService "mallbots/modules/currency/application/services"
+	currencyRepo "mallbots/modules/currency/infrastructure/repositories"
 	productService "mallbots/modules/product/application/services"
 	productRepo "mallbots/modules/product/infrastructure/repositories"
+	ruleService "mallbots/modules/rules/application/services"
+	"mallbots/plugins/outbox"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/config"
 
 	"github.com/google/wire"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var CartSet = wire.NewSet(
+	pgxc.NewTxManager,
+	outbox.NewWriter,
+	currencyRepo.NewExchangeRateRepository,
+	currencyService.NewCurrencyService,
 	productRepo.NewProductRepository,
 	productService.NewProductService,
+	ruleService.NewRuleEngine,
 	repositories.NewCartRepository,
 	services.NewCartService,
 	rest.NewCartHandler,
 )
 
-func InitializeCartHandler(db *pgxpool.Pool) (*rest.CartHandler, error) {
+func InitializeCartHandler(db *pgxpool.Pool, cfg *config.Config) (*rest.CartHandler, error) {
 	wire.Build(CartSet)
 	return &rest.CartHandler{}, nil
 }
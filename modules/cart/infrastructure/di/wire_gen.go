@@ -12,21 +12,35 @@ import (
 	services2 "mallbots/modules/cart/application/services"
 	"mallbots/modules/cart/infrastructure/repositories"
 	"mallbots/modules/cart/infrastructure/rest"
+	services4 "mallbots/modules/currency/application/services"
+	repositories3 "mallbots/modules/currency/infrastructure/repositories"
 	"mallbots/modules/product/application/services"
 	repositories2 "mallbots/modules/product/infrastructure/repositories"
+	services3 "mallbots/modules/rules/application/services"
+	"mallbots/plugins/outbox"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/config"
 )
 
 // Injectors from wire.go:
 
-func InitializeCartHandler(db *pgxpool.Pool) (*rest.CartHandler, error) {
+func InitializeCartHandler(db *pgxpool.Pool, cfg *config.Config) (*rest.CartHandler, error) {
 	cartRepository := repositories.NewCartRepository(db)
 	productRepository := repositories2.NewProductRepository(db)
-	productService := services.NewProductService(productRepository)
-	cartService := services2.NewCartService(cartRepository, productService)
+	exchangeRateRepository := repositories3.NewExchangeRateRepository(db)
+	txManager := pgxc.NewTxManager(db)
+	currencyService := services4.NewCurrencyService(exchangeRateRepository, txManager)
+	productService := services.NewProductService(productRepository, currencyService)
+	ruleEngine, err := services3.NewRuleEngine(cfg)
+	if err != nil {
+		return nil, err
+	}
+	writer := outbox.NewWriter(db)
+	cartService := services2.NewCartService(cartRepository, productService, ruleEngine, currencyService, txManager, writer)
 	cartHandler := rest.NewCartHandler(cartService)
 	return cartHandler, nil
 }
 
 // wire.go:
 
-var CartSet = wire.NewSet(repositories2.NewProductRepository, services.NewProductService, repositories.NewCartRepository, services2.NewCartService, rest.NewCartHandler)
+var CartSet = wire.NewSet(pgxc.NewTxManager, outbox.NewWriter, repositories3.NewExchangeRateRepository, services4.NewCurrencyService, repositories2.NewProductRepository, services.NewProductService, services3.NewRuleEngine, repositories.NewCartRepository, services2.NewCartService, rest.NewCartHandler)
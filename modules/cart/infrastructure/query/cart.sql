@@ -4,10 +4,11 @@ INSERT INTO cart_items (
     product_id,
     quantity,
     price,
+    currency,
     created_at,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7
 ) RETURNING *;
 
 -- name: UpdateCartItem :exec
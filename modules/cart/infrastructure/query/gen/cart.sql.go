@@ -8,6 +8,8 @@ package gen
 import (
 	"context"
 	"time"
+
+	"mallbots/shared/money"
 )
 
 const createCartItem = `-- name: CreateCartItem :one
@@ -16,20 +18,22 @@ INSERT INTO cart_items (
     product_id,
     quantity,
     price,
+    currency,
     created_at,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
-) RETURNING id, user_id, product_id, quantity, price, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING id, user_id, product_id, quantity, price, currency, created_at, updated_at
 `
 
 type CreateCartItemParams struct {
-	UserID    int32     `db:"user_id" json:"user_id"`
-	ProductID int32     `db:"product_id" json:"product_id"`
-	Quantity  int32     `db:"quantity" json:"quantity"`
-	Price     float64   `db:"price" json:"price"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	UserID    int32          `db:"user_id" json:"user_id"`
+	ProductID int32          `db:"product_id" json:"product_id"`
+	Quantity  int32          `db:"quantity" json:"quantity"`
+	Price     money.Minor    `db:"price" json:"price"`
+	Currency  money.Currency `db:"currency" json:"currency"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
 }
 
 func (q *Queries) CreateCartItem(ctx context.Context, arg CreateCartItemParams) (*CartItem, error) {
@@ -38,6 +42,7 @@ func (q *Queries) CreateCartItem(ctx context.Context, arg CreateCartItemParams)
 		arg.ProductID,
 		arg.Quantity,
 		arg.Price,
+		arg.Currency,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -48,6 +53,7 @@ func (q *Queries) CreateCartItem(ctx context.Context, arg CreateCartItemParams)
 		&i.ProductID,
 		&i.Quantity,
 		&i.Price,
+		&i.Currency,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -80,7 +86,7 @@ func (q *Queries) DeleteCartItemsByUser(ctx context.Context, userID int32) error
 }
 
 const getCartItem = `-- name: GetCartItem :one
-SELECT id, user_id, product_id, quantity, price, created_at, updated_at FROM cart_items
+SELECT id, user_id, product_id, quantity, price, currency, created_at, updated_at FROM cart_items
 WHERE user_id = $1 AND product_id = $2
 `
 
@@ -98,6 +104,7 @@ func (q *Queries) GetCartItem(ctx context.Context, arg GetCartItemParams) (*Cart
 		&i.ProductID,
 		&i.Quantity,
 		&i.Price,
+		&i.Currency,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -105,7 +112,7 @@ func (q *Queries) GetCartItem(ctx context.Context, arg GetCartItemParams) (*Cart
 }
 
 const getCartItems = `-- name: GetCartItems :many
-SELECT id, user_id, product_id, quantity, price, created_at, updated_at FROM cart_items
+SELECT id, user_id, product_id, quantity, price, currency, created_at, updated_at FROM cart_items
 WHERE user_id = $1
 ORDER BY created_at DESC
 `
@@ -125,6 +132,7 @@ func (q *Queries) GetCartItems(ctx context.Context, userID int32) ([]*CartItem,
 			&i.ProductID,
 			&i.Quantity,
 			&i.Price,
+			&i.Currency,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -6,14 +6,17 @@ package gen
 
 import (
 	"time"
+
+	"mallbots/shared/money"
 )
 
 type CartItem struct {
-	ID        int32     `db:"id" json:"id"`
-	UserID    int32     `db:"user_id" json:"user_id"`
-	ProductID int32     `db:"product_id" json:"product_id"`
-	Quantity  int32     `db:"quantity" json:"quantity"`
-	Price     float64   `db:"price" json:"price"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID        int32          `db:"id" json:"id"`
+	UserID    int32          `db:"user_id" json:"user_id"`
+	ProductID int32          `db:"product_id" json:"product_id"`
+	Quantity  int32          `db:"quantity" json:"quantity"`
+	Price     money.Minor    `db:"price" json:"price"`
+	Currency  money.Currency `db:"currency" json:"currency"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
 }
@@ -5,6 +5,7 @@ import (
 	"mallbots/modules/cart/domain/entities"
 	"mallbots/modules/cart/domain/interfaces"
 	"mallbots/modules/cart/infrastructure/query/gen"
+	"mallbots/plugins/pgxc"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -18,13 +19,14 @@ func NewCartRepository(db *pgxpool.Pool) interfaces.CartRepository {
 }
 
 func (r *cartRepository) Create(ctx context.Context, item *entities.CartItem) (*entities.CartItem, error) {
-	queries := gen.New(r.db)
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
 
 	dbItem, err := queries.CreateCartItem(ctx, gen.CreateCartItemParams{
 		UserID:    item.UserID,
 		ProductID: item.ProductID,
 		Quantity:  item.Quantity,
-		Price:     item.Price,
+		Price:     item.Price.Minor(),
+		Currency:  item.Price.Currency(),
 		CreatedAt: item.CreatedAt,
 		UpdatedAt: item.UpdatedAt,
 	})
@@ -37,14 +39,14 @@ func (r *cartRepository) Create(ctx context.Context, item *entities.CartItem) (*
 		UserID:    dbItem.UserID,
 		ProductID: dbItem.ProductID,
 		Quantity:  dbItem.Quantity,
-		Price:     dbItem.Price,
+		Price:     dbItem.Price.In(dbItem.Currency),
 		CreatedAt: dbItem.CreatedAt,
 		UpdatedAt: dbItem.UpdatedAt,
 	}, nil
 }
 
 func (r *cartRepository) Update(ctx context.Context, item *entities.CartItem) error {
-	queries := gen.New(r.db)
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
 
 	return queries.UpdateCartItem(ctx, gen.UpdateCartItemParams{
 		UserID:    item.UserID,
@@ -55,7 +57,7 @@ func (r *cartRepository) Update(ctx context.Context, item *entities.CartItem) er
 }
 
 func (r *cartRepository) Delete(ctx context.Context, userID, productID int32) error {
-	queries := gen.New(r.db)
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
 
 	return queries.DeleteCartItem(ctx, gen.DeleteCartItemParams{
 		UserID:    userID,
@@ -64,7 +66,7 @@ func (r *cartRepository) Delete(ctx context.Context, userID, productID int32) er
 }
 
 func (r *cartRepository) GetByUserAndProduct(ctx context.Context, userID, productID int32) (*entities.CartItem, error) {
-	queries := gen.New(r.db)
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
 
 	dbItem, err := queries.GetCartItem(ctx, gen.GetCartItemParams{
 		UserID:    userID,
@@ -79,14 +81,14 @@ func (r *cartRepository) GetByUserAndProduct(ctx context.Context, userID, produc
 		UserID:    dbItem.UserID,
 		ProductID: dbItem.ProductID,
 		Quantity:  dbItem.Quantity,
-		Price:     dbItem.Price,
+		Price:     dbItem.Price.In(dbItem.Currency),
 		CreatedAt: dbItem.CreatedAt,
 		UpdatedAt: dbItem.UpdatedAt,
 	}, nil
 }
 
 func (r *cartRepository) GetByUser(ctx context.Context, userID int32) ([]*entities.CartItem, error) {
-	queries := gen.New(r.db)
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
 
 	dbItems, err := queries.GetCartItems(ctx, userID)
 	if err != nil {
@@ -100,7 +102,7 @@ func (r *cartRepository) GetByUser(ctx context.Context, userID int32) ([]*entiti
 			UserID:    dbItem.UserID,
 			ProductID: dbItem.ProductID,
 			Quantity:  dbItem.Quantity,
-			Price:     dbItem.Price,
+			Price:     dbItem.Price.In(dbItem.Currency),
 			CreatedAt: dbItem.CreatedAt,
 			UpdatedAt: dbItem.UpdatedAt,
 		}
@@ -110,6 +112,6 @@ func (r *cartRepository) GetByUser(ctx context.Context, userID int32) ([]*entiti
 }
 
 func (r *cartRepository) DeleteAllByUser(ctx context.Context, userID int32) error {
-	queries := gen.New(r.db)
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
 	return queries.DeleteCartItemsByUser(ctx, userID)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"mallbots/modules/cart/domain/entities"
+	"mallbots/shared/money"
 	"path/filepath"
 	"testing"
 	"time"
@@ -110,7 +111,7 @@ func TestCartRepository(t *testing.T) {
 			UserID:    1,
 			ProductID: 1,
 			Quantity:  2,
-			Price:     10.99,
+			Price:     money.MustParse("10.99", money.USD),
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		}
@@ -134,7 +135,7 @@ func TestCartRepository(t *testing.T) {
 			UserID:    2,
 			ProductID: 1,
 			Quantity:  1,
-			Price:     10.99,
+			Price:     money.MustParse("10.99", money.USD),
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		}
@@ -161,7 +162,7 @@ func TestCartRepository(t *testing.T) {
 			UserID:    3,
 			ProductID: 1,
 			Quantity:  1,
-			Price:     10.99,
+			Price:     money.MustParse("10.99", money.USD),
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		}
@@ -187,7 +188,7 @@ func TestCartRepository(t *testing.T) {
 				UserID:    userID,
 				ProductID: 1,
 				Quantity:  1,
-				Price:     10.99,
+				Price:     money.MustParse("10.99", money.USD),
 				CreatedAt: time.Now(),
 				UpdatedAt: time.Now(),
 			},
@@ -195,7 +196,7 @@ func TestCartRepository(t *testing.T) {
 				UserID:    userID,
 				ProductID: 2,
 				Quantity:  2,
-				Price:     20.99,
+				Price:     money.MustParse("20.99", money.USD),
 				CreatedAt: time.Now(),
 				UpdatedAt: time.Now(),
 			},
@@ -3,6 +3,7 @@ package rest
 import (
 	"mallbots/modules/cart/application/dto"
 	"mallbots/modules/cart/domain/interfaces"
+	"mallbots/shared/common"
 	"net/http"
 	"strconv"
 
@@ -76,7 +77,9 @@ func (h *CartHandler) RemoveItem(c *fiber.Ctx) error {
 func (h *CartHandler) GetItems(c *fiber.Ctx) error {
 	userID := c.Context().UserValue("userId").(int32)
 
-	items, err := h.service.GetItems(c.Context(), userID)
+	currency := c.Query("currency", c.Get(common.HeaderAcceptCurrency))
+
+	items, err := h.service.GetItemsIn(c.Context(), userID, currency)
 	if err != nil {
 		panic(err)
 	}
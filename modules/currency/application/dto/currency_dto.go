@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"mallbots/shared/money"
+	"time"
+)
+
+type RateLine struct {
+	Currency string `json:"currency" validate:"required"`
+	// Rate is a decimal, units of Currency one unit of the base currency buys
+	Rate string `json:"rate" validate:"required"`
+}
+
+type LoadRatesRequest struct {
+	// Source names where the rates come from, for whoever reconciles them
+	Source string     `json:"source" validate:"required"`
+	Rates  []RateLine `json:"rates" validate:"required,min=1,dive"`
+}
+
+type ExchangeRateResponse struct {
+	ID        int32      `json:"id"`
+	Base      string     `json:"base"`
+	Currency  string     `json:"currency"`
+	Rate      money.Rate `json:"rate"`
+	Source    string     `json:"source"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"mallbots/modules/currency/application/dto"
+	"mallbots/modules/currency/domain/entities"
+	"mallbots/modules/currency/domain/interfaces"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/phathdt/service-context/core"
+)
+
+type currencyService struct {
+	rateRepo  interfaces.ExchangeRateRepository
+	txManager pgxc.TxManager
+}
+
+func NewCurrencyService(rateRepo interfaces.ExchangeRateRepository, txManager pgxc.TxManager) interfaces.CurrencyService {
+	return &currencyService{
+		rateRepo:  rateRepo,
+		txManager: txManager,
+	}
+}
+
+func (s *currencyService) ParseCurrency(code string) (money.Currency, error) {
+	if strings.TrimSpace(code) == "" {
+		return money.DefaultCurrency, nil
+	}
+
+	currency, err := money.ParseCurrency(code)
+	if err != nil {
+		return "", core.ErrBadRequest.
+			WithError(errorx.ErrInvalidCurrency.Error()).
+			WithReasonf("unknown currency %s", code)
+	}
+
+	return currency, nil
+}
+
+func (s *currencyService) Exchange(ctx context.Context, currencies ...money.Currency) (*entities.Exchange, error) {
+	var wanted []money.Currency
+	for _, currency := range currencies {
+		if currency != money.DefaultCurrency && !slices.Contains(wanted, currency) {
+			wanted = append(wanted, currency)
+		}
+	}
+	if len(wanted) == 0 {
+		return entities.NewExchange(), nil
+	}
+
+	rates, err := s.rateRepo.GetLatest(ctx, wanted)
+	if err != nil {
+		return nil, err
+	}
+
+	exchange := entities.NewExchange(rates...)
+	for _, currency := range wanted {
+		if exchange.RateOf(currency) == nil {
+			return nil, core.ErrBadRequest.
+				WithError(errorx.ErrExchangeRateNotFound.Error()).
+				WithReasonf("no exchange rate for %s", currency)
+		}
+	}
+
+	return exchange, nil
+}
+
+func (s *currencyService) LoadRates(ctx context.Context, req *dto.LoadRatesRequest) ([]*dto.ExchangeRateResponse, error) {
+	source := strings.TrimSpace(req.Source)
+	if source == "" {
+		return nil, core.ErrBadRequest.
+			WithError(errorx.ErrInvalidExchangeRate.Error()).
+			WithReason("rates need a source")
+	}
+
+	now := time.Now()
+	rates := make([]*entities.ExchangeRate, 0, len(req.Rates))
+	for _, line := range req.Rates {
+		currency, err := money.ParseCurrency(line.Currency)
+		if err != nil {
+			return nil, core.ErrBadRequest.
+				WithError(errorx.ErrInvalidCurrency.Error()).
+				WithReasonf("unknown currency %s", line.Currency)
+		}
+		if currency == money.DefaultCurrency {
+			return nil, core.ErrBadRequest.
+				WithError(errorx.ErrInvalidExchangeRate.Error()).
+				WithReasonf("%s is the base currency, its rate is always 1", currency)
+		}
+
+		rate, err := money.ParseRate(line.Rate)
+		if err != nil {
+			return nil, core.ErrBadRequest.
+				WithError(errorx.ErrInvalidExchangeRate.Error()).
+				WithReasonf("%s rate %q is not a positive decimal", currency, line.Rate)
+		}
+
+		rates = append(rates, &entities.ExchangeRate{
+			Currency:  currency,
+			Rate:      rate,
+			Source:    source,
+			CreatedAt: now,
+		})
+	}
+
+	response := make([]*dto.ExchangeRateResponse, 0, len(rates))
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		for _, rate := range rates {
+			created, err := s.rateRepo.Create(ctx, rate)
+			if err != nil {
+				return err
+			}
+			response = append(response, toExchangeRateResponse(created))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *currencyService) ListRates(ctx context.Context) ([]*dto.ExchangeRateResponse, error) {
+	rates, err := s.rateRepo.ListLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*dto.ExchangeRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		response = append(response, toExchangeRateResponse(rate))
+	}
+
+	return response, nil
+}
+
+func toExchangeRateResponse(rate *entities.ExchangeRate) *dto.ExchangeRateResponse {
+	return &dto.ExchangeRateResponse{
+		ID:        rate.ID,
+		Base:      money.DefaultCurrency.String(),
+		Currency:  rate.Currency.String(),
+		Rate:      rate.Rate,
+		Source:    rate.Source,
+		CreatedAt: rate.CreatedAt,
+	}
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"mallbots/modules/currency/application/dto"
+	"mallbots/modules/currency/domain/entities"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/phathdt/service-context/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockExchangeRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateRepository) Create(ctx context.Context, rate *entities.ExchangeRate) (*entities.ExchangeRate, error) {
+	args := m.Called(ctx, rate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) GetLatest(ctx context.Context, currencies []money.Currency) ([]*entities.ExchangeRate, error) {
+	args := m.Called(ctx, currencies)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) ListLatest(ctx context.Context) ([]*entities.ExchangeRate, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.ExchangeRate), args.Error(1)
+}
+
+type MockTxManager struct {
+	mock.Mock
+}
+
+func (m *MockTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.Called(ctx)
+	return fn(ctx)
+}
+
+func TestExchange(t *testing.T) {
+	ctx := context.Background()
+	eur := &entities.ExchangeRate{ID: 7, Currency: money.EUR, Rate: money.MustParseRate("0.92"), Source: "ecb"}
+	vnd := &entities.ExchangeRate{ID: 8, Currency: money.VND, Rate: money.MustParseRate("25000"), Source: "ecb"}
+
+	t.Run("Converts Through The Base Currency", func(t *testing.T) {
+		rateRepo := new(MockExchangeRateRepository)
+		service := NewCurrencyService(rateRepo, new(MockTxManager))
+		rateRepo.On("GetLatest", ctx, []money.Currency{money.EUR, money.VND}).Return([]*entities.ExchangeRate{eur, vnd}, nil)
+
+		exchange, err := service.Exchange(ctx, money.EUR, money.USD, money.VND, money.EUR)
+
+		require.NoError(t, err)
+		assert.Equal(t, "9.20", exchange.Convert(money.MustParse("10", money.USD), money.EUR).String())
+		assert.Equal(t, "10.00", exchange.Convert(money.MustParse("9.20", money.EUR), money.USD).String())
+		assert.Equal(t, "271739", exchange.Convert(money.MustParse("10", money.EUR), money.VND).String())
+		assert.Equal(t, eur, exchange.RateOf(money.EUR))
+		assert.Nil(t, exchange.RateOf(money.USD))
+	})
+
+	t.Run("Base Currency Needs No Rate", func(t *testing.T) {
+		rateRepo := new(MockExchangeRateRepository)
+		service := NewCurrencyService(rateRepo, new(MockTxManager))
+
+		exchange, err := service.Exchange(ctx, money.USD)
+
+		require.NoError(t, err)
+		assert.Equal(t, "10.00", exchange.Convert(money.MustParse("10", money.USD), money.USD).String())
+		rateRepo.AssertNotCalled(t, "GetLatest", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Missing Rate", func(t *testing.T) {
+		rateRepo := new(MockExchangeRateRepository)
+		service := NewCurrencyService(rateRepo, new(MockTxManager))
+		rateRepo.On("GetLatest", ctx, []money.Currency{money.EUR, money.GBP}).Return([]*entities.ExchangeRate{eur}, nil)
+
+		_, err := service.Exchange(ctx, money.EUR, money.GBP)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+		assert.Equal(t, errorx.ErrExchangeRateNotFound.Error(), appErr.Error())
+	})
+}
+
+func TestParseCurrency(t *testing.T) {
+	service := NewCurrencyService(new(MockExchangeRateRepository), new(MockTxManager))
+
+	currency, err := service.ParseCurrency("")
+	require.NoError(t, err)
+	assert.Equal(t, money.DefaultCurrency, currency)
+
+	currency, err = service.ParseCurrency(" eur ")
+	require.NoError(t, err)
+	assert.Equal(t, money.EUR, currency)
+
+	_, err = service.ParseCurrency("euro")
+	assert.Error(t, err)
+}
+
+func TestLoadRates(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Publishes Every Rate", func(t *testing.T) {
+		rateRepo := new(MockExchangeRateRepository)
+		txManager := new(MockTxManager)
+		txManager.On("WithTx", ctx).Return()
+		service := NewCurrencyService(rateRepo, txManager)
+
+		rateRepo.On("Create", ctx, mock.MatchedBy(func(rate *entities.ExchangeRate) bool {
+			return rate.Currency == money.EUR && rate.Rate.String() == "0.9215" && rate.Source == "ecb"
+		})).Return(&entities.ExchangeRate{ID: 1, Currency: money.EUR, Rate: money.MustParseRate("0.9215"), Source: "ecb", CreatedAt: time.Now()}, nil)
+		rateRepo.On("Create", ctx, mock.MatchedBy(func(rate *entities.ExchangeRate) bool {
+			return rate.Currency == money.JPY && rate.Rate.String() == "149.5"
+		})).Return(&entities.ExchangeRate{ID: 2, Currency: money.JPY, Rate: money.MustParseRate("149.5"), Source: "ecb", CreatedAt: time.Now()}, nil)
+
+		rates, err := service.LoadRates(ctx, &dto.LoadRatesRequest{
+			Source: "ecb",
+			Rates:  []dto.RateLine{{Currency: "eur", Rate: "0.92150"}, {Currency: "JPY", Rate: "149.5"}},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, rates, 2)
+		assert.Equal(t, "USD", rates[0].Base)
+		assert.Equal(t, "EUR", rates[0].Currency)
+		rateRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects The Whole File On A Bad Rate", func(t *testing.T) {
+		rateRepo := new(MockExchangeRateRepository)
+		service := NewCurrencyService(rateRepo, new(MockTxManager))
+
+		for _, line := range []dto.RateLine{
+			{Currency: "EUR", Rate: "0"},
+			{Currency: "EUR", Rate: "-1.2"},
+			{Currency: "EUR", Rate: "1e3"},
+			{Currency: "USD", Rate: "1"},
+			{Currency: "EURO", Rate: "0.92"},
+		} {
+			_, err := service.LoadRates(ctx, &dto.LoadRatesRequest{
+				Source: "ecb",
+				Rates:  []dto.RateLine{{Currency: "GBP", Rate: "0.79"}, line},
+			})
+			assert.Error(t, err, "%s %s", line.Currency, line.Rate)
+		}
+
+		rateRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
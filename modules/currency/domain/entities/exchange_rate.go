@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"fmt"
+	"mallbots/shared/money"
+	"time"
+)
+
+// ExchangeRate is one published rate of a currency against the base currency.
+// Rates are never updated, a new rate is a new row.
+type ExchangeRate struct {
+	ID       int32
+	Currency money.Currency
+	// Rate is how many units of Currency one unit of the base currency buys
+	Rate      money.Rate
+	Source    string
+	CreatedAt time.Time
+}
+
+// Exchange is a set of rates read together, every amount converted with it is
+// converted at the same rates
+type Exchange struct {
+	rates map[money.Currency]*ExchangeRate
+}
+
+func NewExchange(rates ...*ExchangeRate) *Exchange {
+	exchange := &Exchange{rates: make(map[money.Currency]*ExchangeRate, len(rates))}
+	for _, rate := range rates {
+		exchange.rates[rate.Currency] = rate
+	}
+	return exchange
+}
+
+// RateOf returns the rate the currency is converted at, nil for the base
+// currency which needs none
+func (e *Exchange) RateOf(currency money.Currency) *ExchangeRate {
+	if currency == money.DefaultCurrency {
+		return nil
+	}
+	return e.rates[currency]
+}
+
+// Convert changes the amount into currency to. Both currencies must have
+// been loaded into the exchange.
+func (e *Exchange) Convert(amount money.Money, to money.Currency) money.Money {
+	return amount.Convert(to, e.rate(amount.Currency()), e.rate(to))
+}
+
+func (e *Exchange) rate(currency money.Currency) money.Rate {
+	if currency == money.DefaultCurrency {
+		return money.Rate{}
+	}
+	rate, ok := e.rates[currency]
+	if !ok {
+		panic(fmt.Sprintf("currency: no %s rate in the exchange", currency))
+	}
+	return rate.Rate
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/currency/application/dto"
+	"mallbots/modules/currency/domain/entities"
+	"mallbots/shared/money"
+)
+
+type CurrencyService interface {
+	// ParseCurrency reads the currency a caller asked to be shown prices in,
+	// the base currency when they did not ask
+	ParseCurrency(code string) (money.Currency, error)
+	// Exchange loads the current rates of the currencies, failing when one of
+	// them has no rate
+	Exchange(ctx context.Context, currencies ...money.Currency) (*entities.Exchange, error)
+	// LoadRates publishes new rates, all of them or none
+	LoadRates(ctx context.Context, req *dto.LoadRatesRequest) ([]*dto.ExchangeRateResponse, error)
+	ListRates(ctx context.Context) ([]*dto.ExchangeRateResponse, error)
+}
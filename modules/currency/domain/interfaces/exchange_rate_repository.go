@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/currency/domain/entities"
+	"mallbots/shared/money"
+)
+
+type ExchangeRateRepository interface {
+	Create(ctx context.Context, rate *entities.ExchangeRate) (*entities.ExchangeRate, error)
+	// GetLatest returns the current rate of each currency that has one
+	GetLatest(ctx context.Context, currencies []money.Currency) ([]*entities.ExchangeRate, error)
+	ListLatest(ctx context.Context) ([]*entities.ExchangeRate, error)
+}
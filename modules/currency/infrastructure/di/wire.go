@@ -0,0 +1,31 @@
+//go:build wireinject
+
+package di
+
+import (
+	"mallbots/modules/currency/application/services"
+	"mallbots/modules/currency/domain/interfaces"
+	"mallbots/modules/currency/infrastructure/repositories"
+	"mallbots/modules/currency/infrastructure/rest"
+	"mallbots/plugins/pgxc"
+
+	"github.com/google/wire"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var CurrencySet = wire.NewSet(
+	repositories.NewExchangeRateRepository,
+	pgxc.NewTxManager,
+	services.NewCurrencyService,
+	rest.NewCurrencyHandler,
+)
+
+func InitializeCurrencyHandler(db *pgxpool.Pool) (*rest.CurrencyHandler, error) {
+	wire.Build(CurrencySet)
+	return &rest.CurrencyHandler{}, nil
+}
+
+func InitializeCurrencyService(db *pgxpool.Pool) (interfaces.CurrencyService, error) {
+	wire.Build(CurrencySet)
+	return nil, nil
+}
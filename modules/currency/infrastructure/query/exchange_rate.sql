@@ -0,0 +1,18 @@
+-- name: CreateExchangeRate :one
+INSERT INTO exchange_rates (
+    currency,
+    rate,
+    source,
+    created_at
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetLatestExchangeRates :many
+SELECT DISTINCT ON (currency) * FROM exchange_rates
+WHERE currency = ANY($1::text[])
+ORDER BY currency, created_at DESC, id DESC;
+
+-- name: ListLatestExchangeRates :many
+SELECT DISTINCT ON (currency) * FROM exchange_rates
+ORDER BY currency, created_at DESC, id DESC;
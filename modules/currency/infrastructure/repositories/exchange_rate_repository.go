@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"mallbots/modules/currency/domain/entities"
+	"mallbots/modules/currency/domain/interfaces"
+	"mallbots/modules/currency/infrastructure/query/gen"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type exchangeRateRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewExchangeRateRepository(db *pgxpool.Pool) interfaces.ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+func (r *exchangeRateRepository) Create(ctx context.Context, rate *entities.ExchangeRate) (*entities.ExchangeRate, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbRate, err := queries.CreateExchangeRate(ctx, gen.CreateExchangeRateParams{
+		Currency:  rate.Currency,
+		Rate:      rate.Rate,
+		Source:    rate.Source,
+		CreatedAt: rate.CreatedAt,
+	})
+	if err != nil {
+		return nil, errorx.ErrCannotCreateExchangeRate
+	}
+
+	return toExchangeRate(dbRate), nil
+}
+
+func (r *exchangeRateRepository) GetLatest(ctx context.Context, currencies []money.Currency) ([]*entities.ExchangeRate, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	codes := make([]string, len(currencies))
+	for i, currency := range currencies {
+		codes[i] = currency.String()
+	}
+
+	dbRates, err := queries.GetLatestExchangeRates(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	return toExchangeRates(dbRates), nil
+}
+
+func (r *exchangeRateRepository) ListLatest(ctx context.Context) ([]*entities.ExchangeRate, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbRates, err := queries.ListLatestExchangeRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toExchangeRates(dbRates), nil
+}
+
+func toExchangeRates(dbRates []*gen.ExchangeRate) []*entities.ExchangeRate {
+	rates := make([]*entities.ExchangeRate, 0, len(dbRates))
+	for _, dbRate := range dbRates {
+		rates = append(rates, toExchangeRate(dbRate))
+	}
+	return rates
+}
+
+func toExchangeRate(dbRate *gen.ExchangeRate) *entities.ExchangeRate {
+	return &entities.ExchangeRate{
+		ID:        dbRate.ID,
+		Currency:  dbRate.Currency,
+		Rate:      dbRate.Rate,
+		Source:    dbRate.Source,
+		CreatedAt: dbRate.CreatedAt,
+	}
+}
@@ -0,0 +1,26 @@
+package rest
+
+import (
+	"mallbots/modules/currency/domain/interfaces"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/phathdt/service-context/core"
+)
+
+type CurrencyHandler struct {
+	service interfaces.CurrencyService
+}
+
+func NewCurrencyHandler(service interfaces.CurrencyService) *CurrencyHandler {
+	return &CurrencyHandler{service: service}
+}
+
+func (h *CurrencyHandler) ListRates(c *fiber.Ctx) error {
+	rates, err := h.service.ListRates(c.Context())
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(rates))
+}
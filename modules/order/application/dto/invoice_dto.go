@@ -0,0 +1,9 @@
+package dto
+
+// InvoiceFile is a rendered invoice, ready to be sent as is
+type InvoiceFile struct {
+	Number      string
+	ContentType string
+	Filename    string
+	Content     []byte
+}
@@ -1,39 +1,184 @@
 package dto
 
-import "time"
+import (
+	"mallbots/shared/money"
+	"time"
+)
 
 type CreateOrderRequest struct {
-	ShippingAddress string `json:"shipping_address" validate:"required"`
-	ShippingCity    string `json:"shipping_city" validate:"required"`
+	// AddressID ships to an address from the caller's address book instead
+	// of the inline shipping fields. Without either the order ships to the
+	// default shipping address.
+	AddressID       *int32 `json:"address_id" validate:"omitempty,min=1"`
+	ShippingAddress string `json:"shipping_address"`
+	ShippingCity    string `json:"shipping_city"`
+	ShippingCountry string `json:"shipping_country"`
+	ShippingZip     string `json:"shipping_zip"`
+	// BillingAddressID and the inline billing fields work the same way, the
+	// default billing address and then the shipping address come next
+	BillingAddressID *int32 `json:"billing_address_id" validate:"omitempty,min=1"`
+	BillingAddress   string `json:"billing_address"`
+	BillingCity      string `json:"billing_city"`
+	BillingCountry   string `json:"billing_country"`
+	BillingZip       string `json:"billing_zip"`
+	// AcceptPriceChanges lets checkout go ahead at the current prices when
+	// they differ from the ones stored in the cart
+	AcceptPriceChanges bool `json:"accept_price_changes"`
+	// Currency is what the order is placed and paid in, the base currency
+	// when empty
+	Currency string `json:"currency"`
+}
+
+// PriceChangeResponse compares the prices in the product's own currency
+type PriceChangeResponse struct {
+	ProductID int32       `json:"product_id"`
+	OldPrice  money.Money `json:"old_price"`
+	NewPrice  money.Money `json:"new_price"`
+}
+
+type ShippingQuoteRequest struct {
 	ShippingCountry string `json:"shipping_country" validate:"required"`
-	ShippingZip     string `json:"shipping_zip" validate:"required"`
+	ShippingZip     string `json:"shipping_zip"`
+	Currency        string `json:"currency"`
+}
+
+type ShippingQuoteResponse struct {
+	Zone           string       `json:"zone"`
+	Currency       string       `json:"currency"`
+	Subtotal       money.Money  `json:"subtotal"`
+	ShippingAmount money.Money  `json:"shipping_amount"`
+	DiscountAmount money.Money  `json:"discount_amount"`
+	TaxAmount      money.Money  `json:"tax_amount"`
+	TaxInclusive   bool         `json:"tax_inclusive"`
+	TotalAmount    money.Money  `json:"total_amount"`
+	FreeShipping   bool         `json:"free_shipping"`
+	FreeAbove      *money.Money `json:"free_shipping_above,omitempty"`
+	// Weight is the total weight of the cart in grams
+	Weight int64 `json:"weight"`
 }
 
 type OrderItemResponse struct {
-	ID        int32   `json:"id"`
-	ProductID int32   `json:"product_id"`
-	Quantity  int32   `json:"quantity"`
-	Price     float64 `json:"price"`
+	ID             int32       `json:"id"`
+	ProductID      int32       `json:"product_id"`
+	Quantity       int32       `json:"quantity"`
+	Price          money.Money `json:"price"`
+	DiscountAmount money.Money `json:"discount_amount"`
+	TaxRate        float64     `json:"tax_rate"`
+	TaxAmount      money.Money `json:"tax_amount"`
+	// Price was converted from ProductPrice, the product's own price, at
+	// ExchangeRate against the base currency and the order's rate
+	ProductPrice    money.Money `json:"product_price"`
+	ProductCurrency string      `json:"product_currency"`
+	ExchangeRate    money.Rate  `json:"exchange_rate"`
+	// The product as it was ordered, independent of the live catalog
+	ProductName        string  `json:"product_name"`
+	ProductDescription *string `json:"product_description,omitempty"`
+	CategoryID         *int32  `json:"category_id,omitempty"`
+	CategoryName       *string `json:"category_name,omitempty"`
+}
+
+type TaxBreakdownResponse struct {
+	// Inclusive means item prices already contain the tax
+	Inclusive bool              `json:"inclusive"`
+	Rates     []TaxRateResponse `json:"rates"`
+}
+
+type TaxRateResponse struct {
+	Rate          float64     `json:"rate"`
+	TaxableAmount money.Money `json:"taxable_amount"`
+	TaxAmount     money.Money `json:"tax_amount"`
 }
 
 type OrderResponse struct {
-	ID              int32               `json:"id"`
-	Status          string              `json:"status"`
-	PaymentStatus   string              `json:"payment_status"`
-	TotalAmount     float64             `json:"total_amount"`
-	ShippingAddress string              `json:"shipping_address"`
-	ShippingCity    string              `json:"shipping_city"`
-	ShippingCountry string              `json:"shipping_country"`
-	ShippingZip     string              `json:"shipping_zip"`
-	Items           []OrderItemResponse `json:"items"`
-	CreatedAt       time.Time           `json:"created_at"`
-	UpdatedAt       time.Time           `json:"updated_at"`
+	ID              int32                `json:"id"`
+	Status          string               `json:"status"`
+	PaymentStatus   string               `json:"payment_status"`
+	Currency        string               `json:"currency"`
+	ExchangeRate    money.Rate           `json:"exchange_rate"`
+	TotalAmount     money.Money          `json:"total_amount"`
+	ShippingAmount  money.Money          `json:"shipping_amount"`
+	DiscountAmount  money.Money          `json:"discount_amount"`
+	CouponCode      *string              `json:"coupon_code,omitempty"`
+	TaxAmount       money.Money          `json:"tax_amount"`
+	Tax             TaxBreakdownResponse `json:"tax"`
+	ShippingAddress string               `json:"shipping_address"`
+	ShippingCity    string               `json:"shipping_city"`
+	ShippingCountry string               `json:"shipping_country"`
+	ShippingZip     string               `json:"shipping_zip"`
+	BillingAddress  string               `json:"billing_address"`
+	BillingCity     string               `json:"billing_city"`
+	BillingCountry  string               `json:"billing_country"`
+	BillingZip      string               `json:"billing_zip"`
+	CancelReason    *string              `json:"cancel_reason,omitempty"`
+	CancelledAt     *time.Time           `json:"cancelled_at,omitempty"`
+	Items           []OrderItemResponse  `json:"items"`
+	Shipments       []ShipmentResponse   `json:"shipments,omitempty"`
+	CreatedAt       time.Time            `json:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at"`
+}
+
+// OrderListRequest filters and sorts a customer's order history. Dates are
+// RFC 3339 timestamps or plain dates, a plain To date covers its whole day.
+// Currency only lists the orders placed in it, MinTotal and MaxTotal are
+// amounts in it and the total sorts need it. Include and Exclude list the
+// relations loaded with each order, comma separated, only "items" so far.
+// Items are included unless excluded, Exclude wins when both name them.
+type OrderListRequest struct {
+	Status        string  `query:"status"`
+	PaymentStatus string  `query:"payment_status"`
+	From          string  `query:"from"`
+	To            string  `query:"to"`
+	MinTotal      *string `query:"min_total"`
+	MaxTotal      *string `query:"max_total"`
+	Currency      string  `query:"currency"`
+	ProductID     *int32  `query:"product_id"`
+	SortBy        string  `query:"sort_by"`
+	Include       string  `query:"include"`
+	Exclude       string  `query:"exclude"`
 }
 
 type UpdateOrderStatusRequest struct {
 	Status string `json:"status" validate:"required"`
+	// Reason is recorded on the order when Status is CANCELLED
+	Reason string `json:"reason" validate:"omitempty,max=500"`
 }
 
 type UpdatePaymentStatusRequest struct {
 	PaymentStatus string `json:"payment_status" validate:"required"`
 }
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// ReorderResponse summarizes what copying a past order into the cart did,
+// item by item
+type ReorderResponse struct {
+	OrderID int32                 `json:"order_id"`
+	Items   []ReorderItemResponse `json:"items"`
+}
+
+type ReorderItemResponse struct {
+	ProductID   int32  `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Status      string `json:"status"`
+	// OrderedQuantity is the quantity of the past order, AddedQuantity what
+	// went into the cart
+	OrderedQuantity int32 `json:"ordered_quantity"`
+	AddedQuantity   int32 `json:"added_quantity"`
+	// Price is the current price the item was added at
+	Price *money.Money `json:"price,omitempty"`
+	// Reason explains adjusted and skipped items
+	Reason string `json:"reason,omitempty"`
+}
+
+type OrderEventResponse struct {
+	ID         int32          `json:"id"`
+	Type       string         `json:"type"`
+	FromStatus *string        `json:"from_status,omitempty"`
+	ToStatus   *string        `json:"to_status,omitempty"`
+	ActorID    *int32         `json:"actor_id,omitempty"`
+	ActorRole  string         `json:"actor_role"`
+	Metadata   map[string]any `json:"metadata"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "mallbots/shared/money"
+
+type PaymentIntentResponse struct {
+	OrderID      int32       `json:"order_id"`
+	Provider     string      `json:"provider"`
+	IntentID     string      `json:"intent_id"`
+	ClientSecret string      `json:"client_secret"`
+	Amount       money.Money `json:"amount"`
+	Currency     string      `json:"currency"`
+	Status       string      `json:"status"`
+}
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"encoding/json"
+	"mallbots/shared/money"
+	"time"
+)
+
+type RefundItemRequest struct {
+	OrderItemID int32 `json:"order_item_id" validate:"required"`
+	Quantity    int32 `json:"quantity" validate:"required,gt=0"`
+}
+
+// CreateRefundRequest refunds either specific order items or a custom amount.
+// Amount is a decimal in the order's currency, sent as a string or a number.
+type CreateRefundRequest struct {
+	Reason string              `json:"reason" validate:"required,max=500"`
+	Items  []RefundItemRequest `json:"items" validate:"omitempty,dive"`
+	Amount *json.Number        `json:"amount"`
+}
+
+type RefundItemResponse struct {
+	ID          int32       `json:"id"`
+	OrderItemID int32       `json:"order_item_id"`
+	Quantity    int32       `json:"quantity"`
+	Amount      money.Money `json:"amount"`
+}
+
+type RefundResponse struct {
+	ID          int32                `json:"id"`
+	OrderID     int32                `json:"order_id"`
+	Status      string               `json:"status"`
+	Amount      money.Money          `json:"amount"`
+	Currency    string               `json:"currency"`
+	Reason      string               `json:"reason"`
+	Items       []RefundItemResponse `json:"items"`
+	ApprovedAt  *time.Time           `json:"approved_at,omitempty"`
+	ProcessedAt *time.Time           `json:"processed_at,omitempty"`
+	RejectedAt  *time.Time           `json:"rejected_at,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+type ShipmentItemRequest struct {
+	OrderItemID int32 `json:"order_item_id" validate:"required"`
+	Quantity    int32 `json:"quantity" validate:"required,gt=0"`
+}
+
+// CreateShipmentRequest puts units of order items in a new parcel, which
+// starts out PENDING until it is handed to the carrier
+type CreateShipmentRequest struct {
+	Carrier        string                `json:"carrier" validate:"required,max=100"`
+	TrackingNumber *string               `json:"tracking_number" validate:"omitempty,max=100"`
+	Items          []ShipmentItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// UpdateShipmentRequest moves a shipment along and corrects its carrier
+// details, fields left out stay as they are
+type UpdateShipmentRequest struct {
+	Status         *string `json:"status"`
+	Carrier        *string `json:"carrier" validate:"omitempty,min=1,max=100"`
+	TrackingNumber *string `json:"tracking_number" validate:"omitempty,max=100"`
+}
+
+type ShipmentItemResponse struct {
+	ID          int32 `json:"id"`
+	OrderItemID int32 `json:"order_item_id"`
+	Quantity    int32 `json:"quantity"`
+}
+
+type ShipmentResponse struct {
+	ID             int32                  `json:"id"`
+	OrderID        int32                  `json:"order_id"`
+	Status         string                 `json:"status"`
+	Carrier        string                 `json:"carrier"`
+	TrackingNumber *string                `json:"tracking_number,omitempty"`
+	Items          []ShipmentItemResponse `json:"items"`
+	ShippedAt      *time.Time             `json:"shipped_at,omitempty"`
+	DeliveredAt    *time.Time             `json:"delivered_at,omitempty"`
+	CancelledAt    *time.Time             `json:"cancelled_at,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mallbots/modules/order/application/dto"
+	"mallbots/modules/order/domain/constants"
+	orderEntities "mallbots/modules/order/domain/entities"
+	orderInterfaces "mallbots/modules/order/domain/interfaces"
+	productInterfaces "mallbots/modules/product/domain/interfaces"
+	userInterfaces "mallbots/modules/user/domain/interfaces"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/config"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"time"
+
+	"github.com/phathdt/service-context/core"
+)
+
+const defaultInvoicePrefix = "INV"
+
+type invoiceService struct {
+	orderRepo      orderInterfaces.OrderRepository
+	invoiceRepo    orderInterfaces.InvoiceRepository
+	productService productInterfaces.ProductService
+	userRepo       userInterfaces.UserRepository
+	renderer       orderInterfaces.InvoiceRenderer
+	txManager      pgxc.TxManager
+	policy         orderInterfaces.OrderAccessPolicy
+	eventRepo      orderInterfaces.OrderEventRepository
+	cfg            *config.Config
+}
+
+func NewInvoiceService(
+	orderRepo orderInterfaces.OrderRepository,
+	invoiceRepo orderInterfaces.InvoiceRepository,
+	productService productInterfaces.ProductService,
+	userRepo userInterfaces.UserRepository,
+	renderer orderInterfaces.InvoiceRenderer,
+	txManager pgxc.TxManager,
+	policy orderInterfaces.OrderAccessPolicy,
+	eventRepo orderInterfaces.OrderEventRepository,
+	cfg *config.Config,
+) orderInterfaces.InvoiceService {
+	return &invoiceService{
+		orderRepo:      orderRepo,
+		invoiceRepo:    invoiceRepo,
+		productService: productService,
+		userRepo:       userRepo,
+		renderer:       renderer,
+		txManager:      txManager,
+		policy:         policy,
+		eventRepo:      eventRepo,
+		cfg:            cfg,
+	}
+}
+
+func (s *invoiceService) GetInvoice(ctx context.Context, caller orderEntities.Caller, orderID int32, format constants.InvoiceFormat) (*dto.InvoiceFile, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	if err := s.policy.Authorize(caller, constants.OrderActionView, order); err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	// Refunded orders keep the invoice they were paid with, refunds are
+	// documented separately
+	if order.PaymentStatus != constants.PaymentStatusPaid && order.PaymentStatus != constants.PaymentStatusRefunded {
+		return nil, core.ErrConflict.
+			WithError(errorx.ErrInvoiceNotAvailable.Error()).
+			WithReasonf("order payment is %s", order.PaymentStatus)
+	}
+
+	invoice, err := s.issue(ctx, caller, order)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := s.buildDocument(ctx, order, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := s.renderer.Render(doc, format)
+	if err != nil {
+		return nil, core.ErrInternalServerError.
+			WithError(errorx.ErrCannotRenderInvoice.Error()).
+			WithDebug(err.Error())
+	}
+
+	return &dto.InvoiceFile{
+		Number:      invoice.Number,
+		ContentType: format.ContentType(),
+		Filename:    fmt.Sprintf("%s.%s", invoice.Number, format),
+		Content:     content,
+	}, nil
+}
+
+// issue returns the invoice of the order, numbering a new one the first time
+// it is asked for. The year lock makes concurrent issues take turns, so the
+// check for an existing invoice under it cannot race and every number is
+// handed out exactly once.
+func (s *invoiceService) issue(ctx context.Context, caller orderEntities.Caller, order *orderEntities.Order) (*orderEntities.Invoice, error) {
+	invoice, err := s.invoiceRepo.GetByOrderID(ctx, order.ID)
+	if err == nil {
+		return invoice, nil
+	}
+	if !errors.Is(err, errorx.ErrInvoiceNotFound) {
+		return nil, err
+	}
+
+	prefix := s.cfg.Invoice.NumberPrefix
+	if prefix == "" {
+		prefix = defaultInvoicePrefix
+	}
+
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		issuedAt := time.Now().UTC()
+
+		last, err := s.invoiceRepo.LockYear(ctx, int32(issuedAt.Year()))
+		if err != nil {
+			return err
+		}
+
+		invoice, err = s.invoiceRepo.GetByOrderID(ctx, order.ID)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, errorx.ErrInvoiceNotFound) {
+			return err
+		}
+
+		invoice, err = s.invoiceRepo.Create(ctx, orderEntities.NewInvoice(order.ID, prefix, last+1, issuedAt))
+		if err != nil {
+			return err
+		}
+
+		event := orderEntities.NewOrderEvent(order.ID, constants.OrderEventInvoiceIssued, caller).
+			With("invoice_number", invoice.Number)
+
+		return s.eventRepo.Append(ctx, event)
+	})
+	if err != nil {
+		if errors.Is(err, errorx.ErrCannotIssueInvoice) {
+			return nil, core.ErrInternalServerError.WithError(errorx.ErrCannotIssueInvoice.Error())
+		}
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+func (s *invoiceService) buildDocument(ctx context.Context, order *orderEntities.Order, invoice *orderEntities.Invoice) (*orderEntities.InvoiceDocument, error) {
+	buyer, err := s.userRepo.GetByID(ctx, order.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	seller := s.cfg.Invoice.Seller
+	doc := &orderEntities.InvoiceDocument{
+		Number:    invoice.Number,
+		IssuedAt:  invoice.IssuedAt,
+		OrderID:   order.ID,
+		OrderedAt: order.CreatedAt,
+		Seller: orderEntities.InvoiceParty{
+			Name:    seller.Name,
+			Email:   seller.Email,
+			TaxID:   seller.TaxID,
+			Address: seller.Address,
+			City:    seller.City,
+			Country: seller.Country,
+			Zip:     seller.Zip,
+		},
+		BillTo: orderEntities.InvoiceParty{
+			Name:    buyer.FullName,
+			Email:   buyer.Email,
+			Address: order.BillingAddress,
+			City:    order.BillingCity,
+			Country: order.BillingCountry,
+			Zip:     order.BillingZip,
+		},
+		ShipTo: orderEntities.InvoiceParty{
+			Name:    buyer.FullName,
+			Address: order.ShippingAddress,
+			City:    order.ShippingCity,
+			Country: order.ShippingCountry,
+			Zip:     order.ShippingZip,
+		},
+		Currency:       order.Currency.String(),
+		Subtotal:       money.Zero(order.Currency),
+		ShippingAmount: order.ShippingAmount,
+		DiscountAmount: order.DiscountAmount,
+		TaxAmount:      order.TaxAmount,
+		TaxInclusive:   order.TaxInclusive,
+		TotalAmount:    order.TotalAmount,
+	}
+	if order.CouponCode != nil {
+		doc.CouponCode = *order.CouponCode
+	}
+
+	for _, item := range order.Items {
+		line := orderEntities.InvoiceLine{
+			ProductID:   item.ProductID,
+			Description: s.describe(ctx, item),
+			Quantity:    item.Quantity,
+			UnitPrice:   item.Price,
+			Amount:      item.Price.Mul(int64(item.Quantity)),
+			Discount:    item.DiscountAmount,
+			TaxRate:     item.TaxRate,
+			TaxAmount:   item.TaxAmount,
+		}
+
+		doc.Lines = append(doc.Lines, line)
+		doc.Subtotal = doc.Subtotal.Add(line.Amount)
+	}
+
+	return doc, nil
+}
+
+// describe names a line after the product as it was ordered. Items from
+// before product snapshots whose product has left the catalog since are
+// printed by ID.
+func (s *invoiceService) describe(ctx context.Context, item *orderEntities.OrderItem) string {
+	if item.ProductName != "" {
+		return item.ProductName
+	}
+
+	product, err := s.productService.GetProduct(ctx, item.ProductID)
+	if err != nil {
+		return fmt.Sprintf("Product #%d", item.ProductID)
+	}
+	return product.Name
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/modules/order/domain/interfaces"
+	productDto "mallbots/modules/product/application/dto"
+	userEntities "mallbots/modules/user/domain/entities"
+	"mallbots/shared/config"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/phathdt/service-context/core"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockInvoiceRepository struct {
+	mock.Mock
+}
+
+func (m *MockInvoiceRepository) GetByOrderID(ctx context.Context, orderID int32) (*entities.Invoice, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Invoice), args.Error(1)
+}
+
+func (m *MockInvoiceRepository) LockYear(ctx context.Context, year int32) (int32, error) {
+	args := m.Called(ctx, year)
+	return args.Get(0).(int32), args.Error(1)
+}
+
+func (m *MockInvoiceRepository) Create(ctx context.Context, invoice *entities.Invoice) (*entities.Invoice, error) {
+	args := m.Called(ctx, invoice)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Invoice), args.Error(1)
+}
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, user *userEntities.User) (*userEntities.User, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userEntities.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, id int32) (*userEntities.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userEntities.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*userEntities.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userEntities.User), args.Error(1)
+}
+
+type MockInvoiceRenderer struct {
+	mock.Mock
+}
+
+func (m *MockInvoiceRenderer) Render(doc *entities.InvoiceDocument, format constants.InvoiceFormat) ([]byte, error) {
+	args := m.Called(doc, format)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+type invoiceTestSuite struct {
+	orderRepo      *MockOrderRepository
+	invoiceRepo    *MockInvoiceRepository
+	productService *MockProductService
+	userRepo       *MockUserRepository
+	renderer       *MockInvoiceRenderer
+	eventRepo      *MockOrderEventRepository
+	invoiceService interfaces.InvoiceService
+	ctx            context.Context
+}
+
+func setupInvoiceTest(t *testing.T) *invoiceTestSuite {
+	orderRepo := new(MockOrderRepository)
+	invoiceRepo := new(MockInvoiceRepository)
+	productService := new(MockProductService)
+	userRepo := new(MockUserRepository)
+	renderer := new(MockInvoiceRenderer)
+	txManager := new(MockTxManager)
+	txManager.On("WithTx", mock.Anything).Return()
+	eventRepo := new(MockOrderEventRepository)
+	eventRepo.On("Append", mock.Anything, mock.Anything).Return(nil)
+
+	cfg := &config.Config{
+		Invoice: config.InvoiceConfig{
+			NumberPrefix: "MB",
+			Seller:       config.SellerConfig{Name: "Mallbots Ltd", TaxID: "GB123"},
+		},
+	}
+
+	return &invoiceTestSuite{
+		orderRepo:      orderRepo,
+		invoiceRepo:    invoiceRepo,
+		productService: productService,
+		userRepo:       userRepo,
+		renderer:       renderer,
+		eventRepo:      eventRepo,
+		invoiceService: NewInvoiceService(orderRepo, invoiceRepo, productService, userRepo, renderer, txManager, NewOrderAccessPolicy(), eventRepo, cfg),
+		ctx:            context.Background(),
+	}
+}
+
+func paidOrder() *entities.Order {
+	coupon := "SAVE5"
+
+	return &entities.Order{
+		ID:             1,
+		UserID:         1,
+		Status:         constants.OrderStatusConfirmed,
+		PaymentStatus:  constants.PaymentStatusPaid,
+		Currency:       money.USD,
+		TotalAmount:    usd("41.97"),
+		ShippingAmount: usd("5.00"),
+		DiscountAmount: usd("5.00"),
+		TaxAmount:      usd("0.00"),
+		CouponCode:     &coupon,
+		ShippingCity:   "Test City",
+		BillingCity:    "Billing City",
+		Items: []*entities.OrderItem{
+			{ID: 10, OrderID: 1, ProductID: 1, Quantity: 2, Price: usd("10.99"), DiscountAmount: usd("2.50")},
+			{ID: 11, OrderID: 1, ProductID: 2, Quantity: 1, Price: usd("19.99"), DiscountAmount: usd("2.50")},
+		},
+	}
+}
+
+func (ts *invoiceTestSuite) expectDocument() {
+	ts.userRepo.On("GetByID", mock.Anything, int32(1)).Return(&userEntities.User{ID: 1, FullName: "Test User 1", Email: "test1@example.com"}, nil)
+	ts.productService.On("GetProduct", mock.Anything, int32(1)).Return(&productDto.ProductResponse{ID: 1, Name: "Mug"}, nil)
+	ts.productService.On("GetProduct", mock.Anything, int32(2)).Return(nil, core.ErrNotFound)
+}
+
+func TestInvoiceService(t *testing.T) {
+	t.Run("Get Invoice - Issues The Next Number", func(t *testing.T) {
+		ts := setupInvoiceTest(t)
+		ts.expectDocument()
+		year := int32(time.Now().UTC().Year())
+		number := fmt.Sprintf("MB-%d-000042", year)
+
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(paidOrder(), nil)
+		ts.invoiceRepo.On("GetByOrderID", mock.Anything, int32(1)).Return(nil, errorx.ErrInvoiceNotFound)
+		ts.invoiceRepo.On("LockYear", mock.Anything, year).Return(int32(41), nil)
+		ts.invoiceRepo.On("Create", mock.Anything, mock.MatchedBy(func(invoice *entities.Invoice) bool {
+			return invoice.Sequence == 42 && invoice.Year == year && invoice.Number == number
+		})).Return(&entities.Invoice{
+			ID: 9, OrderID: 1, Year: year, Sequence: 42, Number: number, IssuedAt: time.Now(),
+		}, nil)
+		ts.renderer.On("Render", mock.MatchedBy(func(doc *entities.InvoiceDocument) bool {
+			return doc.Seller.Name == "Mallbots Ltd" &&
+				doc.BillTo.Email == "test1@example.com" &&
+				doc.BillTo.City == "Billing City" &&
+				doc.ShipTo.City == "Test City" &&
+				doc.CouponCode == "SAVE5" &&
+				len(doc.Lines) == 2 &&
+				doc.Lines[0].Description == "Mug" &&
+				doc.Lines[0].Amount == usd("21.98") &&
+				doc.Lines[1].Description == "Product #2" &&
+				doc.Subtotal == usd("41.97")
+		}), constants.InvoiceFormatPDF).Return([]byte("%PDF-1.3"), nil)
+
+		file, err := ts.invoiceService.GetInvoice(ts.ctx, customer(1), 1, constants.InvoiceFormatPDF)
+		require.NoError(t, err)
+		require.Equal(t, number, file.Number)
+		require.Equal(t, number+".pdf", file.Filename)
+		require.Equal(t, "application/pdf", file.ContentType)
+		require.Equal(t, []byte("%PDF-1.3"), file.Content)
+		ts.eventRepo.AssertCalled(t, "Append", mock.Anything, mock.MatchedBy(func(event *entities.OrderEvent) bool {
+			return event.Type == constants.OrderEventInvoiceIssued && event.Metadata["invoice_number"] == number
+		}))
+	})
+
+	t.Run("Get Invoice - Reuses The Issued Number", func(t *testing.T) {
+		ts := setupInvoiceTest(t)
+		ts.expectDocument()
+
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(paidOrder(), nil)
+		ts.invoiceRepo.On("GetByOrderID", ts.ctx, int32(1)).Return(&entities.Invoice{
+			ID: 5, OrderID: 1, Year: 2026, Sequence: 7, Number: "MB-2026-000007", IssuedAt: time.Now(),
+		}, nil)
+		ts.renderer.On("Render", mock.Anything, constants.InvoiceFormatHTML).Return([]byte("<html>"), nil)
+
+		file, err := ts.invoiceService.GetInvoice(ts.ctx, customer(1), 1, constants.InvoiceFormatHTML)
+		require.NoError(t, err)
+		require.Equal(t, "MB-2026-000007.html", file.Filename)
+		ts.invoiceRepo.AssertNotCalled(t, "LockYear", mock.Anything, mock.Anything)
+		ts.eventRepo.AssertNotCalled(t, "Append", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Get Invoice - Describes Lines From The Product Snapshot", func(t *testing.T) {
+		ts := setupInvoiceTest(t)
+		ts.userRepo.On("GetByID", mock.Anything, int32(1)).Return(&userEntities.User{ID: 1, FullName: "Test User 1", Email: "test1@example.com"}, nil)
+
+		order := paidOrder()
+		order.Items[0].ProductName = "Ceramic Mug"
+		order.Items[1].ProductName = "Teapot"
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(order, nil)
+		ts.invoiceRepo.On("GetByOrderID", ts.ctx, int32(1)).Return(&entities.Invoice{
+			ID: 5, OrderID: 1, Year: 2026, Sequence: 7, Number: "MB-2026-000007", IssuedAt: time.Now(),
+		}, nil)
+		ts.renderer.On("Render", mock.MatchedBy(func(doc *entities.InvoiceDocument) bool {
+			return len(doc.Lines) == 2 &&
+				doc.Lines[0].Description == "Ceramic Mug" &&
+				doc.Lines[1].Description == "Teapot"
+		}), constants.InvoiceFormatHTML).Return([]byte("<html>"), nil)
+
+		_, err := ts.invoiceService.GetInvoice(ts.ctx, customer(1), 1, constants.InvoiceFormatHTML)
+		require.NoError(t, err)
+		ts.productService.AssertNotCalled(t, "GetProduct", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Get Invoice - Unpaid Order", func(t *testing.T) {
+		ts := setupInvoiceTest(t)
+
+		order := paidOrder()
+		order.PaymentStatus = constants.PaymentStatusPending
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(order, nil)
+
+		file, err := ts.invoiceService.GetInvoice(ts.ctx, customer(1), 1, constants.InvoiceFormatHTML)
+		require.Nil(t, file)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrInvoiceNotAvailable.Error(), appErr.Error())
+		ts.invoiceRepo.AssertNotCalled(t, "GetByOrderID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Get Invoice - Other Customer's Order", func(t *testing.T) {
+		ts := setupInvoiceTest(t)
+
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(paidOrder(), nil)
+
+		file, err := ts.invoiceService.GetInvoice(ts.ctx, customer(2), 1, constants.InvoiceFormatHTML)
+		require.Nil(t, file)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusNotFound, appErr.StatusCode())
+	})
+}
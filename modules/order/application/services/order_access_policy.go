@@ -0,0 +1,28 @@
+package services
+
+import (
+	"mallbots/modules/order/domain/constants"
+	orderEntities "mallbots/modules/order/domain/entities"
+	orderInterfaces "mallbots/modules/order/domain/interfaces"
+	"mallbots/shared/errorx"
+)
+
+type orderAccessPolicy struct{}
+
+// NewOrderAccessPolicy lets owners do anything with their own orders and
+// admins do anything with every order but reorder it, which fills the
+// caller's own cart
+func NewOrderAccessPolicy() orderInterfaces.OrderAccessPolicy {
+	return &orderAccessPolicy{}
+}
+
+func (p *orderAccessPolicy) Authorize(caller orderEntities.Caller, action constants.OrderAction, order *orderEntities.Order) error {
+	if order.UserID == caller.UserID {
+		return nil
+	}
+	if caller.IsAdmin() && action != constants.OrderActionReorder {
+		return nil
+	}
+
+	return errorx.ErrUnauthorizedOrderAccess
+}
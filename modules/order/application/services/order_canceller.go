@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"mallbots/modules/order/domain/constants"
+	orderEntities "mallbots/modules/order/domain/entities"
+	orderInterfaces "mallbots/modules/order/domain/interfaces"
+	"mallbots/plugins/eventbus"
+	"mallbots/plugins/outbox"
+	"mallbots/plugins/payment"
+	"time"
+
+	sctx "github.com/phathdt/service-context"
+)
+
+// orderCanceller cancels orders for the order service and the expiry job
+// alike, so a cancellation releases the stock, lands on the timeline and
+// settles the payment the same way whoever asked for it
+type orderCanceller struct {
+	orderRepo orderInterfaces.OrderRepository
+	refunds   orderInterfaces.RefundService
+	provider  payment.PaymentProvider
+	eventBus  eventbus.Bus
+	eventRepo orderInterfaces.OrderEventRepository
+	outbox    outbox.Writer
+	logger    sctx.Logger
+}
+
+func newOrderCanceller(
+	orderRepo orderInterfaces.OrderRepository,
+	refunds orderInterfaces.RefundService,
+	provider payment.PaymentProvider,
+	eventBus eventbus.Bus,
+	eventRepo orderInterfaces.OrderEventRepository,
+	outbox outbox.Writer,
+) *orderCanceller {
+	return &orderCanceller{
+		orderRepo: orderRepo,
+		refunds:   refunds,
+		provider:  provider,
+		eventBus:  eventBus,
+		eventRepo: eventRepo,
+		outbox:    outbox,
+		logger:    sctx.GlobalLogger().GetLogger("order"),
+	}
+}
+
+// cancel cancels the order locked by the surrounding transaction. A captured
+// payment gets an approved refund, returned for settle to pay out once the
+// transaction committed; the provider is never called under it.
+func (c *orderCanceller) cancel(ctx context.Context, caller orderEntities.Caller, order *orderEntities.Order, reason string, now time.Time) (*orderEntities.Refund, error) {
+	items, err := c.orderRepo.GetItems(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var refund *orderEntities.Refund
+	if order.PaymentStatus == constants.PaymentStatusPaid {
+		refund, err = c.refunds.RefundCancelledOrder(ctx, caller, order, reason)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	previousStatus := order.Status
+	previousPaymentStatus := order.PaymentStatus
+	order.Cancel(reason, now)
+
+	if err := c.orderRepo.Cancel(ctx, order); err != nil {
+		return nil, err
+	}
+
+	timelineEvent := orderEntities.NewOrderEvent(order.ID, constants.OrderEventCancelled, caller).
+		WithTransition(previousStatus.String(), order.Status.String()).
+		With("reason", reason).
+		With("previous_payment_status", previousPaymentStatus.String()).
+		With("payment_status", order.PaymentStatus.String())
+	if err := c.eventRepo.Append(ctx, timelineEvent); err != nil {
+		return nil, err
+	}
+
+	if err := c.outbox.Add(ctx, newOrderStatusChangedEvent(order, previousStatus, order.Status, now)); err != nil {
+		return nil, err
+	}
+
+	// Subscribers release the reserved stock
+	if err := c.eventBus.Publish(ctx, newOrderCancelledEvent(order, reason, previousPaymentStatus, items, now)); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// settle settles the payment of an order whose cancellation has committed:
+// the refund of a captured payment is paid out, an intent not captured yet
+// is cancelled so it can no longer be paid. The cancellation stands when the
+// provider fails; the refund stays approved for someone to process again,
+// and money landing on an intent left open is refunded when its webhook
+// arrives.
+func (c *orderCanceller) settle(ctx context.Context, caller orderEntities.Caller, order *orderEntities.Order, refund *orderEntities.Refund) {
+	if refund != nil {
+		if _, err := c.refunds.ProcessRefund(ctx, caller, refund.ID); err != nil {
+			c.logger.Errorf("pay out refund %d of cancelled order %d: %v", refund.ID, order.ID, err)
+		}
+		return
+	}
+
+	if order.PaymentStatus == constants.PaymentStatusVoided {
+		if err := cancelIntent(ctx, c.provider, order); err != nil {
+			c.logger.Errorf("cancel payment intent of order %d: %v", order.ID, err)
+		}
+	}
+}
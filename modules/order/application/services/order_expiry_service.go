@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mallbots/modules/order/domain/constants"
+	orderEntities "mallbots/modules/order/domain/entities"
+	orderInterfaces "mallbots/modules/order/domain/interfaces"
+	"mallbots/plugins/eventbus"
+	"mallbots/plugins/outbox"
+	"mallbots/plugins/payment"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/config"
+	"time"
+)
+
+const defaultAutoCancelBatchSize = 100
+
+type orderExpiryService struct {
+	orderRepo orderInterfaces.OrderRepository
+	txManager pgxc.TxManager
+	canceller *orderCanceller
+	cfg       config.AutoCancelConfig
+}
+
+func NewOrderExpiryService(
+	orderRepo orderInterfaces.OrderRepository,
+	refunds orderInterfaces.RefundService,
+	txManager pgxc.TxManager,
+	eventBus eventbus.Bus,
+	eventRepo orderInterfaces.OrderEventRepository,
+	outbox outbox.Writer,
+	provider payment.PaymentProvider,
+	cfg *config.Config,
+) orderInterfaces.OrderExpiryService {
+	return &orderExpiryService{
+		orderRepo: orderRepo,
+		txManager: txManager,
+		canceller: newOrderCanceller(orderRepo, refunds, provider, eventBus, eventRepo, outbox),
+		cfg:       cfg.AutoCancel,
+	}
+}
+
+// CancelExpiredOrders cancels every order in its own transaction, so one
+// failing order neither blocks nor rolls back the others. The orders that
+// failed stay at the head of the listing until they cancel, so the listing
+// is repeated past them rather than letting them starve the ones behind.
+func (s *orderExpiryService) CancelExpiredOrders(ctx context.Context, now time.Time) (int, error) {
+	batchSize := s.cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultAutoCancelBatchSize
+	}
+
+	cancelled := 0
+	var failed []int32
+	var errs []error
+	for {
+		orderIDs, err := s.orderRepo.GetExpiredUnpaidIDs(ctx, now, s.cfg.TTL, s.cfg.ProviderTTLs, failed, batchSize)
+		if err != nil {
+			return cancelled, errors.Join(append(errs, err)...)
+		}
+
+		failedBefore := len(failed)
+		for _, orderID := range orderIDs {
+			ok, err := s.cancelExpiredOrder(ctx, orderID, now)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("cancel order %d: %w", orderID, err))
+				failed = append(failed, orderID)
+				continue
+			}
+			if ok {
+				cancelled++
+			}
+		}
+
+		if len(failed) == failedBefore || len(orderIDs) < int(batchSize) {
+			return cancelled, errors.Join(errs...)
+		}
+	}
+}
+
+// cancelExpiredOrder cancels the order like a customer would, unless it got
+// paid or moved on since it was listed. Its payment intent is cancelled with
+// the provider once the cancellation committed; a late payment landing on it
+// anyway is refunded when its webhook arrives.
+func (s *orderExpiryService) cancelExpiredOrder(ctx context.Context, orderID int32, now time.Time) (bool, error) {
+	var cancelled *orderEntities.Order
+	var refund *orderEntities.Refund
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		ttl := s.cfg.TTLFor(order.PaymentProvider)
+		if order.Status != constants.OrderStatusPending ||
+			order.PaymentStatus != constants.PaymentStatusPending ||
+			ttl <= 0 || !order.CreatedAt.Add(ttl).Before(now) {
+			return nil
+		}
+
+		cancelled = order
+		refund, err = s.canceller.cancel(ctx, orderEntities.SystemCaller, order, fmt.Sprintf("payment not received within %s", ttl), now)
+		return err
+	})
+	if err != nil || cancelled == nil {
+		return false, err
+	}
+
+	s.canceller.settle(ctx, orderEntities.SystemCaller, cancelled, refund)
+	return true, nil
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/modules/order/domain/events"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/plugins/eventbus"
+	"mallbots/shared/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type expiryTestSuite struct {
+	orderRepo     *MockOrderRepository
+	eventRepo     *MockOrderEventRepository
+	outbox        *MockOutboxWriter
+	provider      *MockPaymentProvider
+	eventBus      eventbus.Bus
+	expiryService interfaces.OrderExpiryService
+	ctx           context.Context
+}
+
+var autoCancelConfig = config.AutoCancelConfig{
+	TTL:          24 * time.Hour,
+	ProviderTTLs: map[string]time.Duration{"fake": 30 * time.Minute},
+}
+
+func setupExpiryTest(cfg config.AutoCancelConfig) *expiryTestSuite {
+	orderRepo := new(MockOrderRepository)
+	txManager := new(MockTxManager)
+	txManager.On("WithTx", mock.Anything).Return()
+	eventBus := eventbus.New("eventbus")
+	eventRepo := new(MockOrderEventRepository)
+	eventRepo.On("Append", mock.Anything, mock.Anything).Return(nil)
+	outboxWriter := newMockOutboxWriter()
+	provider := newMockPaymentProvider()
+
+	return &expiryTestSuite{
+		orderRepo:     orderRepo,
+		eventRepo:     eventRepo,
+		outbox:        outboxWriter,
+		provider:      provider,
+		eventBus:      eventBus,
+		expiryService: NewOrderExpiryService(orderRepo, new(MockRefundService), txManager, eventBus, eventRepo, outboxWriter, provider, &config.Config{AutoCancel: cfg}),
+		ctx:           context.Background(),
+	}
+}
+
+func TestOrderExpiryService(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	provider := "fake"
+	intentID := "pi_fake_2"
+
+	t.Run("Cancels Unpaid Orders And Releases Stock", func(t *testing.T) {
+		ts := setupExpiryTest(autoCancelConfig)
+
+		var published []*events.OrderCancelled
+		ts.eventBus.Subscribe(events.OrderCancelledEvent, func(ctx context.Context, event eventbus.Event) error {
+			published = append(published, event.(*events.OrderCancelled))
+			return nil
+		})
+
+		ts.orderRepo.On("GetExpiredUnpaidIDs", ts.ctx, now, 24*time.Hour, autoCancelConfig.ProviderTTLs, []int32(nil), int32(100)).Return([]int32{1, 2}, nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(&entities.Order{
+			ID: 1, UserID: 7, Status: constants.OrderStatusPending, PaymentStatus: constants.PaymentStatusPending,
+			CreatedAt: now.Add(-25 * time.Hour),
+		}, nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(2)).Return(&entities.Order{
+			ID: 2, UserID: 8, Status: constants.OrderStatusPending, PaymentStatus: constants.PaymentStatusPending,
+			PaymentProvider: &provider, PaymentIntentID: &intentID, CreatedAt: now.Add(-time.Hour),
+		}, nil)
+		ts.provider.On("CancelIntent", ts.ctx, intentID).Return(nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(1)).Return([]*entities.OrderItem{{ProductID: 10, Quantity: 2}}, nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(2)).Return([]*entities.OrderItem{{ProductID: 11, Quantity: 1}}, nil)
+		ts.orderRepo.On("Cancel", ts.ctx, mock.MatchedBy(func(order *entities.Order) bool {
+			return order.Status == constants.OrderStatusCancelled &&
+				order.PaymentStatus == constants.PaymentStatusVoided &&
+				order.CancelledAt != nil && order.CancelledAt.Equal(now)
+		})).Return(nil)
+
+		cancelled, err := ts.expiryService.CancelExpiredOrders(ts.ctx, now)
+		require.NoError(t, err)
+		require.Equal(t, 2, cancelled)
+
+		require.Len(t, published, 2)
+		require.Equal(t, "payment not received within 24h0m0s", published[0].Reason)
+		require.Equal(t, []events.OrderItemQuantity{{ProductID: 10, Quantity: 2}}, published[0].Items)
+		require.Equal(t, "payment not received within 30m0s", published[1].Reason)
+
+		recorded := ts.eventRepo.recordedEvents()
+		require.Len(t, recorded, 2)
+		require.Equal(t, constants.OrderEventCancelled, recorded[0].Type)
+		require.Equal(t, entities.SystemCaller.Role, recorded[0].ActorRole)
+		require.Equal(t, "payment not received within 24h0m0s", recorded[0].Metadata["reason"])
+
+		relayed := ts.outbox.recordedEvents()
+		require.Len(t, relayed, 2)
+		require.Equal(t, constants.OrderStatusCancelled.String(), relayed[0].(*events.OrderStatusChanged).To)
+
+		ts.provider.AssertExpectations(t)
+	})
+
+	t.Run("Cancels The Order When The Intent Cannot Be Cancelled", func(t *testing.T) {
+		ts := setupExpiryTest(autoCancelConfig)
+
+		ts.orderRepo.On("GetExpiredUnpaidIDs", ts.ctx, now, mock.Anything, mock.Anything, []int32(nil), mock.Anything).Return([]int32{2}, nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(2)).Return(&entities.Order{
+			ID: 2, Status: constants.OrderStatusPending, PaymentStatus: constants.PaymentStatusPending,
+			PaymentProvider: &provider, PaymentIntentID: &intentID, CreatedAt: now.Add(-time.Hour),
+		}, nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(2)).Return([]*entities.OrderItem{}, nil)
+		ts.orderRepo.On("Cancel", ts.ctx, mock.Anything).Return(nil)
+		ts.provider.On("CancelIntent", ts.ctx, intentID).Return(errors.New("provider unavailable"))
+
+		// The intent is cancelled after commit, a payment landing on it
+		// anyway is refunded by its webhook
+		cancelled, err := ts.expiryService.CancelExpiredOrders(ts.ctx, now)
+		require.NoError(t, err)
+		require.Equal(t, 1, cancelled)
+		ts.provider.AssertExpectations(t)
+		require.Len(t, ts.eventRepo.recordedEvents(), 1)
+	})
+
+	t.Run("Skips Orders Paid Meanwhile", func(t *testing.T) {
+		ts := setupExpiryTest(autoCancelConfig)
+
+		ts.orderRepo.On("GetExpiredUnpaidIDs", ts.ctx, now, mock.Anything, mock.Anything, []int32(nil), mock.Anything).Return([]int32{1}, nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(&entities.Order{
+			ID: 1, Status: constants.OrderStatusConfirmed, PaymentStatus: constants.PaymentStatusPaid,
+			CreatedAt: now.Add(-25 * time.Hour),
+		}, nil)
+
+		cancelled, err := ts.expiryService.CancelExpiredOrders(ts.ctx, now)
+		require.NoError(t, err)
+		require.Zero(t, cancelled)
+		ts.orderRepo.AssertNotCalled(t, "Cancel", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Skips Orders Still Within Their TTL", func(t *testing.T) {
+		ts := setupExpiryTest(autoCancelConfig)
+
+		// A provider TTL was raised between the listing and the lock
+		ts.orderRepo.On("GetExpiredUnpaidIDs", ts.ctx, now, mock.Anything, mock.Anything, []int32(nil), mock.Anything).Return([]int32{1}, nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(&entities.Order{
+			ID: 1, Status: constants.OrderStatusPending, PaymentStatus: constants.PaymentStatusPending,
+			CreatedAt: now.Add(-time.Hour),
+		}, nil)
+
+		cancelled, err := ts.expiryService.CancelExpiredOrders(ts.ctx, now)
+		require.NoError(t, err)
+		require.Zero(t, cancelled)
+		ts.orderRepo.AssertNotCalled(t, "Cancel", mock.Anything, mock.Anything)
+	})
+
+	t.Run("One Failing Order Does Not Stop The Batch", func(t *testing.T) {
+		ts := setupExpiryTest(autoCancelConfig)
+
+		ts.orderRepo.On("GetExpiredUnpaidIDs", ts.ctx, now, mock.Anything, mock.Anything, []int32(nil), mock.Anything).Return([]int32{1, 2}, nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(nil, errors.New("connection reset"))
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(2)).Return(&entities.Order{
+			ID: 2, Status: constants.OrderStatusPending, PaymentStatus: constants.PaymentStatusPending,
+			CreatedAt: now.Add(-25 * time.Hour),
+		}, nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(2)).Return([]*entities.OrderItem{}, nil)
+		ts.orderRepo.On("Cancel", ts.ctx, mock.Anything).Return(nil)
+
+		cancelled, err := ts.expiryService.CancelExpiredOrders(ts.ctx, now)
+		require.ErrorContains(t, err, "cancel order 1: connection reset")
+		require.Equal(t, 1, cancelled)
+	})
+
+	t.Run("Pages Past Orders That Keep Failing", func(t *testing.T) {
+		ts := setupExpiryTest(config.AutoCancelConfig{TTL: 24 * time.Hour, BatchSize: 2})
+
+		expired := func(id int32) *entities.Order {
+			return &entities.Order{
+				ID: id, Status: constants.OrderStatusPending, PaymentStatus: constants.PaymentStatusPending,
+				CreatedAt: now.Add(-25 * time.Hour),
+			}
+		}
+
+		ts.orderRepo.On("GetExpiredUnpaidIDs", ts.ctx, now, mock.Anything, mock.Anything, []int32(nil), int32(2)).Return([]int32{1, 2}, nil).Once()
+		ts.orderRepo.On("GetExpiredUnpaidIDs", ts.ctx, now, mock.Anything, mock.Anything, []int32{1, 2}, int32(2)).Return([]int32{3}, nil).Once()
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(nil, errors.New("connection reset"))
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(2)).Return(nil, errors.New("connection reset"))
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(3)).Return(expired(3), nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(3)).Return([]*entities.OrderItem{}, nil)
+		ts.orderRepo.On("Cancel", ts.ctx, mock.Anything).Return(nil)
+
+		cancelled, err := ts.expiryService.CancelExpiredOrders(ts.ctx, now)
+		require.ErrorContains(t, err, "cancel order 1")
+		require.ErrorContains(t, err, "cancel order 2")
+		require.Equal(t, 1, cancelled)
+		ts.orderRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"mallbots/modules/order/application/dto"
+	"mallbots/modules/order/domain/constants"
+	orderEntities "mallbots/modules/order/domain/entities"
+	orderInterfaces "mallbots/modules/order/domain/interfaces"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"strings"
+	"time"
+
+	"github.com/phathdt/service-context/core"
+)
+
+const dateLayout = "2006-01-02"
+
+// newOrderFilter validates the history filters of a request, together with the
+// cursor of the page it asks for
+func (s *orderService) newOrderFilter(req *dto.OrderListRequest, paging *core.Paging) (*orderInterfaces.OrderFilter, error) {
+	filter := orderInterfaces.OrderFilter{
+		ProductID: req.ProductID,
+		SortBy:    constants.OrderSortCreatedAtDesc,
+	}
+
+	if req.Status != "" {
+		status := constants.OrderStatus(req.Status)
+		if !status.IsValid() {
+			return nil, invalidFilter("unknown order status %s", req.Status)
+		}
+		filter.Status = &status
+	}
+
+	if req.PaymentStatus != "" {
+		status := constants.PaymentStatus(req.PaymentStatus)
+		if !status.IsValid() {
+			return nil, invalidFilter("unknown payment status %s", req.PaymentStatus)
+		}
+		filter.PaymentStatus = &status
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseDateFilter("from", req.From, false); err != nil {
+		return nil, err
+	}
+	if filter.CreatedTo, err = parseDateFilter("to", req.To, true); err != nil {
+		return nil, err
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return nil, invalidFilter("from must be before to")
+	}
+
+	currency, err := s.currencies.ParseCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if filter.MinTotal, err = parseTotalFilter("min_total", req.MinTotal, currency); err != nil {
+		return nil, err
+	}
+	if filter.MaxTotal, err = parseTotalFilter("max_total", req.MaxTotal, currency); err != nil {
+		return nil, err
+	}
+	if filter.MinTotal != nil && filter.MaxTotal != nil && filter.MinTotal.Cmp(*filter.MaxTotal) > 0 {
+		return nil, invalidFilter("min_total must not exceed max_total")
+	}
+	if req.Currency != "" || filter.MinTotal != nil || filter.MaxTotal != nil {
+		filter.Currency = &currency
+	}
+
+	if req.SortBy != "" {
+		filter.SortBy = constants.OrderSort(req.SortBy)
+		if !filter.SortBy.IsValid() {
+			return nil, invalidFilter("unknown sort %s", req.SortBy)
+		}
+	}
+	// Totals in different currencies do not compare, a total sort lists the
+	// orders of the one currency it is asked for
+	if filter.SortBy.ByTotal() && req.Currency == "" {
+		return nil, invalidFilter("sort %s needs a currency", filter.SortBy)
+	}
+
+	// Items come with the orders unless excluded, even when also included
+	if _, err := parseRelations("include", req.Include); err != nil {
+		return nil, err
+	}
+	if filter.SkipItems, err = parseRelations("exclude", req.Exclude); err != nil {
+		return nil, err
+	}
+
+	if paging.Cursor != "" {
+		cursor, err := orderEntities.ParseOrderCursor(paging.Cursor)
+		if err != nil {
+			return nil, core.ErrBadRequest.
+				WithError(errorx.ErrInvalidCursor.Error()).
+				WithReason(err.Error())
+		}
+		// A cursor only makes sense in the order it was handed out for
+		if cursor.SortBy != filter.SortBy {
+			return nil, core.ErrBadRequest.
+				WithError(errorx.ErrInvalidCursor.Error()).
+				WithReasonf("cursor was issued for sort %s", cursor.SortBy)
+		}
+		filter.After = cursor
+	}
+
+	return &filter, nil
+}
+
+// parseRelations reads a comma separated list of the relations loaded along
+// with the orders and tells whether it names the items, the only one so far
+func parseRelations(name, value string) (bool, error) {
+	items := false
+	for _, relation := range strings.Split(value, ",") {
+		switch strings.TrimSpace(relation) {
+		case "":
+		case "items":
+			items = true
+		default:
+			return false, invalidFilter("cannot %s %s", name, relation)
+		}
+	}
+
+	return items, nil
+}
+
+func invalidFilter(format string, args ...interface{}) error {
+	return core.ErrBadRequest.
+		WithError(errorx.ErrInvalidOrderFilter.Error()).
+		WithReasonf(format, args...)
+}
+
+// parseDateFilter reads an RFC 3339 timestamp or a plain date. A plain date
+// that ends a range stands for the start of the following day.
+func parseDateFilter(name, value string, end bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		at = at.UTC()
+		return &at, nil
+	}
+
+	day, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return nil, invalidFilter("%s %q is neither a date nor a timestamp", name, value)
+	}
+	if end {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return &day, nil
+}
+
+func parseTotalFilter(name string, value *string, currency money.Currency) (*money.Money, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+
+	total, err := money.Parse(*value, currency)
+	if err != nil {
+		return nil, core.ErrBadRequest.
+			WithError(errorx.ErrInvalidAmount.Error()).
+			WithReasonf("%s %q is not a valid amount", name, *value)
+	}
+
+	return &total, nil
+}
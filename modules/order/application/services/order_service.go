@@ -2,107 +2,637 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	cartDto "mallbots/modules/cart/application/dto"
 	"mallbots/modules/cart/domain/interfaces"
+	currencyEntities "mallbots/modules/currency/domain/entities"
+	currencyInterfaces "mallbots/modules/currency/domain/interfaces"
 	"mallbots/modules/order/application/dto"
 	"mallbots/modules/order/domain/constants"
 	orderEntities "mallbots/modules/order/domain/entities"
+	"mallbots/modules/order/domain/events"
 	orderInterfaces "mallbots/modules/order/domain/interfaces"
+	productDto "mallbots/modules/product/application/dto"
+	productInterfaces "mallbots/modules/product/domain/interfaces"
+	promotionDto "mallbots/modules/promotion/application/dto"
+	promotionInterfaces "mallbots/modules/promotion/domain/interfaces"
+	ruleConstants "mallbots/modules/rules/domain/constants"
+	ruleEntities "mallbots/modules/rules/domain/entities"
+	ruleInterfaces "mallbots/modules/rules/domain/interfaces"
+	taxEntities "mallbots/modules/tax/domain/entities"
+	taxInterfaces "mallbots/modules/tax/domain/interfaces"
+	userDto "mallbots/modules/user/application/dto"
+	userInterfaces "mallbots/modules/user/domain/interfaces"
+	"mallbots/plugins/eventbus"
+	"mallbots/plugins/outbox"
+	"mallbots/plugins/payment"
+	"mallbots/plugins/pgxc"
 	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"net/http"
+	"sort"
 	"time"
 
-	"fmt"
-
 	"github.com/phathdt/service-context/core"
 )
 
+// adminCancelReason is recorded when an administrator cancels an order
+// through a status change without giving a reason
+const adminCancelReason = "cancelled by an administrator"
+
 type orderService struct {
-	orderRepo   orderInterfaces.OrderRepository
-	cartService interfaces.CartService
+	orderRepo      orderInterfaces.OrderRepository
+	cartService    interfaces.CartService
+	addresses      userInterfaces.AddressService
+	productService productInterfaces.ProductService
+	inventory      productInterfaces.InventoryService
+	shipping       orderInterfaces.ShippingCalculator
+	tax            taxInterfaces.TaxCalculator
+	promotions     promotionInterfaces.PromotionService
+	rules          ruleInterfaces.RuleEngine
+	currencies     currencyInterfaces.CurrencyService
+	txManager      pgxc.TxManager
+	policy         orderInterfaces.OrderAccessPolicy
+	eventRepo      orderInterfaces.OrderEventRepository
+	outbox         outbox.Writer
+	canceller      *orderCanceller
 }
 
 func NewOrderService(
 	orderRepo orderInterfaces.OrderRepository,
 	cartService interfaces.CartService,
+	addresses userInterfaces.AddressService,
+	productService productInterfaces.ProductService,
+	inventory productInterfaces.InventoryService,
+	shipping orderInterfaces.ShippingCalculator,
+	tax taxInterfaces.TaxCalculator,
+	promotions promotionInterfaces.PromotionService,
+	rules ruleInterfaces.RuleEngine,
+	currencies currencyInterfaces.CurrencyService,
+	refunds orderInterfaces.RefundService,
+	provider payment.PaymentProvider,
+	txManager pgxc.TxManager,
+	eventBus eventbus.Bus,
+	policy orderInterfaces.OrderAccessPolicy,
+	eventRepo orderInterfaces.OrderEventRepository,
+	outbox outbox.Writer,
 ) orderInterfaces.OrderService {
 	return &orderService{
-		orderRepo:   orderRepo,
-		cartService: cartService,
+		orderRepo:      orderRepo,
+		cartService:    cartService,
+		addresses:      addresses,
+		productService: productService,
+		inventory:      inventory,
+		shipping:       shipping,
+		tax:            tax,
+		promotions:     promotions,
+		rules:          rules,
+		currencies:     currencies,
+		txManager:      txManager,
+		policy:         policy,
+		eventRepo:      eventRepo,
+		outbox:         outbox,
+		canceller:      newOrderCanceller(orderRepo, refunds, provider, eventBus, eventRepo, outbox),
 	}
 }
 
-func (s *orderService) CreateOrder(ctx context.Context, userID int32, req *dto.CreateOrderRequest) (*dto.OrderResponse, error) {
-	// Get cart items
-	cartItems, err := s.cartService.GetItems(ctx, userID)
+func (s *orderService) CreateOrder(ctx context.Context, caller orderEntities.Caller, req *dto.CreateOrderRequest) (*dto.OrderResponse, error) {
+	userID := caller.UserID
+
+	shipTo, billTo, err := s.resolveAddresses(ctx, userID, req)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(cartItems) == 0 {
-		return nil, errorx.ErrCartEmpty
+	cart, err := s.loadCheckoutCart(ctx, userID, req.Currency)
+	if err != nil {
+		return nil, err
 	}
+	cartItems, priceChanges := cart.items, cart.priceChanges
 
-	// Calculate total amount
-	var totalAmount float64
-	for _, item := range cartItems {
-		totalAmount += item.Price * float64(item.Quantity)
+	if len(priceChanges) > 0 && !req.AcceptPriceChanges {
+		return nil, core.ErrConflict.
+			WithError(errorx.ErrProductPriceChanged.Error()).
+			WithReasonf("%d item(s) changed price since they were added to the cart", len(priceChanges)).
+			WithDetail("items", priceChanges)
+	}
+
+	quote, err := s.shipping.Quote(ctx, cart.parcel(shipTo.country, shipTo.zip))
+	if err != nil {
+		return nil, err
 	}
+	quote = cart.localQuote(quote)
 
-	// Create order
+	cart.discount, err = s.promotions.PriceCartCoupon(ctx, userID, cart.discountRequest(quote.Amount))
+	if err != nil {
+		return nil, err
+	}
+
+	check := s.ruleCheck(userID, shipTo.country, cart)
+	if err := s.rules.Check(ctx, check); err != nil {
+		return nil, err
+	}
+
+	tax, err := s.tax.Calculate(ctx, cart.taxRequest(shipTo.country, shipTo.zip))
+	if err != nil {
+		return nil, err
+	}
+
+	// Create order, locking the rate its currency was converted at
+	exchangeRateID, exchangeRate := cart.lockedRate(cart.currency)
 	order := &orderEntities.Order{
 		UserID:          userID,
 		Status:          constants.OrderStatusPending,
 		PaymentStatus:   constants.PaymentStatusPending,
-		TotalAmount:     totalAmount,
-		ShippingAddress: req.ShippingAddress,
-		ShippingCity:    req.ShippingCity,
-		ShippingCountry: req.ShippingCountry,
-		ShippingZip:     req.ShippingZip,
+		Currency:        cart.currency,
+		ExchangeRateID:  exchangeRateID,
+		ExchangeRate:    exchangeRate,
+		TotalAmount:     cart.total(quote, tax),
+		ShippingAmount:  quote.Amount,
+		TaxAmount:       tax.Amount,
+		TaxInclusive:    tax.Inclusive,
+		DiscountAmount:  cart.discountAmount(),
+		CouponCode:      cart.couponCode(),
+		ShippingAddress: shipTo.address,
+		ShippingCity:    shipTo.city,
+		ShippingCountry: shipTo.country,
+		ShippingZip:     shipTo.zip,
+		BillingAddress:  billTo.address,
+		BillingCity:     billTo.city,
+		BillingCountry:  billTo.country,
+		BillingZip:      billTo.zip,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
 
-	newOrder, err := s.orderRepo.Create(ctx, order)
+	var newOrder *orderEntities.Order
+	var orderItems []*orderEntities.OrderItem
+
+	// Order, order items, stock reservation, coupon redemption and cart
+	// cleanup are committed or rolled back together
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		// Concurrent checkouts of the user could all pass the daily order
+		// limit above, it is checked again once they take turns
+		if err := s.orderRepo.LockUserOrders(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.rules.Check(ctx, check); err != nil {
+			return err
+		}
+
+		newOrder, err = s.orderRepo.Create(ctx, order)
+		if err != nil {
+			return err
+		}
+
+		// Create order items, tax and discount lines follow the order of the
+		// cart items
+		for i, item := range cartItems {
+			exchangeRateID, exchangeRate := cart.lockedRate(cart.productPrices[i].Currency())
+			orderItems = append(orderItems, &orderEntities.OrderItem{
+				OrderID:        newOrder.ID,
+				ProductID:      item.ProductID,
+				Quantity:       item.Quantity,
+				Price:          item.Price,
+				DiscountAmount: cart.lineDiscount(i),
+				TaxRate:        tax.Lines[i].Rate,
+				TaxAmount:      tax.Lines[i].Amount,
+				ProductPrice:   cart.productPrices[i],
+				ExchangeRateID: exchangeRateID,
+				ExchangeRate:   exchangeRate,
+				// Snapshot the product so the order outlives catalog changes
+				ProductName:        cart.products[i].Name,
+				ProductDescription: cart.products[i].Description,
+				CategoryID:         &cart.products[i].CategoryID,
+				CategoryName:       &cart.products[i].CategoryName,
+				CreatedAt:          time.Now(),
+				UpdatedAt:          time.Now(),
+			})
+		}
+
+		if err := s.orderRepo.CreateOrderItems(ctx, newOrder.ID, orderItems); err != nil {
+			return err
+		}
+
+		if err := s.inventory.Reserve(ctx, stockLines(orderItems)); err != nil {
+			return err
+		}
+
+		if cart.discount != nil {
+			if err := s.promotions.Redeem(ctx, userID, newOrder.ID, cart.discount); err != nil {
+				return err
+			}
+		}
+
+		event := orderEntities.NewOrderEvent(newOrder.ID, constants.OrderEventCreated, caller).
+			WithTransition("", newOrder.Status.String()).
+			With("currency", newOrder.Currency).
+			With("exchange_rate", newOrder.ExchangeRate).
+			With("total_amount", newOrder.TotalAmount).
+			With("shipping_amount", order.ShippingAmount).
+			With("shipping_zone", quote.Zone).
+			With("tax_amount", order.TaxAmount).
+			With("item_count", len(orderItems))
+		if len(priceChanges) > 0 {
+			event.With("price_changes", priceChanges)
+		}
+		if cart.discount != nil {
+			event.With("coupon_code", cart.discount.Code).
+				With("discount_amount", cart.discount.Amount)
+		}
+		if err := s.eventRepo.Append(ctx, event); err != nil {
+			return err
+		}
+
+		if err := s.outbox.Add(ctx, newOrderCreatedEvent(newOrder, orderItems)); err != nil {
+			return err
+		}
+
+		// Clear cart after successful order creation
+		return s.cartService.RemoveAllItems(ctx, userID)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Create order items
-	var orderItems []*orderEntities.OrderItem
-	for _, item := range cartItems {
-		orderItems = append(orderItems, &orderEntities.OrderItem{
-			OrderID:   newOrder.ID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+	newOrder.Items = orderItems
+	return s.convertToResponse(newOrder), nil
+}
+
+// postalAddress is an address as the order keeps it, copied from the request
+// or the address book so later edits of the book leave the order alone
+type postalAddress struct {
+	address string
+	city    string
+	country string
+	zip     string
+}
+
+func (a postalAddress) isEmpty() bool {
+	return a.address == "" && a.city == "" && a.country == "" && a.zip == ""
+}
+
+func (a postalAddress) isComplete() bool {
+	return a.address != "" && a.city != "" && a.country != "" && a.zip != ""
+}
+
+func fromAddressBook(address *userDto.AddressResponse) postalAddress {
+	return postalAddress{address: address.Address, city: address.City, country: address.Country, zip: address.Zip}
+}
+
+// resolveAddresses picks where the order ships to and who it bills. Either
+// comes from an address book entry or the inline fields, which cannot be
+// mixed, or else from the caller's default. Billing falls back to the
+// shipping address last.
+func (s *orderService) resolveAddresses(ctx context.Context, userID int32, req *dto.CreateOrderRequest) (shipTo, billTo postalAddress, err error) {
+	shipTo, err = s.resolveAddress(ctx, userID, req.AddressID, postalAddress{
+		address: req.ShippingAddress,
+		city:    req.ShippingCity,
+		country: req.ShippingCountry,
+		zip:     req.ShippingZip,
+	}, s.addresses.GetDefaultShippingAddress, errorx.ErrInvalidShippingAddress)
+	if err != nil {
+		return shipTo, billTo, err
+	}
+	if shipTo.isEmpty() {
+		return shipTo, billTo, core.ErrBadRequest.
+			WithError(errorx.ErrInvalidShippingAddress.Error()).
+			WithReason("give the shipping fields or an address_id, or set a default shipping address")
+	}
+
+	billTo, err = s.resolveAddress(ctx, userID, req.BillingAddressID, postalAddress{
+		address: req.BillingAddress,
+		city:    req.BillingCity,
+		country: req.BillingCountry,
+		zip:     req.BillingZip,
+	}, s.addresses.GetDefaultBillingAddress, errorx.ErrInvalidBillingAddress)
+	if err != nil {
+		return shipTo, billTo, err
+	}
+	if billTo.isEmpty() {
+		billTo = shipTo
+	}
+
+	return shipTo, billTo, nil
+}
+
+// resolveAddress returns an empty address when neither the request nor the
+// caller's defaults name one
+func (s *orderService) resolveAddress(
+	ctx context.Context,
+	userID int32,
+	addressID *int32,
+	inline postalAddress,
+	getDefault func(ctx context.Context, userID int32) (*userDto.AddressResponse, error),
+	invalid error,
+) (postalAddress, error) {
+	switch {
+	case addressID != nil && !inline.isEmpty():
+		return postalAddress{}, core.ErrBadRequest.
+			WithError(invalid.Error()).
+			WithReason("an address from the address book cannot be combined with inline address fields")
+	case addressID != nil:
+		address, err := s.addresses.GetAddress(ctx, userID, *addressID)
+		if err != nil {
+			return postalAddress{}, err
+		}
+		return fromAddressBook(address), nil
+	case !inline.isEmpty():
+		if !inline.isComplete() {
+			return postalAddress{}, core.ErrBadRequest.
+				WithError(invalid.Error()).
+				WithReason("address, city, country and zip are all required")
+		}
+		return inline, nil
+	}
+
+	address, err := getDefault(ctx, userID)
+	if err != nil || address == nil {
+		return postalAddress{}, err
+	}
+	return fromAddressBook(address), nil
+}
+
+// checkoutCart is the caller's cart priced the way checkout will charge it,
+// in the currency the order is placed in
+type checkoutCart struct {
+	items        []*cartDto.CartItemResponse
+	priceChanges []dto.PriceChangeResponse
+	currency     money.Currency
+	subtotal     money.Money
+	// products are the catalog entries of the items as they are now, in the
+	// order of the items
+	products []*productDto.ProductResponse
+	// productPrices are the current prices of the items in the products' own
+	// currencies, the item prices are converted from them
+	productPrices []money.Money
+	// exchange holds the rates of the order currency and of every product
+	// currency, read once for the whole checkout
+	exchange *currencyEntities.Exchange
+	// weight is the total weight in grams
+	weight     int64
+	categories map[int32]int32
+	// discount is the applied coupon priced against the cart, nil without one
+	discount *promotionDto.DiscountResult
+}
+
+func (c *checkoutCart) parcel(country, zip string) orderEntities.Parcel {
+	return orderEntities.Parcel{
+		Country:  country,
+		Zip:      zip,
+		Subtotal: c.toBase(c.subtotal),
+		Weight:   c.weight,
+	}
+}
+
+// localQuote converts a quote of the shipping tables, which are written in the
+// base currency, into the cart currency
+func (c *checkoutCart) localQuote(quote *orderEntities.ShippingQuote) *orderEntities.ShippingQuote {
+	local := *quote
+	local.Amount = c.fromBase(quote.Amount)
+	local.FreeAbove = c.fromBase(quote.FreeAbove)
+	return &local
+}
+
+func (c *checkoutCart) toBase(amount money.Money) money.Money {
+	if amount.IsZero() {
+		return money.Zero(money.DefaultCurrency)
+	}
+	return c.exchange.Convert(amount, money.DefaultCurrency)
+}
+
+func (c *checkoutCart) fromBase(amount money.Money) money.Money {
+	if amount.IsZero() {
+		return money.Zero(c.currency)
+	}
+	return c.exchange.Convert(amount, c.currency)
+}
+
+// lockedRate is the rate of the currency the order records, none for the
+// base currency
+func (c *checkoutCart) lockedRate(currency money.Currency) (*int32, money.Rate) {
+	rate := c.exchange.RateOf(currency)
+	if rate == nil {
+		return nil, money.Rate{}
+	}
+	id := rate.ID
+	return &id, rate.Rate
+}
+
+func (c *checkoutCart) taxRequest(country, zip string) taxEntities.TaxRequest {
+	req := taxEntities.TaxRequest{Country: country, Zip: zip, Currency: c.currency}
+	for i, item := range c.items {
+		req.Lines = append(req.Lines, taxEntities.TaxableLine{
+			ProductID:  item.ProductID,
+			CategoryID: c.categories[item.ProductID],
+			Amount:     item.Price.Mul(int64(item.Quantity)).Sub(c.lineDiscount(i)),
 		})
 	}
+	return req
+}
+
+func (c *checkoutCart) discountRequest(shippingAmount money.Money) promotionDto.DiscountRequest {
+	req := promotionDto.DiscountRequest{Currency: c.currency, ShippingAmount: shippingAmount}
+	for _, item := range c.items {
+		req.Lines = append(req.Lines, promotionDto.DiscountLine{
+			ProductID:  item.ProductID,
+			CategoryID: c.categories[item.ProductID],
+			Price:      item.Price,
+			Quantity:   item.Quantity,
+		})
+	}
+	return req
+}
+
+func (c *checkoutCart) lineDiscount(i int) money.Money {
+	if c.discount == nil {
+		return money.Zero(c.currency)
+	}
+	return c.discount.LineDiscounts[i]
+}
+
+func (c *checkoutCart) discountAmount() money.Money {
+	if c.discount == nil {
+		return money.Zero(c.currency)
+	}
+	return c.discount.Amount
+}
+
+func (c *checkoutCart) couponCode() *string {
+	if c.discount == nil {
+		return nil
+	}
+	return &c.discount.Code
+}
+
+// total is what the customer pays: tax is only added when prices do not
+// already include it
+func (c *checkoutCart) total(quote *orderEntities.ShippingQuote, tax *taxEntities.TaxResult) money.Money {
+	total := c.subtotal.Sub(c.discountAmount()).Add(quote.Amount)
+	if !tax.Inclusive {
+		total = total.Add(tax.Amount)
+	}
+	return total
+}
+
+// ruleCheck describes the order about to be placed to the order rules
+func (s *orderService) ruleCheck(userID int32, country string, cart *checkoutCart) ruleEntities.RuleCheck {
+	check := ruleEntities.RuleCheck{
+		Stage:    ruleConstants.StageCheckout,
+		UserID:   userID,
+		Country:  country,
+		Subtotal: cart.subtotal,
+		// The day starts at midnight UTC, the zone orders are stored in
+		OrdersToday: func(ctx context.Context) (int64, error) {
+			return s.orderRepo.CountUserOrdersSince(ctx, userID, time.Now().UTC().Truncate(24*time.Hour))
+		},
+	}
+	for i, item := range cart.items {
+		check.Lines = append(check.Lines, ruleEntities.RuleLine{ProductID: item.ProductID, Quantity: item.Quantity})
+		check.Subtotal = check.Subtotal.Sub(cart.lineDiscount(i))
+	}
+	// Order rules are written in the base currency
+	check.Subtotal = cart.toBase(check.Subtotal)
+	return check
+}
 
-	if err := s.orderRepo.CreateOrderItems(ctx, newOrder.ID, orderItems); err != nil {
+// loadCheckoutCart replaces the price captured when each item was added to
+// the cart with the current product price, reporting every item that changed,
+// converts the prices into the currency and totals the cart
+func (s *orderService) loadCheckoutCart(ctx context.Context, userID int32, code string) (*checkoutCart, error) {
+	currency, err := s.currencies.ParseCurrency(code)
+	if err != nil {
 		return nil, err
 	}
 
-	// Clear cart after successful order creation
-	if err := s.cartService.RemoveAllItems(ctx, userID); err != nil {
-		fmt.Printf("Failed to clear cart after order creation %+v\n", err)
+	cartItems, err := s.cartService.GetItems(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	newOrder.Items = orderItems
-	return s.convertToResponse(newOrder), nil
+	if len(cartItems) == 0 {
+		return nil, errorx.ErrCartEmpty
+	}
+
+	cart := &checkoutCart{
+		items:      cartItems,
+		currency:   currency,
+		subtotal:   money.Zero(currency),
+		categories: make(map[int32]int32, len(cartItems)),
+	}
+	currencies := []money.Currency{currency}
+	for _, item := range cartItems {
+		product, err := s.productService.GetProduct(ctx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Cart prices are in the product's own currency, a product whose
+		// currency changed has changed price
+		if product.Price.Currency() != item.Price.Currency() || product.Price.Cmp(item.Price) != 0 {
+			cart.priceChanges = append(cart.priceChanges, dto.PriceChangeResponse{
+				ProductID: item.ProductID,
+				OldPrice:  item.Price,
+				NewPrice:  product.Price,
+			})
+		}
+
+		cart.productPrices = append(cart.productPrices, product.Price)
+		cart.products = append(cart.products, product)
+		currencies = append(currencies, product.Price.Currency())
+		cart.weight += int64(product.Weight) * int64(item.Quantity)
+		cart.categories[item.ProductID] = product.CategoryID
+	}
+
+	cart.exchange, err = s.currencies.Exchange(ctx, currencies...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range cartItems {
+		item.BasePrice = cart.productPrices[i]
+		item.BaseCurrency = item.BasePrice.Currency().String()
+		item.Price = cart.exchange.Convert(item.BasePrice, currency)
+		item.Currency = currency.String()
+		cart.subtotal = cart.subtotal.Add(item.Price.Mul(int64(item.Quantity)))
+	}
+
+	return cart, nil
 }
 
-func (s *orderService) GetOrder(ctx context.Context, orderID int32) (*dto.OrderResponse, error) {
-	order, err := s.orderRepo.GetByID(ctx, orderID)
+func (s *orderService) QuoteShipping(ctx context.Context, caller orderEntities.Caller, req *dto.ShippingQuoteRequest) (*dto.ShippingQuoteResponse, error) {
+	cart, err := s.loadCheckoutCart(ctx, caller.UserID, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := s.shipping.Quote(ctx, cart.parcel(req.ShippingCountry, req.ShippingZip))
+	if err != nil {
+		return nil, err
+	}
+	quote = cart.localQuote(quote)
+
+	cart.discount, err = s.promotions.PriceCartCoupon(ctx, caller.UserID, cart.discountRequest(quote.Amount))
+	if err != nil {
+		return nil, err
+	}
+
+	tax, err := s.tax.Calculate(ctx, cart.taxRequest(req.ShippingCountry, req.ShippingZip))
 	if err != nil {
 		return nil, err
 	}
 
+	var freeAbove *money.Money
+	if quote.FreeAbove.IsPositive() {
+		freeAbove = &quote.FreeAbove
+	}
+
+	return &dto.ShippingQuoteResponse{
+		Zone:           quote.Zone,
+		Currency:       cart.currency.String(),
+		Subtotal:       cart.subtotal,
+		ShippingAmount: quote.Amount,
+		DiscountAmount: cart.discountAmount(),
+		TaxAmount:      tax.Amount,
+		TaxInclusive:   tax.Inclusive,
+		TotalAmount:    cart.total(quote, tax),
+		FreeShipping:   quote.FreeShipping,
+		FreeAbove:      freeAbove,
+		Weight:         cart.weight,
+	}, nil
+}
+
+func (s *orderService) GetOrder(ctx context.Context, caller orderEntities.Caller, orderID int32) (*dto.OrderResponse, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	if err := s.policy.Authorize(caller, constants.OrderActionView, order); err != nil {
+		return nil, wrapNotFound(err)
+	}
+
 	return s.convertToResponse(order), nil
 }
 
-func (s *orderService) GetUserOrders(ctx context.Context, userID int32, paging *core.Paging) ([]*dto.OrderResponse, error) {
-	orders, err := s.orderRepo.GetByUserID(ctx, userID, paging)
+// getOrder reloads an order after a change the caller was already allowed to make
+func (s *orderService) getOrder(ctx context.Context, orderID int32) (*dto.OrderResponse, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	return s.convertToResponse(order), nil
+}
+
+func (s *orderService) GetUserOrders(ctx context.Context, userID int32, req *dto.OrderListRequest, paging *core.Paging) ([]*dto.OrderResponse, error) {
+	filter, err := s.newOrderFilter(req, paging)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.orderRepo.GetByUserID(ctx, userID, filter, paging)
 	if err != nil {
 		return nil, err
 	}
@@ -115,28 +645,476 @@ func (s *orderService) GetUserOrders(ctx context.Context, userID int32, paging *
 	return responses, nil
 }
 
-func (s *orderService) convertToResponse(order *orderEntities.Order) *dto.OrderResponse {
-	var itemResponses []dto.OrderItemResponse
-	for _, item := range order.Items {
-		itemResponses = append(itemResponses, dto.OrderItemResponse{
-			ID:        item.ID,
+func (s *orderService) UpdateOrderStatus(ctx context.Context, caller orderEntities.Caller, orderID int32, req *dto.UpdateOrderStatusRequest) (*dto.OrderResponse, error) {
+	next := constants.OrderStatus(req.Status)
+	if !next.IsValid() {
+		return nil, core.ErrBadRequest.WithError(errorx.ErrInvalidOrderStatus.Error())
+	}
+
+	if next.IsDerivedFromShipments() {
+		return nil, core.ErrConflict.
+			WithError(errorx.ErrStatusFollowsShipments.Error()).
+			WithReasonf("create or update shipments to move the order to %s", next)
+	}
+
+	var cancelled *orderEntities.Order
+	var refund *orderEntities.Refund
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		if !order.Status.CanTransitionTo(next) {
+			return core.ErrConflict.
+				WithError(errorx.ErrInvalidStatusTransition.Error()).
+				WithReasonf("cannot move order from %s to %s", order.Status, next)
+		}
+
+		if next == constants.OrderStatusCancelled {
+			reason := req.Reason
+			if reason == "" {
+				reason = adminCancelReason
+			}
+			cancelled = order
+			refund, err = s.canceller.cancel(ctx, caller, order, reason, time.Now())
+			return err
+		}
+
+		if err := s.orderRepo.UpdateStatus(ctx, orderID, next); err != nil {
+			return err
+		}
+
+		event := orderEntities.NewOrderEvent(orderID, constants.OrderEventStatusChanged, caller).
+			WithTransition(order.Status.String(), next.String())
+		if err := s.eventRepo.Append(ctx, event); err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, newOrderStatusChangedEvent(order, order.Status, next, time.Now()))
+	})
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	if cancelled != nil {
+		s.canceller.settle(ctx, caller, cancelled, refund)
+	}
+
+	return s.getOrder(ctx, orderID)
+}
+
+func (s *orderService) UpdatePaymentStatus(ctx context.Context, caller orderEntities.Caller, orderID int32, req *dto.UpdatePaymentStatusRequest) (*dto.OrderResponse, error) {
+	next := constants.PaymentStatus(req.PaymentStatus)
+	if !next.IsValid() {
+		return nil, core.ErrBadRequest.WithError(errorx.ErrInvalidPaymentStatus.Error())
+	}
+
+	if next == constants.PaymentStatusRefunded {
+		return nil, core.ErrConflict.
+			WithError(errorx.ErrRefundNeedsRefundFlow.Error()).
+			WithReason("request a refund for the order instead")
+	}
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		if !order.PaymentStatus.CanTransitionTo(next) {
+			return core.ErrConflict.
+				WithError(errorx.ErrInvalidPaymentStatusTransition.Error()).
+				WithReasonf("cannot move payment from %s to %s", order.PaymentStatus, next)
+		}
+
+		if err := s.orderRepo.UpdatePaymentStatus(ctx, orderID, next); err != nil {
+			return err
+		}
+
+		event := orderEntities.NewOrderEvent(orderID, constants.OrderEventPaymentStatusChanged, caller).
+			WithTransition(order.PaymentStatus.String(), next.String())
+		if err := s.eventRepo.Append(ctx, event); err != nil {
+			return err
+		}
+
+		if next == constants.PaymentStatusPaid {
+			return s.outbox.Add(ctx, newPaymentCapturedEvent(order, time.Now()))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	return s.getOrder(ctx, orderID)
+}
+
+func (s *orderService) CancelOrder(ctx context.Context, caller orderEntities.Caller, orderID int32, req *dto.CancelOrderRequest) (*dto.OrderResponse, error) {
+	var cancelled *orderEntities.Order
+	var refund *orderEntities.Refund
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.policy.Authorize(caller, constants.OrderActionCancel, order); err != nil {
+			return err
+		}
+
+		if order.Status == constants.OrderStatusCancelled {
+			return core.ErrConflict.WithError(errorx.ErrOrderAlreadyCancelled.Error())
+		}
+
+		if !order.CanBeCancelled() {
+			return core.ErrConflict.
+				WithError(errorx.ErrOrderNotCancellable.Error()).
+				WithReasonf("order is %s", order.Status)
+		}
+
+		cancelled = order
+		refund, err = s.canceller.cancel(ctx, caller, order, req.Reason, time.Now())
+		return err
+	})
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	s.canceller.settle(ctx, caller, cancelled, refund)
+
+	return s.getOrder(ctx, orderID)
+}
+
+// cancelIntent stops the provider from capturing the order's payment intent,
+// if it has one
+func cancelIntent(ctx context.Context, provider payment.PaymentProvider, order *orderEntities.Order) error {
+	if order.PaymentIntentID == nil {
+		return nil
+	}
+
+	if err := provider.CancelIntent(ctx, *order.PaymentIntentID); err != nil {
+		return core.ErrInternalServerError.
+			WithError(errorx.ErrPaymentFailed.Error()).
+			WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+func newOrderCancelledEvent(order *orderEntities.Order, reason string, previousPaymentStatus constants.PaymentStatus, items []*orderEntities.OrderItem, at time.Time) *events.OrderCancelled {
+	event := &events.OrderCancelled{
+		OrderID:               order.ID,
+		UserID:                order.UserID,
+		Reason:                reason,
+		PreviousPaymentStatus: previousPaymentStatus,
+		PaymentStatus:         order.PaymentStatus,
+		CancelledAt:           at,
+	}
+	for _, item := range items {
+		event.Items = append(event.Items, events.OrderItemQuantity{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	return event
+}
+
+func newOrderCreatedEvent(order *orderEntities.Order, items []*orderEntities.OrderItem) *events.OrderCreated {
+	event := &events.OrderCreated{
+		OrderID:     order.ID,
+		UserID:      order.UserID,
+		Status:      order.Status.String(),
+		Currency:    order.Currency,
+		TotalAmount: order.TotalAmount,
+		Items:       make([]events.OrderCreatedItem, 0, len(items)),
+		CreatedAt:   order.CreatedAt,
+	}
+	for _, item := range items {
+		event.Items = append(event.Items, events.OrderCreatedItem{
 			ProductID: item.ProductID,
 			Quantity:  item.Quantity,
 			Price:     item.Price,
 		})
 	}
 
+	return event
+}
+
+func newOrderStatusChangedEvent(order *orderEntities.Order, from, to constants.OrderStatus, at time.Time) *events.OrderStatusChanged {
+	return &events.OrderStatusChanged{
+		OrderID:   order.ID,
+		UserID:    order.UserID,
+		From:      from.String(),
+		To:        to.String(),
+		ChangedAt: at,
+	}
+}
+
+func newPaymentCapturedEvent(order *orderEntities.Order, at time.Time) *events.PaymentCaptured {
+	event := &events.PaymentCaptured{
+		OrderID:    order.ID,
+		UserID:     order.UserID,
+		Amount:     order.TotalAmount,
+		Currency:   order.Currency,
+		CapturedAt: at,
+	}
+	if order.PaymentProvider != nil {
+		event.Provider = *order.PaymentProvider
+	}
+	if order.PaymentIntentID != nil {
+		event.IntentID = *order.PaymentIntentID
+	}
+
+	return event
+}
+
+func stockLines(items []*orderEntities.OrderItem) []productDto.StockLine {
+	lines := make([]productDto.StockLine, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, productDto.StockLine{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	return lines
+}
+
+func (s *orderService) GetOrderTimeline(ctx context.Context, caller orderEntities.Caller, orderID int32) ([]*dto.OrderEventResponse, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	if err := s.policy.Authorize(caller, constants.OrderActionView, order); err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	events, err := s.eventRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.OrderEventResponse, 0, len(events))
+	for _, event := range events {
+		responses = append(responses, &dto.OrderEventResponse{
+			ID:         event.ID,
+			Type:       event.Type.String(),
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			ActorID:    event.ActorID,
+			ActorRole:  event.ActorRole,
+			Metadata:   event.Metadata,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+
+	return responses, nil
+}
+
+func (s *orderService) Reorder(ctx context.Context, caller orderEntities.Caller, orderID int32) (*dto.ReorderResponse, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	if err := s.policy.Authorize(caller, constants.OrderActionReorder, order); err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	response := &dto.ReorderResponse{
+		OrderID: order.ID,
+		Items:   make([]dto.ReorderItemResponse, 0, len(order.Items)),
+	}
+
+	// Items that cannot be added are skipped, but a failing store leaves
+	// the cart as it was
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		cartItems, err := s.cartService.GetItems(ctx, caller.UserID)
+		if err != nil {
+			return err
+		}
+
+		inCart := make(map[int32]int32, len(cartItems))
+		for _, cartItem := range cartItems {
+			inCart[cartItem.ProductID] += cartItem.Quantity
+		}
+
+		for _, item := range order.Items {
+			line, err := s.reorderItem(ctx, caller.UserID, item, inCart)
+			if err != nil {
+				return err
+			}
+			response.Items = append(response.Items, line)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// reorderItem adds one item of a past order to the cart, capped at the stock
+// left after what inCart already holds of the product. Products gone from the
+// catalog, out of stock or turned down by the cart rules are reported as
+// skipped instead of failing.
+func (s *orderService) reorderItem(ctx context.Context, userID int32, item *orderEntities.OrderItem, inCart map[int32]int32) (dto.ReorderItemResponse, error) {
+	line := dto.ReorderItemResponse{
+		ProductID:       item.ProductID,
+		ProductName:     item.ProductName,
+		Status:          constants.ReorderItemSkipped.String(),
+		OrderedQuantity: item.Quantity,
+	}
+
+	product, err := s.productService.GetProduct(ctx, item.ProductID)
+	if errors.Is(err, errorx.ErrProductNotFound) {
+		line.Reason = errorx.ErrProductNotFound.Error()
+		return line, nil
+	}
+	if err != nil {
+		return line, err
+	}
+
+	// Items ordered before product snapshots have no name of their own
+	if line.ProductName == "" {
+		line.ProductName = product.Name
+	}
+
+	if product.Stock <= 0 {
+		line.Reason = errorx.ErrProductOutOfStock.Error()
+		return line, nil
+	}
+
+	available := product.Stock - inCart[item.ProductID]
+	if available <= 0 {
+		line.Reason = fmt.Sprintf("the cart already holds the %d left in stock", product.Stock)
+		return line, nil
+	}
+
+	quantity := min(item.Quantity, available)
+	_, err = s.cartService.AddItem(ctx, userID, &cartDto.CartItemRequest{
+		ProductID: item.ProductID,
+		Quantity:  quantity,
+	})
+	if err != nil {
+		var appErr *core.DefaultError
+		if errors.As(err, &appErr) && appErr.StatusCode() < http.StatusInternalServerError {
+			line.Reason = appErr.Error()
+			return line, nil
+		}
+		return line, err
+	}
+
+	inCart[item.ProductID] += quantity
+
+	price := product.Price
+	line.Price = &price
+	line.AddedQuantity = quantity
+	line.Status = constants.ReorderItemAdded.String()
+	if quantity < item.Quantity {
+		line.Status = constants.ReorderItemAdjusted.String()
+		line.Reason = fmt.Sprintf("only %d left in stock", product.Stock)
+		if held := product.Stock - available; held > 0 {
+			line.Reason = fmt.Sprintf("only %d left in stock, %d already in the cart", product.Stock, held)
+		}
+	}
+
+	return line, nil
+}
+
+// wrapNotFound turns a missing order into a 404 response error. Orders the
+// caller may not access are reported the same way so IDs cannot be probed.
+func wrapNotFound(err error) error {
+	if errors.Is(err, errorx.ErrOrderNotFound) || errors.Is(err, errorx.ErrUnauthorizedOrderAccess) {
+		return core.ErrNotFound.WithError(errorx.ErrOrderNotFound.Error())
+	}
+	return err
+}
+
+func (s *orderService) convertToResponse(order *orderEntities.Order) *dto.OrderResponse {
+	itemResponses := make([]dto.OrderItemResponse, 0, len(order.Items))
+	for _, item := range order.Items {
+		itemResponses = append(itemResponses, dto.OrderItemResponse{
+			ID:                 item.ID,
+			ProductID:          item.ProductID,
+			Quantity:           item.Quantity,
+			Price:              item.Price,
+			DiscountAmount:     item.DiscountAmount,
+			TaxRate:            item.TaxRate,
+			TaxAmount:          item.TaxAmount,
+			ProductPrice:       item.ProductPrice,
+			ProductCurrency:    item.ProductPrice.Currency().String(),
+			ExchangeRate:       item.ExchangeRate,
+			ProductName:        item.ProductName,
+			ProductDescription: item.ProductDescription,
+			CategoryID:         item.CategoryID,
+			CategoryName:       item.CategoryName,
+		})
+	}
+
+	var shipmentResponses []dto.ShipmentResponse
+	for _, shipment := range order.Shipments {
+		shipmentResponses = append(shipmentResponses, toShipmentResponse(shipment))
+	}
+
 	return &dto.OrderResponse{
 		ID:              order.ID,
 		Status:          order.Status.String(),
 		PaymentStatus:   order.PaymentStatus.String(),
+		Currency:        order.Currency.String(),
+		ExchangeRate:    order.ExchangeRate,
 		TotalAmount:     order.TotalAmount,
+		ShippingAmount:  order.ShippingAmount,
+		DiscountAmount:  order.DiscountAmount,
+		CouponCode:      order.CouponCode,
+		TaxAmount:       order.TaxAmount,
+		Tax:             taxBreakdown(order),
 		ShippingAddress: order.ShippingAddress,
 		ShippingCity:    order.ShippingCity,
 		ShippingCountry: order.ShippingCountry,
 		ShippingZip:     order.ShippingZip,
+		BillingAddress:  order.BillingAddress,
+		BillingCity:     order.BillingCity,
+		BillingCountry:  order.BillingCountry,
+		BillingZip:      order.BillingZip,
+		CancelReason:    order.CancelReason,
+		CancelledAt:     order.CancelledAt,
 		Items:           itemResponses,
+		Shipments:       shipmentResponses,
 		CreatedAt:       order.CreatedAt,
 		UpdatedAt:       order.UpdatedAt,
 	}
 }
+
+// taxBreakdown groups the tax of the loaded items by rate
+func taxBreakdown(order *orderEntities.Order) dto.TaxBreakdownResponse {
+	breakdown := dto.TaxBreakdownResponse{
+		Inclusive: order.TaxInclusive,
+		Rates:     []dto.TaxRateResponse{},
+	}
+
+	byRate := make(map[float64]int)
+	for _, item := range order.Items {
+		i, ok := byRate[item.TaxRate]
+		if !ok {
+			i = len(breakdown.Rates)
+			byRate[item.TaxRate] = i
+			breakdown.Rates = append(breakdown.Rates, dto.TaxRateResponse{
+				Rate:          item.TaxRate,
+				TaxableAmount: money.Zero(order.Currency),
+				TaxAmount:     money.Zero(order.Currency),
+			})
+		}
+		rate := &breakdown.Rates[i]
+		rate.TaxableAmount = rate.TaxableAmount.Add(item.Price.Mul(int64(item.Quantity)).Sub(item.DiscountAmount))
+		rate.TaxAmount = rate.TaxAmount.Add(item.TaxAmount)
+	}
+
+	sort.Slice(breakdown.Rates, func(i, j int) bool {
+		return breakdown.Rates[i].Rate < breakdown.Rates[j].Rate
+	})
+
+	return breakdown
+}
@@ -2,12 +2,31 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	cartDto "mallbots/modules/cart/application/dto"
+	currencyServices "mallbots/modules/currency/application/services"
+	currencyEntities "mallbots/modules/currency/domain/entities"
 	"mallbots/modules/order/application/dto"
 	"mallbots/modules/order/domain/constants"
 	"mallbots/modules/order/domain/entities"
+	"mallbots/modules/order/domain/events"
 	"mallbots/modules/order/domain/interfaces"
+	productDto "mallbots/modules/product/application/dto"
+	promotionDto "mallbots/modules/promotion/application/dto"
+	ruleServices "mallbots/modules/rules/application/services"
+	taxServices "mallbots/modules/tax/application/services"
+	taxInterfaces "mallbots/modules/tax/domain/interfaces"
+	userDto "mallbots/modules/user/application/dto"
+	"mallbots/plugins/eventbus"
+	"mallbots/plugins/outbox"
+	"mallbots/plugins/payment"
+	"mallbots/plugins/payment/fake"
+	"mallbots/shared/common"
+	"mallbots/shared/config"
 	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"net/http"
 	"testing"
 	"time"
 
@@ -16,6 +35,11 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// usd is a test amount in US dollars
+func usd(amount string) money.Money {
+	return money.MustParse(amount, money.USD)
+}
+
 type MockOrderRepository struct {
 	mock.Mock
 }
@@ -41,14 +65,40 @@ func (m *MockOrderRepository) GetByID(ctx context.Context, id int32) (*entities.
 	return args.Get(0).(*entities.Order), args.Error(1)
 }
 
-func (m *MockOrderRepository) GetByUserID(ctx context.Context, userID int32, paging *core.Paging) ([]*entities.Order, error) {
-	args := m.Called(ctx, userID, paging)
+func (m *MockOrderRepository) GetByIDForUpdate(ctx context.Context, id int32) (*entities.Order, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) GetItems(ctx context.Context, orderID int32) ([]*entities.OrderItem, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.OrderItem), args.Error(1)
+}
+
+func (m *MockOrderRepository) GetByUserID(ctx context.Context, userID int32, filter *interfaces.OrderFilter, paging *core.Paging) ([]*entities.Order, error) {
+	args := m.Called(ctx, userID, filter, paging)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*entities.Order), args.Error(1)
 }
 
+func (m *MockOrderRepository) CountUserOrdersSince(ctx context.Context, userID int32, since time.Time) (int64, error) {
+	args := m.Called(ctx, userID, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockOrderRepository) LockUserOrders(ctx context.Context, userID int32) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
 func (m *MockOrderRepository) UpdateStatus(ctx context.Context, id int32, status constants.OrderStatus) error {
 	args := m.Called(ctx, id, status)
 	return args.Error(0)
@@ -59,6 +109,164 @@ func (m *MockOrderRepository) UpdatePaymentStatus(ctx context.Context, id int32,
 	return args.Error(0)
 }
 
+func (m *MockOrderRepository) Cancel(ctx context.Context, order *entities.Order) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) GetExpiredUnpaidIDs(ctx context.Context, now time.Time, ttl time.Duration, providerTTLs map[string]time.Duration, skipIDs []int32, limit int32) ([]int32, error) {
+	args := m.Called(ctx, now, ttl, providerTTLs, skipIDs, limit)
+	return args.Get(0).([]int32), args.Error(1)
+}
+
+func (m *MockOrderRepository) GetByPaymentIntentIDForUpdate(ctx context.Context, intentID string) (*entities.Order, error) {
+	args := m.Called(ctx, intentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) SetPaymentIntent(ctx context.Context, id int32, provider, intentID string) error {
+	args := m.Called(ctx, id, provider, intentID)
+	return args.Error(0)
+}
+
+type MockOrderEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderEventRepository) Append(ctx context.Context, event *entities.OrderEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockOrderEventRepository) GetByOrderID(ctx context.Context, orderID int32) ([]*entities.OrderEvent, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.OrderEvent), args.Error(1)
+}
+
+// recordedEvents returns every event appended to the timeline, in order
+func (m *MockOrderEventRepository) recordedEvents() []*entities.OrderEvent {
+	var events []*entities.OrderEvent
+	for _, call := range m.Calls {
+		if call.Method == "Append" {
+			events = append(events, call.Arguments.Get(1).(*entities.OrderEvent))
+		}
+	}
+	return events
+}
+
+type MockOutboxWriter struct {
+	mock.Mock
+}
+
+// newMockOutboxWriter accepts every event written to the outbox
+func newMockOutboxWriter() *MockOutboxWriter {
+	writer := new(MockOutboxWriter)
+	writer.On("Add", mock.Anything, mock.Anything).Return(nil)
+	return writer
+}
+
+func (m *MockOutboxWriter) Add(ctx context.Context, events ...outbox.Event) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+// recordedEvents returns every event written to the outbox, in order
+func (m *MockOutboxWriter) recordedEvents() []outbox.Event {
+	var events []outbox.Event
+	for _, call := range m.Calls {
+		if call.Method == "Add" {
+			events = append(events, call.Arguments.Get(1).([]outbox.Event)...)
+		}
+	}
+	return events
+}
+
+type MockRefundService struct {
+	mock.Mock
+}
+
+func (m *MockRefundService) RequestRefund(ctx context.Context, caller entities.Caller, orderID int32, req *dto.CreateRefundRequest) (*dto.RefundResponse, error) {
+	args := m.Called(ctx, caller, orderID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RefundResponse), args.Error(1)
+}
+
+func (m *MockRefundService) GetOrderRefunds(ctx context.Context, caller entities.Caller, orderID int32) ([]*dto.RefundResponse, error) {
+	args := m.Called(ctx, caller, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*dto.RefundResponse), args.Error(1)
+}
+
+func (m *MockRefundService) ApproveRefund(ctx context.Context, caller entities.Caller, refundID int32) (*dto.RefundResponse, error) {
+	args := m.Called(ctx, caller, refundID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RefundResponse), args.Error(1)
+}
+
+func (m *MockRefundService) RejectRefund(ctx context.Context, caller entities.Caller, refundID int32) (*dto.RefundResponse, error) {
+	args := m.Called(ctx, caller, refundID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RefundResponse), args.Error(1)
+}
+
+func (m *MockRefundService) ProcessRefund(ctx context.Context, caller entities.Caller, refundID int32) (*dto.RefundResponse, error) {
+	args := m.Called(ctx, caller, refundID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RefundResponse), args.Error(1)
+}
+
+func (m *MockRefundService) RefundCancelledOrder(ctx context.Context, caller entities.Caller, order *entities.Order, reason string) (*entities.Refund, error) {
+	args := m.Called(ctx, caller, order, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Refund), args.Error(1)
+}
+
+func (m *MockRefundService) RefundUnappliedPayment(ctx context.Context, caller entities.Caller, order *entities.Order, amount money.Money, reason string) (*entities.Refund, error) {
+	args := m.Called(ctx, caller, order, amount, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Refund), args.Error(1)
+}
+
+func (m *MockRefundService) RecordProviderRefund(ctx context.Context, caller entities.Caller, order *entities.Order, refunded money.Money) error {
+	args := m.Called(ctx, caller, order, refunded)
+	return args.Error(0)
+}
+
+// MockPaymentProvider is the fake provider with intent cancellation mocked
+type MockPaymentProvider struct {
+	mock.Mock
+	payment.PaymentProvider
+}
+
+func newMockPaymentProvider() *MockPaymentProvider {
+	return &MockPaymentProvider{PaymentProvider: fake.NewWithSecret("payment", testWebhookSecret)}
+}
+
+func (m *MockPaymentProvider) CancelIntent(ctx context.Context, intentID string) error {
+	args := m.Called(ctx, intentID)
+	return args.Error(0)
+}
+
 type MockCartService struct {
 	mock.Mock
 }
@@ -97,128 +305,504 @@ func (m *MockCartService) GetItems(ctx context.Context, userID int32) ([]*cartDt
 	return args.Get(0).([]*cartDto.CartItemResponse), args.Error(1)
 }
 
-type testSuite struct {
-	orderRepo    *MockOrderRepository
-	cartService  *MockCartService
-	orderService interfaces.OrderService
-	ctx          context.Context
+func (m *MockCartService) GetItemsIn(ctx context.Context, userID int32, currency string) ([]*cartDto.CartItemResponse, error) {
+	args := m.Called(ctx, userID, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*cartDto.CartItemResponse), args.Error(1)
 }
 
-func setupTest(t *testing.T) *testSuite {
-	orderRepo := new(MockOrderRepository)
-	cartService := new(MockCartService)
-	orderService := NewOrderService(orderRepo, cartService)
+type MockAddressService struct {
+	mock.Mock
+}
 
-	return &testSuite{
-		orderRepo:    orderRepo,
-		cartService:  cartService,
-		orderService: orderService,
-		ctx:          context.Background(),
+func (m *MockAddressService) CreateAddress(ctx context.Context, userID int32, req *userDto.AddressRequest) (*userDto.AddressResponse, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*userDto.AddressResponse), args.Error(1)
 }
 
-func TestOrderService(t *testing.T) {
-	t.Run("Create Order - Success", func(t *testing.T) {
-		// Setup
-		ts := setupTest(t)
-
-		userID := int32(1)
-		cartItems := []*cartDto.CartItemResponse{
-			{
-				ID:        1,
-				ProductID: 1,
-				Quantity:  2,
-				Price:     10.99,
-			},
-			{
-				ID:        2,
-				ProductID: 2,
-				Quantity:  1,
-				Price:     20.99,
-			},
-		}
+func (m *MockAddressService) GetAddresses(ctx context.Context, userID int32) ([]*userDto.AddressResponse, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*userDto.AddressResponse), args.Error(1)
+}
 
-		req := &dto.CreateOrderRequest{
-			ShippingAddress: "123 Test St",
-			ShippingCity:    "Test City",
-			ShippingCountry: "Test Country",
-			ShippingZip:     "12345",
-		}
+func (m *MockAddressService) GetAddress(ctx context.Context, userID, id int32) (*userDto.AddressResponse, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userDto.AddressResponse), args.Error(1)
+}
 
-		// Setup expectations
-		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+func (m *MockAddressService) UpdateAddress(ctx context.Context, userID, id int32, req *userDto.AddressRequest) (*userDto.AddressResponse, error) {
+	args := m.Called(ctx, userID, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userDto.AddressResponse), args.Error(1)
+}
 
-		// Calculate expected total
-		expectedTotal := 10.99*2 + 20.99
+func (m *MockAddressService) DeleteAddress(ctx context.Context, userID, id int32) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
 
-		// Mock order creation
-		ts.orderRepo.On("Create", ts.ctx, mock.MatchedBy(func(order *entities.Order) bool {
-			return order.UserID == userID &&
-				order.TotalAmount == expectedTotal &&
-				order.Status == constants.OrderStatusPending &&
-				order.PaymentStatus == constants.PaymentStatusPending
-		})).Return(&entities.Order{
-			ID:              1,
-			UserID:          userID,
-			Status:          constants.OrderStatusPending,
-			PaymentStatus:   constants.PaymentStatusPending,
-			TotalAmount:     expectedTotal,
-			ShippingAddress: req.ShippingAddress,
-			ShippingCity:    req.ShippingCity,
-			ShippingCountry: req.ShippingCountry,
-			ShippingZip:     req.ShippingZip,
-			CreatedAt:       time.Now(),
-			UpdatedAt:       time.Now(),
-		}, nil)
+func (m *MockAddressService) GetDefaultShippingAddress(ctx context.Context, userID int32) (*userDto.AddressResponse, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userDto.AddressResponse), args.Error(1)
+}
 
-		// Mock order items creation
-		ts.orderRepo.On("CreateOrderItems", ts.ctx, int32(1), mock.MatchedBy(func(items []*entities.OrderItem) bool {
-			return len(items) == 2 &&
-				items[0].ProductID == cartItems[0].ProductID &&
-				items[0].Quantity == cartItems[0].Quantity &&
-				items[0].Price == cartItems[0].Price &&
-				items[1].ProductID == cartItems[1].ProductID &&
-				items[1].Quantity == cartItems[1].Quantity &&
-				items[1].Price == cartItems[1].Price
-		})).Return(nil)
+func (m *MockAddressService) GetDefaultBillingAddress(ctx context.Context, userID int32) (*userDto.AddressResponse, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userDto.AddressResponse), args.Error(1)
+}
 
-		// Mock cart cleanup
-		ts.cartService.On("RemoveAllItems", ts.ctx, userID).Return(nil)
+// newMockAddressService is an address book without defaults
+func newMockAddressService() *MockAddressService {
+	addresses := new(MockAddressService)
+	addresses.On("GetDefaultShippingAddress", mock.Anything, mock.Anything).Return(nil, nil)
+	addresses.On("GetDefaultBillingAddress", mock.Anything, mock.Anything).Return(nil, nil)
+	return addresses
+}
 
-		// Execute test
-		order, err := ts.orderService.CreateOrder(ts.ctx, userID, req)
-		require.NoError(t, err)
-		require.NotNil(t, order)
-		require.Equal(t, int32(1), order.ID)
-		require.Equal(t, expectedTotal, order.TotalAmount)
-		require.Equal(t, constants.OrderStatusPending.String(), order.Status)
-		require.Equal(t, constants.PaymentStatusPending.String(), order.PaymentStatus)
+type MockProductService struct {
+	mock.Mock
+}
 
-		// Verify all expectations
-		ts.cartService.AssertExpectations(t)
-		ts.orderRepo.AssertExpectations(t)
-	})
+func (m *MockProductService) GetProductIn(ctx context.Context, id int32, currency string) (*productDto.ProductResponse, error) {
+	args := m.Called(ctx, id, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*productDto.ProductResponse), args.Error(1)
+}
 
-	t.Run("Create Order - Empty Cart", func(t *testing.T) {
-		// Setup new test suite instance
-		ts := setupTest(t)
+func (m *MockProductService) GetProduct(ctx context.Context, id int32) (*productDto.ProductResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*productDto.ProductResponse), args.Error(1)
+}
 
-		userID := int32(1)
-		req := &dto.CreateOrderRequest{
-			ShippingAddress: "123 Test St",
-			ShippingCity:    "Test City",
-			ShippingCountry: "Test Country",
-			ShippingZip:     "12345",
-		}
+func (m *MockProductService) GetProducts(ctx context.Context, req *productDto.ProductListRequest, paging *core.Paging) ([]*productDto.ProductResponse, error) {
+	args := m.Called(ctx, req, paging)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*productDto.ProductResponse), args.Error(1)
+}
 
-		// Setup expectations for empty cart
-		ts.cartService.On("GetItems", ts.ctx, userID).Return([]*cartDto.CartItemResponse{}, nil)
+type MockInventoryService struct {
+	mock.Mock
+}
 
-		// Execute test
-		order, err := ts.orderService.CreateOrder(ts.ctx, userID, req)
-		require.Error(t, err)
-		require.Equal(t, errorx.ErrCartEmpty, err)
-		require.Nil(t, order)
+func (m *MockInventoryService) GetInventory(ctx context.Context, productID int32) (*productDto.InventoryResponse, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*productDto.InventoryResponse), args.Error(1)
+}
+
+func (m *MockInventoryService) SetStock(ctx context.Context, productID int32, req *productDto.SetStockRequest) (*productDto.InventoryResponse, error) {
+	args := m.Called(ctx, productID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*productDto.InventoryResponse), args.Error(1)
+}
+
+func (m *MockInventoryService) Reserve(ctx context.Context, lines []productDto.StockLine) error {
+	args := m.Called(ctx, lines)
+	return args.Error(0)
+}
+
+func (m *MockInventoryService) Release(ctx context.Context, lines []productDto.StockLine) error {
+	args := m.Called(ctx, lines)
+	return args.Error(0)
+}
+
+func (m *MockInventoryService) Commit(ctx context.Context, lines []productDto.StockLine) error {
+	args := m.Called(ctx, lines)
+	return args.Error(0)
+}
+
+type MockPromotionService struct {
+	mock.Mock
+}
+
+func (m *MockPromotionService) CreateCoupon(ctx context.Context, req *promotionDto.CreateCouponRequest) (*promotionDto.CouponResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*promotionDto.CouponResponse), args.Error(1)
+}
+
+func (m *MockPromotionService) ListCoupons(ctx context.Context, paging *core.Paging) ([]*promotionDto.CouponResponse, error) {
+	args := m.Called(ctx, paging)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*promotionDto.CouponResponse), args.Error(1)
+}
+
+func (m *MockPromotionService) ApplyCoupon(ctx context.Context, userID int32, req *promotionDto.ApplyCouponRequest) (*promotionDto.CartCouponResponse, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*promotionDto.CartCouponResponse), args.Error(1)
+}
+
+func (m *MockPromotionService) RemoveCoupon(ctx context.Context, userID int32) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockPromotionService) PriceCartCoupon(ctx context.Context, userID int32, req promotionDto.DiscountRequest) (*promotionDto.DiscountResult, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*promotionDto.DiscountResult), args.Error(1)
+}
+
+func (m *MockPromotionService) Redeem(ctx context.Context, userID, orderID int32, discount *promotionDto.DiscountResult) error {
+	args := m.Called(ctx, userID, orderID, discount)
+	return args.Error(0)
+}
+
+func (m *MockPromotionService) ReleaseOrder(ctx context.Context, orderID int32) error {
+	args := m.Called(ctx, orderID)
+	return args.Error(0)
+}
+
+type MockExchangeRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateRepository) Create(ctx context.Context, rate *currencyEntities.ExchangeRate) (*currencyEntities.ExchangeRate, error) {
+	args := m.Called(ctx, rate)
+	return args.Get(0).(*currencyEntities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) GetLatest(ctx context.Context, currencies []money.Currency) ([]*currencyEntities.ExchangeRate, error) {
+	args := m.Called(ctx, currencies)
+	return args.Get(0).([]*currencyEntities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) ListLatest(ctx context.Context) ([]*currencyEntities.ExchangeRate, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*currencyEntities.ExchangeRate), args.Error(1)
+}
+
+type MockTxManager struct {
+	mock.Mock
+}
+
+func (m *MockTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.Called(ctx)
+	return fn(ctx)
+}
+
+type testSuite struct {
+	orderRepo      *MockOrderRepository
+	cartService    *MockCartService
+	addresses      *MockAddressService
+	productService *MockProductService
+	inventory      *MockInventoryService
+	promotions     *MockPromotionService
+	refunds        *MockRefundService
+	provider       *MockPaymentProvider
+	rateRepo       *MockExchangeRateRepository
+	txManager      *MockTxManager
+	eventBus       eventbus.Bus
+	eventRepo      *MockOrderEventRepository
+	outbox         *MockOutboxWriter
+	orderService   interfaces.OrderService
+	ctx            context.Context
+}
+
+func setupTest(t *testing.T) *testSuite {
+	return setupTestWithRules(t, config.OrderRulesConfig{})
+}
+
+func setupTestWithRules(t *testing.T, rulesCfg config.OrderRulesConfig) *testSuite {
+	rules, err := ruleServices.NewRuleEngine(&config.Config{OrderRules: rulesCfg})
+	require.NoError(t, err)
+
+	orderRepo := new(MockOrderRepository)
+	// Checkouts get their turn at once unless a test says otherwise
+	orderRepo.On("LockUserOrders", mock.Anything, mock.Anything).Return(nil).Maybe()
+	cartService := new(MockCartService)
+	addresses := newMockAddressService()
+	productService := new(MockProductService)
+	inventory := new(MockInventoryService)
+	// Carts carry no coupon unless a test applies one
+	promotions := new(MockPromotionService)
+	promotions.On("PriceCartCoupon", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	rateRepo := new(MockExchangeRateRepository)
+	refunds := new(MockRefundService)
+	provider := newMockPaymentProvider()
+	txManager := new(MockTxManager)
+	txManager.On("WithTx", mock.Anything).Return()
+	eventBus := eventbus.New("eventbus")
+	eventRepo := new(MockOrderEventRepository)
+	eventRepo.On("Append", mock.Anything, mock.Anything).Return(nil)
+	outboxWriter := newMockOutboxWriter()
+	currencies := currencyServices.NewCurrencyService(rateRepo, txManager)
+	orderService := NewOrderService(orderRepo, cartService, addresses, productService, inventory, newTestShippingCalculator(t), newTestTaxCalculator(t), promotions, rules, currencies, refunds, provider, txManager, eventBus, NewOrderAccessPolicy(), eventRepo, outboxWriter)
+
+	return &testSuite{
+		orderRepo:      orderRepo,
+		cartService:    cartService,
+		addresses:      addresses,
+		productService: productService,
+		inventory:      inventory,
+		promotions:     promotions,
+		refunds:        refunds,
+		provider:       provider,
+		rateRepo:       rateRepo,
+		txManager:      txManager,
+		eventBus:       eventBus,
+		eventRepo:      eventRepo,
+		outbox:         outboxWriter,
+		orderService:   orderService,
+		ctx:            context.Background(),
+	}
+}
+
+// newTestTaxCalculator charges 10% on top of prices shipped to Test Country
+// zip codes starting with 9, every other destination is untaxed
+func newTestTaxCalculator(t *testing.T) taxInterfaces.TaxCalculator {
+	calculator, err := taxServices.NewTaxCalculator(&config.Config{
+		Tax: config.TaxConfig{
+			Rules: []config.TaxRuleConfig{
+				{Name: "test-region", Country: "Test Country", ZipPrefixes: []string{"9"}, Rate: 10, CategoryRates: map[int32]float64{2: 5}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return calculator
+}
+
+// applyCoupon makes the cart carry a coupon worth the discount
+func (ts *testSuite) applyCoupon(discount *promotionDto.DiscountResult) {
+	ts.promotions.ExpectedCalls = nil
+	ts.promotions.On("PriceCartCoupon", ts.ctx, mock.Anything, mock.Anything).Return(discount, nil)
+}
+
+// stubCurrentPrices makes the catalog agree with the prices held in the cart
+func (ts *testSuite) stubCurrentPrices(cartItems []*cartDto.CartItemResponse) {
+	for _, item := range cartItems {
+		ts.productService.On("GetProduct", ts.ctx, item.ProductID).
+			Return(&productDto.ProductResponse{
+				ID:           item.ProductID,
+				Name:         fmt.Sprintf("Product %d", item.ProductID),
+				Price:        item.Price,
+				CategoryID:   7,
+				CategoryName: "Kitchen",
+			}, nil)
+	}
+}
+
+var admin = entities.Caller{UserID: 99, Role: common.RoleAdmin}
+
+func customer(userID int32) entities.Caller {
+	return entities.Caller{UserID: userID, Role: common.RoleUser}
+}
+
+func TestOrderService(t *testing.T) {
+	t.Run("Create Order - Success", func(t *testing.T) {
+		// Setup
+		ts := setupTest(t)
+
+		userID := int32(1)
+		cartItems := []*cartDto.CartItemResponse{
+			{
+				ID:        1,
+				ProductID: 1,
+				Quantity:  2,
+				Price:     usd("10.99"),
+			},
+			{
+				ID:        2,
+				ProductID: 2,
+				Quantity:  1,
+				Price:     usd("20.99"),
+			},
+		}
+
+		req := &dto.CreateOrderRequest{
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "12345",
+		}
+
+		// Setup expectations
+		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.stubCurrentPrices(cartItems)
+		ts.inventory.On("Reserve", ts.ctx, mock.Anything).Return(nil)
+
+		// Calculate expected total
+		expectedTotal := usd("10.99").Mul(2).Add(usd("20.99"))
+
+		// Mock order creation
+		ts.orderRepo.On("Create", ts.ctx, mock.MatchedBy(func(order *entities.Order) bool {
+			return order.UserID == userID &&
+				order.TotalAmount == expectedTotal &&
+				order.Status == constants.OrderStatusPending &&
+				order.PaymentStatus == constants.PaymentStatusPending
+		})).Return(&entities.Order{
+			ID:              1,
+			UserID:          userID,
+			Status:          constants.OrderStatusPending,
+			PaymentStatus:   constants.PaymentStatusPending,
+			TotalAmount:     expectedTotal,
+			ShippingAddress: req.ShippingAddress,
+			ShippingCity:    req.ShippingCity,
+			ShippingCountry: req.ShippingCountry,
+			ShippingZip:     req.ShippingZip,
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}, nil)
+
+		// Mock order items creation
+		ts.orderRepo.On("CreateOrderItems", ts.ctx, int32(1), mock.MatchedBy(func(items []*entities.OrderItem) bool {
+			return len(items) == 2 &&
+				items[0].ProductID == cartItems[0].ProductID &&
+				items[0].Quantity == cartItems[0].Quantity &&
+				items[0].Price == cartItems[0].Price &&
+				items[1].ProductID == cartItems[1].ProductID &&
+				items[1].Quantity == cartItems[1].Quantity &&
+				items[1].Price == cartItems[1].Price &&
+				items[0].ProductName == "Product 1" &&
+				items[1].ProductName == "Product 2" &&
+				*items[1].CategoryID == 7 &&
+				*items[1].CategoryName == "Kitchen"
+		})).Return(nil)
+
+		// Mock cart cleanup
+		ts.cartService.On("RemoveAllItems", ts.ctx, userID).Return(nil)
+
+		// Execute test
+		order, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), req)
+		require.NoError(t, err)
+		require.NotNil(t, order)
+		require.Equal(t, int32(1), order.ID)
+		require.Equal(t, expectedTotal, order.TotalAmount)
+		ts.txManager.AssertNumberOfCalls(t, "WithTx", 1)
+		require.Equal(t, constants.OrderStatusPending.String(), order.Status)
+		require.Equal(t, constants.PaymentStatusPending.String(), order.PaymentStatus)
+
+		recorded := ts.eventRepo.recordedEvents()
+		require.Len(t, recorded, 1)
+		require.Equal(t, constants.OrderEventCreated, recorded[0].Type)
+		require.Nil(t, recorded[0].FromStatus)
+		require.Equal(t, constants.OrderStatusPending.String(), *recorded[0].ToStatus)
+
+		published := ts.outbox.recordedEvents()
+		require.Len(t, published, 1)
+		created := published[0].(*events.OrderCreated)
+		require.Equal(t, int32(1), created.OrderID)
+		require.Equal(t, expectedTotal, created.TotalAmount)
+		require.Len(t, created.Items, 2)
+
+		// Verify all expectations
+		ts.cartService.AssertExpectations(t)
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Create Order - Locks Exchange Rate", func(t *testing.T) {
+		ts := setupTest(t)
+
+		userID := int32(1)
+		cartItems := []*cartDto.CartItemResponse{
+			{ID: 1, ProductID: 1, Quantity: 2, Price: usd("10.99")},
+		}
+
+		req := &dto.CreateOrderRequest{
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "12345",
+			Currency:        "EUR",
+		}
+
+		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.stubCurrentPrices(cartItems)
+		ts.rateRepo.On("GetLatest", ts.ctx, []money.Currency{money.EUR}).Return([]*currencyEntities.ExchangeRate{
+			{ID: 3, Currency: money.EUR, Rate: money.MustParseRate("0.92")},
+		}, nil)
+		ts.inventory.On("Reserve", ts.ctx, mock.Anything).Return(nil)
+
+		// 10.99 USD is 10.11 EUR at 0.92
+		expectedTotal := money.MustParse("20.22", money.EUR)
+
+		ts.orderRepo.On("Create", ts.ctx, mock.MatchedBy(func(order *entities.Order) bool {
+			return order.TotalAmount == expectedTotal &&
+				order.ExchangeRateID != nil && *order.ExchangeRateID == 3 &&
+				order.ExchangeRate.String() == "0.92"
+		})).Return(&entities.Order{
+			ID:           1,
+			UserID:       userID,
+			Status:       constants.OrderStatusPending,
+			TotalAmount:  expectedTotal,
+			ExchangeRate: money.MustParseRate("0.92"),
+		}, nil)
+		ts.orderRepo.On("CreateOrderItems", ts.ctx, int32(1), mock.MatchedBy(func(items []*entities.OrderItem) bool {
+			return len(items) == 1 &&
+				items[0].Price == money.MustParse("10.11", money.EUR) &&
+				items[0].ProductPrice == usd("10.99") &&
+				items[0].ExchangeRateID == nil
+		})).Return(nil)
+		ts.cartService.On("RemoveAllItems", ts.ctx, userID).Return(nil)
+
+		order, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), req)
+		require.NoError(t, err)
+		require.Equal(t, expectedTotal, order.TotalAmount)
+		require.Equal(t, "0.92", order.ExchangeRate.String())
+
+		ts.rateRepo.AssertExpectations(t)
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Create Order - Empty Cart", func(t *testing.T) {
+		// Setup new test suite instance
+		ts := setupTest(t)
+
+		userID := int32(1)
+		req := &dto.CreateOrderRequest{
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "12345",
+		}
+
+		// Setup expectations for empty cart
+		ts.cartService.On("GetItems", ts.ctx, userID).Return([]*cartDto.CartItemResponse{}, nil)
+
+		// Execute test
+		order, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), req)
+		require.Error(t, err)
+		require.Equal(t, errorx.ErrCartEmpty, err)
+		require.Nil(t, order)
 
 		ts.cartService.AssertExpectations(t)
 	})
@@ -233,7 +817,7 @@ func TestOrderService(t *testing.T) {
 				ID:        1,
 				ProductID: 1,
 				Quantity:  2,
-				Price:     10.99,
+				Price:     usd("10.99"),
 			},
 		}
 
@@ -246,6 +830,8 @@ func TestOrderService(t *testing.T) {
 
 		// Setup expectations
 		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.stubCurrentPrices(cartItems)
+		ts.inventory.On("Reserve", ts.ctx, mock.Anything).Return(nil)
 
 		// Mock order creation
 		ts.orderRepo.On("Create", ts.ctx, mock.Anything).Return(&entities.Order{
@@ -253,7 +839,7 @@ func TestOrderService(t *testing.T) {
 			UserID:          userID,
 			Status:          constants.OrderStatusPending,
 			PaymentStatus:   constants.PaymentStatusPending,
-			TotalAmount:     21.98, // 10.99 * 2
+			TotalAmount:     usd("21.98"), // 10.99 * 2
 			ShippingAddress: req.ShippingAddress,
 			ShippingCity:    req.ShippingCity,
 			ShippingCountry: req.ShippingCountry,
@@ -269,13 +855,1277 @@ func TestOrderService(t *testing.T) {
 		ts.cartService.On("RemoveAllItems", ts.ctx, userID).Return(errorx.ErrCannotCreateOrder)
 
 		// Execute test
-		order, err := ts.orderService.CreateOrder(ts.ctx, userID, req)
-		require.NoError(t, err) // Order should still be created even if cart cleanup fails
-		require.NotNil(t, order)
-		require.Equal(t, int32(1), order.ID)
+		order, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), req)
+		require.Error(t, err) // Cart cleanup failure rolls back the whole checkout
+		require.Equal(t, errorx.ErrCannotCreateOrder, err)
+		require.Nil(t, order)
 
 		// Verify expectations
 		ts.cartService.AssertExpectations(t)
 		ts.orderRepo.AssertExpectations(t)
+		ts.txManager.AssertNumberOfCalls(t, "WithTx", 1)
+	})
+
+	t.Run("Create Order - Out Of Stock", func(t *testing.T) {
+		ts := setupTest(t)
+
+		userID := int32(1)
+		cartItems := []*cartDto.CartItemResponse{
+			{ID: 1, ProductID: 1, Quantity: 2, Price: usd("10.99")},
+		}
+		outOfStock := core.ErrConflict.WithError(errorx.ErrProductOutOfStock.Error())
+
+		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.stubCurrentPrices(cartItems)
+		ts.orderRepo.On("Create", ts.ctx, mock.Anything).Return(&entities.Order{ID: 1, UserID: userID}, nil)
+		ts.orderRepo.On("CreateOrderItems", ts.ctx, int32(1), mock.Anything).Return(nil)
+		ts.inventory.On("Reserve", ts.ctx, []productDto.StockLine{{ProductID: 1, Quantity: 2}}).Return(outOfStock)
+
+		order, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), &dto.CreateOrderRequest{
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "12345",
+		})
+		require.Nil(t, order)
+		require.ErrorIs(t, err, outOfStock)
+
+		ts.cartService.AssertNotCalled(t, "RemoveAllItems", mock.Anything, mock.Anything)
+		ts.inventory.AssertExpectations(t)
+	})
+
+	t.Run("Create Order - Price Changed", func(t *testing.T) {
+		ts := setupTest(t)
+
+		userID := int32(1)
+		cartItems := []*cartDto.CartItemResponse{
+			{ID: 1, ProductID: 1, Quantity: 2, Price: usd("10.99")},
+			{ID: 2, ProductID: 2, Quantity: 1, Price: usd("20.99")},
+		}
+
+		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(1)).Return(&productDto.ProductResponse{ID: 1, Price: usd("12.49")}, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(2)).Return(&productDto.ProductResponse{ID: 2, Price: usd("20.99")}, nil)
+
+		order, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), &dto.CreateOrderRequest{
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "12345",
+		})
+		require.Nil(t, order)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrProductPriceChanged.Error(), appErr.Error())
+		require.Equal(t, []dto.PriceChangeResponse{
+			{ProductID: 1, OldPrice: usd("10.99"), NewPrice: usd("12.49")},
+		}, appErr.Details()["items"])
+
+		ts.orderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Create Order - Accept Price Changes", func(t *testing.T) {
+		ts := setupTest(t)
+
+		userID := int32(1)
+		cartItems := []*cartDto.CartItemResponse{
+			{ID: 1, ProductID: 1, Quantity: 2, Price: usd("10.99")},
+		}
+
+		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(1)).Return(&productDto.ProductResponse{ID: 1, Price: usd("12.49")}, nil)
+		ts.inventory.On("Reserve", ts.ctx, mock.Anything).Return(nil)
+		ts.orderRepo.On("Create", ts.ctx, mock.MatchedBy(func(order *entities.Order) bool {
+			return order.TotalAmount == usd("24.98")
+		})).Return(&entities.Order{ID: 1, UserID: userID, TotalAmount: usd("24.98")}, nil)
+		ts.orderRepo.On("CreateOrderItems", ts.ctx, int32(1), mock.MatchedBy(func(items []*entities.OrderItem) bool {
+			return len(items) == 1 && items[0].Price == usd("12.49")
+		})).Return(nil)
+		ts.cartService.On("RemoveAllItems", ts.ctx, userID).Return(nil)
+
+		order, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), &dto.CreateOrderRequest{
+			ShippingAddress:    "123 Test St",
+			ShippingCity:       "Test City",
+			ShippingCountry:    "Test Country",
+			ShippingZip:        "12345",
+			AcceptPriceChanges: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, usd("24.98"), order.TotalAmount)
+
+		recorded := ts.eventRepo.recordedEvents()
+		require.Len(t, recorded, 1)
+		require.Contains(t, recorded[0].Metadata, "price_changes")
+
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Create Order - Adds Shipping Below Free Threshold", func(t *testing.T) {
+		ts := setupTest(t)
+
+		userID := int32(1)
+		cartItems := []*cartDto.CartItemResponse{
+			{ID: 1, ProductID: 1, Quantity: 1, Price: usd("10.99")},
+		}
+
+		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.stubCurrentPrices(cartItems)
+		ts.inventory.On("Reserve", ts.ctx, mock.Anything).Return(nil)
+		ts.orderRepo.On("Create", ts.ctx, mock.MatchedBy(func(order *entities.Order) bool {
+			return order.ShippingAmount == usd("4.99") && order.TotalAmount == usd("15.98")
+		})).Return(&entities.Order{ID: 1, UserID: userID, TotalAmount: usd("15.98"), ShippingAmount: usd("4.99")}, nil)
+		ts.orderRepo.On("CreateOrderItems", ts.ctx, int32(1), mock.Anything).Return(nil)
+		ts.cartService.On("RemoveAllItems", ts.ctx, userID).Return(nil)
+
+		order, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), &dto.CreateOrderRequest{
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "12345",
+		})
+		require.NoError(t, err)
+		require.Equal(t, usd("4.99"), order.ShippingAmount)
+
+		recorded := ts.eventRepo.recordedEvents()
+		require.Len(t, recorded, 1)
+		require.Equal(t, "domestic", recorded[0].Metadata["shipping_zone"])
+
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Create Order - Shipping Country Not Served", func(t *testing.T) {
+		ts := setupTest(t)
+
+		userID := int32(1)
+		cartItems := []*cartDto.CartItemResponse{
+			{ID: 1, ProductID: 1, Quantity: 1, Price: usd("10.99")},
+		}
+
+		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.stubCurrentPrices(cartItems)
+
+		order, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), &dto.CreateOrderRequest{
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Elsewhere",
+			ShippingZip:     "12345",
+		})
+		require.Nil(t, order)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+		require.Equal(t, errorx.ErrInvalidShippingCountry.Error(), appErr.Error())
+
+		ts.orderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Create Order - Ships To An Address Book Entry", func(t *testing.T) {
+		ts := setupTest(t)
+
+		userID := int32(1)
+		cartItems := []*cartDto.CartItemResponse{
+			{ID: 1, ProductID: 1, Quantity: 1, Price: usd("10.99")},
+		}
+		addressID := int32(5)
+
+		ts.addresses.ExpectedCalls = nil
+		ts.addresses.On("GetAddress", ts.ctx, userID, addressID).Return(&userDto.AddressResponse{
+			ID: addressID, Address: "1 Book St", City: "Book City", Country: "Test Country", Zip: "12345",
+		}, nil)
+		ts.addresses.On("GetDefaultBillingAddress", ts.ctx, userID).Return(&userDto.AddressResponse{
+			ID: 6, Address: "2 Office Rd", City: "Office City", Country: "Billing Country", Zip: "54321",
+		}, nil)
+		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.stubCurrentPrices(cartItems)
+		ts.inventory.On("Reserve", ts.ctx, mock.Anything).Return(nil)
+		ts.orderRepo.On("Create", ts.ctx, mock.MatchedBy(func(order *entities.Order) bool {
+			return order.ShippingAddress == "1 Book St" &&
+				order.ShippingCity == "Book City" &&
+				order.ShippingCountry == "Test Country" &&
+				order.ShippingZip == "12345" &&
+				order.BillingAddress == "2 Office Rd" &&
+				order.BillingCity == "Office City" &&
+				order.BillingCountry == "Billing Country" &&
+				order.BillingZip == "54321"
+		})).Return(&entities.Order{ID: 1, UserID: userID}, nil)
+		ts.orderRepo.On("CreateOrderItems", ts.ctx, int32(1), mock.Anything).Return(nil)
+		ts.cartService.On("RemoveAllItems", ts.ctx, userID).Return(nil)
+
+		_, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), &dto.CreateOrderRequest{AddressID: &addressID})
+		require.NoError(t, err)
+
+		ts.addresses.AssertExpectations(t)
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Create Order - Falls Back To The Default Shipping Address", func(t *testing.T) {
+		ts := setupTest(t)
+
+		userID := int32(1)
+		cartItems := []*cartDto.CartItemResponse{
+			{ID: 1, ProductID: 1, Quantity: 1, Price: usd("10.99")},
+		}
+
+		ts.addresses.ExpectedCalls = nil
+		ts.addresses.On("GetDefaultShippingAddress", ts.ctx, userID).Return(&userDto.AddressResponse{
+			ID: 5, Address: "1 Home St", City: "Home City", Country: "Test Country", Zip: "12345",
+		}, nil)
+		ts.addresses.On("GetDefaultBillingAddress", ts.ctx, userID).Return(nil, nil)
+		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.stubCurrentPrices(cartItems)
+		ts.inventory.On("Reserve", ts.ctx, mock.Anything).Return(nil)
+		// Without a billing address the order is billed where it ships
+		ts.orderRepo.On("Create", ts.ctx, mock.MatchedBy(func(order *entities.Order) bool {
+			return order.ShippingAddress == "1 Home St" &&
+				order.ShippingCity == "Home City" &&
+				order.BillingAddress == "1 Home St" &&
+				order.BillingCity == "Home City" &&
+				order.BillingCountry == "Test Country" &&
+				order.BillingZip == "12345"
+		})).Return(&entities.Order{ID: 1, UserID: userID}, nil)
+		ts.orderRepo.On("CreateOrderItems", ts.ctx, int32(1), mock.Anything).Return(nil)
+		ts.cartService.On("RemoveAllItems", ts.ctx, userID).Return(nil)
+
+		_, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), &dto.CreateOrderRequest{})
+		require.NoError(t, err)
+
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Create Order - Rejects Unusable Addresses", func(t *testing.T) {
+		addressID := int32(5)
+		cases := map[string]*dto.CreateOrderRequest{
+			"no address and no default": {},
+			"partial shipping fields":   {ShippingAddress: "123 Test St", ShippingCity: "Test City"},
+			"address id with fields":    {AddressID: &addressID, ShippingCity: "Test City"},
+			"partial billing fields": {
+				ShippingAddress: "123 Test St",
+				ShippingCity:    "Test City",
+				ShippingCountry: "Test Country",
+				ShippingZip:     "12345",
+				BillingCity:     "Billing City",
+			},
+		}
+
+		for name, req := range cases {
+			t.Run(name, func(t *testing.T) {
+				ts := setupTest(t)
+
+				order, err := ts.orderService.CreateOrder(ts.ctx, customer(1), req)
+				require.Nil(t, order)
+
+				var appErr *core.DefaultError
+				require.ErrorAs(t, err, &appErr)
+				require.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+
+				ts.cartService.AssertNotCalled(t, "GetItems", mock.Anything, mock.Anything)
+				ts.orderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Create Order - Adds Tax On Top Of Prices", func(t *testing.T) {
+		ts := setupTest(t)
+
+		userID := int32(1)
+		cartItems := []*cartDto.CartItemResponse{
+			{ID: 1, ProductID: 1, Quantity: 2, Price: usd("10.00")},
+			{ID: 2, ProductID: 2, Quantity: 1, Price: usd("20.00")},
+		}
+
+		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(1)).
+			Return(&productDto.ProductResponse{ID: 1, Price: usd("10.00"), CategoryID: 1}, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(2)).
+			Return(&productDto.ProductResponse{ID: 2, Price: usd("20.00"), CategoryID: 2}, nil)
+		ts.inventory.On("Reserve", ts.ctx, mock.Anything).Return(nil)
+		ts.orderRepo.On("Create", ts.ctx, mock.MatchedBy(func(order *entities.Order) bool {
+			return order.TaxAmount == usd("3.00") && !order.TaxInclusive && order.TotalAmount == usd("43.00")
+		})).Return(&entities.Order{ID: 1, UserID: userID, TotalAmount: usd("43.00"), TaxAmount: usd("3.00")}, nil)
+		ts.orderRepo.On("CreateOrderItems", ts.ctx, int32(1), mock.MatchedBy(func(items []*entities.OrderItem) bool {
+			return len(items) == 2 &&
+				items[0].TaxRate == 10 && items[0].TaxAmount == usd("2.00") &&
+				items[1].TaxRate == 5 && items[1].TaxAmount == usd("1.00")
+		})).Return(nil)
+		ts.cartService.On("RemoveAllItems", ts.ctx, userID).Return(nil)
+
+		order, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), &dto.CreateOrderRequest{
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "90210",
+		})
+		require.NoError(t, err)
+		require.Equal(t, usd("3.00"), order.TaxAmount)
+		require.Equal(t, dto.TaxBreakdownResponse{
+			Rates: []dto.TaxRateResponse{
+				{Rate: 5, TaxableAmount: usd("20.00"), TaxAmount: usd("1.00")},
+				{Rate: 10, TaxableAmount: usd("20.00"), TaxAmount: usd("2.00")},
+			},
+		}, order.Tax)
+
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Create Order - Records Coupon Discount", func(t *testing.T) {
+		ts := setupTest(t)
+
+		userID := int32(1)
+		cartItems := []*cartDto.CartItemResponse{
+			{ID: 1, ProductID: 1, Quantity: 2, Price: usd("10.00")},
+			{ID: 2, ProductID: 2, Quantity: 1, Price: usd("20.00")},
+		}
+		discount := &promotionDto.DiscountResult{
+			CouponID:      7,
+			Code:          "SAVE10",
+			Type:          "PERCENTAGE",
+			LineDiscounts: []money.Money{usd("2.00"), usd("0")},
+			Amount:        usd("2.00"),
+		}
+
+		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(1)).
+			Return(&productDto.ProductResponse{ID: 1, Price: usd("10.00"), CategoryID: 1}, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(2)).
+			Return(&productDto.ProductResponse{ID: 2, Price: usd("20.00"), CategoryID: 2}, nil)
+		ts.applyCoupon(discount)
+		ts.promotions.On("Redeem", ts.ctx, userID, int32(1), discount).Return(nil)
+		ts.inventory.On("Reserve", ts.ctx, mock.Anything).Return(nil)
+		// Tax is worked out on the discounted line: 10% of 18.00 plus 5% of 20.00
+		ts.orderRepo.On("Create", ts.ctx, mock.MatchedBy(func(order *entities.Order) bool {
+			return order.DiscountAmount == usd("2.00") && *order.CouponCode == "SAVE10" &&
+				order.TaxAmount == usd("2.80") && order.TotalAmount == usd("40.80")
+		})).Return(&entities.Order{ID: 1, UserID: userID, TotalAmount: usd("40.80"), DiscountAmount: usd("2.00")}, nil)
+		ts.orderRepo.On("CreateOrderItems", ts.ctx, int32(1), mock.MatchedBy(func(items []*entities.OrderItem) bool {
+			return len(items) == 2 &&
+				items[0].DiscountAmount == usd("2.00") && items[0].TaxAmount == usd("1.80") &&
+				items[1].DiscountAmount.IsZero() && items[1].TaxAmount == usd("1.00")
+		})).Return(nil)
+		ts.cartService.On("RemoveAllItems", ts.ctx, userID).Return(nil)
+
+		order, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), &dto.CreateOrderRequest{
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "90210",
+		})
+		require.NoError(t, err)
+		require.Equal(t, usd("2.00"), order.DiscountAmount)
+		require.Equal(t, usd("2.00"), order.Items[0].DiscountAmount)
+
+		ts.orderRepo.AssertExpectations(t)
+		ts.promotions.AssertExpectations(t)
+	})
+
+	t.Run("Create Order - Coupon Used Up", func(t *testing.T) {
+		ts := setupTest(t)
+
+		userID := int32(1)
+		cartItems := []*cartDto.CartItemResponse{
+			{ID: 1, ProductID: 1, Quantity: 1, Price: usd("30.00")},
+		}
+		discount := &promotionDto.DiscountResult{
+			CouponID:      7,
+			Code:          "LAST ONE",
+			LineDiscounts: []money.Money{usd("5.00")},
+			Amount:        usd("5.00"),
+		}
+
+		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.stubCurrentPrices(cartItems)
+		ts.applyCoupon(discount)
+		ts.promotions.On("Redeem", ts.ctx, userID, int32(1), discount).
+			Return(core.ErrConflict.WithError(errorx.ErrCouponUsageLimitReached.Error()))
+		ts.inventory.On("Reserve", ts.ctx, mock.Anything).Return(nil)
+		ts.orderRepo.On("Create", ts.ctx, mock.Anything).Return(&entities.Order{ID: 1, UserID: userID}, nil)
+		ts.orderRepo.On("CreateOrderItems", ts.ctx, int32(1), mock.Anything).Return(nil)
+
+		_, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), &dto.CreateOrderRequest{
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "12345",
+		})
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrCouponUsageLimitReached.Error(), appErr.Error())
+
+		ts.cartService.AssertNotCalled(t, "RemoveAllItems", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Create Order - Below Minimum Amount", func(t *testing.T) {
+		ts := setupTestWithRules(t, config.OrderRulesConfig{
+			MinimumAmounts: []config.MinimumAmountConfig{{Country: "test country", Amount: usd("50")}},
+		})
+
+		userID := int32(1)
+		cartItems := []*cartDto.CartItemResponse{
+			{ID: 1, ProductID: 1, Quantity: 3, Price: usd("10.00")},
+		}
+
+		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.stubCurrentPrices(cartItems)
+
+		order, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), &dto.CreateOrderRequest{
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "12345",
+		})
+		require.Nil(t, order)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+		require.Equal(t, errorx.ErrMinimumOrderAmountNotMet.Error(), appErr.Error())
+
+		ts.orderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Create Order - Daily Order Limit Reached", func(t *testing.T) {
+		ts := setupTestWithRules(t, config.OrderRulesConfig{MaxOrdersPerDay: 2})
+
+		userID := int32(1)
+		cartItems := []*cartDto.CartItemResponse{
+			{ID: 1, ProductID: 1, Quantity: 1, Price: usd("30.00")},
+		}
+
+		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.stubCurrentPrices(cartItems)
+		ts.orderRepo.On("CountUserOrdersSince", ts.ctx, userID, mock.Anything).Return(int64(2), nil)
+
+		_, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), &dto.CreateOrderRequest{
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "12345",
+		})
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+		require.Equal(t, errorx.ErrMaximumDailyOrdersExceeded.Error(), appErr.Error())
+
+		ts.orderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Create Order - Daily Order Limit Reached While Waiting", func(t *testing.T) {
+		ts := setupTestWithRules(t, config.OrderRulesConfig{MaxOrdersPerDay: 2})
+
+		userID := int32(1)
+		cartItems := []*cartDto.CartItemResponse{
+			{ID: 1, ProductID: 1, Quantity: 1, Price: usd("30.00")},
+		}
+
+		ts.cartService.On("GetItems", ts.ctx, userID).Return(cartItems, nil)
+		ts.stubCurrentPrices(cartItems)
+		// Another checkout of the user placed its order before this one got
+		// the lock
+		ts.orderRepo.On("CountUserOrdersSince", ts.ctx, userID, mock.Anything).Return(int64(1), nil).Once()
+		ts.orderRepo.On("CountUserOrdersSince", ts.ctx, userID, mock.Anything).Return(int64(2), nil).Once()
+
+		_, err := ts.orderService.CreateOrder(ts.ctx, customer(userID), &dto.CreateOrderRequest{
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "12345",
+		})
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, errorx.ErrMaximumDailyOrdersExceeded.Error(), appErr.Error())
+
+		ts.orderRepo.AssertCalled(t, "LockUserOrders", mock.Anything, userID)
+		ts.orderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Quote Shipping", func(t *testing.T) {
+		ts := setupTest(t)
+		freeAbove := usd("20")
+
+		userID := int32(1)
+		ts.cartService.On("GetItems", ts.ctx, userID).Return([]*cartDto.CartItemResponse{
+			{ID: 1, ProductID: 1, Quantity: 3, Price: usd("5.00")},
+		}, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(1)).
+			Return(&productDto.ProductResponse{ID: 1, Price: usd("5.00"), Weight: 250}, nil)
+
+		quote, err := ts.orderService.QuoteShipping(ts.ctx, customer(userID), &dto.ShippingQuoteRequest{
+			ShippingCountry: "test country",
+		})
+		require.NoError(t, err)
+		require.Equal(t, &dto.ShippingQuoteResponse{
+			Zone:           "domestic",
+			Currency:       "USD",
+			Subtotal:       usd("15.00"),
+			ShippingAmount: usd("4.99"),
+			DiscountAmount: usd("0"),
+			TaxAmount:      usd("0"),
+			TotalAmount:    usd("19.99"),
+			FreeAbove:      &freeAbove,
+			Weight:         750,
+		}, quote)
+
+		ts.orderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Get Order - Owner", func(t *testing.T) {
+		ts := setupTest(t)
+
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(&entities.Order{ID: 1, UserID: 1}, nil)
+
+		order, err := ts.orderService.GetOrder(ts.ctx, customer(1), 1)
+		require.NoError(t, err)
+		require.Equal(t, int32(1), order.ID)
+	})
+
+	t.Run("Get Order - Admin Sees Any Order", func(t *testing.T) {
+		ts := setupTest(t)
+
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(&entities.Order{ID: 1, UserID: 1}, nil)
+
+		order, err := ts.orderService.GetOrder(ts.ctx, entities.Caller{UserID: 9, Role: common.RoleAdmin}, 1)
+		require.NoError(t, err)
+		require.Equal(t, int32(1), order.ID)
+	})
+
+	t.Run("Get Order - Other User Gets Not Found", func(t *testing.T) {
+		ts := setupTest(t)
+
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(&entities.Order{ID: 1, UserID: 1}, nil)
+
+		order, err := ts.orderService.GetOrder(ts.ctx, customer(2), 1)
+		require.Nil(t, order)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusNotFound, appErr.StatusCode())
+		require.Equal(t, errorx.ErrOrderNotFound.Error(), appErr.Error())
+	})
+
+	t.Run("Get Order - Missing Order", func(t *testing.T) {
+		ts := setupTest(t)
+
+		ts.orderRepo.On("GetByID", ts.ctx, int32(99)).Return(nil, errorx.ErrOrderNotFound)
+
+		order, err := ts.orderService.GetOrder(ts.ctx, customer(1), 99)
+		require.Nil(t, order)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusNotFound, appErr.StatusCode())
+	})
+
+	t.Run("Update Order Status - Success", func(t *testing.T) {
+		ts := setupTest(t)
+
+		orderID := int32(1)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, orderID).Return(&entities.Order{
+			ID:            orderID,
+			Status:        constants.OrderStatusPending,
+			PaymentStatus: constants.PaymentStatusPending,
+		}, nil)
+		ts.orderRepo.On("UpdateStatus", ts.ctx, orderID, constants.OrderStatusConfirmed).Return(nil)
+		ts.orderRepo.On("GetByID", ts.ctx, orderID).Return(&entities.Order{
+			ID:            orderID,
+			Status:        constants.OrderStatusConfirmed,
+			PaymentStatus: constants.PaymentStatusPending,
+		}, nil)
+
+		order, err := ts.orderService.UpdateOrderStatus(ts.ctx, admin, orderID, &dto.UpdateOrderStatusRequest{
+			Status: constants.OrderStatusConfirmed.String(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, constants.OrderStatusConfirmed.String(), order.Status)
+
+		recorded := ts.eventRepo.recordedEvents()
+		require.Len(t, recorded, 1)
+		require.Equal(t, constants.OrderEventStatusChanged, recorded[0].Type)
+		require.Equal(t, constants.OrderStatusPending.String(), *recorded[0].FromStatus)
+		require.Equal(t, constants.OrderStatusConfirmed.String(), *recorded[0].ToStatus)
+		require.Equal(t, admin.UserID, *recorded[0].ActorID)
+		require.Equal(t, common.RoleAdmin, recorded[0].ActorRole)
+
+		published := ts.outbox.recordedEvents()
+		require.Len(t, published, 1)
+		changed := published[0].(*events.OrderStatusChanged)
+		require.Equal(t, constants.OrderStatusPending.String(), changed.From)
+		require.Equal(t, constants.OrderStatusConfirmed.String(), changed.To)
+
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Update Order Status - Shipped Follows Shipments", func(t *testing.T) {
+		ts := setupTest(t)
+
+		orderID := int32(1)
+		for _, next := range []constants.OrderStatus{constants.OrderStatusShipped, constants.OrderStatusPartiallyShipped} {
+			_, err := ts.orderService.UpdateOrderStatus(ts.ctx, admin, orderID, &dto.UpdateOrderStatusRequest{
+				Status: next.String(),
+			})
+
+			var appErr *core.DefaultError
+			require.ErrorAs(t, err, &appErr)
+			require.Equal(t, http.StatusConflict, appErr.StatusCode())
+			require.Equal(t, errorx.ErrStatusFollowsShipments.Error(), appErr.Error())
+		}
+
+		ts.orderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+		ts.inventory.AssertNotCalled(t, "Commit", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Update Order Status - Admin Cancel Settles The Payment", func(t *testing.T) {
+		ts := setupTest(t)
+
+		var published *events.OrderCancelled
+		ts.eventBus.Subscribe(events.OrderCancelledEvent, func(ctx context.Context, event eventbus.Event) error {
+			published = event.(*events.OrderCancelled)
+			return nil
+		})
+
+		orderID := int32(1)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, orderID).Return(&entities.Order{
+			ID:            orderID,
+			UserID:        1,
+			Status:        constants.OrderStatusProcessing,
+			PaymentStatus: constants.PaymentStatusPaid,
+		}, nil)
+		ts.orderRepo.On("GetItems", ts.ctx, orderID).Return([]*entities.OrderItem{
+			{ProductID: 1, Quantity: 2},
+		}, nil)
+		ts.refunds.On("RefundCancelledOrder", ts.ctx, admin, mock.Anything, "out of stock at the warehouse").
+			Return(&entities.Refund{ID: 5, Status: constants.RefundStatusApproved}, nil)
+		ts.refunds.On("ProcessRefund", ts.ctx, admin, int32(5)).Return(&dto.RefundResponse{ID: 5}, nil)
+		ts.orderRepo.On("Cancel", ts.ctx, mock.MatchedBy(func(order *entities.Order) bool {
+			return order.Status == constants.OrderStatusCancelled &&
+				order.PaymentStatus == constants.PaymentStatusRefunded &&
+				order.CancelReason != nil && *order.CancelReason == "out of stock at the warehouse" &&
+				order.CancelledAt != nil
+		})).Return(nil)
+		ts.orderRepo.On("GetByID", ts.ctx, orderID).Return(&entities.Order{ID: orderID, Status: constants.OrderStatusCancelled}, nil)
+
+		_, err := ts.orderService.UpdateOrderStatus(ts.ctx, admin, orderID, &dto.UpdateOrderStatusRequest{
+			Status: constants.OrderStatusCancelled.String(),
+			Reason: "out of stock at the warehouse",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, published)
+		require.Equal(t, "out of stock at the warehouse", published.Reason)
+		require.Equal(t, []events.OrderItemQuantity{{ProductID: 1, Quantity: 2}}, published.Items)
+
+		recorded := ts.eventRepo.recordedEvents()
+		require.Len(t, recorded, 1)
+		require.Equal(t, constants.OrderEventCancelled, recorded[0].Type)
+		require.Equal(t, admin.UserID, *recorded[0].ActorID)
+
+		ts.refunds.AssertExpectations(t)
+		ts.orderRepo.AssertExpectations(t)
+		ts.orderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Update Order Status - Admin Cancel Without Reason", func(t *testing.T) {
+		ts := setupTest(t)
+
+		orderID := int32(1)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, orderID).Return(&entities.Order{
+			ID:            orderID,
+			UserID:        1,
+			Status:        constants.OrderStatusConfirmed,
+			PaymentStatus: constants.PaymentStatusPending,
+		}, nil)
+		ts.orderRepo.On("GetItems", ts.ctx, orderID).Return([]*entities.OrderItem{}, nil)
+		ts.orderRepo.On("Cancel", ts.ctx, mock.MatchedBy(func(order *entities.Order) bool {
+			return order.PaymentStatus == constants.PaymentStatusVoided &&
+				order.CancelReason != nil && *order.CancelReason == adminCancelReason
+		})).Return(nil)
+		ts.orderRepo.On("GetByID", ts.ctx, orderID).Return(&entities.Order{ID: orderID, Status: constants.OrderStatusCancelled}, nil)
+
+		_, err := ts.orderService.UpdateOrderStatus(ts.ctx, admin, orderID, &dto.UpdateOrderStatusRequest{
+			Status: constants.OrderStatusCancelled.String(),
+		})
+		require.NoError(t, err)
+
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Update Order Status - Invalid Transition", func(t *testing.T) {
+		ts := setupTest(t)
+
+		orderID := int32(1)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, orderID).Return(&entities.Order{
+			ID:            orderID,
+			Status:        constants.OrderStatusPending,
+			PaymentStatus: constants.PaymentStatusPending,
+		}, nil)
+
+		order, err := ts.orderService.UpdateOrderStatus(ts.ctx, admin, orderID, &dto.UpdateOrderStatusRequest{
+			Status: constants.OrderStatusDelivered.String(),
+		})
+		require.Nil(t, order)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrInvalidStatusTransition.Error(), appErr.Error())
+
+		ts.orderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Update Order Status - Unknown Status", func(t *testing.T) {
+		ts := setupTest(t)
+
+		order, err := ts.orderService.UpdateOrderStatus(ts.ctx, admin, 1, &dto.UpdateOrderStatusRequest{
+			Status: "LOST",
+		})
+		require.Nil(t, order)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+	})
+
+	t.Run("Update Order Status - Order Not Found", func(t *testing.T) {
+		ts := setupTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(99)).Return(nil, errorx.ErrOrderNotFound)
+
+		order, err := ts.orderService.UpdateOrderStatus(ts.ctx, admin, 99, &dto.UpdateOrderStatusRequest{
+			Status: constants.OrderStatusConfirmed.String(),
+		})
+		require.Nil(t, order)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusNotFound, appErr.StatusCode())
+	})
+
+	t.Run("Update Payment Status - Success", func(t *testing.T) {
+		ts := setupTest(t)
+
+		orderID := int32(1)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, orderID).Return(&entities.Order{
+			ID:            orderID,
+			Status:        constants.OrderStatusConfirmed,
+			PaymentStatus: constants.PaymentStatusPending,
+		}, nil)
+		ts.orderRepo.On("UpdatePaymentStatus", ts.ctx, orderID, constants.PaymentStatusPaid).Return(nil)
+		ts.orderRepo.On("GetByID", ts.ctx, orderID).Return(&entities.Order{
+			ID:            orderID,
+			Status:        constants.OrderStatusConfirmed,
+			PaymentStatus: constants.PaymentStatusPaid,
+		}, nil)
+
+		order, err := ts.orderService.UpdatePaymentStatus(ts.ctx, admin, orderID, &dto.UpdatePaymentStatusRequest{
+			PaymentStatus: constants.PaymentStatusPaid.String(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, constants.PaymentStatusPaid.String(), order.PaymentStatus)
+
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Update Payment Status - Refunded Is Final", func(t *testing.T) {
+		ts := setupTest(t)
+
+		orderID := int32(1)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, orderID).Return(&entities.Order{
+			ID:            orderID,
+			Status:        constants.OrderStatusRefunded,
+			PaymentStatus: constants.PaymentStatusRefunded,
+		}, nil)
+
+		order, err := ts.orderService.UpdatePaymentStatus(ts.ctx, admin, orderID, &dto.UpdatePaymentStatusRequest{
+			PaymentStatus: constants.PaymentStatusPaid.String(),
+		})
+		require.Nil(t, order)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrInvalidPaymentStatusTransition.Error(), appErr.Error())
+	})
+
+	t.Run("Update Payment Status - Refunded Needs A Refund", func(t *testing.T) {
+		ts := setupTest(t)
+
+		order, err := ts.orderService.UpdatePaymentStatus(ts.ctx, admin, 1, &dto.UpdatePaymentStatusRequest{
+			PaymentStatus: constants.PaymentStatusRefunded.String(),
+		})
+		require.Nil(t, order)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrRefundNeedsRefundFlow.Error(), appErr.Error())
+
+		ts.orderRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Cancel Order - Success", func(t *testing.T) {
+		ts := setupTest(t)
+
+		userID := int32(1)
+		orderID := int32(1)
+
+		var published *events.OrderCancelled
+		ts.eventBus.Subscribe(events.OrderCancelledEvent, func(ctx context.Context, event eventbus.Event) error {
+			published = event.(*events.OrderCancelled)
+			return nil
+		})
+
+		intentID := "pi_fake_1"
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, orderID).Return(&entities.Order{
+			ID:              orderID,
+			UserID:          userID,
+			Status:          constants.OrderStatusPending,
+			PaymentStatus:   constants.PaymentStatusPending,
+			PaymentIntentID: &intentID,
+		}, nil)
+		ts.orderRepo.On("GetItems", ts.ctx, orderID).Return([]*entities.OrderItem{
+			{ID: 1, OrderID: orderID, ProductID: 7, Quantity: 3},
+		}, nil)
+		ts.provider.On("CancelIntent", ts.ctx, intentID).Return(nil)
+		ts.orderRepo.On("Cancel", ts.ctx, mock.MatchedBy(func(order *entities.Order) bool {
+			return order.Status == constants.OrderStatusCancelled &&
+				order.PaymentStatus == constants.PaymentStatusVoided &&
+				order.CancelReason != nil && *order.CancelReason == "changed my mind" &&
+				order.CancelledAt != nil
+		})).Return(nil)
+		ts.orderRepo.On("GetByID", ts.ctx, orderID).Return(&entities.Order{
+			ID:            orderID,
+			UserID:        userID,
+			Status:        constants.OrderStatusCancelled,
+			PaymentStatus: constants.PaymentStatusVoided,
+		}, nil)
+
+		order, err := ts.orderService.CancelOrder(ts.ctx, customer(userID), orderID, &dto.CancelOrderRequest{Reason: "changed my mind"})
+		require.NoError(t, err)
+		require.Equal(t, constants.OrderStatusCancelled.String(), order.Status)
+
+		require.NotNil(t, published)
+		require.Equal(t, orderID, published.OrderID)
+		require.Equal(t, constants.PaymentStatusPending, published.PreviousPaymentStatus)
+		require.Equal(t, constants.PaymentStatusVoided, published.PaymentStatus)
+		require.Equal(t, []events.OrderItemQuantity{{ProductID: 7, Quantity: 3}}, published.Items)
+
+		recorded := ts.eventRepo.recordedEvents()
+		require.Len(t, recorded, 1)
+		require.Equal(t, constants.OrderEventCancelled, recorded[0].Type)
+		require.Equal(t, "changed my mind", recorded[0].Metadata["reason"])
+		require.Equal(t, userID, *recorded[0].ActorID)
+
+		ts.orderRepo.AssertExpectations(t)
+		ts.provider.AssertExpectations(t)
+	})
+
+	t.Run("Cancel Order - Paid Order Is Refunded", func(t *testing.T) {
+		ts := setupTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(&entities.Order{
+			ID:            1,
+			UserID:        1,
+			Status:        constants.OrderStatusConfirmed,
+			PaymentStatus: constants.PaymentStatusPaid,
+		}, nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(1)).Return([]*entities.OrderItem{}, nil)
+		// The refund is recorded before the payment is marked refunded and
+		// paid out once the cancellation committed
+		ts.refunds.On("RefundCancelledOrder", ts.ctx, customer(1), mock.MatchedBy(func(order *entities.Order) bool {
+			return order.PaymentStatus == constants.PaymentStatusPaid
+		}), "too slow").Return(&entities.Refund{ID: 5, Status: constants.RefundStatusApproved}, nil)
+		ts.orderRepo.On("Cancel", ts.ctx, mock.MatchedBy(func(order *entities.Order) bool {
+			return order.PaymentStatus == constants.PaymentStatusRefunded
+		})).Return(nil)
+		ts.refunds.On("ProcessRefund", ts.ctx, customer(1), int32(5)).Return(&dto.RefundResponse{ID: 5}, nil)
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(&entities.Order{ID: 1}, nil)
+
+		_, err := ts.orderService.CancelOrder(ts.ctx, customer(1), 1, &dto.CancelOrderRequest{Reason: "too slow"})
+		require.NoError(t, err)
+
+		ts.refunds.AssertExpectations(t)
+		ts.orderRepo.AssertExpectations(t)
+		ts.provider.AssertNotCalled(t, "CancelIntent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Cancel Order - Payout Failure Keeps The Cancellation", func(t *testing.T) {
+		ts := setupTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(&entities.Order{
+			ID:            1,
+			UserID:        1,
+			Status:        constants.OrderStatusConfirmed,
+			PaymentStatus: constants.PaymentStatusPaid,
+		}, nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(1)).Return([]*entities.OrderItem{}, nil)
+		ts.refunds.On("RefundCancelledOrder", ts.ctx, mock.Anything, mock.Anything, mock.Anything).
+			Return(&entities.Refund{ID: 5, Status: constants.RefundStatusApproved}, nil)
+		ts.orderRepo.On("Cancel", ts.ctx, mock.Anything).Return(nil)
+		ts.refunds.On("ProcessRefund", ts.ctx, mock.Anything, int32(5)).
+			Return(nil, core.ErrInternalServerError.WithError(errorx.ErrPaymentFailed.Error()))
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(&entities.Order{ID: 1, Status: constants.OrderStatusCancelled}, nil)
+
+		order, err := ts.orderService.CancelOrder(ts.ctx, customer(1), 1, &dto.CancelOrderRequest{Reason: "too slow"})
+		require.NoError(t, err)
+		require.Equal(t, constants.OrderStatusCancelled.String(), order.Status)
+
+		ts.refunds.AssertExpectations(t)
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Cancel Order - Intent Left Open Keeps The Cancellation", func(t *testing.T) {
+		ts := setupTest(t)
+
+		intentID := "pi_fake_1"
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(&entities.Order{
+			ID:              1,
+			UserID:          1,
+			Status:          constants.OrderStatusPending,
+			PaymentStatus:   constants.PaymentStatusPending,
+			PaymentIntentID: &intentID,
+		}, nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(1)).Return([]*entities.OrderItem{}, nil)
+		ts.orderRepo.On("Cancel", ts.ctx, mock.Anything).Return(nil)
+		ts.provider.On("CancelIntent", ts.ctx, intentID).Return(errors.New("provider unavailable"))
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(&entities.Order{ID: 1, Status: constants.OrderStatusCancelled}, nil)
+
+		_, err := ts.orderService.CancelOrder(ts.ctx, customer(1), 1, &dto.CancelOrderRequest{Reason: "changed my mind"})
+		require.NoError(t, err)
+
+		ts.orderRepo.AssertExpectations(t)
+		ts.provider.AssertExpectations(t)
+	})
+
+	t.Run("Cancel Order - Refund Record Failure Keeps The Order", func(t *testing.T) {
+		ts := setupTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(&entities.Order{
+			ID:            1,
+			UserID:        1,
+			Status:        constants.OrderStatusConfirmed,
+			PaymentStatus: constants.PaymentStatusPaid,
+		}, nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(1)).Return([]*entities.OrderItem{}, nil)
+		ts.refunds.On("RefundCancelledOrder", ts.ctx, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errorx.ErrCannotCreateRefund)
+
+		_, err := ts.orderService.CancelOrder(ts.ctx, customer(1), 1, &dto.CancelOrderRequest{Reason: "too slow"})
+		require.ErrorIs(t, err, errorx.ErrCannotCreateRefund)
+
+		ts.orderRepo.AssertNotCalled(t, "Cancel", mock.Anything, mock.Anything)
+		ts.refunds.AssertNotCalled(t, "ProcessRefund", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Cancel Order - Not Owner", func(t *testing.T) {
+		ts := setupTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(&entities.Order{
+			ID:            1,
+			UserID:        2,
+			Status:        constants.OrderStatusPending,
+			PaymentStatus: constants.PaymentStatusPending,
+		}, nil)
+
+		order, err := ts.orderService.CancelOrder(ts.ctx, customer(1), 1, &dto.CancelOrderRequest{Reason: "not mine"})
+		require.Nil(t, order)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusNotFound, appErr.StatusCode())
+		require.Equal(t, errorx.ErrOrderNotFound.Error(), appErr.Error())
+
+		ts.orderRepo.AssertNotCalled(t, "Cancel", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Cancel Order - Already Cancelled", func(t *testing.T) {
+		ts := setupTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(&entities.Order{
+			ID:            1,
+			UserID:        1,
+			Status:        constants.OrderStatusCancelled,
+			PaymentStatus: constants.PaymentStatusVoided,
+		}, nil)
+
+		_, err := ts.orderService.CancelOrder(ts.ctx, customer(1), 1, &dto.CancelOrderRequest{Reason: "again"})
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrOrderAlreadyCancelled.Error(), appErr.Error())
+	})
+
+	t.Run("Cancel Order - Already Shipped", func(t *testing.T) {
+		ts := setupTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(&entities.Order{
+			ID:            1,
+			UserID:        1,
+			Status:        constants.OrderStatusShipped,
+			PaymentStatus: constants.PaymentStatusPaid,
+		}, nil)
+
+		_, err := ts.orderService.CancelOrder(ts.ctx, customer(1), 1, &dto.CancelOrderRequest{Reason: "too late"})
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrOrderNotCancellable.Error(), appErr.Error())
+	})
+
+	t.Run("Get Order Timeline - Owner", func(t *testing.T) {
+		ts := setupTest(t)
+
+		toStatus := constants.OrderStatusPending.String()
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(&entities.Order{ID: 1, UserID: 1}, nil)
+		ts.eventRepo.On("GetByOrderID", ts.ctx, int32(1)).Return([]*entities.OrderEvent{
+			{ID: 1, OrderID: 1, Type: constants.OrderEventCreated, ToStatus: &toStatus, ActorRole: common.RoleUser},
+		}, nil)
+
+		timeline, err := ts.orderService.GetOrderTimeline(ts.ctx, customer(1), 1)
+		require.NoError(t, err)
+		require.Len(t, timeline, 1)
+		require.Equal(t, constants.OrderEventCreated.String(), timeline[0].Type)
+	})
+
+	t.Run("Get Order Timeline - Other User Gets Not Found", func(t *testing.T) {
+		ts := setupTest(t)
+
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(&entities.Order{ID: 1, UserID: 1}, nil)
+
+		timeline, err := ts.orderService.GetOrderTimeline(ts.ctx, customer(2), 1)
+		require.Nil(t, timeline)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusNotFound, appErr.StatusCode())
+		ts.eventRepo.AssertNotCalled(t, "GetByOrderID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Reorder - Reports Added, Adjusted And Skipped Items", func(t *testing.T) {
+		ts := setupTest(t)
+
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(&entities.Order{ID: 1, UserID: 1, Items: []*entities.OrderItem{
+			{ID: 10, ProductID: 1, Quantity: 2, Price: usd("9.99"), ProductName: "Mug"},
+			{ID: 11, ProductID: 2, Quantity: 5, Price: usd("19.99"), ProductName: "Teapot"},
+			{ID: 12, ProductID: 3, Quantity: 1, Price: usd("4.99"), ProductName: "Spoon"},
+			{ID: 13, ProductID: 4, Quantity: 1, Price: usd("2.99"), ProductName: "Saucer"},
+			{ID: 14, ProductID: 5, Quantity: 9, Price: usd("1.99"), ProductName: "Napkin"},
+		}}, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(1)).Return(&productDto.ProductResponse{ID: 1, Name: "Mug", Price: usd("10.99"), Stock: 10}, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(2)).Return(&productDto.ProductResponse{ID: 2, Name: "Teapot", Price: usd("19.99"), Stock: 3}, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(3)).Return(nil, errorx.ErrProductNotFound)
+		ts.productService.On("GetProduct", ts.ctx, int32(4)).Return(&productDto.ProductResponse{ID: 4, Name: "Saucer", Price: usd("2.99")}, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(5)).Return(&productDto.ProductResponse{ID: 5, Name: "Napkin", Price: usd("1.99"), Stock: 100}, nil)
+		ts.cartService.On("GetItems", ts.ctx, int32(1)).Return([]*cartDto.CartItemResponse{}, nil)
+		ts.cartService.On("AddItem", ts.ctx, int32(1), &cartDto.CartItemRequest{ProductID: 1, Quantity: 2}).
+			Return(&cartDto.CartItemResponse{ProductID: 1, Quantity: 2, Price: usd("10.99")}, nil)
+		ts.cartService.On("AddItem", ts.ctx, int32(1), &cartDto.CartItemRequest{ProductID: 2, Quantity: 3}).
+			Return(&cartDto.CartItemResponse{ProductID: 2, Quantity: 3, Price: usd("19.99")}, nil)
+		ts.cartService.On("AddItem", ts.ctx, int32(1), &cartDto.CartItemRequest{ProductID: 5, Quantity: 9}).
+			Return(nil, core.ErrBadRequest.WithError(errorx.ErrMaximumOrderQuantityExceeded.Error()))
+
+		summary, err := ts.orderService.Reorder(ts.ctx, customer(1), 1)
+		require.NoError(t, err)
+		require.Equal(t, int32(1), summary.OrderID)
+		require.Len(t, summary.Items, 5)
+		ts.txManager.AssertNumberOfCalls(t, "WithTx", 1)
+
+		require.Equal(t, constants.ReorderItemAdded.String(), summary.Items[0].Status)
+		require.Equal(t, int32(2), summary.Items[0].AddedQuantity)
+		require.Equal(t, usd("10.99"), *summary.Items[0].Price)
+
+		require.Equal(t, constants.ReorderItemAdjusted.String(), summary.Items[1].Status)
+		require.Equal(t, int32(5), summary.Items[1].OrderedQuantity)
+		require.Equal(t, int32(3), summary.Items[1].AddedQuantity)
+
+		require.Equal(t, constants.ReorderItemSkipped.String(), summary.Items[2].Status)
+		require.Equal(t, "Spoon", summary.Items[2].ProductName)
+		require.Equal(t, errorx.ErrProductNotFound.Error(), summary.Items[2].Reason)
+
+		require.Equal(t, constants.ReorderItemSkipped.String(), summary.Items[3].Status)
+		require.Equal(t, errorx.ErrProductOutOfStock.Error(), summary.Items[3].Reason)
+
+		require.Equal(t, constants.ReorderItemSkipped.String(), summary.Items[4].Status)
+		require.Equal(t, errorx.ErrMaximumOrderQuantityExceeded.Error(), summary.Items[4].Reason)
+		require.Zero(t, summary.Items[4].AddedQuantity)
+		require.Nil(t, summary.Items[4].Price)
+	})
+
+	t.Run("Reorder - Store Failure Fails The Reorder", func(t *testing.T) {
+		ts := setupTest(t)
+
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(&entities.Order{ID: 1, UserID: 1, Items: []*entities.OrderItem{
+			{ID: 10, ProductID: 1, Quantity: 2, Price: usd("9.99")},
+		}}, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(1)).Return(&productDto.ProductResponse{ID: 1, Price: usd("10.99"), Stock: 10}, nil)
+		ts.cartService.On("GetItems", ts.ctx, int32(1)).Return([]*cartDto.CartItemResponse{}, nil)
+		ts.cartService.On("AddItem", ts.ctx, int32(1), mock.Anything).Return(nil, errors.New("connection reset"))
+
+		summary, err := ts.orderService.Reorder(ts.ctx, customer(1), 1)
+		require.Nil(t, summary)
+		require.EqualError(t, err, "connection reset")
+	})
+
+	t.Run("Reorder - Counts What The Cart Already Holds", func(t *testing.T) {
+		ts := setupTest(t)
+
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(&entities.Order{ID: 1, UserID: 1, Items: []*entities.OrderItem{
+			{ID: 10, ProductID: 1, Quantity: 4, Price: usd("9.99"), ProductName: "Mug"},
+			{ID: 11, ProductID: 2, Quantity: 1, Price: usd("19.99"), ProductName: "Teapot"},
+		}}, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(1)).Return(&productDto.ProductResponse{ID: 1, Name: "Mug", Price: usd("9.99"), Stock: 5}, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(2)).Return(&productDto.ProductResponse{ID: 2, Name: "Teapot", Price: usd("19.99"), Stock: 2}, nil)
+		ts.cartService.On("GetItems", ts.ctx, int32(1)).Return([]*cartDto.CartItemResponse{
+			{ProductID: 1, Quantity: 3},
+			{ProductID: 2, Quantity: 2},
+		}, nil)
+		ts.cartService.On("AddItem", ts.ctx, int32(1), &cartDto.CartItemRequest{ProductID: 1, Quantity: 2}).
+			Return(&cartDto.CartItemResponse{ProductID: 1, Quantity: 5, Price: usd("9.99")}, nil)
+
+		summary, err := ts.orderService.Reorder(ts.ctx, customer(1), 1)
+		require.NoError(t, err)
+		require.Len(t, summary.Items, 2)
+
+		require.Equal(t, constants.ReorderItemAdjusted.String(), summary.Items[0].Status)
+		require.Equal(t, int32(2), summary.Items[0].AddedQuantity)
+		require.Equal(t, "only 5 left in stock, 3 already in the cart", summary.Items[0].Reason)
+
+		require.Equal(t, constants.ReorderItemSkipped.String(), summary.Items[1].Status)
+		require.Equal(t, "the cart already holds the 2 left in stock", summary.Items[1].Reason)
+
+		ts.cartService.AssertNumberOfCalls(t, "AddItem", 1)
+	})
+
+	t.Run("Reorder - Only The Owner Can Reorder", func(t *testing.T) {
+		for _, caller := range []entities.Caller{customer(2), admin} {
+			ts := setupTest(t)
+
+			ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(&entities.Order{ID: 1, UserID: 1, Items: []*entities.OrderItem{
+				{ID: 10, ProductID: 1, Quantity: 2, Price: usd("9.99")},
+			}}, nil)
+
+			summary, err := ts.orderService.Reorder(ts.ctx, caller, 1)
+			require.Nil(t, summary)
+
+			var appErr *core.DefaultError
+			require.ErrorAs(t, err, &appErr)
+			require.Equal(t, http.StatusNotFound, appErr.StatusCode())
+			ts.cartService.AssertNotCalled(t, "AddItem", mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("Get User Orders - Filters", func(t *testing.T) {
+		ts := setupTest(t)
+
+		minTotal, productID := "25", int32(3)
+		req := &dto.OrderListRequest{
+			Status:    constants.OrderStatusDelivered.String(),
+			From:      "2026-01-01",
+			To:        "2026-01-31",
+			MinTotal:  &minTotal,
+			Currency:  "EUR",
+			ProductID: &productID,
+			SortBy:    constants.OrderSortTotalDesc.String(),
+			Include:   "items",
+			Exclude:   "items",
+		}
+		paging := &core.Paging{Page: 1, Limit: 10}
+
+		ts.orderRepo.On("GetByUserID", ts.ctx, int32(1), mock.MatchedBy(func(filter *interfaces.OrderFilter) bool {
+			return *filter.Status == constants.OrderStatusDelivered &&
+				filter.PaymentStatus == nil &&
+				filter.CreatedFrom.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) &&
+				// A plain end date covers the whole day
+				filter.CreatedTo.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) &&
+				*filter.Currency == money.EUR &&
+				*filter.MinTotal == money.MustParse("25", money.EUR) &&
+				filter.MaxTotal == nil &&
+				*filter.ProductID == 3 &&
+				filter.SortBy == constants.OrderSortTotalDesc &&
+				filter.After == nil &&
+				filter.SkipItems
+		}), paging).Return([]*entities.Order{{ID: 4, UserID: 1, TotalAmount: money.MustParse("30", money.EUR)}}, nil)
+
+		orders, err := ts.orderService.GetUserOrders(ts.ctx, 1, req, paging)
+		require.NoError(t, err)
+		require.Len(t, orders, 1)
+		// Left out items still serialise as a list
+		require.NotNil(t, orders[0].Items)
+		require.Empty(t, orders[0].Items)
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Get User Orders - Includes Items", func(t *testing.T) {
+		ts := setupTest(t)
+
+		paging := &core.Paging{Page: 1, Limit: 10}
+		ts.orderRepo.On("GetByUserID", ts.ctx, int32(1), mock.MatchedBy(func(filter *interfaces.OrderFilter) bool {
+			return !filter.SkipItems
+		}), paging).Return([]*entities.Order{}, nil)
+
+		_, err := ts.orderService.GetUserOrders(ts.ctx, 1, &dto.OrderListRequest{Include: "items"}, paging)
+		require.NoError(t, err)
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Get User Orders - Resumes From Cursor", func(t *testing.T) {
+		ts := setupTest(t)
+
+		last := &entities.Order{ID: 9, CreatedAt: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC), TotalAmount: usd("12.50")}
+		paging := &core.Paging{Page: 1, Limit: 10, Cursor: entities.CursorAfter(last, constants.OrderSortCreatedAtDesc).Encode()}
+
+		ts.orderRepo.On("GetByUserID", ts.ctx, int32(1), mock.MatchedBy(func(filter *interfaces.OrderFilter) bool {
+			return filter.SortBy == constants.OrderSortCreatedAtDesc &&
+				filter.Currency == nil &&
+				!filter.SkipItems &&
+				filter.After != nil &&
+				filter.After.ID == 9 &&
+				filter.After.CreatedAt.Equal(last.CreatedAt) &&
+				filter.After.Total == last.TotalAmount.Minor()
+		}), paging).Return([]*entities.Order{}, nil)
+
+		_, err := ts.orderService.GetUserOrders(ts.ctx, 1, &dto.OrderListRequest{}, paging)
+		require.NoError(t, err)
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Get User Orders - Rejects Bad Filters", func(t *testing.T) {
+		byTotal := entities.CursorAfter(&entities.Order{ID: 9, TotalAmount: usd("1")}, constants.OrderSortTotalAsc).Encode()
+		minTotal, maxTotal := "50", "10"
+
+		for name, tc := range map[string]struct {
+			req    *dto.OrderListRequest
+			cursor string
+			err    error
+		}{
+			"unknown status":          {req: &dto.OrderListRequest{Status: "LOST"}, err: errorx.ErrInvalidOrderFilter},
+			"unknown payment":         {req: &dto.OrderListRequest{PaymentStatus: "MAYBE"}, err: errorx.ErrInvalidOrderFilter},
+			"unknown sort":            {req: &dto.OrderListRequest{SortBy: "name"}, err: errorx.ErrInvalidOrderFilter},
+			"bad date":                {req: &dto.OrderListRequest{From: "yesterday"}, err: errorx.ErrInvalidOrderFilter},
+			"empty date range":        {req: &dto.OrderListRequest{From: "2026-02-01", To: "2026-01-01"}, err: errorx.ErrInvalidOrderFilter},
+			"inverted total range":    {req: &dto.OrderListRequest{MinTotal: &minTotal, MaxTotal: &maxTotal}, err: errorx.ErrInvalidOrderFilter},
+			"unknown include":         {req: &dto.OrderListRequest{Include: "refunds"}, err: errorx.ErrInvalidOrderFilter},
+			"unknown exclude":         {req: &dto.OrderListRequest{Exclude: "items,refunds"}, err: errorx.ErrInvalidOrderFilter},
+			"total sort, no currency": {req: &dto.OrderListRequest{SortBy: constants.OrderSortTotalAsc.String()}, err: errorx.ErrInvalidOrderFilter},
+			"garbled cursor":          {req: &dto.OrderListRequest{}, cursor: "not-a-cursor", err: errorx.ErrInvalidCursor},
+			"cursor of other sort":    {req: &dto.OrderListRequest{}, cursor: byTotal, err: errorx.ErrInvalidCursor},
+		} {
+			t.Run(name, func(t *testing.T) {
+				ts := setupTest(t)
+
+				orders, err := ts.orderService.GetUserOrders(ts.ctx, 1, tc.req, &core.Paging{Page: 1, Limit: 10, Cursor: tc.cursor})
+				require.Nil(t, orders)
+
+				var appErr *core.DefaultError
+				require.ErrorAs(t, err, &appErr)
+				require.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+				require.Equal(t, tc.err.Error(), appErr.Error())
+				ts.orderRepo.AssertNotCalled(t, "GetByUserID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
 	})
 }
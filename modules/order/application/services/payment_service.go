@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mallbots/modules/order/application/dto"
+	"mallbots/modules/order/domain/constants"
+	orderEntities "mallbots/modules/order/domain/entities"
+	orderInterfaces "mallbots/modules/order/domain/interfaces"
+	"mallbots/plugins/payment"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"time"
+
+	sctx "github.com/phathdt/service-context"
+	"github.com/phathdt/service-context/core"
+)
+
+type paymentService struct {
+	orderRepo    orderInterfaces.OrderRepository
+	webhookRepo  orderInterfaces.PaymentWebhookRepository
+	orderService orderInterfaces.OrderService
+	refunds      orderInterfaces.RefundService
+	provider     payment.PaymentProvider
+	txManager    pgxc.TxManager
+	policy       orderInterfaces.OrderAccessPolicy
+	eventRepo    orderInterfaces.OrderEventRepository
+	logger       sctx.Logger
+}
+
+func NewPaymentService(
+	orderRepo orderInterfaces.OrderRepository,
+	webhookRepo orderInterfaces.PaymentWebhookRepository,
+	orderService orderInterfaces.OrderService,
+	refunds orderInterfaces.RefundService,
+	provider payment.PaymentProvider,
+	txManager pgxc.TxManager,
+	policy orderInterfaces.OrderAccessPolicy,
+	eventRepo orderInterfaces.OrderEventRepository,
+) orderInterfaces.PaymentService {
+	return &paymentService{
+		orderRepo:    orderRepo,
+		webhookRepo:  webhookRepo,
+		orderService: orderService,
+		refunds:      refunds,
+		provider:     provider,
+		txManager:    txManager,
+		policy:       policy,
+		eventRepo:    eventRepo,
+		logger:       sctx.GlobalLogger().GetLogger("payment"),
+	}
+}
+
+// webhookStatuses maps provider events to the payment status they settle on.
+// Refunds are reconciled with the refund records rather than set directly.
+var webhookStatuses = map[payment.EventType]constants.PaymentStatus{
+	payment.EventPaymentSucceeded: constants.PaymentStatusPaid,
+	payment.EventPaymentFailed:    constants.PaymentStatusFailed,
+	payment.EventPaymentRefunded:  constants.PaymentStatusRefunded,
+}
+
+func (s *paymentService) CreatePaymentIntent(ctx context.Context, caller orderEntities.Caller, orderID int32) (*dto.PaymentIntentResponse, error) {
+	var intent *payment.Intent
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.policy.Authorize(caller, constants.OrderActionPay, order); err != nil {
+			return err
+		}
+
+		if !order.PaymentStatus.CanTransitionTo(constants.PaymentStatusPaid) {
+			return core.ErrConflict.
+				WithError(errorx.ErrOrderNotPayable.Error()).
+				WithReasonf("payment is %s", order.PaymentStatus)
+		}
+
+		// Only the latest intent is kept on the order, an earlier one still
+		// open could be paid without the webhook ever finding the order
+		if err := cancelIntent(ctx, s.provider, order); err != nil {
+			return err
+		}
+
+		intent, err = s.provider.CreateIntent(ctx, payment.IntentRequest{
+			OrderID: order.ID,
+			Amount:  order.TotalAmount,
+		})
+		if err != nil {
+			return core.ErrInternalServerError.
+				WithError(errorx.ErrPaymentFailed.Error()).
+				WithDebug(err.Error())
+		}
+
+		return s.orderRepo.SetPaymentIntent(ctx, order.ID, s.provider.Name(), intent.ID)
+	})
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	return &dto.PaymentIntentResponse{
+		OrderID:      orderID,
+		Provider:     s.provider.Name(),
+		IntentID:     intent.ID,
+		ClientSecret: intent.ClientSecret,
+		Amount:       intent.Amount,
+		Currency:     intent.Amount.Currency().String(),
+		Status:       string(intent.Status),
+	}, nil
+}
+
+// CapturePayment holds the order lock while the provider captures, so a
+// concurrent cancel cannot void the payment between the check and the capture
+func (s *paymentService) CapturePayment(ctx context.Context, caller orderEntities.Caller, orderID int32) (*dto.OrderResponse, error) {
+	var response *dto.OrderResponse
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		if order.PaymentIntentID == nil {
+			return core.ErrConflict.WithError(errorx.ErrPaymentIntentNotFound.Error())
+		}
+
+		if order.PaymentStatus == constants.PaymentStatusPaid {
+			return core.ErrConflict.WithError(errorx.ErrPaymentAlreadyProcessed.Error())
+		}
+
+		if !order.PaymentStatus.CanTransitionTo(constants.PaymentStatusPaid) {
+			return core.ErrConflict.
+				WithError(errorx.ErrOrderNotPayable.Error()).
+				WithReasonf("payment is %s", order.PaymentStatus)
+		}
+
+		if _, err := s.provider.Capture(ctx, *order.PaymentIntentID); err != nil {
+			return core.ErrInternalServerError.
+				WithError(errorx.ErrPaymentFailed.Error()).
+				WithDebug(err.Error())
+		}
+
+		response, err = s.orderService.UpdatePaymentStatus(ctx, caller, orderID, &dto.UpdatePaymentStatusRequest{
+			PaymentStatus: constants.PaymentStatusPaid.String(),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	return response, nil
+}
+
+func (s *paymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.provider.VerifyWebhook(payload, signature)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			return core.ErrUnauthorized.WithError(errorx.ErrInvalidWebhookSignature.Error())
+		}
+		return core.ErrBadRequest.WithError(errorx.ErrInvalidWebhookPayload.Error())
+	}
+
+	next, ok := webhookStatuses[event.Type]
+	if !ok {
+		// Providers add event types over time; acknowledging them keeps the
+		// provider from retrying something this service will never handle
+		return nil
+	}
+
+	// The event row and whatever it changes commit together, so a retried
+	// delivery either sees the recorded event or applies it from scratch.
+	// Events this service cannot apply are still recorded and acknowledged,
+	// the provider would otherwise retry them forever.
+	var unapplied *orderEntities.Refund
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.GetByPaymentIntentIDForUpdate(ctx, event.IntentID)
+		if err != nil {
+			if errors.Is(err, errorx.ErrOrderNotFound) {
+				// An intent replaced or never stored has nothing to update,
+				// refusing it would only have the provider retry forever
+				s.logger.Warnf("webhook %s for unknown intent %s ignored", event.ID, event.IntentID)
+				return nil
+			}
+			return err
+		}
+
+		recorded, err := s.webhookRepo.Record(ctx, &orderEntities.PaymentWebhookEvent{
+			Provider:  s.provider.Name(),
+			EventID:   event.ID,
+			EventType: string(event.Type),
+			OrderID:   order.ID,
+			Payload:   payload,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+
+		if !recorded {
+			return errorx.ErrPaymentAlreadyProcessed
+		}
+
+		amount, err := money.Parse(event.Amount.String(), order.Currency)
+		if err != nil && next != constants.PaymentStatusFailed {
+			return s.flagPayment(ctx, order, event, errorx.ErrInvalidWebhookPayload, "amount %q is not a %s amount", event.Amount, order.Currency)
+		}
+
+		switch {
+		case next == constants.PaymentStatusRefunded:
+			return s.refunds.RecordProviderRefund(ctx, orderEntities.SystemCaller, order, amount)
+		case order.PaymentStatus == next:
+			return nil
+		case next == constants.PaymentStatusPaid && !order.PaymentStatus.CanTransitionTo(next):
+			// The order was cancelled or settled before the money arrived
+			unapplied, err = s.refunds.RefundUnappliedPayment(ctx, orderEntities.SystemCaller, order, amount,
+				fmt.Sprintf("payment received while the payment was %s", order.PaymentStatus))
+			return err
+		case next == constants.PaymentStatusPaid && amount.Cmp(order.TotalAmount) != 0:
+			return s.flagPayment(ctx, order, event, errorx.ErrPaymentAmountMismatch, "received %s for an order of %s", amount, order.TotalAmount)
+		case !order.PaymentStatus.CanTransitionTo(next):
+			// A failed attempt changes nothing on a payment that is settled
+			return nil
+		}
+
+		_, err = s.orderService.UpdatePaymentStatus(ctx, orderEntities.SystemCaller, order.ID, &dto.UpdatePaymentStatusRequest{
+			PaymentStatus: next.String(),
+		})
+		return err
+	})
+	if err != nil || unapplied == nil {
+		return err
+	}
+
+	// The event is handled once the refund is recorded, a failed payout is
+	// left approved for someone to process again
+	if _, err := s.refunds.ProcessRefund(ctx, orderEntities.SystemCaller, unapplied.ID); err != nil {
+		s.logger.Errorf("pay out refund %d of unapplied payment on order %d: %v", unapplied.ID, unapplied.OrderID, err)
+	}
+
+	return nil
+}
+
+// flagPayment leaves the payment as it is and notes the event on the order
+// timeline for someone to settle by hand
+func (s *paymentService) flagPayment(ctx context.Context, order *orderEntities.Order, event *payment.WebhookEvent, problem error, format string, args ...any) error {
+	timelineEvent := orderEntities.NewOrderEvent(order.ID, constants.OrderEventPaymentFlagged, orderEntities.SystemCaller).
+		With("event_id", event.ID).
+		With("event_type", string(event.Type)).
+		With("problem", problem.Error()).
+		With("reason", fmt.Sprintf(format, args...))
+
+	return s.eventRepo.Append(ctx, timelineEvent)
+}
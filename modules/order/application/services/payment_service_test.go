@@ -0,0 +1,409 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	currencyServices "mallbots/modules/currency/application/services"
+	"mallbots/modules/order/application/dto"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+	orderEvents "mallbots/modules/order/domain/events"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/plugins/eventbus"
+	"mallbots/plugins/payment"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"net/http"
+	"testing"
+
+	"github.com/phathdt/service-context/core"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookSecret = "test-secret"
+
+type MockPaymentWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockPaymentWebhookRepository) Record(ctx context.Context, event *entities.PaymentWebhookEvent) (bool, error) {
+	args := m.Called(ctx, event)
+	return args.Bool(0), args.Error(1)
+}
+
+type paymentTestSuite struct {
+	orderRepo      *MockOrderRepository
+	webhookRepo    *MockPaymentWebhookRepository
+	eventRepo      *MockOrderEventRepository
+	outbox         *MockOutboxWriter
+	refunds        *MockRefundService
+	provider       *MockPaymentProvider
+	paymentService interfaces.PaymentService
+	ctx            context.Context
+}
+
+func setupPaymentTest(t *testing.T) *paymentTestSuite {
+	orderRepo := new(MockOrderRepository)
+	webhookRepo := new(MockPaymentWebhookRepository)
+	txManager := new(MockTxManager)
+	txManager.On("WithTx", mock.Anything).Return()
+	eventRepo := new(MockOrderEventRepository)
+	eventRepo.On("Append", mock.Anything, mock.Anything).Return(nil)
+
+	outboxWriter := newMockOutboxWriter()
+	refunds := new(MockRefundService)
+
+	policy := NewOrderAccessPolicy()
+	orderService := NewOrderService(orderRepo, new(MockCartService), newMockAddressService(), new(MockProductService), new(MockInventoryService), newTestShippingCalculator(t), newTestTaxCalculator(t), new(MockPromotionService), nil, currencyServices.NewCurrencyService(new(MockExchangeRateRepository), txManager), refunds, newMockPaymentProvider(), txManager, eventbus.New("eventbus"), policy, eventRepo, outboxWriter)
+	provider := newMockPaymentProvider()
+
+	return &paymentTestSuite{
+		orderRepo:      orderRepo,
+		webhookRepo:    webhookRepo,
+		eventRepo:      eventRepo,
+		outbox:         outboxWriter,
+		refunds:        refunds,
+		provider:       provider,
+		paymentService: NewPaymentService(orderRepo, webhookRepo, orderService, refunds, provider, txManager, policy, eventRepo),
+		ctx:            context.Background(),
+	}
+}
+
+func pendingOrder() *entities.Order {
+	provider, intentID := "fake", "pi_fake_1"
+	return &entities.Order{
+		ID:              1,
+		UserID:          1,
+		Status:          constants.OrderStatusPending,
+		PaymentStatus:   constants.PaymentStatusPending,
+		Currency:        money.USD,
+		TotalAmount:     usd("41.97"),
+		PaymentProvider: &provider,
+		PaymentIntentID: &intentID,
+	}
+}
+
+func signedWebhook(t *testing.T, event payment.WebhookEvent) ([]byte, string) {
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+	return payload, payment.Sign(testWebhookSecret, payload)
+}
+
+func TestPaymentService(t *testing.T) {
+	t.Run("Create Payment Intent - Success", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		order := pendingOrder()
+		order.PaymentIntentID = nil
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(order, nil)
+		ts.orderRepo.On("SetPaymentIntent", ts.ctx, int32(1), "fake", "pi_fake_1").Return(nil)
+
+		intent, err := ts.paymentService.CreatePaymentIntent(ts.ctx, customer(1), 1)
+		require.NoError(t, err)
+		require.Equal(t, "pi_fake_1", intent.IntentID)
+		require.Equal(t, usd("41.97"), intent.Amount)
+		require.Equal(t, "USD", intent.Currency)
+
+		ts.orderRepo.AssertExpectations(t)
+		ts.provider.AssertNotCalled(t, "CancelIntent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Create Payment Intent - Cancels The Previous Intent", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(pendingOrder(), nil)
+		ts.provider.On("CancelIntent", ts.ctx, "pi_fake_1").Return(nil)
+		ts.orderRepo.On("SetPaymentIntent", ts.ctx, int32(1), "fake", "pi_fake_1").Return(nil)
+
+		_, err := ts.paymentService.CreatePaymentIntent(ts.ctx, customer(1), 1)
+		require.NoError(t, err)
+
+		ts.orderRepo.AssertExpectations(t)
+		ts.provider.AssertExpectations(t)
+	})
+
+	t.Run("Create Payment Intent - Previous Intent Cannot Be Cancelled", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(pendingOrder(), nil)
+		ts.provider.On("CancelIntent", ts.ctx, "pi_fake_1").Return(errors.New("intent already succeeded"))
+
+		_, err := ts.paymentService.CreatePaymentIntent(ts.ctx, customer(1), 1)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusInternalServerError, appErr.StatusCode())
+		ts.orderRepo.AssertNotCalled(t, "SetPaymentIntent", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Create Payment Intent - Already Paid", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		order := pendingOrder()
+		order.PaymentStatus = constants.PaymentStatusPaid
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(order, nil)
+
+		intent, err := ts.paymentService.CreatePaymentIntent(ts.ctx, customer(1), 1)
+		require.Nil(t, intent)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrOrderNotPayable.Error(), appErr.Error())
+	})
+
+	t.Run("Create Payment Intent - Other User", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(pendingOrder(), nil)
+
+		_, err := ts.paymentService.CreatePaymentIntent(ts.ctx, customer(2), 1)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusNotFound, appErr.StatusCode())
+		ts.orderRepo.AssertNotCalled(t, "SetPaymentIntent", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Capture Payment - No Intent", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		order := pendingOrder()
+		order.PaymentIntentID = nil
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(order, nil)
+
+		_, err := ts.paymentService.CapturePayment(ts.ctx, admin, 1)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrPaymentIntentNotFound.Error(), appErr.Error())
+	})
+
+	t.Run("Capture Payment - Voided Payment", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		order := pendingOrder()
+		order.Status = constants.OrderStatusCancelled
+		order.PaymentStatus = constants.PaymentStatusVoided
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(order, nil)
+
+		_, err := ts.paymentService.CapturePayment(ts.ctx, admin, 1)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrOrderNotPayable.Error(), appErr.Error())
+		ts.orderRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Capture Payment - Success", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(pendingOrder(), nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(pendingOrder(), nil)
+		ts.orderRepo.On("UpdatePaymentStatus", ts.ctx, int32(1), constants.PaymentStatusPaid).Return(nil)
+
+		_, err := ts.paymentService.CapturePayment(ts.ctx, admin, 1)
+		require.NoError(t, err)
+
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Webhook - Payment Succeeded", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		payload, signature := signedWebhook(t, payment.WebhookEvent{
+			ID:       "evt_1",
+			Type:     payment.EventPaymentSucceeded,
+			IntentID: "pi_fake_1",
+			Amount:   json.Number("41.97"),
+		})
+
+		ts.orderRepo.On("GetByPaymentIntentIDForUpdate", ts.ctx, "pi_fake_1").Return(pendingOrder(), nil)
+		ts.webhookRepo.On("Record", ts.ctx, mock.MatchedBy(func(event *entities.PaymentWebhookEvent) bool {
+			return event.EventID == "evt_1" && event.OrderID == 1
+		})).Return(true, nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(pendingOrder(), nil)
+		ts.orderRepo.On("UpdatePaymentStatus", ts.ctx, int32(1), constants.PaymentStatusPaid).Return(nil)
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(pendingOrder(), nil)
+
+		err := ts.paymentService.HandleWebhook(ts.ctx, payload, signature)
+		require.NoError(t, err)
+
+		events := ts.eventRepo.recordedEvents()
+		require.Len(t, events, 1)
+		require.Equal(t, entities.SystemCaller.Role, events[0].ActorRole)
+
+		published := ts.outbox.recordedEvents()
+		require.Len(t, published, 1)
+		captured := published[0].(*orderEvents.PaymentCaptured)
+		require.Equal(t, int32(1), captured.OrderID)
+		require.Equal(t, pendingOrder().TotalAmount, captured.Amount)
+		require.Equal(t, "fake", captured.Provider)
+		require.Equal(t, "pi_fake_1", captured.IntentID)
+
+		ts.orderRepo.AssertExpectations(t)
+		ts.webhookRepo.AssertExpectations(t)
+	})
+
+	t.Run("Webhook - Invalid Signature", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		payload, _ := signedWebhook(t, payment.WebhookEvent{
+			ID:       "evt_1",
+			Type:     payment.EventPaymentSucceeded,
+			IntentID: "pi_fake_1",
+			Amount:   json.Number("41.97"),
+		})
+
+		err := ts.paymentService.HandleWebhook(ts.ctx, payload, payment.Sign("wrong-secret", payload))
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusUnauthorized, appErr.StatusCode())
+		require.Equal(t, errorx.ErrInvalidWebhookSignature.Error(), appErr.Error())
+		ts.orderRepo.AssertNotCalled(t, "GetByPaymentIntentIDForUpdate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Webhook - Duplicate Delivery", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		payload, signature := signedWebhook(t, payment.WebhookEvent{
+			ID:       "evt_1",
+			Type:     payment.EventPaymentSucceeded,
+			IntentID: "pi_fake_1",
+			Amount:   json.Number("41.97"),
+		})
+
+		ts.orderRepo.On("GetByPaymentIntentIDForUpdate", ts.ctx, "pi_fake_1").Return(pendingOrder(), nil)
+		ts.webhookRepo.On("Record", ts.ctx, mock.Anything).Return(false, nil)
+
+		err := ts.paymentService.HandleWebhook(ts.ctx, payload, signature)
+		require.ErrorIs(t, err, errorx.ErrPaymentAlreadyProcessed)
+
+		ts.orderRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything)
+		require.Empty(t, ts.eventRepo.recordedEvents())
+	})
+
+	t.Run("Webhook - Amount Mismatch Is Flagged", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		payload, signature := signedWebhook(t, payment.WebhookEvent{
+			ID:       "evt_1",
+			Type:     payment.EventPaymentSucceeded,
+			IntentID: "pi_fake_1",
+			Amount:   json.Number("1.00"),
+		})
+
+		ts.orderRepo.On("GetByPaymentIntentIDForUpdate", ts.ctx, "pi_fake_1").Return(pendingOrder(), nil)
+		ts.webhookRepo.On("Record", ts.ctx, mock.Anything).Return(true, nil)
+
+		err := ts.paymentService.HandleWebhook(ts.ctx, payload, signature)
+		require.NoError(t, err)
+
+		recorded := ts.eventRepo.recordedEvents()
+		require.Len(t, recorded, 1)
+		require.Equal(t, constants.OrderEventPaymentFlagged, recorded[0].Type)
+		require.Equal(t, errorx.ErrPaymentAmountMismatch.Error(), recorded[0].Metadata["problem"])
+
+		ts.webhookRepo.AssertExpectations(t)
+		ts.orderRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Webhook - Payment On A Voided Order Is Refunded", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		payload, signature := signedWebhook(t, payment.WebhookEvent{
+			ID:       "evt_1",
+			Type:     payment.EventPaymentSucceeded,
+			IntentID: "pi_fake_1",
+			Amount:   json.Number("41.97"),
+		})
+
+		order := pendingOrder()
+		order.Status = constants.OrderStatusCancelled
+		order.PaymentStatus = constants.PaymentStatusVoided
+
+		ts.orderRepo.On("GetByPaymentIntentIDForUpdate", ts.ctx, "pi_fake_1").Return(order, nil)
+		ts.webhookRepo.On("Record", ts.ctx, mock.Anything).Return(true, nil)
+		ts.refunds.On("RefundUnappliedPayment", ts.ctx, entities.SystemCaller, order, usd("41.97"), mock.Anything).
+			Return(&entities.Refund{ID: 5, Status: constants.RefundStatusApproved}, nil)
+		ts.refunds.On("ProcessRefund", ts.ctx, entities.SystemCaller, int32(5)).
+			Return(&dto.RefundResponse{ID: 5, Status: constants.RefundStatusProcessed.String()}, nil)
+
+		err := ts.paymentService.HandleWebhook(ts.ctx, payload, signature)
+		require.NoError(t, err)
+
+		ts.refunds.AssertExpectations(t)
+		ts.orderRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Webhook - Failure On A Settled Payment Is Acknowledged", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		payload, signature := signedWebhook(t, payment.WebhookEvent{
+			ID:       "evt_1",
+			Type:     payment.EventPaymentFailed,
+			IntentID: "pi_fake_1",
+		})
+
+		order := pendingOrder()
+		order.PaymentStatus = constants.PaymentStatusPaid
+
+		ts.orderRepo.On("GetByPaymentIntentIDForUpdate", ts.ctx, "pi_fake_1").Return(order, nil)
+		ts.webhookRepo.On("Record", ts.ctx, mock.Anything).Return(true, nil)
+
+		err := ts.paymentService.HandleWebhook(ts.ctx, payload, signature)
+		require.NoError(t, err)
+
+		ts.webhookRepo.AssertExpectations(t)
+		ts.orderRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Webhook - Refund Is Reconciled With The Refunds", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		payload, signature := signedWebhook(t, payment.WebhookEvent{
+			ID:       "evt_2",
+			Type:     payment.EventPaymentRefunded,
+			IntentID: "pi_fake_1",
+			Amount:   json.Number("41.97"),
+		})
+
+		order := pendingOrder()
+		order.PaymentStatus = constants.PaymentStatusPaid
+
+		ts.orderRepo.On("GetByPaymentIntentIDForUpdate", ts.ctx, "pi_fake_1").Return(order, nil)
+		ts.webhookRepo.On("Record", ts.ctx, mock.Anything).Return(true, nil)
+		ts.refunds.On("RecordProviderRefund", ts.ctx, entities.SystemCaller, order, usd("41.97")).Return(nil)
+
+		err := ts.paymentService.HandleWebhook(ts.ctx, payload, signature)
+		require.NoError(t, err)
+
+		ts.refunds.AssertExpectations(t)
+		ts.orderRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Webhook - Unknown Intent", func(t *testing.T) {
+		ts := setupPaymentTest(t)
+
+		payload, signature := signedWebhook(t, payment.WebhookEvent{
+			ID:       "evt_1",
+			Type:     payment.EventPaymentFailed,
+			IntentID: "pi_fake_404",
+		})
+
+		ts.orderRepo.On("GetByPaymentIntentIDForUpdate", ts.ctx, "pi_fake_404").Return(nil, errorx.ErrOrderNotFound)
+
+		err := ts.paymentService.HandleWebhook(ts.ctx, payload, signature)
+		require.NoError(t, err)
+		ts.webhookRepo.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+	})
+}
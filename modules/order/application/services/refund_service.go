@@ -0,0 +1,546 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mallbots/modules/order/application/dto"
+	"mallbots/modules/order/domain/constants"
+	orderEntities "mallbots/modules/order/domain/entities"
+	orderInterfaces "mallbots/modules/order/domain/interfaces"
+	"mallbots/plugins/outbox"
+	"mallbots/plugins/payment"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"time"
+
+	"github.com/phathdt/service-context/core"
+)
+
+type refundService struct {
+	orderRepo  orderInterfaces.OrderRepository
+	refundRepo orderInterfaces.RefundRepository
+	txManager  pgxc.TxManager
+	policy     orderInterfaces.OrderAccessPolicy
+	eventRepo  orderInterfaces.OrderEventRepository
+	provider   payment.PaymentProvider
+	outbox     outbox.Writer
+}
+
+func NewRefundService(
+	orderRepo orderInterfaces.OrderRepository,
+	refundRepo orderInterfaces.RefundRepository,
+	txManager pgxc.TxManager,
+	policy orderInterfaces.OrderAccessPolicy,
+	eventRepo orderInterfaces.OrderEventRepository,
+	provider payment.PaymentProvider,
+	outbox outbox.Writer,
+) orderInterfaces.RefundService {
+	return &refundService{
+		orderRepo:  orderRepo,
+		refundRepo: refundRepo,
+		txManager:  txManager,
+		policy:     policy,
+		eventRepo:  eventRepo,
+		provider:   provider,
+		outbox:     outbox,
+	}
+}
+
+func (s *refundService) RequestRefund(ctx context.Context, caller orderEntities.Caller, orderID int32, req *dto.CreateRefundRequest) (*dto.RefundResponse, error) {
+	if (len(req.Items) == 0) == (req.Amount == nil) {
+		return nil, core.ErrBadRequest.WithError(errorx.ErrInvalidRefundRequest.Error())
+	}
+
+	var refund *orderEntities.Refund
+
+	// The order row lock serialises concurrent requests so the amount check
+	// below always sees every earlier refund
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.policy.Authorize(caller, constants.OrderActionRequestRefund, order); err != nil {
+			return err
+		}
+
+		if !order.CanBeRefunded() {
+			return core.ErrConflict.
+				WithError(errorx.ErrOrderNotRefundable.Error()).
+				WithReasonf("order is %s with payment %s", order.Status, order.PaymentStatus)
+		}
+
+		now := time.Now()
+		refund = &orderEntities.Refund{
+			OrderID:   order.ID,
+			UserID:    order.UserID,
+			Status:    constants.RefundStatusRequested,
+			Reason:    req.Reason,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		if req.Amount != nil {
+			refund.Amount, err = money.Parse(req.Amount.String(), order.Currency)
+			if err != nil || !refund.Amount.IsPositive() {
+				return core.ErrBadRequest.
+					WithError(errorx.ErrInvalidAmount.Error()).
+					WithReasonf("amount %q is not a positive %s amount", req.Amount.String(), order.Currency)
+			}
+		} else {
+			refund.Items, err = s.buildRefundItems(ctx, order, req.Items, now)
+			if err != nil {
+				return err
+			}
+
+			refund.Amount = money.Zero(order.Currency)
+			for _, item := range refund.Items {
+				refund.Amount = refund.Amount.Add(item.Amount)
+			}
+		}
+
+		refunded, err := s.refundRepo.SumOpenAmount(ctx, orderID, order.Currency)
+		if err != nil {
+			return err
+		}
+
+		remaining := order.TotalAmount.Sub(refunded)
+		if refund.Amount.GreaterThan(remaining) {
+			return core.ErrConflict.
+				WithError(errorx.ErrRefundExceedsPaidAmount.Error()).
+				WithReasonf("requested %s but only %s is refundable", refund.Amount, remaining)
+		}
+
+		refund, err = s.refundRepo.Create(ctx, refund)
+		if err != nil {
+			return err
+		}
+
+		return s.recordRefundEvent(ctx, caller, constants.OrderEventRefundRequested, refund)
+	})
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	return s.convertToResponse(refund), nil
+}
+
+// buildRefundItems prices the requested items, with the tax paid on them, from
+// the order and makes sure no item is refunded more times than it was ordered
+func (s *refundService) buildRefundItems(ctx context.Context, order *orderEntities.Order, reqItems []dto.RefundItemRequest, now time.Time) ([]*orderEntities.RefundItem, error) {
+	orderItems, err := s.orderRepo.GetItems(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int32]*orderEntities.OrderItem, len(orderItems))
+	for _, item := range orderItems {
+		byID[item.ID] = item
+	}
+
+	refunded, err := s.refundRepo.GetRefundedQuantities(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*orderEntities.RefundItem
+	for _, reqItem := range reqItems {
+		orderItem, ok := byID[reqItem.OrderItemID]
+		if !ok {
+			return nil, core.ErrBadRequest.
+				WithError(errorx.ErrRefundItemNotFound.Error()).
+				WithReasonf("order item %d", reqItem.OrderItemID)
+		}
+
+		refunded[orderItem.ID] += reqItem.Quantity
+		if refunded[orderItem.ID] > orderItem.Quantity {
+			return nil, core.ErrConflict.
+				WithError(errorx.ErrRefundQuantityExceeded.Error()).
+				WithReasonf("order item %d was ordered %d times", orderItem.ID, orderItem.Quantity)
+		}
+
+		items = append(items, &orderEntities.RefundItem{
+			OrderItemID: orderItem.ID,
+			Quantity:    reqItem.Quantity,
+			Amount:      orderItem.AmountFor(reqItem.Quantity, order.TaxInclusive),
+			CreatedAt:   now,
+		})
+	}
+
+	return items, nil
+}
+
+func (s *refundService) GetOrderRefunds(ctx context.Context, caller orderEntities.Caller, orderID int32) ([]*dto.RefundResponse, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	if err := s.policy.Authorize(caller, constants.OrderActionView, order); err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	refunds, err := s.refundRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.RefundResponse, 0, len(refunds))
+	for _, refund := range refunds {
+		responses = append(responses, s.convertToResponse(refund))
+	}
+
+	return responses, nil
+}
+
+func (s *refundService) ApproveRefund(ctx context.Context, caller orderEntities.Caller, refundID int32) (*dto.RefundResponse, error) {
+	return s.transition(ctx, caller, refundID, constants.RefundStatusApproved)
+}
+
+func (s *refundService) RejectRefund(ctx context.Context, caller orderEntities.Caller, refundID int32) (*dto.RefundResponse, error) {
+	return s.transition(ctx, caller, refundID, constants.RefundStatusRejected)
+}
+
+func (s *refundService) ProcessRefund(ctx context.Context, caller orderEntities.Caller, refundID int32) (*dto.RefundResponse, error) {
+	if err := s.payOut(ctx, caller, refundID); err != nil {
+		return nil, wrapRefundNotFound(err)
+	}
+
+	refund, err := s.refundRepo.GetByID(ctx, refundID)
+	if err != nil {
+		return nil, wrapRefundNotFound(err)
+	}
+
+	return s.convertToResponse(refund), nil
+}
+
+func (s *refundService) RefundCancelledOrder(ctx context.Context, caller orderEntities.Caller, order *orderEntities.Order, reason string) (*orderEntities.Refund, error) {
+	refunded, err := s.refundRepo.SumOpenAmount(ctx, order.ID, order.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.approveCaptured(ctx, caller, order, order.TotalAmount.Sub(refunded), reason)
+}
+
+func (s *refundService) RefundUnappliedPayment(ctx context.Context, caller orderEntities.Caller, order *orderEntities.Order, amount money.Money, reason string) (*orderEntities.Refund, error) {
+	return s.approveCaptured(ctx, caller, order, amount, reason)
+}
+
+func (s *refundService) RecordProviderRefund(ctx context.Context, caller orderEntities.Caller, order *orderEntities.Order, refunded money.Money) error {
+	processed, err := s.refundRepo.SumProcessedAmount(ctx, order.ID, order.Currency)
+	if err != nil {
+		return err
+	}
+
+	missing := refunded.Sub(processed)
+	if !missing.IsPositive() {
+		return nil
+	}
+
+	refunds, err := s.refundRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	// Refunds handed to the provider whose answer never got recorded are
+	// what it reports first
+	now := time.Now()
+	var settled *orderEntities.Refund
+	for _, refund := range refunds {
+		if refund.Status != constants.RefundStatusProcessing || refund.Amount.GreaterThan(missing) {
+			continue
+		}
+
+		refund.TransitionTo(constants.RefundStatusProcessed, now)
+		if err := s.refundRepo.UpdateStatus(ctx, refund); err != nil {
+			return err
+		}
+
+		if err := s.recordRefundEvent(ctx, caller, constants.OrderEventRefundProcessed, refund); err != nil {
+			return err
+		}
+
+		missing = missing.Sub(refund.Amount)
+		settled = refund
+	}
+
+	if missing.IsPositive() {
+		// The money is already back with the customer, only the record is missing
+		settled, err = s.refundRepo.Create(ctx, &orderEntities.Refund{
+			OrderID:     order.ID,
+			UserID:      order.UserID,
+			Status:      constants.RefundStatusProcessed,
+			Amount:      missing,
+			Reason:      "refunded at the payment provider",
+			ApprovedAt:  &now,
+			ProcessedAt: &now,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := s.recordRefundEvent(ctx, caller, constants.OrderEventRefundProcessed, settled); err != nil {
+			return err
+		}
+	}
+
+	return s.settleOrder(ctx, caller, order, settled)
+}
+
+// approveCaptured records an approved refund of amount, skipping the
+// request a customer goes through. Nothing is paid under the caller's
+// transaction; the refund is paid out by ProcessRefund once it committed.
+func (s *refundService) approveCaptured(ctx context.Context, caller orderEntities.Caller, order *orderEntities.Order, amount money.Money, reason string) (*orderEntities.Refund, error) {
+	if !amount.IsPositive() {
+		return nil, nil
+	}
+
+	now := time.Now()
+	refund, err := s.refundRepo.Create(ctx, &orderEntities.Refund{
+		OrderID:    order.ID,
+		UserID:     order.UserID,
+		Status:     constants.RefundStatusApproved,
+		Amount:     amount,
+		Reason:     reason,
+		ApprovedAt: &now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recordRefundEvent(ctx, caller, constants.OrderEventRefundApproved, refund); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// refundEvents maps each refund status to the timeline entry it produces
+var refundEvents = map[constants.RefundStatus]constants.OrderEventType{
+	constants.RefundStatusRequested: constants.OrderEventRefundRequested,
+	constants.RefundStatusApproved:  constants.OrderEventRefundApproved,
+	constants.RefundStatusRejected:  constants.OrderEventRefundRejected,
+}
+
+func (s *refundService) recordRefundEvent(ctx context.Context, caller orderEntities.Caller, eventType constants.OrderEventType, refund *orderEntities.Refund) error {
+	event := orderEntities.NewOrderEvent(refund.OrderID, eventType, caller).
+		With("refund_id", refund.ID).
+		With("amount", refund.Amount).
+		With("reason", refund.Reason)
+
+	return s.eventRepo.Append(ctx, event)
+}
+
+// transition approves or rejects a refund, processing goes through payOut
+func (s *refundService) transition(ctx context.Context, caller orderEntities.Caller, refundID int32, next constants.RefundStatus) (*dto.RefundResponse, error) {
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		refund, err := s.refundRepo.GetByIDForUpdate(ctx, refundID)
+		if err != nil {
+			return err
+		}
+
+		current := refund.Status
+		if !refund.TransitionTo(next, time.Now()) {
+			return core.ErrConflict.
+				WithError(errorx.ErrInvalidRefundStatusTransition.Error()).
+				WithReasonf("cannot move refund from %s to %s", current, next)
+		}
+
+		if err := s.refundRepo.UpdateStatus(ctx, refund); err != nil {
+			return err
+		}
+
+		return s.recordRefundEvent(ctx, caller, refundEvents[next], refund)
+	})
+	if err != nil {
+		return nil, wrapRefundNotFound(err)
+	}
+
+	refund, err := s.refundRepo.GetByID(ctx, refundID)
+	if err != nil {
+		return nil, wrapRefundNotFound(err)
+	}
+
+	return s.convertToResponse(refund), nil
+}
+
+// payOut pays an approved refund back. The refund is claimed as PROCESSING
+// and committed before the provider is called, so money never moves without
+// a record and a concurrent call cannot pay it twice. It becomes PROCESSED,
+// settling the order, once the provider paid, and goes back to APPROVED when
+// the provider failed.
+func (s *refundService) payOut(ctx context.Context, caller orderEntities.Caller, refundID int32) error {
+	var refund *orderEntities.Refund
+	var order *orderEntities.Order
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		refund, err = s.refundRepo.GetByIDForUpdate(ctx, refundID)
+		if err != nil {
+			return err
+		}
+
+		current := refund.Status
+		if !refund.TransitionTo(constants.RefundStatusProcessing, time.Now()) {
+			return core.ErrConflict.
+				WithError(errorx.ErrInvalidRefundStatusTransition.Error()).
+				WithReasonf("cannot move refund from %s to %s", current, constants.RefundStatusProcessed)
+		}
+
+		order, err = s.orderRepo.GetByID(ctx, refund.OrderID)
+		if err != nil {
+			return err
+		}
+
+		return s.refundRepo.UpdateStatus(ctx, refund)
+	})
+	if err != nil {
+		return err
+	}
+
+	providerRefundID, payErr := s.payBack(ctx, order, refund.Amount)
+	orderID := order.ID
+
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		// The order is locked before the refund, like the webhooks do
+		order, err := s.orderRepo.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		refund, err := s.refundRepo.GetByIDForUpdate(ctx, refundID)
+		if err != nil {
+			return err
+		}
+
+		// A provider webhook may have settled the refund meanwhile
+		if refund.Status != constants.RefundStatusProcessing {
+			return nil
+		}
+
+		if payErr != nil {
+			refund.TransitionTo(constants.RefundStatusApproved, time.Now())
+			return s.refundRepo.UpdateStatus(ctx, refund)
+		}
+
+		refund.TransitionTo(constants.RefundStatusProcessed, time.Now())
+		refund.ProviderRefundID = providerRefundID
+		if err := s.refundRepo.UpdateStatus(ctx, refund); err != nil {
+			return err
+		}
+
+		if err := s.recordRefundEvent(ctx, caller, constants.OrderEventRefundProcessed, refund); err != nil {
+			return err
+		}
+
+		return s.settleOrder(ctx, caller, order, refund)
+	})
+	if err != nil {
+		return err
+	}
+
+	return payErr
+}
+
+// payBack returns amount through the provider that took the payment and
+// hands back the provider's refund ID. Orders paid outside a provider are
+// settled by hand.
+func (s *refundService) payBack(ctx context.Context, order *orderEntities.Order, amount money.Money) (*string, error) {
+	if order.PaymentIntentID == nil {
+		return nil, nil
+	}
+
+	providerRefund, err := s.provider.Refund(ctx, *order.PaymentIntentID, amount)
+	if err != nil {
+		return nil, core.ErrInternalServerError.
+			WithError(errorx.ErrPaymentFailed.Error()).
+			WithDebug(err.Error())
+	}
+
+	return &providerRefund.ID, nil
+}
+
+func (s *refundService) settleOrder(ctx context.Context, caller orderEntities.Caller, order *orderEntities.Order, refund *orderEntities.Refund) error {
+	orderID := order.ID
+
+	processed, err := s.refundRepo.SumProcessedAmount(ctx, orderID, order.Currency)
+	if err != nil {
+		return err
+	}
+
+	if processed.LessThan(order.TotalAmount) {
+		return nil
+	}
+
+	if order.PaymentStatus.CanTransitionTo(constants.PaymentStatusRefunded) {
+		if err := s.orderRepo.UpdatePaymentStatus(ctx, orderID, constants.PaymentStatusRefunded); err != nil {
+			return err
+		}
+
+		event := orderEntities.NewOrderEvent(orderID, constants.OrderEventPaymentStatusChanged, caller).
+			WithTransition(order.PaymentStatus.String(), constants.PaymentStatusRefunded.String()).
+			With("refund_id", refund.ID)
+		if err := s.eventRepo.Append(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	if order.Status.CanTransitionTo(constants.OrderStatusRefunded) {
+		if err := s.orderRepo.UpdateStatus(ctx, orderID, constants.OrderStatusRefunded); err != nil {
+			return err
+		}
+
+		event := orderEntities.NewOrderEvent(orderID, constants.OrderEventStatusChanged, caller).
+			WithTransition(order.Status.String(), constants.OrderStatusRefunded.String()).
+			With("refund_id", refund.ID)
+		if err := s.eventRepo.Append(ctx, event); err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, newOrderStatusChangedEvent(order, order.Status, constants.OrderStatusRefunded, time.Now()))
+	}
+
+	return nil
+}
+
+// wrapRefundNotFound turns a missing refund into a 404 response error
+func wrapRefundNotFound(err error) error {
+	if errors.Is(err, errorx.ErrRefundNotFound) {
+		return core.ErrNotFound.WithError(errorx.ErrRefundNotFound.Error())
+	}
+	return wrapNotFound(err)
+}
+
+func (s *refundService) convertToResponse(refund *orderEntities.Refund) *dto.RefundResponse {
+	itemResponses := make([]dto.RefundItemResponse, 0, len(refund.Items))
+	for _, item := range refund.Items {
+		itemResponses = append(itemResponses, dto.RefundItemResponse{
+			ID:          item.ID,
+			OrderItemID: item.OrderItemID,
+			Quantity:    item.Quantity,
+			Amount:      item.Amount,
+		})
+	}
+
+	return &dto.RefundResponse{
+		ID:          refund.ID,
+		OrderID:     refund.OrderID,
+		Status:      refund.Status.String(),
+		Amount:      refund.Amount,
+		Currency:    refund.Amount.Currency().String(),
+		Reason:      refund.Reason,
+		Items:       itemResponses,
+		ApprovedAt:  refund.ApprovedAt,
+		ProcessedAt: refund.ProcessedAt,
+		RejectedAt:  refund.RejectedAt,
+		CreatedAt:   refund.CreatedAt,
+		UpdatedAt:   refund.UpdatedAt,
+	}
+}
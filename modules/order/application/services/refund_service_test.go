@@ -0,0 +1,644 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"mallbots/modules/order/application/dto"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/modules/order/domain/events"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/plugins/payment/fake"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/phathdt/service-context/core"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRefundRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefundRepository) Create(ctx context.Context, refund *entities.Refund) (*entities.Refund, error) {
+	args := m.Called(ctx, refund)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Refund), args.Error(1)
+}
+
+func (m *MockRefundRepository) GetByID(ctx context.Context, id int32) (*entities.Refund, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Refund), args.Error(1)
+}
+
+func (m *MockRefundRepository) GetByIDForUpdate(ctx context.Context, id int32) (*entities.Refund, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Refund), args.Error(1)
+}
+
+func (m *MockRefundRepository) GetByOrderID(ctx context.Context, orderID int32) ([]*entities.Refund, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Refund), args.Error(1)
+}
+
+func (m *MockRefundRepository) UpdateStatus(ctx context.Context, refund *entities.Refund) error {
+	args := m.Called(ctx, refund)
+	return args.Error(0)
+}
+
+func (m *MockRefundRepository) SumOpenAmount(ctx context.Context, orderID int32, currency money.Currency) (money.Money, error) {
+	args := m.Called(ctx, orderID)
+	return args.Get(0).(money.Money), args.Error(1)
+}
+
+func (m *MockRefundRepository) SumProcessedAmount(ctx context.Context, orderID int32, currency money.Currency) (money.Money, error) {
+	args := m.Called(ctx, orderID)
+	return args.Get(0).(money.Money), args.Error(1)
+}
+
+func (m *MockRefundRepository) GetRefundedQuantities(ctx context.Context, orderID int32) (map[int32]int32, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int32]int32), args.Error(1)
+}
+
+type refundTestSuite struct {
+	orderRepo     *MockOrderRepository
+	refundRepo    *MockRefundRepository
+	txManager     *MockTxManager
+	eventRepo     *MockOrderEventRepository
+	outbox        *MockOutboxWriter
+	refundService interfaces.RefundService
+	ctx           context.Context
+}
+
+func setupRefundTest(t *testing.T) *refundTestSuite {
+	orderRepo := new(MockOrderRepository)
+	refundRepo := new(MockRefundRepository)
+	txManager := new(MockTxManager)
+	txManager.On("WithTx", mock.Anything).Return()
+	eventRepo := new(MockOrderEventRepository)
+	eventRepo.On("Append", mock.Anything, mock.Anything).Return(nil)
+	outboxWriter := newMockOutboxWriter()
+
+	return &refundTestSuite{
+		orderRepo:     orderRepo,
+		refundRepo:    refundRepo,
+		txManager:     txManager,
+		eventRepo:     eventRepo,
+		outbox:        outboxWriter,
+		refundService: NewRefundService(orderRepo, refundRepo, txManager, NewOrderAccessPolicy(), eventRepo, fake.NewWithSecret("payment", "secret"), outboxWriter),
+		ctx:           context.Background(),
+	}
+}
+
+func deliveredOrder() *entities.Order {
+	return &entities.Order{
+		ID:            1,
+		UserID:        1,
+		Status:        constants.OrderStatusDelivered,
+		PaymentStatus: constants.PaymentStatusPaid,
+		Currency:      money.USD,
+		TotalAmount:   usd("41.97"),
+	}
+}
+
+func deliveredOrderItems() []*entities.OrderItem {
+	return []*entities.OrderItem{
+		{ID: 10, OrderID: 1, ProductID: 1, Quantity: 2, Price: usd("10.99")},
+		{ID: 11, OrderID: 1, ProductID: 2, Quantity: 1, Price: usd("19.99")},
+	}
+}
+
+func TestRefundService(t *testing.T) {
+	t.Run("Request Refund - Items", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(deliveredOrder(), nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(1)).Return(deliveredOrderItems(), nil)
+		ts.refundRepo.On("GetRefundedQuantities", ts.ctx, int32(1)).Return(map[int32]int32{}, nil)
+		ts.refundRepo.On("SumOpenAmount", ts.ctx, int32(1)).Return(money.Zero(money.USD), nil)
+		ts.refundRepo.On("Create", ts.ctx, mock.MatchedBy(func(refund *entities.Refund) bool {
+			return refund.Status == constants.RefundStatusRequested &&
+				refund.Amount == usd("10.99") &&
+				len(refund.Items) == 1 &&
+				refund.Items[0].OrderItemID == 10 &&
+				refund.Items[0].Quantity == 1
+		})).Return(&entities.Refund{
+			ID:      5,
+			OrderID: 1,
+			Status:  constants.RefundStatusRequested,
+			Amount:  usd("10.99"),
+			Items:   []*entities.RefundItem{{ID: 1, OrderItemID: 10, Quantity: 1, Amount: usd("10.99")}},
+		}, nil)
+
+		refund, err := ts.refundService.RequestRefund(ts.ctx, customer(1), 1, &dto.CreateRefundRequest{
+			Reason: "damaged",
+			Items:  []dto.RefundItemRequest{{OrderItemID: 10, Quantity: 1}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(5), refund.ID)
+		require.Equal(t, constants.RefundStatusRequested.String(), refund.Status)
+		require.Len(t, refund.Items, 1)
+
+		ts.orderRepo.AssertExpectations(t)
+		ts.refundRepo.AssertExpectations(t)
+	})
+
+	t.Run("Request Refund - Items Include Tax Charged On Top", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		order := deliveredOrder()
+		order.TotalAmount = usd("44.00")
+		order.TaxAmount = usd("4.00")
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(order, nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(1)).Return([]*entities.OrderItem{
+			{ID: 10, OrderID: 1, ProductID: 1, Quantity: 2, Price: usd("20.00"), TaxRate: 10, TaxAmount: usd("4.00")},
+		}, nil)
+		ts.refundRepo.On("GetRefundedQuantities", ts.ctx, int32(1)).Return(map[int32]int32{}, nil)
+		ts.refundRepo.On("SumOpenAmount", ts.ctx, int32(1)).Return(money.Zero(money.USD), nil)
+		ts.refundRepo.On("Create", ts.ctx, mock.MatchedBy(func(refund *entities.Refund) bool {
+			return refund.Amount == usd("22.00") && refund.Items[0].Amount == usd("22.00")
+		})).Return(&entities.Refund{ID: 5, OrderID: 1, Status: constants.RefundStatusRequested, Amount: usd("22.00")}, nil)
+
+		_, err := ts.refundService.RequestRefund(ts.ctx, customer(1), 1, &dto.CreateRefundRequest{
+			Reason: "damaged",
+			Items:  []dto.RefundItemRequest{{OrderItemID: 10, Quantity: 1}},
+		})
+		require.NoError(t, err)
+
+		ts.refundRepo.AssertExpectations(t)
+	})
+
+	t.Run("Request Refund - Custom Amount", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		amount := json.Number("5.5")
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(deliveredOrder(), nil)
+		ts.refundRepo.On("SumOpenAmount", ts.ctx, int32(1)).Return(usd("30.00"), nil)
+		ts.refundRepo.On("Create", ts.ctx, mock.MatchedBy(func(refund *entities.Refund) bool {
+			return refund.Amount == usd("5.50") && len(refund.Items) == 0
+		})).Return(&entities.Refund{ID: 6, OrderID: 1, Status: constants.RefundStatusRequested, Amount: usd("5.50")}, nil)
+
+		refund, err := ts.refundService.RequestRefund(ts.ctx, customer(1), 1, &dto.CreateRefundRequest{
+			Reason: "late delivery",
+			Amount: &amount,
+		})
+		require.NoError(t, err)
+		require.Equal(t, usd("5.50"), refund.Amount)
+
+		ts.refundRepo.AssertExpectations(t)
+	})
+
+	t.Run("Request Refund - Exceeds Paid Amount", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		amount := json.Number("12")
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(deliveredOrder(), nil)
+		ts.refundRepo.On("SumOpenAmount", ts.ctx, int32(1)).Return(usd("30.00"), nil)
+
+		refund, err := ts.refundService.RequestRefund(ts.ctx, customer(1), 1, &dto.CreateRefundRequest{
+			Reason: "late delivery",
+			Amount: &amount,
+		})
+		require.Nil(t, refund)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrRefundExceedsPaidAmount.Error(), appErr.Error())
+		ts.refundRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Request Refund - Amount Finer Than A Cent", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		amount := json.Number("0.005")
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(deliveredOrder(), nil)
+
+		refund, err := ts.refundService.RequestRefund(ts.ctx, customer(1), 1, &dto.CreateRefundRequest{
+			Reason: "late delivery",
+			Amount: &amount,
+		})
+		require.Nil(t, refund)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+		require.Equal(t, errorx.ErrInvalidAmount.Error(), appErr.Error())
+		ts.refundRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Request Refund - Quantity Already Refunded", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(deliveredOrder(), nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(1)).Return(deliveredOrderItems(), nil)
+		ts.refundRepo.On("GetRefundedQuantities", ts.ctx, int32(1)).Return(map[int32]int32{11: 1}, nil)
+
+		refund, err := ts.refundService.RequestRefund(ts.ctx, customer(1), 1, &dto.CreateRefundRequest{
+			Reason: "damaged",
+			Items:  []dto.RefundItemRequest{{OrderItemID: 11, Quantity: 1}},
+		})
+		require.Nil(t, refund)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrRefundQuantityExceeded.Error(), appErr.Error())
+	})
+
+	t.Run("Request Refund - Items And Amount", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		amount := json.Number("1")
+		refund, err := ts.refundService.RequestRefund(ts.ctx, customer(1), 1, &dto.CreateRefundRequest{
+			Reason: "damaged",
+			Items:  []dto.RefundItemRequest{{OrderItemID: 10, Quantity: 1}},
+			Amount: &amount,
+		})
+		require.Nil(t, refund)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+		ts.txManager.AssertNotCalled(t, "WithTx", mock.Anything)
+	})
+
+	t.Run("Request Refund - Order Not Delivered", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		order := deliveredOrder()
+		order.Status = constants.OrderStatusShipped
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(order, nil)
+
+		amount := json.Number("1")
+		refund, err := ts.refundService.RequestRefund(ts.ctx, customer(1), 1, &dto.CreateRefundRequest{
+			Reason: "changed my mind",
+			Amount: &amount,
+		})
+		require.Nil(t, refund)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrOrderNotRefundable.Error(), appErr.Error())
+	})
+
+	t.Run("Request Refund - Not Owner", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(deliveredOrder(), nil)
+
+		amount := json.Number("1")
+		refund, err := ts.refundService.RequestRefund(ts.ctx, customer(2), 1, &dto.CreateRefundRequest{
+			Reason: "damaged",
+			Amount: &amount,
+		})
+		require.Nil(t, refund)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusNotFound, appErr.StatusCode())
+	})
+
+	t.Run("Approve Refund - Success", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		ts.refundRepo.On("GetByIDForUpdate", ts.ctx, int32(5)).Return(&entities.Refund{
+			ID:      5,
+			OrderID: 1,
+			Status:  constants.RefundStatusRequested,
+		}, nil)
+		ts.refundRepo.On("UpdateStatus", ts.ctx, mock.MatchedBy(func(refund *entities.Refund) bool {
+			return refund.Status == constants.RefundStatusApproved && refund.ApprovedAt != nil
+		})).Return(nil)
+		ts.refundRepo.On("GetByID", ts.ctx, int32(5)).Return(&entities.Refund{
+			ID:      5,
+			OrderID: 1,
+			Status:  constants.RefundStatusApproved,
+		}, nil)
+
+		refund, err := ts.refundService.ApproveRefund(ts.ctx, admin, 5)
+		require.NoError(t, err)
+		require.Equal(t, constants.RefundStatusApproved.String(), refund.Status)
+
+		ts.refundRepo.AssertExpectations(t)
+		ts.orderRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Process Refund - Not Approved", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		ts.refundRepo.On("GetByIDForUpdate", ts.ctx, int32(5)).Return(&entities.Refund{
+			ID:     5,
+			Status: constants.RefundStatusRequested,
+		}, nil)
+
+		refund, err := ts.refundService.ProcessRefund(ts.ctx, admin, 5)
+		require.Nil(t, refund)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrInvalidRefundStatusTransition.Error(), appErr.Error())
+	})
+
+	t.Run("Process Refund - Partial Keeps Order Paid", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		ts.refundRepo.On("GetByIDForUpdate", ts.ctx, int32(5)).Return(&entities.Refund{
+			ID:      5,
+			OrderID: 1,
+			Status:  constants.RefundStatusApproved,
+			Amount:  usd("10.99"),
+		}, nil)
+		ts.refundRepo.On("UpdateStatus", ts.ctx, mock.Anything).Return(nil)
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(deliveredOrder(), nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(deliveredOrder(), nil)
+		ts.refundRepo.On("SumProcessedAmount", ts.ctx, int32(1)).Return(usd("10.99"), nil)
+		ts.refundRepo.On("GetByID", ts.ctx, int32(5)).Return(&entities.Refund{
+			ID:          5,
+			OrderID:     1,
+			Status:      constants.RefundStatusProcessed,
+			ProcessedAt: &time.Time{},
+		}, nil)
+
+		refund, err := ts.refundService.ProcessRefund(ts.ctx, admin, 5)
+		require.NoError(t, err)
+		require.Equal(t, constants.RefundStatusProcessed.String(), refund.Status)
+
+		ts.orderRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything)
+		ts.orderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Process Refund - Pays Back Through Provider", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		intentID := "pi_fake_1"
+		order := deliveredOrder()
+		order.PaymentIntentID = &intentID
+
+		refund := &entities.Refund{
+			ID:      5,
+			OrderID: 1,
+			Status:  constants.RefundStatusApproved,
+			Amount:  usd("10.99"),
+		}
+		var statuses []constants.RefundStatus
+		ts.refundRepo.On("GetByIDForUpdate", ts.ctx, int32(5)).Return(refund, nil)
+		ts.refundRepo.On("UpdateStatus", ts.ctx, refund).Run(func(args mock.Arguments) {
+			statuses = append(statuses, args.Get(1).(*entities.Refund).Status)
+		}).Return(nil)
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(order, nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(order, nil)
+		ts.refundRepo.On("SumProcessedAmount", ts.ctx, int32(1)).Return(usd("10.99"), nil)
+		ts.refundRepo.On("GetByID", ts.ctx, int32(5)).Return(&entities.Refund{
+			ID:      5,
+			OrderID: 1,
+			Status:  constants.RefundStatusProcessed,
+		}, nil)
+
+		_, err := ts.refundService.ProcessRefund(ts.ctx, admin, 5)
+		require.NoError(t, err)
+
+		// The claim commits before the provider pays, the result after
+		require.Equal(t, []constants.RefundStatus{constants.RefundStatusProcessing, constants.RefundStatusProcessed}, statuses)
+		require.NotNil(t, refund.ProviderRefundID)
+		require.Equal(t, "re_fake_1_1099", *refund.ProviderRefundID)
+		ts.refundRepo.AssertExpectations(t)
+	})
+
+	t.Run("Process Refund - Provider Failure", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		intentID := "pi_other_1"
+		order := deliveredOrder()
+		order.PaymentIntentID = &intentID
+
+		approvedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		refund := &entities.Refund{
+			ID:         5,
+			OrderID:    1,
+			Status:     constants.RefundStatusApproved,
+			Amount:     usd("10.99"),
+			ApprovedAt: &approvedAt,
+		}
+		ts.refundRepo.On("GetByIDForUpdate", ts.ctx, int32(5)).Return(refund, nil)
+		ts.refundRepo.On("UpdateStatus", ts.ctx, refund).Return(nil)
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(order, nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(order, nil)
+
+		response, err := ts.refundService.ProcessRefund(ts.ctx, admin, 5)
+		require.Nil(t, response)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusInternalServerError, appErr.StatusCode())
+		require.Equal(t, errorx.ErrPaymentFailed.Error(), appErr.Error())
+
+		// The refund goes back to be processed again
+		require.Equal(t, constants.RefundStatusApproved, refund.Status)
+		require.Equal(t, approvedAt, *refund.ApprovedAt)
+		require.Nil(t, refund.ProcessedAt)
+		ts.refundRepo.AssertNumberOfCalls(t, "UpdateStatus", 2)
+		require.Empty(t, ts.eventRepo.recordedEvents())
+	})
+
+	t.Run("Process Refund - Settled By The Provider Meanwhile", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		refund := &entities.Refund{
+			ID:      5,
+			OrderID: 1,
+			Status:  constants.RefundStatusApproved,
+			Amount:  usd("10.99"),
+		}
+		ts.refundRepo.On("GetByIDForUpdate", ts.ctx, int32(5)).Return(refund, nil).Once()
+		ts.refundRepo.On("UpdateStatus", ts.ctx, refund).Return(nil).Once()
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(deliveredOrder(), nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(deliveredOrder(), nil)
+		ts.refundRepo.On("GetByIDForUpdate", ts.ctx, int32(5)).Return(&entities.Refund{
+			ID:      5,
+			OrderID: 1,
+			Status:  constants.RefundStatusProcessed,
+		}, nil).Once()
+		ts.refundRepo.On("GetByID", ts.ctx, int32(5)).Return(&entities.Refund{
+			ID:      5,
+			OrderID: 1,
+			Status:  constants.RefundStatusProcessed,
+		}, nil)
+
+		response, err := ts.refundService.ProcessRefund(ts.ctx, admin, 5)
+		require.NoError(t, err)
+		require.Equal(t, constants.RefundStatusProcessed.String(), response.Status)
+
+		require.Empty(t, ts.eventRepo.recordedEvents())
+		ts.refundRepo.AssertExpectations(t)
+	})
+
+	t.Run("Process Refund - Full Refund Settles Order", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		ts.refundRepo.On("GetByIDForUpdate", ts.ctx, int32(5)).Return(&entities.Refund{
+			ID:      5,
+			OrderID: 1,
+			Status:  constants.RefundStatusApproved,
+			Amount:  usd("30.98"),
+		}, nil)
+		ts.refundRepo.On("UpdateStatus", ts.ctx, mock.Anything).Return(nil)
+		ts.orderRepo.On("GetByID", ts.ctx, int32(1)).Return(deliveredOrder(), nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(deliveredOrder(), nil)
+		ts.refundRepo.On("SumProcessedAmount", ts.ctx, int32(1)).Return(usd("41.97"), nil)
+		ts.orderRepo.On("UpdatePaymentStatus", ts.ctx, int32(1), constants.PaymentStatusRefunded).Return(nil)
+		ts.orderRepo.On("UpdateStatus", ts.ctx, int32(1), constants.OrderStatusRefunded).Return(nil)
+		ts.refundRepo.On("GetByID", ts.ctx, int32(5)).Return(&entities.Refund{
+			ID:      5,
+			OrderID: 1,
+			Status:  constants.RefundStatusProcessed,
+		}, nil)
+
+		_, err := ts.refundService.ProcessRefund(ts.ctx, admin, 5)
+		require.NoError(t, err)
+
+		var types []constants.OrderEventType
+		for _, event := range ts.eventRepo.recordedEvents() {
+			types = append(types, event.Type)
+		}
+		require.Equal(t, []constants.OrderEventType{
+			constants.OrderEventRefundProcessed,
+			constants.OrderEventPaymentStatusChanged,
+			constants.OrderEventStatusChanged,
+		}, types)
+
+		published := ts.outbox.recordedEvents()
+		require.Len(t, published, 1)
+		require.Equal(t, constants.OrderStatusRefunded.String(), published[0].(*events.OrderStatusChanged).To)
+
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Refund Cancelled Order - Approves What Is Left", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		intentID := "pi_fake_1"
+		order := deliveredOrder()
+		order.Status = constants.OrderStatusConfirmed
+		order.PaymentIntentID = &intentID
+
+		ts.refundRepo.On("SumOpenAmount", ts.ctx, int32(1)).Return(usd("1.97"), nil)
+		ts.refundRepo.On("Create", ts.ctx, mock.MatchedBy(func(refund *entities.Refund) bool {
+			return refund.Status == constants.RefundStatusApproved &&
+				refund.Amount == usd("40.00") &&
+				refund.Reason == "changed my mind" &&
+				refund.ApprovedAt != nil &&
+				refund.ProcessedAt == nil
+		})).Return(&entities.Refund{ID: 5, OrderID: 1, Status: constants.RefundStatusApproved, Amount: usd("40.00")}, nil)
+
+		refund, err := ts.refundService.RefundCancelledOrder(ts.ctx, customer(1), order, "changed my mind")
+		require.NoError(t, err)
+		require.Equal(t, int32(5), refund.ID)
+
+		recorded := ts.eventRepo.recordedEvents()
+		require.Len(t, recorded, 1)
+		require.Equal(t, constants.OrderEventRefundApproved, recorded[0].Type)
+
+		ts.refundRepo.AssertExpectations(t)
+	})
+
+	t.Run("Refund Cancelled Order - Nothing Left To Refund", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		ts.refundRepo.On("SumOpenAmount", ts.ctx, int32(1)).Return(usd("41.97"), nil)
+
+		refund, err := ts.refundService.RefundCancelledOrder(ts.ctx, customer(1), deliveredOrder(), "changed my mind")
+		require.NoError(t, err)
+		require.Nil(t, refund)
+
+		ts.refundRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Record Provider Refund - Records What The Refunds Miss", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		ts.refundRepo.On("SumProcessedAmount", ts.ctx, int32(1)).Return(usd("10.99"), nil).Once()
+		ts.refundRepo.On("GetByOrderID", ts.ctx, int32(1)).Return([]*entities.Refund{}, nil)
+		ts.refundRepo.On("Create", ts.ctx, mock.MatchedBy(func(refund *entities.Refund) bool {
+			return refund.Status == constants.RefundStatusProcessed &&
+				refund.Amount == usd("30.98") &&
+				refund.ProviderRefundID == nil
+		})).Return(&entities.Refund{ID: 6, OrderID: 1, Status: constants.RefundStatusProcessed, Amount: usd("30.98")}, nil)
+		ts.refundRepo.On("SumProcessedAmount", ts.ctx, int32(1)).Return(usd("41.97"), nil).Once()
+		ts.orderRepo.On("UpdatePaymentStatus", ts.ctx, int32(1), constants.PaymentStatusRefunded).Return(nil)
+		ts.orderRepo.On("UpdateStatus", ts.ctx, int32(1), constants.OrderStatusRefunded).Return(nil)
+
+		err := ts.refundService.RecordProviderRefund(ts.ctx, entities.SystemCaller, deliveredOrder(), usd("41.97"))
+		require.NoError(t, err)
+
+		ts.refundRepo.AssertExpectations(t)
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Record Provider Refund - Settles The Refunds Processing", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		processing := &entities.Refund{ID: 5, OrderID: 1, Status: constants.RefundStatusProcessing, Amount: usd("10.99")}
+		ts.refundRepo.On("SumProcessedAmount", ts.ctx, int32(1)).Return(money.Zero(money.USD), nil).Once()
+		ts.refundRepo.On("GetByOrderID", ts.ctx, int32(1)).Return([]*entities.Refund{
+			{ID: 4, OrderID: 1, Status: constants.RefundStatusRequested, Amount: usd("5.00")},
+			processing,
+		}, nil)
+		ts.refundRepo.On("UpdateStatus", ts.ctx, processing).Return(nil)
+		ts.refundRepo.On("SumProcessedAmount", ts.ctx, int32(1)).Return(usd("10.99"), nil).Once()
+
+		err := ts.refundService.RecordProviderRefund(ts.ctx, entities.SystemCaller, deliveredOrder(), usd("10.99"))
+		require.NoError(t, err)
+
+		require.Equal(t, constants.RefundStatusProcessed, processing.Status)
+		require.NotNil(t, processing.ProcessedAt)
+		ts.refundRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		ts.refundRepo.AssertExpectations(t)
+	})
+
+	t.Run("Record Provider Refund - Already Recorded", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		ts.refundRepo.On("SumProcessedAmount", ts.ctx, int32(1)).Return(usd("10.99"), nil)
+
+		err := ts.refundService.RecordProviderRefund(ts.ctx, entities.SystemCaller, deliveredOrder(), usd("10.99"))
+		require.NoError(t, err)
+
+		ts.refundRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		require.Empty(t, ts.eventRepo.recordedEvents())
+	})
+
+	t.Run("Approve Refund - Not Found", func(t *testing.T) {
+		ts := setupRefundTest(t)
+
+		ts.refundRepo.On("GetByIDForUpdate", ts.ctx, int32(99)).Return(nil, errorx.ErrRefundNotFound)
+
+		refund, err := ts.refundService.ApproveRefund(ts.ctx, admin, 99)
+		require.Nil(t, refund)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusNotFound, appErr.StatusCode())
+	})
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mallbots/modules/order/application/dto"
+	"mallbots/modules/order/domain/constants"
+	orderEntities "mallbots/modules/order/domain/entities"
+	orderInterfaces "mallbots/modules/order/domain/interfaces"
+	productDto "mallbots/modules/product/application/dto"
+	productInterfaces "mallbots/modules/product/domain/interfaces"
+	"mallbots/plugins/outbox"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+	"time"
+
+	"github.com/phathdt/service-context/core"
+)
+
+type shipmentService struct {
+	orderRepo    orderInterfaces.OrderRepository
+	shipmentRepo orderInterfaces.ShipmentRepository
+	inventory    productInterfaces.InventoryService
+	txManager    pgxc.TxManager
+	eventRepo    orderInterfaces.OrderEventRepository
+	outbox       outbox.Writer
+}
+
+func NewShipmentService(
+	orderRepo orderInterfaces.OrderRepository,
+	shipmentRepo orderInterfaces.ShipmentRepository,
+	inventory productInterfaces.InventoryService,
+	txManager pgxc.TxManager,
+	eventRepo orderInterfaces.OrderEventRepository,
+	outbox outbox.Writer,
+) orderInterfaces.ShipmentService {
+	return &shipmentService{
+		orderRepo:    orderRepo,
+		shipmentRepo: shipmentRepo,
+		inventory:    inventory,
+		txManager:    txManager,
+		eventRepo:    eventRepo,
+		outbox:       outbox,
+	}
+}
+
+func (s *shipmentService) CreateShipment(ctx context.Context, caller orderEntities.Caller, orderID int32, req *dto.CreateShipmentRequest) (*dto.ShipmentResponse, error) {
+	var shipment *orderEntities.Shipment
+
+	// The order row lock serialises concurrent shipments so the quantity
+	// check below always sees every earlier one
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		if order.Status != constants.OrderStatusProcessing && order.Status != constants.OrderStatusPartiallyShipped {
+			return core.ErrConflict.
+				WithError(errorx.ErrOrderNotShippable.Error()).
+				WithReasonf("order is %s", order.Status)
+		}
+
+		now := time.Now()
+		shipment = &orderEntities.Shipment{
+			OrderID:        order.ID,
+			Status:         constants.ShipmentStatusPending,
+			Carrier:        req.Carrier,
+			TrackingNumber: req.TrackingNumber,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+
+		shipment.Items, err = s.buildShipmentItems(ctx, order, req.Items, now)
+		if err != nil {
+			return err
+		}
+
+		shipment, err = s.shipmentRepo.Create(ctx, shipment)
+		if err != nil {
+			return err
+		}
+
+		return s.recordShipmentEvent(ctx, caller, constants.OrderEventShipmentCreated, shipment)
+	})
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	response := toShipmentResponse(shipment)
+	return &response, nil
+}
+
+// buildShipmentItems checks every requested line against what is left to
+// ship of its order item, units in cancelled shipments count as left
+func (s *shipmentService) buildShipmentItems(ctx context.Context, order *orderEntities.Order, reqItems []dto.ShipmentItemRequest, now time.Time) ([]*orderEntities.ShipmentItem, error) {
+	orderItems, err := s.orderRepo.GetItems(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int32]*orderEntities.OrderItem, len(orderItems))
+	for _, item := range orderItems {
+		byID[item.ID] = item
+	}
+
+	shipments, err := s.shipmentRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	allocated := orderEntities.AllocatedQuantities(shipments)
+
+	var items []*orderEntities.ShipmentItem
+	seen := make(map[int32]bool, len(reqItems))
+	for _, reqItem := range reqItems {
+		orderItem, ok := byID[reqItem.OrderItemID]
+		if !ok || seen[orderItem.ID] {
+			return nil, core.ErrBadRequest.
+				WithError(errorx.ErrShipmentItemNotFound.Error()).
+				WithReasonf("order item %d", reqItem.OrderItemID)
+		}
+		seen[orderItem.ID] = true
+
+		if left := orderItem.Quantity - allocated[orderItem.ID]; reqItem.Quantity > left {
+			return nil, core.ErrConflict.
+				WithError(errorx.ErrShipmentQuantityExceeded.Error()).
+				WithReasonf("order item %d has %d units left to ship", orderItem.ID, left)
+		}
+
+		items = append(items, &orderEntities.ShipmentItem{
+			OrderItemID: orderItem.ID,
+			Quantity:    reqItem.Quantity,
+			CreatedAt:   now,
+		})
+	}
+
+	return items, nil
+}
+
+func (s *shipmentService) UpdateShipment(ctx context.Context, caller orderEntities.Caller, shipmentID int32, req *dto.UpdateShipmentRequest) (*dto.ShipmentResponse, error) {
+	var next constants.ShipmentStatus
+	if req.Status != nil {
+		next = constants.ShipmentStatus(*req.Status)
+		if !next.IsValid() {
+			return nil, core.ErrBadRequest.WithError(errorx.ErrInvalidShipmentStatus.Error())
+		}
+	}
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		shipment, err := s.shipmentRepo.GetByIDForUpdate(ctx, shipmentID)
+		if err != nil {
+			return err
+		}
+
+		order, err := s.orderRepo.GetByIDForUpdate(ctx, shipment.OrderID)
+		if err != nil {
+			return err
+		}
+
+		if req.Carrier != nil {
+			shipment.Carrier = *req.Carrier
+		}
+		if req.TrackingNumber != nil {
+			shipment.TrackingNumber = req.TrackingNumber
+		}
+		shipment.UpdatedAt = time.Now()
+
+		moved := next != "" && next != shipment.Status
+		if moved {
+			if err := s.moveShipment(order, shipment, next); err != nil {
+				return err
+			}
+		}
+
+		if err := s.shipmentRepo.Update(ctx, shipment); err != nil {
+			return err
+		}
+
+		if err := s.recordShipmentEvent(ctx, caller, constants.OrderEventShipmentUpdated, shipment); err != nil {
+			return err
+		}
+
+		if !moved {
+			return nil
+		}
+
+		return s.fulfil(ctx, caller, order, shipment)
+	})
+	if err != nil {
+		return nil, wrapShipmentNotFound(err)
+	}
+
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, wrapShipmentNotFound(err)
+	}
+
+	response := toShipmentResponse(shipment)
+	return &response, nil
+}
+
+// moveShipment checks the shipment may move to next. Parcels only leave for
+// orders that are still being shipped, while cancelling one that has not
+// left is always possible.
+func (s *shipmentService) moveShipment(order *orderEntities.Order, shipment *orderEntities.Shipment, next constants.ShipmentStatus) error {
+	if next == constants.ShipmentStatusShipped &&
+		order.Status != constants.OrderStatusProcessing && order.Status != constants.OrderStatusPartiallyShipped {
+		return core.ErrConflict.
+			WithError(errorx.ErrOrderNotShippable.Error()).
+			WithReasonf("order is %s", order.Status)
+	}
+
+	current := shipment.Status
+	if !shipment.TransitionTo(next, shipment.UpdatedAt) {
+		return core.ErrConflict.
+			WithError(errorx.ErrInvalidShipmentStatusTransition.Error()).
+			WithReasonf("cannot move shipment from %s to %s", current, next)
+	}
+
+	return nil
+}
+
+// fulfil follows up on a shipment that changed status: stock of a parcel that
+// left is taken off hand, and the order moves to the status its shipments
+// now put it in
+func (s *shipmentService) fulfil(ctx context.Context, caller orderEntities.Caller, order *orderEntities.Order, shipment *orderEntities.Shipment) error {
+	orderItems, err := s.orderRepo.GetItems(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	shipments, err := s.shipmentRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	if shipment.Status == constants.ShipmentStatusShipped {
+		if err := s.inventory.Commit(ctx, shipmentStockLines(orderItems, shipments, shipment.ID)); err != nil {
+			return err
+		}
+	}
+
+	next, ok := orderEntities.FulfillmentStatus(orderItems, shipments)
+	if !ok || next == order.Status || !order.Status.CanTransitionTo(next) {
+		return nil
+	}
+
+	if err := s.orderRepo.UpdateStatus(ctx, order.ID, next); err != nil {
+		return err
+	}
+
+	event := orderEntities.NewOrderEvent(order.ID, constants.OrderEventStatusChanged, caller).
+		WithTransition(order.Status.String(), next.String()).
+		With("shipment_id", shipment.ID)
+	if err := s.eventRepo.Append(ctx, event); err != nil {
+		return err
+	}
+
+	return s.outbox.Add(ctx, newOrderStatusChangedEvent(order, order.Status, next, time.Now()))
+}
+
+// shipmentStockLines lists the products in the shipment with the given ID
+func shipmentStockLines(orderItems []*orderEntities.OrderItem, shipments []*orderEntities.Shipment, shipmentID int32) []productDto.StockLine {
+	products := make(map[int32]int32, len(orderItems))
+	for _, item := range orderItems {
+		products[item.ID] = item.ProductID
+	}
+
+	var lines []productDto.StockLine
+	for _, shipment := range shipments {
+		if shipment.ID != shipmentID {
+			continue
+		}
+		for _, item := range shipment.Items {
+			lines = append(lines, productDto.StockLine{ProductID: products[item.OrderItemID], Quantity: item.Quantity})
+		}
+	}
+	return lines
+}
+
+func (s *shipmentService) recordShipmentEvent(ctx context.Context, caller orderEntities.Caller, eventType constants.OrderEventType, shipment *orderEntities.Shipment) error {
+	event := orderEntities.NewOrderEvent(shipment.OrderID, eventType, caller).
+		With("shipment_id", shipment.ID).
+		With("status", shipment.Status).
+		With("carrier", shipment.Carrier)
+
+	if shipment.TrackingNumber != nil {
+		event.With("tracking_number", *shipment.TrackingNumber)
+	}
+
+	return s.eventRepo.Append(ctx, event)
+}
+
+func wrapShipmentNotFound(err error) error {
+	if errors.Is(err, errorx.ErrShipmentNotFound) {
+		return core.ErrNotFound.WithError(errorx.ErrShipmentNotFound.Error())
+	}
+	return wrapNotFound(err)
+}
+
+func toShipmentResponse(shipment *orderEntities.Shipment) dto.ShipmentResponse {
+	items := make([]dto.ShipmentItemResponse, 0, len(shipment.Items))
+	for _, item := range shipment.Items {
+		items = append(items, dto.ShipmentItemResponse{
+			ID:          item.ID,
+			OrderItemID: item.OrderItemID,
+			Quantity:    item.Quantity,
+		})
+	}
+
+	return dto.ShipmentResponse{
+		ID:             shipment.ID,
+		OrderID:        shipment.OrderID,
+		Status:         shipment.Status.String(),
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		Items:          items,
+		ShippedAt:      shipment.ShippedAt,
+		DeliveredAt:    shipment.DeliveredAt,
+		CancelledAt:    shipment.CancelledAt,
+		CreatedAt:      shipment.CreatedAt,
+		UpdatedAt:      shipment.UpdatedAt,
+	}
+}
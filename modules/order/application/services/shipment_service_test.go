@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"mallbots/modules/order/application/dto"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/modules/order/domain/events"
+	"mallbots/modules/order/domain/interfaces"
+	productDto "mallbots/modules/product/application/dto"
+	"mallbots/shared/errorx"
+	"net/http"
+	"testing"
+
+	"github.com/phathdt/service-context/core"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockShipmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockShipmentRepository) Create(ctx context.Context, shipment *entities.Shipment) (*entities.Shipment, error) {
+	args := m.Called(ctx, shipment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Shipment), args.Error(1)
+}
+
+func (m *MockShipmentRepository) GetByID(ctx context.Context, id int32) (*entities.Shipment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Shipment), args.Error(1)
+}
+
+func (m *MockShipmentRepository) GetByIDForUpdate(ctx context.Context, id int32) (*entities.Shipment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Shipment), args.Error(1)
+}
+
+func (m *MockShipmentRepository) GetByOrderID(ctx context.Context, orderID int32) ([]*entities.Shipment, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Shipment), args.Error(1)
+}
+
+func (m *MockShipmentRepository) Update(ctx context.Context, shipment *entities.Shipment) error {
+	args := m.Called(ctx, shipment)
+	return args.Error(0)
+}
+
+type shipmentTestSuite struct {
+	orderRepo       *MockOrderRepository
+	shipmentRepo    *MockShipmentRepository
+	inventory       *MockInventoryService
+	eventRepo       *MockOrderEventRepository
+	outbox          *MockOutboxWriter
+	shipmentService interfaces.ShipmentService
+	ctx             context.Context
+}
+
+func setupShipmentTest(t *testing.T) *shipmentTestSuite {
+	orderRepo := new(MockOrderRepository)
+	shipmentRepo := new(MockShipmentRepository)
+	inventory := new(MockInventoryService)
+	txManager := new(MockTxManager)
+	txManager.On("WithTx", mock.Anything).Return()
+	eventRepo := new(MockOrderEventRepository)
+	eventRepo.On("Append", mock.Anything, mock.Anything).Return(nil)
+	outboxWriter := newMockOutboxWriter()
+
+	return &shipmentTestSuite{
+		orderRepo:       orderRepo,
+		shipmentRepo:    shipmentRepo,
+		inventory:       inventory,
+		eventRepo:       eventRepo,
+		outbox:          outboxWriter,
+		shipmentService: NewShipmentService(orderRepo, shipmentRepo, inventory, txManager, eventRepo, outboxWriter),
+		ctx:             context.Background(),
+	}
+}
+
+func processingOrder(status constants.OrderStatus) *entities.Order {
+	return &entities.Order{ID: 1, UserID: 1, Status: status, PaymentStatus: constants.PaymentStatusPaid}
+}
+
+// Order item 10 is two units of product 1, order item 11 one unit of product 2
+func shippableItems() []*entities.OrderItem {
+	return []*entities.OrderItem{
+		{ID: 10, OrderID: 1, ProductID: 1, Quantity: 2},
+		{ID: 11, OrderID: 1, ProductID: 2, Quantity: 1},
+	}
+}
+
+func shipmentOf(id int32, status constants.ShipmentStatus, items ...*entities.ShipmentItem) *entities.Shipment {
+	return &entities.Shipment{ID: id, OrderID: 1, Status: status, Carrier: "DHL", Items: items}
+}
+
+func TestShipmentService(t *testing.T) {
+	t.Run("Create Shipment - Part Of The Order", func(t *testing.T) {
+		ts := setupShipmentTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(processingOrder(constants.OrderStatusProcessing), nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(1)).Return(shippableItems(), nil)
+		ts.shipmentRepo.On("GetByOrderID", ts.ctx, int32(1)).Return([]*entities.Shipment{
+			shipmentOf(1, constants.ShipmentStatusPending, &entities.ShipmentItem{OrderItemID: 10, Quantity: 1}),
+			shipmentOf(2, constants.ShipmentStatusCancelled, &entities.ShipmentItem{OrderItemID: 11, Quantity: 1}),
+		}, nil)
+		ts.shipmentRepo.On("Create", ts.ctx, mock.MatchedBy(func(shipment *entities.Shipment) bool {
+			return shipment.Status == constants.ShipmentStatusPending &&
+				shipment.Carrier == "DHL" &&
+				len(shipment.Items) == 2
+		})).Return(shipmentOf(3, constants.ShipmentStatusPending,
+			&entities.ShipmentItem{ID: 1, OrderItemID: 10, Quantity: 1},
+			&entities.ShipmentItem{ID: 2, OrderItemID: 11, Quantity: 1},
+		), nil)
+
+		shipment, err := ts.shipmentService.CreateShipment(ts.ctx, admin, 1, &dto.CreateShipmentRequest{
+			Carrier: "DHL",
+			Items: []dto.ShipmentItemRequest{
+				{OrderItemID: 10, Quantity: 1},
+				{OrderItemID: 11, Quantity: 1},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(3), shipment.ID)
+		require.Equal(t, constants.ShipmentStatusPending.String(), shipment.Status)
+		require.Len(t, shipment.Items, 2)
+		ts.eventRepo.AssertCalled(t, "Append", mock.Anything, mock.MatchedBy(func(event *entities.OrderEvent) bool {
+			return event.Type == constants.OrderEventShipmentCreated && event.Metadata["shipment_id"] == int32(3)
+		}))
+	})
+
+	t.Run("Create Shipment - More Than Is Left", func(t *testing.T) {
+		ts := setupShipmentTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(processingOrder(constants.OrderStatusPartiallyShipped), nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(1)).Return(shippableItems(), nil)
+		ts.shipmentRepo.On("GetByOrderID", ts.ctx, int32(1)).Return([]*entities.Shipment{
+			shipmentOf(1, constants.ShipmentStatusShipped, &entities.ShipmentItem{OrderItemID: 10, Quantity: 1}),
+		}, nil)
+
+		shipment, err := ts.shipmentService.CreateShipment(ts.ctx, admin, 1, &dto.CreateShipmentRequest{
+			Carrier: "DHL",
+			Items:   []dto.ShipmentItemRequest{{OrderItemID: 10, Quantity: 2}},
+		})
+		require.Nil(t, shipment)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrShipmentQuantityExceeded.Error(), appErr.Error())
+		ts.shipmentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Create Shipment - Item Of Another Order", func(t *testing.T) {
+		ts := setupShipmentTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(processingOrder(constants.OrderStatusProcessing), nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(1)).Return(shippableItems(), nil)
+		ts.shipmentRepo.On("GetByOrderID", ts.ctx, int32(1)).Return([]*entities.Shipment{}, nil)
+
+		_, err := ts.shipmentService.CreateShipment(ts.ctx, admin, 1, &dto.CreateShipmentRequest{
+			Carrier: "DHL",
+			Items:   []dto.ShipmentItemRequest{{OrderItemID: 99, Quantity: 1}},
+		})
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+		require.Equal(t, errorx.ErrShipmentItemNotFound.Error(), appErr.Error())
+	})
+
+	t.Run("Create Shipment - Order Not Being Processed", func(t *testing.T) {
+		ts := setupShipmentTest(t)
+
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(processingOrder(constants.OrderStatusConfirmed), nil)
+
+		_, err := ts.shipmentService.CreateShipment(ts.ctx, admin, 1, &dto.CreateShipmentRequest{
+			Carrier: "DHL",
+			Items:   []dto.ShipmentItemRequest{{OrderItemID: 10, Quantity: 1}},
+		})
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrOrderNotShippable.Error(), appErr.Error())
+	})
+
+	t.Run("Update Shipment - First Parcel Leaves", func(t *testing.T) {
+		ts := setupShipmentTest(t)
+
+		tracking := "1Z999"
+		ts.shipmentRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(shipmentOf(1, constants.ShipmentStatusPending), nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(processingOrder(constants.OrderStatusProcessing), nil)
+		ts.shipmentRepo.On("Update", ts.ctx, mock.MatchedBy(func(shipment *entities.Shipment) bool {
+			return shipment.Status == constants.ShipmentStatusShipped &&
+				shipment.ShippedAt != nil &&
+				*shipment.TrackingNumber == tracking
+		})).Return(nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(1)).Return(shippableItems(), nil)
+		ts.shipmentRepo.On("GetByOrderID", ts.ctx, int32(1)).Return([]*entities.Shipment{
+			shipmentOf(1, constants.ShipmentStatusShipped, &entities.ShipmentItem{OrderItemID: 10, Quantity: 2}),
+			shipmentOf(2, constants.ShipmentStatusPending, &entities.ShipmentItem{OrderItemID: 11, Quantity: 1}),
+		}, nil)
+		ts.inventory.On("Commit", ts.ctx, []productDto.StockLine{{ProductID: 1, Quantity: 2}}).Return(nil)
+		ts.orderRepo.On("UpdateStatus", ts.ctx, int32(1), constants.OrderStatusPartiallyShipped).Return(nil)
+		ts.shipmentRepo.On("GetByID", ts.ctx, int32(1)).Return(shipmentOf(1, constants.ShipmentStatusShipped), nil)
+
+		status := constants.ShipmentStatusShipped.String()
+		shipment, err := ts.shipmentService.UpdateShipment(ts.ctx, admin, 1, &dto.UpdateShipmentRequest{
+			Status:         &status,
+			TrackingNumber: &tracking,
+		})
+		require.NoError(t, err)
+		require.Equal(t, status, shipment.Status)
+
+		ts.inventory.AssertExpectations(t)
+		ts.orderRepo.AssertExpectations(t)
+		ts.eventRepo.AssertCalled(t, "Append", mock.Anything, mock.MatchedBy(func(event *entities.OrderEvent) bool {
+			return event.Type == constants.OrderEventStatusChanged &&
+				*event.FromStatus == constants.OrderStatusProcessing.String() &&
+				*event.ToStatus == constants.OrderStatusPartiallyShipped.String()
+		}))
+	})
+
+	t.Run("Update Shipment - Last Parcel Leaves", func(t *testing.T) {
+		ts := setupShipmentTest(t)
+
+		ts.shipmentRepo.On("GetByIDForUpdate", ts.ctx, int32(2)).Return(shipmentOf(2, constants.ShipmentStatusPending), nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(processingOrder(constants.OrderStatusPartiallyShipped), nil)
+		ts.shipmentRepo.On("Update", ts.ctx, mock.Anything).Return(nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(1)).Return(shippableItems(), nil)
+		ts.shipmentRepo.On("GetByOrderID", ts.ctx, int32(1)).Return([]*entities.Shipment{
+			shipmentOf(1, constants.ShipmentStatusDelivered, &entities.ShipmentItem{OrderItemID: 10, Quantity: 2}),
+			shipmentOf(2, constants.ShipmentStatusShipped, &entities.ShipmentItem{OrderItemID: 11, Quantity: 1}),
+		}, nil)
+		ts.inventory.On("Commit", ts.ctx, []productDto.StockLine{{ProductID: 2, Quantity: 1}}).Return(nil)
+		ts.orderRepo.On("UpdateStatus", ts.ctx, int32(1), constants.OrderStatusShipped).Return(nil)
+		ts.shipmentRepo.On("GetByID", ts.ctx, int32(2)).Return(shipmentOf(2, constants.ShipmentStatusShipped), nil)
+
+		status := constants.ShipmentStatusShipped.String()
+		_, err := ts.shipmentService.UpdateShipment(ts.ctx, admin, 2, &dto.UpdateShipmentRequest{Status: &status})
+		require.NoError(t, err)
+
+		published := ts.outbox.recordedEvents()
+		require.Len(t, published, 1)
+		changed := published[0].(*events.OrderStatusChanged)
+		require.Equal(t, constants.OrderStatusPartiallyShipped.String(), changed.From)
+		require.Equal(t, constants.OrderStatusShipped.String(), changed.To)
+
+		ts.orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Update Shipment - Everything Delivered", func(t *testing.T) {
+		ts := setupShipmentTest(t)
+
+		ts.shipmentRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(shipmentOf(1, constants.ShipmentStatusShipped), nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(processingOrder(constants.OrderStatusShipped), nil)
+		ts.shipmentRepo.On("Update", ts.ctx, mock.Anything).Return(nil)
+		ts.orderRepo.On("GetItems", ts.ctx, int32(1)).Return(shippableItems(), nil)
+		ts.shipmentRepo.On("GetByOrderID", ts.ctx, int32(1)).Return([]*entities.Shipment{
+			shipmentOf(1, constants.ShipmentStatusDelivered,
+				&entities.ShipmentItem{OrderItemID: 10, Quantity: 2},
+				&entities.ShipmentItem{OrderItemID: 11, Quantity: 1},
+			),
+		}, nil)
+		ts.orderRepo.On("UpdateStatus", ts.ctx, int32(1), constants.OrderStatusDelivered).Return(nil)
+		ts.shipmentRepo.On("GetByID", ts.ctx, int32(1)).Return(shipmentOf(1, constants.ShipmentStatusDelivered), nil)
+
+		status := constants.ShipmentStatusDelivered.String()
+		_, err := ts.shipmentService.UpdateShipment(ts.ctx, admin, 1, &dto.UpdateShipmentRequest{Status: &status})
+		require.NoError(t, err)
+
+		ts.orderRepo.AssertExpectations(t)
+		ts.inventory.AssertNotCalled(t, "Commit", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Update Shipment - Invalid Transition", func(t *testing.T) {
+		ts := setupShipmentTest(t)
+
+		ts.shipmentRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(shipmentOf(1, constants.ShipmentStatusShipped), nil)
+		ts.orderRepo.On("GetByIDForUpdate", ts.ctx, int32(1)).Return(processingOrder(constants.OrderStatusPartiallyShipped), nil)
+
+		status := constants.ShipmentStatusCancelled.String()
+		_, err := ts.shipmentService.UpdateShipment(ts.ctx, admin, 1, &dto.UpdateShipmentRequest{Status: &status})
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrInvalidShipmentStatusTransition.Error(), appErr.Error())
+		ts.shipmentRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Update Shipment - Not Found", func(t *testing.T) {
+		ts := setupShipmentTest(t)
+
+		ts.shipmentRepo.On("GetByIDForUpdate", ts.ctx, int32(9)).Return(nil, errorx.ErrShipmentNotFound)
+
+		carrier := "UPS"
+		_, err := ts.shipmentService.UpdateShipment(ts.ctx, admin, 9, &dto.UpdateShipmentRequest{Carrier: &carrier})
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusNotFound, appErr.StatusCode())
+	})
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"mallbots/modules/order/domain/constants"
+	orderEntities "mallbots/modules/order/domain/entities"
+	orderInterfaces "mallbots/modules/order/domain/interfaces"
+	"mallbots/shared/config"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"strconv"
+	"strings"
+
+	"github.com/phathdt/service-context/core"
+)
+
+const anyCountry = "*"
+
+type shippingZone struct {
+	config.ShippingZoneConfig
+	rateType  constants.ShippingRateType
+	countries map[string]bool
+	// priceBounds are the UpTo bounds of a price rate's tiers as amounts
+	priceBounds []money.Money
+}
+
+type tableShippingCalculator struct {
+	zones []shippingZone
+}
+
+// NewShippingCalculator prices shipping from the zone table in the config.
+// Mistakes in the table are reported here rather than on the first checkout.
+func NewShippingCalculator(cfg *config.Config) (orderInterfaces.ShippingCalculator, error) {
+	zones := make([]shippingZone, 0, len(cfg.Shipping.Zones))
+	for i, zoneCfg := range cfg.Shipping.Zones {
+		zone := shippingZone{
+			ShippingZoneConfig: zoneCfg,
+			rateType:           constants.ShippingRateType(zoneCfg.Rate.Type),
+			countries:          make(map[string]bool, len(zoneCfg.Countries)),
+		}
+		if zone.Name == "" {
+			zone.Name = fmt.Sprintf("zone %d", i+1)
+		}
+
+		if !zone.rateType.IsValid() {
+			return nil, fmt.Errorf("shipping %s: unknown rate type %q", zone.Name, zoneCfg.Rate.Type)
+		}
+		if zone.rateType != constants.ShippingRateFlat && len(zoneCfg.Rate.Tiers) == 0 {
+			return nil, fmt.Errorf("shipping %s: %s rate needs at least one tier", zone.Name, zone.rateType)
+		}
+		if len(zoneCfg.Countries) == 0 {
+			return nil, fmt.Errorf("shipping %s: no countries", zone.Name)
+		}
+
+		for _, country := range zoneCfg.Countries {
+			zone.countries[normalizeCountry(country)] = true
+		}
+
+		if zone.rateType == constants.ShippingRatePrice {
+			for _, tier := range zoneCfg.Rate.Tiers {
+				bound, err := money.Parse(strconv.FormatFloat(tier.UpTo, 'f', -1, 64), money.DefaultCurrency)
+				if err != nil {
+					return nil, fmt.Errorf("shipping %s: tier bound %v is not an amount", zone.Name, tier.UpTo)
+				}
+				zone.priceBounds = append(zone.priceBounds, bound)
+			}
+		}
+
+		zones = append(zones, zone)
+	}
+
+	return &tableShippingCalculator{zones: zones}, nil
+}
+
+func (c *tableShippingCalculator) Quote(ctx context.Context, parcel orderEntities.Parcel) (*orderEntities.ShippingQuote, error) {
+	zone := c.findZone(parcel.Country, parcel.Zip)
+	if zone == nil {
+		return nil, core.ErrBadRequest.
+			WithError(errorx.ErrInvalidShippingCountry.Error()).
+			WithReasonf("no shipping zone covers %s %s", parcel.Country, parcel.Zip)
+	}
+
+	quote := &orderEntities.ShippingQuote{
+		Zone:      zone.Name,
+		Amount:    money.Zero(parcel.Subtotal.Currency()),
+		FreeAbove: zone.FreeAbove,
+	}
+
+	if zone.FreeAbove.IsPositive() && !parcel.Subtotal.LessThan(zone.FreeAbove) {
+		quote.FreeShipping = true
+		return quote, nil
+	}
+
+	switch zone.rateType {
+	case constants.ShippingRateFlat:
+		quote.Amount = quote.Amount.Add(zone.Rate.Amount)
+	case constants.ShippingRateWeight:
+		amount, ok := tierAmount(zone.Rate.Tiers, func(i int) bool {
+			return float64(parcel.Weight) <= zone.Rate.Tiers[i].UpTo
+		})
+		if !ok {
+			return nil, core.ErrBadRequest.
+				WithError(errorx.ErrShippingCalculationFailed.Error()).
+				WithReasonf("%s does not ship parcels of %dg", zone.Name, parcel.Weight)
+		}
+		quote.Amount = quote.Amount.Add(amount)
+	case constants.ShippingRatePrice:
+		amount, ok := tierAmount(zone.Rate.Tiers, func(i int) bool {
+			return !parcel.Subtotal.GreaterThan(zone.priceBounds[i])
+		})
+		if !ok {
+			return nil, core.ErrBadRequest.
+				WithError(errorx.ErrShippingCalculationFailed.Error()).
+				WithReasonf("%s does not ship orders of %s", zone.Name, parcel.Subtotal)
+		}
+		quote.Amount = quote.Amount.Add(amount)
+	}
+
+	return quote, nil
+}
+
+// findZone returns the first zone serving the country whose zip prefixes, if
+// any, match the zip code
+func (c *tableShippingCalculator) findZone(country, zip string) *shippingZone {
+	country = normalizeCountry(country)
+	zip = strings.ToUpper(strings.ReplaceAll(zip, " ", ""))
+
+	for i := range c.zones {
+		zone := &c.zones[i]
+		if !zone.countries[country] && !zone.countries[anyCountry] {
+			continue
+		}
+
+		if len(zone.ZipPrefixes) == 0 {
+			return zone
+		}
+		for _, prefix := range zone.ZipPrefixes {
+			if strings.HasPrefix(zip, strings.ToUpper(prefix)) {
+				return zone
+			}
+		}
+	}
+
+	return nil
+}
+
+// tierAmount returns the amount of the first tier that is unbounded or whose
+// bound fits
+func tierAmount(tiers []config.ShippingTierConfig, fits func(i int) bool) (money.Money, bool) {
+	for i, tier := range tiers {
+		if tier.UpTo == 0 || fits(i) {
+			return tier.Amount, true
+		}
+	}
+	return money.Money{}, false
+}
+
+func normalizeCountry(country string) string {
+	return strings.ToUpper(strings.TrimSpace(country))
+}
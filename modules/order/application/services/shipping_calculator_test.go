@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/shared/config"
+	"mallbots/shared/errorx"
+	"net/http"
+	"testing"
+
+	"github.com/phathdt/service-context/core"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestShippingCalculator ships to Test Country for 4.99, free from 20
+func newTestShippingCalculator(t *testing.T) interfaces.ShippingCalculator {
+	calculator, err := NewShippingCalculator(&config.Config{
+		Shipping: config.ShippingConfig{
+			Zones: []config.ShippingZoneConfig{
+				{
+					Name:      "domestic",
+					Countries: []string{"Test Country"},
+					FreeAbove: usd("20"),
+					Rate:      config.ShippingRateConfig{Type: "flat", Amount: usd("4.99")},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return calculator
+}
+
+func TestShippingCalculator(t *testing.T) {
+	ctx := context.Background()
+
+	calculator, err := NewShippingCalculator(&config.Config{
+		Shipping: config.ShippingConfig{
+			Zones: []config.ShippingZoneConfig{
+				{
+					Name:        "metro",
+					Countries:   []string{"VN"},
+					ZipPrefixes: []string{"70"},
+					FreeAbove:   usd("50"),
+					Rate:        config.ShippingRateConfig{Type: "flat", Amount: usd("2")},
+				},
+				{
+					Name:      "domestic",
+					Countries: []string{"vn"},
+					Rate: config.ShippingRateConfig{Type: "weight", Tiers: []config.ShippingTierConfig{
+						{UpTo: 1000, Amount: usd("3")},
+						{UpTo: 5000, Amount: usd("6")},
+					}},
+				},
+				{
+					Name:      "international",
+					Countries: []string{"*"},
+					Rate: config.ShippingRateConfig{Type: "price", Tiers: []config.ShippingTierConfig{
+						{UpTo: 100, Amount: usd("25")},
+						{Amount: usd("40")},
+					}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("Zip Prefix Zone Wins", func(t *testing.T) {
+		quote, err := calculator.Quote(ctx, entities.Parcel{Country: "VN", Zip: "700000", Subtotal: usd("10"), Weight: 4000})
+		require.NoError(t, err)
+		require.Equal(t, "metro", quote.Zone)
+		require.Equal(t, usd("2"), quote.Amount)
+		require.Equal(t, usd("50"), quote.FreeAbove)
+		require.False(t, quote.FreeShipping)
+	})
+
+	t.Run("Free Above Threshold", func(t *testing.T) {
+		quote, err := calculator.Quote(ctx, entities.Parcel{Country: "VN", Zip: "700000", Subtotal: usd("50")})
+		require.NoError(t, err)
+		require.True(t, quote.FreeShipping)
+		require.True(t, quote.Amount.IsZero())
+	})
+
+	t.Run("Weight Tiers", func(t *testing.T) {
+		quote, err := calculator.Quote(ctx, entities.Parcel{Country: " vn ", Zip: "100000", Weight: 1000})
+		require.NoError(t, err)
+		require.Equal(t, "domestic", quote.Zone)
+		require.Equal(t, usd("3"), quote.Amount)
+
+		quote, err = calculator.Quote(ctx, entities.Parcel{Country: "VN", Zip: "100000", Weight: 1001})
+		require.NoError(t, err)
+		require.Equal(t, usd("6"), quote.Amount)
+	})
+
+	t.Run("Too Heavy For Every Tier", func(t *testing.T) {
+		_, err := calculator.Quote(ctx, entities.Parcel{Country: "VN", Zip: "100000", Weight: 5001})
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+		require.Equal(t, errorx.ErrShippingCalculationFailed.Error(), appErr.Error())
+	})
+
+	t.Run("Price Tiers With Open Last Tier", func(t *testing.T) {
+		quote, err := calculator.Quote(ctx, entities.Parcel{Country: "US", Subtotal: usd("99.99")})
+		require.NoError(t, err)
+		require.Equal(t, "international", quote.Zone)
+		require.Equal(t, usd("25"), quote.Amount)
+
+		quote, err = calculator.Quote(ctx, entities.Parcel{Country: "US", Subtotal: usd("1000")})
+		require.NoError(t, err)
+		require.Equal(t, usd("40"), quote.Amount)
+	})
+
+	t.Run("Country Not Served", func(t *testing.T) {
+		_, err := newTestShippingCalculator(t).Quote(ctx, entities.Parcel{Country: "Elsewhere"})
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+		require.Equal(t, errorx.ErrInvalidShippingCountry.Error(), appErr.Error())
+	})
+
+	t.Run("Invalid Rate Type", func(t *testing.T) {
+		_, err := NewShippingCalculator(&config.Config{
+			Shipping: config.ShippingConfig{
+				Zones: []config.ShippingZoneConfig{
+					{Name: "broken", Countries: []string{"*"}, Rate: config.ShippingRateConfig{Type: "volume"}},
+				},
+			},
+		})
+		require.ErrorContains(t, err, `unknown rate type "volume"`)
+	})
+
+	t.Run("Tiered Rate Without Tiers", func(t *testing.T) {
+		_, err := NewShippingCalculator(&config.Config{
+			Shipping: config.ShippingConfig{
+				Zones: []config.ShippingZoneConfig{
+					{Name: "broken", Countries: []string{"*"}, Rate: config.ShippingRateConfig{Type: "weight"}},
+				},
+			},
+		})
+		require.ErrorContains(t, err, "needs at least one tier")
+	})
+}
@@ -0,0 +1,13 @@
+package constants
+
+// OrderAction names what a caller wants to do with an order, so the access
+// policy can grant each one separately
+type OrderAction string
+
+const (
+	OrderActionView          OrderAction = "VIEW"
+	OrderActionCancel        OrderAction = "CANCEL"
+	OrderActionRequestRefund OrderAction = "REQUEST_REFUND"
+	OrderActionPay           OrderAction = "PAY"
+	OrderActionReorder       OrderAction = "REORDER"
+)
@@ -0,0 +1,29 @@
+package constants
+
+// InvoiceFormat is a document format invoices are rendered in
+type InvoiceFormat string
+
+const (
+	InvoiceFormatHTML InvoiceFormat = "html"
+	InvoiceFormatPDF  InvoiceFormat = "pdf"
+)
+
+func (f InvoiceFormat) String() string {
+	return string(f)
+}
+
+func (f InvoiceFormat) IsValid() bool {
+	switch f {
+	case InvoiceFormatHTML, InvoiceFormatPDF:
+		return true
+	}
+	return false
+}
+
+// ContentType is the media type documents of the format are served as
+func (f InvoiceFormat) ContentType() string {
+	if f == InvoiceFormatPDF {
+		return "application/pdf"
+	}
+	return "text/html; charset=utf-8"
+}
@@ -0,0 +1,26 @@
+package constants
+
+// OrderEventType classifies an entry of the order timeline
+type OrderEventType string
+
+const (
+	OrderEventCreated              OrderEventType = "ORDER_CREATED"
+	OrderEventStatusChanged        OrderEventType = "STATUS_CHANGED"
+	OrderEventPaymentStatusChanged OrderEventType = "PAYMENT_STATUS_CHANGED"
+	OrderEventCancelled            OrderEventType = "ORDER_CANCELLED"
+	OrderEventRefundRequested      OrderEventType = "REFUND_REQUESTED"
+	OrderEventRefundApproved       OrderEventType = "REFUND_APPROVED"
+	OrderEventRefundRejected       OrderEventType = "REFUND_REJECTED"
+	OrderEventRefundProcessed      OrderEventType = "REFUND_PROCESSED"
+	// OrderEventPaymentFlagged marks a provider notification that could not
+	// be applied and needs a look by hand
+	OrderEventPaymentFlagged  OrderEventType = "PAYMENT_FLAGGED"
+	OrderEventInvoiceIssued   OrderEventType = "INVOICE_ISSUED"
+	OrderEventShipmentCreated OrderEventType = "SHIPMENT_CREATED"
+	OrderEventShipmentUpdated OrderEventType = "SHIPMENT_UPDATED"
+)
+
+// String returns the string representation of the OrderEventType
+func (t OrderEventType) String() string {
+	return string(t)
+}
@@ -0,0 +1,29 @@
+package constants
+
+// OrderSort is the order a customer's order history is listed in
+type OrderSort string
+
+const (
+	OrderSortCreatedAtDesc OrderSort = "created_at_desc"
+	OrderSortCreatedAtAsc  OrderSort = "created_at_asc"
+	OrderSortTotalAsc      OrderSort = "total_asc"
+	OrderSortTotalDesc     OrderSort = "total_desc"
+)
+
+func (s OrderSort) String() string {
+	return string(s)
+}
+
+func (s OrderSort) IsValid() bool {
+	switch s {
+	case OrderSortCreatedAtDesc, OrderSortCreatedAtAsc, OrderSortTotalAsc, OrderSortTotalDesc:
+		return true
+	}
+	return false
+}
+
+// ByTotal tells whether the sort compares order totals, which only compare
+// within one currency
+func (s OrderSort) ByTotal() bool {
+	return s == OrderSortTotalAsc || s == OrderSortTotalDesc
+}
@@ -0,0 +1,48 @@
+package constants
+
+// RefundStatus represents the current state of a refund
+type RefundStatus string
+
+const (
+	RefundStatusRequested RefundStatus = "REQUESTED"
+	RefundStatusApproved  RefundStatus = "APPROVED"
+	// RefundStatusProcessing marks a refund handed to the provider whose
+	// answer is not recorded yet
+	RefundStatusProcessing RefundStatus = "PROCESSING"
+	RefundStatusProcessed  RefundStatus = "PROCESSED"
+	RefundStatusRejected   RefundStatus = "REJECTED"
+)
+
+// IsValid checks if the refund status is valid
+func (s RefundStatus) IsValid() bool {
+	switch s {
+	case RefundStatusRequested, RefundStatusApproved, RefundStatusProcessing,
+		RefundStatusProcessed, RefundStatusRejected:
+		return true
+	}
+	return false
+}
+
+// String returns the string representation of the RefundStatus
+func (s RefundStatus) String() string {
+	return string(s)
+}
+
+// refundStatusTransitions declares every allowed move of the refund
+// lifecycle. Statuses without an entry are terminal.
+var refundStatusTransitions = map[RefundStatus][]RefundStatus{
+	RefundStatusRequested: {RefundStatusApproved, RefundStatusRejected},
+	RefundStatusApproved:  {RefundStatusProcessing},
+	// A refund the provider failed to pay goes back to be processed again
+	RefundStatusProcessing: {RefundStatusProcessed, RefundStatusApproved},
+}
+
+// CanTransitionTo reports whether a refund may move from s to next
+func (s RefundStatus) CanTransitionTo(next RefundStatus) bool {
+	for _, allowed := range refundStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package constants
+
+// ReorderItemStatus tells what became of an order item copied back into the
+// cart
+type ReorderItemStatus string
+
+const (
+	// ReorderItemAdded means the full quantity went into the cart
+	ReorderItemAdded ReorderItemStatus = "ADDED"
+	// ReorderItemAdjusted means less than the ordered quantity went into the
+	// cart, as much as is still in stock
+	ReorderItemAdjusted ReorderItemStatus = "ADJUSTED"
+	// ReorderItemSkipped means nothing went into the cart
+	ReorderItemSkipped ReorderItemStatus = "SKIPPED"
+)
+
+// String returns the string representation of the ReorderItemStatus
+func (s ReorderItemStatus) String() string {
+	return string(s)
+}
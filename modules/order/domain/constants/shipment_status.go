@@ -0,0 +1,54 @@
+package constants
+
+// ShipmentStatus represents the current state of a shipment
+type ShipmentStatus string
+
+const (
+	ShipmentStatusPending   ShipmentStatus = "PENDING"
+	ShipmentStatusShipped   ShipmentStatus = "SHIPPED"
+	ShipmentStatusDelivered ShipmentStatus = "DELIVERED"
+	ShipmentStatusCancelled ShipmentStatus = "CANCELLED"
+)
+
+// IsValid checks if the shipment status is valid
+func (s ShipmentStatus) IsValid() bool {
+	switch s {
+	case ShipmentStatusPending, ShipmentStatusShipped,
+		ShipmentStatusDelivered, ShipmentStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// String returns the string representation of the ShipmentStatus
+func (s ShipmentStatus) String() string {
+	return string(s)
+}
+
+// shipmentStatusTransitions declares every allowed move of the shipment
+// lifecycle. Only a parcel that has not left yet can be cancelled. Statuses
+// without an entry are terminal.
+var shipmentStatusTransitions = map[ShipmentStatus][]ShipmentStatus{
+	ShipmentStatusPending: {ShipmentStatusShipped, ShipmentStatusCancelled},
+	ShipmentStatusShipped: {ShipmentStatusDelivered},
+}
+
+// CanTransitionTo reports whether a shipment may move from s to next
+func (s ShipmentStatus) CanTransitionTo(next ShipmentStatus) bool {
+	for _, allowed := range shipmentStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal reports whether no further transition is possible from s
+func (s ShipmentStatus) IsFinal() bool {
+	return len(shipmentStatusTransitions[s]) == 0
+}
+
+// HasLeft reports whether the items of a shipment in s are out of the warehouse
+func (s ShipmentStatus) HasLeft() bool {
+	return s == ShipmentStatusShipped || s == ShipmentStatusDelivered
+}
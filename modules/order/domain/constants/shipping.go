@@ -0,0 +1,22 @@
+package constants
+
+// ShippingRateType is how a shipping zone prices an order
+type ShippingRateType string
+
+const (
+	ShippingRateFlat   ShippingRateType = "flat"
+	ShippingRateWeight ShippingRateType = "weight"
+	ShippingRatePrice  ShippingRateType = "price"
+)
+
+func (t ShippingRateType) String() string {
+	return string(t)
+}
+
+func (t ShippingRateType) IsValid() bool {
+	switch t {
+	case ShippingRateFlat, ShippingRateWeight, ShippingRatePrice:
+		return true
+	}
+	return false
+}
@@ -7,18 +7,21 @@ const (
 	OrderStatusPending    OrderStatus = "PENDING"
 	OrderStatusConfirmed  OrderStatus = "CONFIRMED"
 	OrderStatusProcessing OrderStatus = "PROCESSING"
-	OrderStatusShipped    OrderStatus = "SHIPPED"
-	OrderStatusDelivered  OrderStatus = "DELIVERED"
-	OrderStatusCancelled  OrderStatus = "CANCELLED"
-	OrderStatusRefunded   OrderStatus = "REFUNDED"
+	// OrderStatusPartiallyShipped means some of the items have left the
+	// warehouse and the rest is still to follow
+	OrderStatusPartiallyShipped OrderStatus = "PARTIALLY_SHIPPED"
+	OrderStatusShipped          OrderStatus = "SHIPPED"
+	OrderStatusDelivered        OrderStatus = "DELIVERED"
+	OrderStatusCancelled        OrderStatus = "CANCELLED"
+	OrderStatusRefunded         OrderStatus = "REFUNDED"
 )
 
 // IsValid checks if the order status is valid
 func (s OrderStatus) IsValid() bool {
 	switch s {
 	case OrderStatusPending, OrderStatusConfirmed, OrderStatusProcessing,
-		OrderStatusShipped, OrderStatusDelivered, OrderStatusCancelled,
-		OrderStatusRefunded:
+		OrderStatusPartiallyShipped, OrderStatusShipped, OrderStatusDelivered,
+		OrderStatusCancelled, OrderStatusRefunded:
 		return true
 	}
 	return false
@@ -29,6 +32,38 @@ func (s OrderStatus) String() string {
 	return string(s)
 }
 
+// orderStatusTransitions declares every allowed move of the order lifecycle.
+// Statuses without an entry are terminal.
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:          {OrderStatusConfirmed, OrderStatusCancelled},
+	OrderStatusConfirmed:        {OrderStatusProcessing, OrderStatusCancelled},
+	OrderStatusProcessing:       {OrderStatusPartiallyShipped, OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusPartiallyShipped: {OrderStatusShipped},
+	OrderStatusShipped:          {OrderStatusDelivered},
+	OrderStatusDelivered:        {OrderStatusRefunded},
+}
+
+// IsDerivedFromShipments reports whether s is only ever reached through the
+// shipments of an order rather than set by hand
+func (s OrderStatus) IsDerivedFromShipments() bool {
+	return s == OrderStatusPartiallyShipped || s == OrderStatusShipped
+}
+
+// CanTransitionTo reports whether an order may move from s to next
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal reports whether no further transition is possible from s
+func (s OrderStatus) IsFinal() bool {
+	return len(orderStatusTransitions[s]) == 0
+}
+
 // PaymentStatus represents the current state of a payment
 type PaymentStatus string
 
@@ -37,13 +72,15 @@ const (
 	PaymentStatusPaid     PaymentStatus = "PAID"
 	PaymentStatusFailed   PaymentStatus = "FAILED"
 	PaymentStatusRefunded PaymentStatus = "REFUNDED"
+	PaymentStatusVoided   PaymentStatus = "VOIDED"
 )
 
 // IsValid checks if the payment status is valid
 func (s PaymentStatus) IsValid() bool {
 	switch s {
 	case PaymentStatusPending, PaymentStatusPaid,
-		PaymentStatusFailed, PaymentStatusRefunded:
+		PaymentStatusFailed, PaymentStatusRefunded,
+		PaymentStatusVoided:
 		return true
 	}
 	return false
@@ -53,3 +90,27 @@ func (s PaymentStatus) IsValid() bool {
 func (s PaymentStatus) String() string {
 	return string(s)
 }
+
+// paymentStatusTransitions declares every allowed move of the payment
+// lifecycle. A failed payment may be retried, an uncaptured one is voided
+// when its order is cancelled. Statuses without an entry are terminal.
+var paymentStatusTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentStatusPending: {PaymentStatusPaid, PaymentStatusFailed, PaymentStatusVoided},
+	PaymentStatusFailed:  {PaymentStatusPending, PaymentStatusPaid, PaymentStatusVoided},
+	PaymentStatusPaid:    {PaymentStatusRefunded},
+}
+
+// CanTransitionTo reports whether a payment may move from s to next
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	for _, allowed := range paymentStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal reports whether no further transition is possible from s
+func (s PaymentStatus) IsFinal() bool {
+	return len(paymentStatusTransitions[s]) == 0
+}
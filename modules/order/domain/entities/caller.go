@@ -0,0 +1,16 @@
+package entities
+
+import "mallbots/shared/common"
+
+// Caller is the authenticated user acting on an order
+type Caller struct {
+	UserID int32
+	Role   string
+}
+
+func (c Caller) IsAdmin() bool {
+	return c.Role == common.RoleAdmin
+}
+
+// SystemCaller acts on behalf of the application itself, e.g. background jobs
+var SystemCaller = Caller{Role: common.RoleSystem}
@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"fmt"
+	"mallbots/shared/money"
+	"time"
+)
+
+// Invoice is the billing document of an order. Sequence runs without gaps
+// within Year, Number is how it is printed.
+type Invoice struct {
+	ID       int32
+	OrderID  int32
+	Year     int32
+	Sequence int32
+	Number   string
+	IssuedAt time.Time
+}
+
+// NewInvoice numbers the invoice of an order as the given sequence of the year
+// it is issued in
+func NewInvoice(orderID int32, prefix string, sequence int32, issuedAt time.Time) *Invoice {
+	year := int32(issuedAt.Year())
+
+	return &Invoice{
+		OrderID:  orderID,
+		Year:     year,
+		Sequence: sequence,
+		Number:   fmt.Sprintf("%s-%d-%06d", prefix, year, sequence),
+		IssuedAt: issuedAt,
+	}
+}
+
+// InvoiceDocument is everything printed on an invoice, in the order currency
+type InvoiceDocument struct {
+	Number    string
+	IssuedAt  time.Time
+	OrderID   int32
+	OrderedAt time.Time
+	Seller    InvoiceParty
+	BillTo    InvoiceParty
+	ShipTo    InvoiceParty
+	Currency  string
+	Lines     []InvoiceLine
+	// Subtotal sums the line amounts, the total takes DiscountAmount off it
+	// and adds shipping, and tax unless the prices include it
+	Subtotal       money.Money
+	ShippingAmount money.Money
+	DiscountAmount money.Money
+	CouponCode     string
+	TaxAmount      money.Money
+	// TaxInclusive means the prices already contain TaxAmount
+	TaxInclusive bool
+	TotalAmount  money.Money
+}
+
+type InvoiceParty struct {
+	Name    string
+	Email   string
+	TaxID   string
+	Address string
+	City    string
+	Country string
+	Zip     string
+}
+
+type InvoiceLine struct {
+	ProductID   int32
+	Description string
+	Quantity    int32
+	UnitPrice   money.Money
+	// Amount is UnitPrice times Quantity, Discount the share of the order
+	// discount it received
+	Amount    money.Money
+	Discount  money.Money
+	TaxRate   float64
+	TaxAmount money.Money
+}
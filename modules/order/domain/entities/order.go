@@ -4,22 +4,44 @@ package entities
 
 import (
 	"mallbots/modules/order/domain/constants"
+	"mallbots/shared/money"
 	"time"
 )
 
 type Order struct {
-	ID              int32
-	UserID          int32
-	Status          constants.OrderStatus
-	PaymentStatus   constants.PaymentStatus
-	TotalAmount     float64
+	ID            int32
+	UserID        int32
+	Status        constants.OrderStatus
+	PaymentStatus constants.PaymentStatus
+	// Currency is what every amount of the order and its items is in
+	Currency money.Currency
+	// ExchangeRate is the rate of Currency against the base currency the
+	// order was placed at, ExchangeRateID the published rate it was read
+	// from, nil for the base currency
+	ExchangeRateID  *int32
+	ExchangeRate    money.Rate
+	TotalAmount     money.Money
+	ShippingAmount  money.Money
+	TaxAmount       money.Money
+	TaxInclusive    bool
+	DiscountAmount  money.Money
+	CouponCode      *string
 	ShippingAddress string
 	ShippingCity    string
 	ShippingCountry string
 	ShippingZip     string
+	BillingAddress  string
+	BillingCity     string
+	BillingCountry  string
+	BillingZip      string
+	CancelReason    *string
+	CancelledAt     *time.Time
+	PaymentProvider *string
+	PaymentIntentID *string
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 	Items           []*OrderItem
+	Shipments       []*Shipment
 }
 
 func (o *Order) CanBeCancelled() bool {
@@ -31,3 +53,21 @@ func (o *Order) CanBeRefunded() bool {
 	return o.Status == constants.OrderStatusDelivered &&
 		o.PaymentStatus == constants.PaymentStatusPaid
 }
+
+// Cancel moves the order to CANCELLED and settles the payment status: a
+// captured payment becomes REFUNDED, anything not yet captured VOIDED. The
+// caller moves the money with the provider beforehand.
+func (o *Order) Cancel(reason string, at time.Time) {
+	o.Status = constants.OrderStatusCancelled
+
+	switch {
+	case o.PaymentStatus == constants.PaymentStatusPaid:
+		o.PaymentStatus = constants.PaymentStatusRefunded
+	case o.PaymentStatus.CanTransitionTo(constants.PaymentStatusVoided):
+		o.PaymentStatus = constants.PaymentStatusVoided
+	}
+
+	o.CancelReason = &reason
+	o.CancelledAt = &at
+	o.UpdatedAt = at
+}
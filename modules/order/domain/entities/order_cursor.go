@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/shared/money"
+	"time"
+)
+
+// OrderCursor marks the last order of a page of order history, the next page
+// starts right after it. Clients only ever see it encoded.
+type OrderCursor struct {
+	SortBy    constants.OrderSort `json:"s"`
+	CreatedAt time.Time           `json:"c"`
+	Total     money.Minor         `json:"t"`
+	ID        int32               `json:"i"`
+}
+
+// CursorAfter is the cursor of the page following the given order
+func CursorAfter(order *Order, sortBy constants.OrderSort) *OrderCursor {
+	return &OrderCursor{
+		SortBy:    sortBy,
+		CreatedAt: order.CreatedAt,
+		Total:     order.TotalAmount.Minor(),
+		ID:        order.ID,
+	}
+}
+
+// Encode turns the cursor into the opaque token handed to clients
+func (c *OrderCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// ParseOrderCursor reads back a token produced by Encode
+func ParseOrderCursor(token string) (*OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor OrderCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.ID <= 0 || !cursor.SortBy.IsValid() {
+		return nil, errors.New("cursor does not point at an order")
+	}
+
+	return &cursor, nil
+}
@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"mallbots/modules/order/domain/constants"
+	"time"
+)
+
+// OrderEvent is one immutable entry of an order's timeline. ActorID is nil
+// when the system acted on its own.
+type OrderEvent struct {
+	ID         int32
+	OrderID    int32
+	Type       constants.OrderEventType
+	FromStatus *string
+	ToStatus   *string
+	ActorID    *int32
+	ActorRole  string
+	Metadata   map[string]any
+	CreatedAt  time.Time
+}
+
+// NewOrderEvent starts a timeline entry for an action taken by caller
+func NewOrderEvent(orderID int32, eventType constants.OrderEventType, caller Caller) *OrderEvent {
+	event := &OrderEvent{
+		OrderID:   orderID,
+		Type:      eventType,
+		ActorRole: caller.Role,
+		Metadata:  map[string]any{},
+		CreatedAt: time.Now(),
+	}
+
+	if caller.UserID != 0 {
+		actorID := caller.UserID
+		event.ActorID = &actorID
+	}
+
+	return event
+}
+
+// WithTransition records the status the order moved from and to. An empty
+// from is left unset, as for a newly created order.
+func (e *OrderEvent) WithTransition(from, to string) *OrderEvent {
+	if from != "" {
+		e.FromStatus = &from
+	}
+	e.ToStatus = &to
+	return e
+}
+
+// With adds a metadata entry
+func (e *OrderEvent) With(key string, value any) *OrderEvent {
+	e.Metadata[key] = value
+	return e
+}
@@ -1,13 +1,47 @@
 package entities
 
-import "time"
+import (
+	"mallbots/shared/money"
+	"time"
+)
 
 type OrderItem struct {
 	ID        int32
 	OrderID   int32
 	ProductID int32
 	Quantity  int32
-	Price     float64
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Price     money.Money
+	// TaxRate is a percentage, 10 means 10%
+	TaxRate        float64
+	TaxAmount      money.Money
+	DiscountAmount money.Money
+	// ProductPrice is the product's own price Price was converted from, at
+	// ExchangeRate, the rate of its currency against the base currency
+	ProductPrice   money.Money
+	ExchangeRateID *int32
+	ExchangeRate   money.Rate
+	// The product as it was when the order was placed, orders keep showing
+	// it after the product is renamed, moved or deleted. Items ordered before
+	// snapshots were taken may lack the category of a deleted product.
+	ProductName        string
+	ProductDescription *string
+	CategoryID         *int32
+	CategoryName       *string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// AmountFor is what the customer paid for quantity units of the item: its
+// price less its share of the discount, plus its share of tax that was
+// charged on top of the price. Shares are rounded half away from zero.
+func (i *OrderItem) AmountFor(quantity int32, taxInclusive bool) money.Money {
+	if i.Quantity == 0 {
+		return money.Zero(i.Price.Currency())
+	}
+
+	paid := i.Price.Mul(int64(i.Quantity)).Sub(i.DiscountAmount)
+	if !taxInclusive {
+		paid = paid.Add(i.TaxAmount)
+	}
+	return paid.Ratio(int64(quantity), int64(i.Quantity))
 }
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// PaymentWebhookEvent is a provider notification that has been applied to an order
+type PaymentWebhookEvent struct {
+	Provider  string
+	EventID   string
+	EventType string
+	OrderID   int32
+	Payload   []byte
+	CreatedAt time.Time
+}
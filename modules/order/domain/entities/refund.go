@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"mallbots/modules/order/domain/constants"
+	"mallbots/shared/money"
+	"time"
+)
+
+type Refund struct {
+	ID               int32
+	OrderID          int32
+	UserID           int32
+	Status           constants.RefundStatus
+	Amount           money.Money
+	Reason           string
+	ApprovedAt       *time.Time
+	ProcessedAt      *time.Time
+	RejectedAt       *time.Time
+	ProviderRefundID *string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Items            []*RefundItem
+}
+
+// RefundItem records how many units of an order item a refund gives back.
+// Refunds for a custom amount have no items.
+type RefundItem struct {
+	ID          int32
+	RefundID    int32
+	OrderItemID int32
+	Quantity    int32
+	Amount      money.Money
+	CreatedAt   time.Time
+}
+
+// TransitionTo moves the refund to next and stamps the matching timestamp.
+// It reports false and leaves the refund untouched when the move is not allowed
+func (r *Refund) TransitionTo(next constants.RefundStatus, at time.Time) bool {
+	if !r.Status.CanTransitionTo(next) {
+		return false
+	}
+
+	switch next {
+	case constants.RefundStatusApproved:
+		// A refund put back after a failed payment keeps its approval time
+		if r.ApprovedAt == nil {
+			r.ApprovedAt = &at
+		}
+	case constants.RefundStatusProcessed:
+		r.ProcessedAt = &at
+	case constants.RefundStatusRejected:
+		r.RejectedAt = &at
+	}
+
+	r.Status = next
+	r.UpdatedAt = at
+
+	return true
+}
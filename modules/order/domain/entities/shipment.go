@@ -0,0 +1,109 @@
+package entities
+
+import (
+	"mallbots/modules/order/domain/constants"
+	"time"
+)
+
+// Shipment is one parcel of an order. Its items may carry only part of the
+// quantity of an order item, the rest following in later shipments.
+type Shipment struct {
+	ID             int32
+	OrderID        int32
+	Status         constants.ShipmentStatus
+	Carrier        string
+	TrackingNumber *string
+	ShippedAt      *time.Time
+	DeliveredAt    *time.Time
+	CancelledAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Items          []*ShipmentItem
+}
+
+type ShipmentItem struct {
+	ID          int32
+	ShipmentID  int32
+	OrderItemID int32
+	Quantity    int32
+	CreatedAt   time.Time
+}
+
+// TransitionTo moves the shipment to next and stamps the matching timestamp.
+// It reports false and leaves the shipment untouched when the move is not allowed
+func (s *Shipment) TransitionTo(next constants.ShipmentStatus, at time.Time) bool {
+	if !s.Status.CanTransitionTo(next) {
+		return false
+	}
+
+	switch next {
+	case constants.ShipmentStatusShipped:
+		s.ShippedAt = &at
+	case constants.ShipmentStatusDelivered:
+		s.DeliveredAt = &at
+	case constants.ShipmentStatusCancelled:
+		s.CancelledAt = &at
+	}
+
+	s.Status = next
+	s.UpdatedAt = at
+
+	return true
+}
+
+// AllocatedQuantities sums per order item the units already put in a
+// shipment that was not cancelled
+func AllocatedQuantities(shipments []*Shipment) map[int32]int32 {
+	allocated := map[int32]int32{}
+	for _, shipment := range shipments {
+		if shipment.Status == constants.ShipmentStatusCancelled {
+			continue
+		}
+		for _, item := range shipment.Items {
+			allocated[item.OrderItemID] += item.Quantity
+		}
+	}
+	return allocated
+}
+
+// FulfillmentStatus derives the status the shipments put an order with the
+// given items in: PARTIALLY_SHIPPED once some units have left, SHIPPED once
+// all have and DELIVERED once all have arrived. It reports false while
+// nothing has left yet.
+func FulfillmentStatus(items []*OrderItem, shipments []*Shipment) (constants.OrderStatus, bool) {
+	shipped := map[int32]int32{}
+	delivered := map[int32]int32{}
+	for _, shipment := range shipments {
+		if !shipment.Status.HasLeft() {
+			continue
+		}
+		for _, item := range shipment.Items {
+			shipped[item.OrderItemID] += item.Quantity
+			if shipment.Status == constants.ShipmentStatusDelivered {
+				delivered[item.OrderItemID] += item.Quantity
+			}
+		}
+	}
+
+	if len(shipped) == 0 {
+		return "", false
+	}
+
+	allShipped, allDelivered := true, true
+	for _, item := range items {
+		if shipped[item.ID] < item.Quantity {
+			allShipped = false
+		}
+		if delivered[item.ID] < item.Quantity {
+			allDelivered = false
+		}
+	}
+
+	switch {
+	case allDelivered:
+		return constants.OrderStatusDelivered, true
+	case allShipped:
+		return constants.OrderStatusShipped, true
+	}
+	return constants.OrderStatusPartiallyShipped, true
+}
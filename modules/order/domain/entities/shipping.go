@@ -0,0 +1,21 @@
+package entities
+
+import "mallbots/shared/money"
+
+// Parcel is what a shipping quote is calculated for
+type Parcel struct {
+	Country  string
+	Zip      string
+	Subtotal money.Money
+	// Weight is the total weight in grams
+	Weight int64
+}
+
+type ShippingQuote struct {
+	Zone   string
+	Amount money.Money
+	// FreeAbove is the subtotal from which the zone ships for free, zero when
+	// it never does
+	FreeAbove    money.Money
+	FreeShipping bool
+}
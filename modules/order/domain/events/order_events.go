@@ -0,0 +1,86 @@
+package events
+
+import (
+	"mallbots/modules/order/domain/constants"
+	"mallbots/shared/money"
+	"time"
+)
+
+const (
+	OrderCancelledEvent     = "order.cancelled"
+	OrderCreatedEvent       = "order.created"
+	OrderStatusChangedEvent = "order.status_changed"
+	PaymentCapturedEvent    = "payment.captured"
+)
+
+type OrderItemQuantity struct {
+	ProductID int32
+	Quantity  int32
+}
+
+// OrderCancelled is published inside the cancellation transaction, so
+// subscribers such as inventory release commit or roll back with it
+type OrderCancelled struct {
+	OrderID               int32
+	UserID                int32
+	Reason                string
+	PreviousPaymentStatus constants.PaymentStatus
+	PaymentStatus         constants.PaymentStatus
+	Items                 []OrderItemQuantity
+	CancelledAt           time.Time
+}
+
+func (e *OrderCancelled) EventName() string {
+	return OrderCancelledEvent
+}
+
+// The events below go through the outbox: they are recorded with the state
+// change and relayed to other systems once it commits, so their JSON form
+// is a public contract
+
+type OrderCreatedItem struct {
+	ProductID int32       `json:"product_id"`
+	Quantity  int32       `json:"quantity"`
+	Price     money.Money `json:"price"`
+}
+
+type OrderCreated struct {
+	OrderID     int32              `json:"order_id"`
+	UserID      int32              `json:"user_id"`
+	Status      string             `json:"status"`
+	Currency    money.Currency     `json:"currency"`
+	TotalAmount money.Money        `json:"total_amount"`
+	Items       []OrderCreatedItem `json:"items"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+func (e *OrderCreated) EventName() string {
+	return OrderCreatedEvent
+}
+
+type OrderStatusChanged struct {
+	OrderID   int32     `json:"order_id"`
+	UserID    int32     `json:"user_id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func (e *OrderStatusChanged) EventName() string {
+	return OrderStatusChangedEvent
+}
+
+type PaymentCaptured struct {
+	OrderID  int32          `json:"order_id"`
+	UserID   int32          `json:"user_id"`
+	Amount   money.Money    `json:"amount"`
+	Currency money.Currency `json:"currency"`
+	// Provider and IntentID are empty when an admin marked the order paid
+	Provider   string    `json:"provider,omitempty"`
+	IntentID   string    `json:"intent_id,omitempty"`
+	CapturedAt time.Time `json:"captured_at"`
+}
+
+func (e *PaymentCaptured) EventName() string {
+	return PaymentCapturedEvent
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/order/domain/entities"
+)
+
+type InvoiceRepository interface {
+	GetByOrderID(ctx context.Context, orderID int32) (*entities.Invoice, error)
+	// LockYear holds the numbering of the year until the surrounding
+	// transaction ends and returns the last sequence handed out in it
+	LockYear(ctx context.Context, year int32) (int32, error)
+	// Create stores the invoice and moves the counter of its year to its
+	// sequence, the year must be locked
+	Create(ctx context.Context, invoice *entities.Invoice) (*entities.Invoice, error)
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/order/application/dto"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+)
+
+type InvoiceService interface {
+	// GetInvoice renders the invoice of a paid order, issuing it on first use
+	GetInvoice(ctx context.Context, caller entities.Caller, orderID int32, format constants.InvoiceFormat) (*dto.InvoiceFile, error)
+}
+
+// InvoiceRenderer lays an invoice out as a document of the given format
+type InvoiceRenderer interface {
+	Render(doc *entities.InvoiceDocument, format constants.InvoiceFormat) ([]byte, error)
+}
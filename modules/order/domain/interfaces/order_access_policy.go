@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+)
+
+// OrderAccessPolicy decides whether a caller may perform an action on an
+// order. A denial is reported as errorx.ErrUnauthorizedOrderAccess.
+type OrderAccessPolicy interface {
+	Authorize(caller entities.Caller, action constants.OrderAction, order *entities.Order) error
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/order/domain/entities"
+)
+
+// OrderEventRepository stores the order timeline. Events are append-only:
+// they are never updated or deleted.
+type OrderEventRepository interface {
+	Append(ctx context.Context, event *entities.OrderEvent) error
+	GetByOrderID(ctx context.Context, orderID int32) ([]*entities.OrderEvent, error)
+}
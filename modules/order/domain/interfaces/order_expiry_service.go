@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// OrderExpiryService cancels the pending orders whose payment never arrived,
+// which releases their reserved stock
+type OrderExpiryService interface {
+	// CancelExpiredOrders cancels one batch of orders unpaid past their TTL
+	// at now and returns how many it cancelled
+	CancelExpiredOrders(ctx context.Context, now time.Time) (int, error)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"mallbots/modules/order/domain/constants"
 	"mallbots/modules/order/domain/entities"
+	"mallbots/shared/money"
+	"time"
 
 	"github.com/phathdt/service-context/core"
 )
@@ -11,8 +13,48 @@ import (
 type OrderRepository interface {
 	Create(ctx context.Context, order *entities.Order) (*entities.Order, error)
 	CreateOrderItems(ctx context.Context, orderID int32, items []*entities.OrderItem) error
+	// GetByID returns the order with its items and shipments
 	GetByID(ctx context.Context, id int32) (*entities.Order, error)
-	GetByUserID(ctx context.Context, userID int32, paging *core.Paging) ([]*entities.Order, error)
+	// GetByIDForUpdate locks the order row (without items) until the surrounding transaction ends
+	GetByIDForUpdate(ctx context.Context, id int32) (*entities.Order, error)
+	GetItems(ctx context.Context, orderID int32) ([]*entities.OrderItem, error)
+	// GetByUserID pages through the user's orders matching the filter, by
+	// offset or, once the filter carries a cursor, by keyset. It sets the
+	// paging total and, when more orders follow, the next cursor.
+	GetByUserID(ctx context.Context, userID int32, filter *OrderFilter, paging *core.Paging) ([]*entities.Order, error)
+	// CountUserOrdersSince counts the orders a user placed from the given time on, cancelled ones excluded
+	CountUserOrdersSince(ctx context.Context, userID int32, since time.Time) (int64, error)
+	// LockUserOrders makes the other checkouts of the user wait until the
+	// surrounding transaction ends, so orders are counted and placed in turn
+	LockUserOrders(ctx context.Context, userID int32) error
 	UpdateStatus(ctx context.Context, id int32, status constants.OrderStatus) error
 	UpdatePaymentStatus(ctx context.Context, id int32, status constants.PaymentStatus) error
+	Cancel(ctx context.Context, order *entities.Order) error
+	// GetExpiredUnpaidIDs returns, oldest first, the pending orders still
+	// waiting for their payment past their TTL: the provider TTL of their
+	// payment provider, else ttl. Orders whose TTL is zero are left out, so
+	// are the skipIDs.
+	GetExpiredUnpaidIDs(ctx context.Context, now time.Time, ttl time.Duration, providerTTLs map[string]time.Duration, skipIDs []int32, limit int32) ([]int32, error)
+	// GetByPaymentIntentIDForUpdate locks the order paid through the given provider intent
+	GetByPaymentIntentIDForUpdate(ctx context.Context, intentID string) (*entities.Order, error)
+	SetPaymentIntent(ctx context.Context, id int32, provider, intentID string) error
+}
+
+type OrderFilter struct {
+	Status        *constants.OrderStatus
+	PaymentStatus *constants.PaymentStatus
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	// Currency restricts the orders to the ones placed in it. Sorting by
+	// total needs it, as do MinTotal and MaxTotal which are amounts in it.
+	Currency  *money.Currency
+	MinTotal  *money.Money
+	MaxTotal  *money.Money
+	ProductID *int32
+	SortBy    constants.OrderSort
+	// After resumes the listing right after the order it points at
+	After *entities.OrderCursor
+	// SkipItems lists the orders without their items, which are loaded along
+	// with them otherwise
+	SkipItems bool
 }
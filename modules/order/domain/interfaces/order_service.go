@@ -3,12 +3,22 @@ package interfaces
 import (
 	"context"
 	"mallbots/modules/order/application/dto"
+	"mallbots/modules/order/domain/entities"
 
 	"github.com/phathdt/service-context/core"
 )
 
 type OrderService interface {
-	CreateOrder(ctx context.Context, userID int32, req *dto.CreateOrderRequest) (*dto.OrderResponse, error)
-	GetOrder(ctx context.Context, orderID int32) (*dto.OrderResponse, error)
-	GetUserOrders(ctx context.Context, userID int32, paging *core.Paging) ([]*dto.OrderResponse, error)
+	CreateOrder(ctx context.Context, caller entities.Caller, req *dto.CreateOrderRequest) (*dto.OrderResponse, error)
+	QuoteShipping(ctx context.Context, caller entities.Caller, req *dto.ShippingQuoteRequest) (*dto.ShippingQuoteResponse, error)
+	GetOrder(ctx context.Context, caller entities.Caller, orderID int32) (*dto.OrderResponse, error)
+	GetUserOrders(ctx context.Context, userID int32, req *dto.OrderListRequest, paging *core.Paging) ([]*dto.OrderResponse, error)
+	UpdateOrderStatus(ctx context.Context, caller entities.Caller, orderID int32, req *dto.UpdateOrderStatusRequest) (*dto.OrderResponse, error)
+	UpdatePaymentStatus(ctx context.Context, caller entities.Caller, orderID int32, req *dto.UpdatePaymentStatusRequest) (*dto.OrderResponse, error)
+	CancelOrder(ctx context.Context, caller entities.Caller, orderID int32, req *dto.CancelOrderRequest) (*dto.OrderResponse, error)
+	GetOrderTimeline(ctx context.Context, caller entities.Caller, orderID int32) ([]*dto.OrderEventResponse, error)
+	// Reorder copies the items of the caller's past order into their cart at
+	// current prices. Unavailable products are skipped and short stock lowers
+	// the quantity rather than failing the whole reorder.
+	Reorder(ctx context.Context, caller entities.Caller, orderID int32) (*dto.ReorderResponse, error)
 }
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/order/application/dto"
+	"mallbots/modules/order/domain/entities"
+)
+
+type PaymentService interface {
+	CreatePaymentIntent(ctx context.Context, caller entities.Caller, orderID int32) (*dto.PaymentIntentResponse, error)
+	CapturePayment(ctx context.Context, caller entities.Caller, orderID int32) (*dto.OrderResponse, error)
+	// HandleWebhook applies a signed provider notification. A redelivered
+	// event returns errorx.ErrPaymentAlreadyProcessed unwrapped.
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/order/domain/entities"
+)
+
+type PaymentWebhookRepository interface {
+	// Record stores the event and reports false when the provider already
+	// delivered an event with the same ID
+	Record(ctx context.Context, event *entities.PaymentWebhookEvent) (bool, error)
+}
@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/shared/money"
+)
+
+type RefundRepository interface {
+	// Create stores the refund together with its items
+	Create(ctx context.Context, refund *entities.Refund) (*entities.Refund, error)
+	GetByID(ctx context.Context, id int32) (*entities.Refund, error)
+	// GetByIDForUpdate locks the refund row (without items) until the surrounding transaction ends
+	GetByIDForUpdate(ctx context.Context, id int32) (*entities.Refund, error)
+	GetByOrderID(ctx context.Context, orderID int32) ([]*entities.Refund, error)
+	UpdateStatus(ctx context.Context, refund *entities.Refund) error
+	// SumOpenAmount totals every refund of the order that has not been
+	// rejected. Refunds are always in the order's currency.
+	SumOpenAmount(ctx context.Context, orderID int32, currency money.Currency) (money.Money, error)
+	SumProcessedAmount(ctx context.Context, orderID int32, currency money.Currency) (money.Money, error)
+	// GetRefundedQuantities returns the quantity claimed by non-rejected refunds, keyed by order item ID
+	GetRefundedQuantities(ctx context.Context, orderID int32) (map[int32]int32, error)
+}
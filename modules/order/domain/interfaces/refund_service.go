@@ -0,0 +1,35 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/order/application/dto"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/shared/money"
+)
+
+type RefundService interface {
+	RequestRefund(ctx context.Context, caller entities.Caller, orderID int32, req *dto.CreateRefundRequest) (*dto.RefundResponse, error)
+	GetOrderRefunds(ctx context.Context, caller entities.Caller, orderID int32) ([]*dto.RefundResponse, error)
+	ApproveRefund(ctx context.Context, caller entities.Caller, refundID int32) (*dto.RefundResponse, error)
+	RejectRefund(ctx context.Context, caller entities.Caller, refundID int32) (*dto.RefundResponse, error)
+	// ProcessRefund pays an approved refund back through the provider. It
+	// commits on its own around the provider call and must not run inside
+	// another transaction.
+	ProcessRefund(ctx context.Context, caller entities.Caller, refundID int32) (*dto.RefundResponse, error)
+	// RefundCancelledOrder records an approved refund of whatever of a paid
+	// order is not refunded yet. It joins the transaction of ctx, which must
+	// hold the order row lock; the caller pays it out with ProcessRefund once
+	// that transaction committed.
+	RefundCancelledOrder(ctx context.Context, caller entities.Caller, order *entities.Order, reason string) (*entities.Refund, error)
+	// RefundUnappliedPayment records an approved refund of money the provider
+	// captured for an order that can no longer take it, e.g. one cancelled
+	// meanwhile. Like RefundCancelledOrder it runs under the caller's order
+	// row lock and is paid out after the commit.
+	RefundUnappliedPayment(ctx context.Context, caller entities.Caller, order *entities.Order, amount money.Money, reason string) (*entities.Refund, error)
+	// RecordProviderRefund reconciles the refunds with the total the provider
+	// reports refunded on the order's intent. Money refunded at the provider
+	// beyond the processed refunds settles the refunds still processing, the
+	// rest is recorded as a processed refund, and the order settles once it
+	// is refunded in full. It runs under the caller's order row lock.
+	RecordProviderRefund(ctx context.Context, caller entities.Caller, order *entities.Order, refunded money.Money) error
+}
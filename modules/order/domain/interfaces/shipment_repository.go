@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/order/domain/entities"
+)
+
+type ShipmentRepository interface {
+	// Create stores the shipment together with its items
+	Create(ctx context.Context, shipment *entities.Shipment) (*entities.Shipment, error)
+	GetByID(ctx context.Context, id int32) (*entities.Shipment, error)
+	// GetByIDForUpdate locks the shipment row (without items) until the surrounding transaction ends
+	GetByIDForUpdate(ctx context.Context, id int32) (*entities.Shipment, error)
+	// GetByOrderID returns the shipments of the order with their items, oldest first
+	GetByOrderID(ctx context.Context, orderID int32) ([]*entities.Shipment, error)
+	Update(ctx context.Context, shipment *entities.Shipment) error
+}
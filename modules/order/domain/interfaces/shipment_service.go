@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/order/application/dto"
+	"mallbots/modules/order/domain/entities"
+)
+
+// ShipmentService fulfils orders parcel by parcel. The order status follows
+// its shipments: PARTIALLY_SHIPPED, SHIPPED and DELIVERED are derived from
+// what has left the warehouse and arrived.
+type ShipmentService interface {
+	CreateShipment(ctx context.Context, caller entities.Caller, orderID int32, req *dto.CreateShipmentRequest) (*dto.ShipmentResponse, error)
+	UpdateShipment(ctx context.Context, caller entities.Caller, shipmentID int32, req *dto.UpdateShipmentRequest) (*dto.ShipmentResponse, error)
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/order/domain/entities"
+)
+
+// ShippingCalculator prices the delivery of a parcel. Destinations that are
+// not served are rejected with errorx.ErrInvalidShippingCountry.
+type ShippingCalculator interface {
+	Quote(ctx context.Context, parcel entities.Parcel) (*entities.ShippingQuote, error)
+}
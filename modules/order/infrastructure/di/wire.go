@@ -5,27 +5,141 @@ package di
 import (
 	cartService "mallbots/modules/cart/application/services"
 	cartRepo "mallbots/modules/cart/infrastructure/repositories"
+	currencyService "mallbots/modules/currency/application/services"
+	currencyRepo "mallbots/modules/currency/infrastructure/repositories"
 	"mallbots/modules/order/application/services"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/modules/order/infrastructure/invoice"
 	"mallbots/modules/order/infrastructure/repositories"
 	"mallbots/modules/order/infrastructure/rest"
 	productService "mallbots/modules/product/application/services"
 	productRepo "mallbots/modules/product/infrastructure/repositories"
+	promotionService "mallbots/modules/promotion/application/services"
+	promotionRepo "mallbots/modules/promotion/infrastructure/repositories"
+	ruleService "mallbots/modules/rules/application/services"
+	taxService "mallbots/modules/tax/application/services"
+	userService "mallbots/modules/user/application/services"
+	userRepo "mallbots/modules/user/infrastructure/repositories"
+	"mallbots/plugins/eventbus"
+	"mallbots/plugins/outbox"
+	"mallbots/plugins/payment"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/config"
 
 	"github.com/google/wire"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var OrderSet = wire.NewSet(
+	pgxc.NewTxManager,
+	outbox.NewWriter,
+	currencyRepo.NewExchangeRateRepository,
+	currencyService.NewCurrencyService,
 	productRepo.NewProductRepository,
 	productService.NewProductService,
+	productRepo.NewInventoryRepository,
+	productService.NewInventoryService,
+	ruleService.NewRuleEngine,
 	cartRepo.NewCartRepository,
 	cartService.NewCartService,
+	userRepo.NewAddressRepository,
+	userService.NewAddressService,
 	repositories.NewOrderRepository,
+	repositories.NewOrderEventRepository,
+	repositories.NewRefundRepository,
+	services.NewOrderAccessPolicy,
+	services.NewRefundService,
+	services.NewShippingCalculator,
+	taxService.NewTaxCalculator,
+	promotionRepo.NewCouponRepository,
+	promotionService.NewPromotionService,
 	services.NewOrderService,
 	rest.NewOrderHandler,
 )
 
-func InitializeOrderHandler(db *pgxpool.Pool) (*rest.OrderHandler, error) {
+var RefundSet = wire.NewSet(
+	pgxc.NewTxManager,
+	outbox.NewWriter,
+	repositories.NewOrderRepository,
+	repositories.NewRefundRepository,
+	repositories.NewOrderEventRepository,
+	services.NewOrderAccessPolicy,
+	services.NewRefundService,
+	rest.NewRefundHandler,
+)
+
+var ShipmentSet = wire.NewSet(
+	pgxc.NewTxManager,
+	outbox.NewWriter,
+	productRepo.NewInventoryRepository,
+	productService.NewInventoryService,
+	repositories.NewOrderRepository,
+	repositories.NewShipmentRepository,
+	repositories.NewOrderEventRepository,
+	services.NewShipmentService,
+	rest.NewShipmentHandler,
+)
+
+var InvoiceSet = wire.NewSet(
+	pgxc.NewTxManager,
+	currencyRepo.NewExchangeRateRepository,
+	currencyService.NewCurrencyService,
+	productRepo.NewProductRepository,
+	productService.NewProductService,
+	userRepo.NewUserRepository,
+	repositories.NewOrderRepository,
+	repositories.NewInvoiceRepository,
+	repositories.NewOrderEventRepository,
+	services.NewOrderAccessPolicy,
+	invoice.NewInvoiceRenderer,
+	services.NewInvoiceService,
+	rest.NewInvoiceHandler,
+)
+
+var OrderExpirySet = wire.NewSet(
+	pgxc.NewTxManager,
+	outbox.NewWriter,
+	repositories.NewOrderRepository,
+	repositories.NewRefundRepository,
+	repositories.NewOrderEventRepository,
+	services.NewOrderAccessPolicy,
+	services.NewRefundService,
+	services.NewOrderExpiryService,
+)
+
+var PaymentSet = wire.NewSet(
+	OrderSet,
+	repositories.NewPaymentWebhookRepository,
+	services.NewPaymentService,
+	rest.NewPaymentHandler,
+)
+
+func InitializeOrderHandler(db *pgxpool.Pool, bus eventbus.Bus, cfg *config.Config, provider payment.PaymentProvider) (*rest.OrderHandler, error) {
 	wire.Build(OrderSet)
 	return &rest.OrderHandler{}, nil
 }
+
+func InitializeRefundHandler(db *pgxpool.Pool, provider payment.PaymentProvider) (*rest.RefundHandler, error) {
+	wire.Build(RefundSet)
+	return &rest.RefundHandler{}, nil
+}
+
+func InitializeShipmentHandler(db *pgxpool.Pool) (*rest.ShipmentHandler, error) {
+	wire.Build(ShipmentSet)
+	return &rest.ShipmentHandler{}, nil
+}
+
+func InitializeInvoiceHandler(db *pgxpool.Pool, cfg *config.Config) (*rest.InvoiceHandler, error) {
+	wire.Build(InvoiceSet)
+	return &rest.InvoiceHandler{}, nil
+}
+
+func InitializeOrderExpiryService(db *pgxpool.Pool, bus eventbus.Bus, cfg *config.Config, provider payment.PaymentProvider) (interfaces.OrderExpiryService, error) {
+	wire.Build(OrderExpirySet)
+	return nil, nil
+}
+
+func InitializePaymentHandler(db *pgxpool.Pool, bus eventbus.Bus, cfg *config.Config, provider payment.PaymentProvider) (*rest.PaymentHandler, error) {
+	wire.Build(PaymentSet)
+	return &rest.PaymentHandler{}, nil
+}
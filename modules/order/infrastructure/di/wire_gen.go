@@ -11,26 +11,173 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	services2 "mallbots/modules/cart/application/services"
 	repositories2 "mallbots/modules/cart/infrastructure/repositories"
+	services7 "mallbots/modules/currency/application/services"
+	repositories5 "mallbots/modules/currency/infrastructure/repositories"
 	services3 "mallbots/modules/order/application/services"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/modules/order/infrastructure/invoice"
 	"mallbots/modules/order/infrastructure/repositories"
 	"mallbots/modules/order/infrastructure/rest"
 	"mallbots/modules/product/application/services"
 	repositories3 "mallbots/modules/product/infrastructure/repositories"
+	services5 "mallbots/modules/promotion/application/services"
+	repositories4 "mallbots/modules/promotion/infrastructure/repositories"
+	services6 "mallbots/modules/rules/application/services"
+	services4 "mallbots/modules/tax/application/services"
+	services8 "mallbots/modules/user/application/services"
+	repositories6 "mallbots/modules/user/infrastructure/repositories"
+	"mallbots/plugins/eventbus"
+	"mallbots/plugins/outbox"
+	"mallbots/plugins/payment"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/config"
 )
 
 // Injectors from wire.go:
 
-func InitializeOrderHandler(db *pgxpool.Pool) (*rest.OrderHandler, error) {
+func InitializeOrderHandler(db *pgxpool.Pool, bus eventbus.Bus, cfg *config.Config, provider payment.PaymentProvider) (*rest.OrderHandler, error) {
 	orderRepository := repositories.NewOrderRepository(db)
 	cartRepository := repositories2.NewCartRepository(db)
 	productRepository := repositories3.NewProductRepository(db)
-	productService := services.NewProductService(productRepository)
-	cartService := services2.NewCartService(cartRepository, productService)
-	orderService := services3.NewOrderService(orderRepository, cartService)
+	exchangeRateRepository := repositories5.NewExchangeRateRepository(db)
+	txManager := pgxc.NewTxManager(db)
+	currencyService := services7.NewCurrencyService(exchangeRateRepository, txManager)
+	productService := services.NewProductService(productRepository, currencyService)
+	ruleEngine, err := services6.NewRuleEngine(cfg)
+	if err != nil {
+		return nil, err
+	}
+	writer := outbox.NewWriter(db)
+	cartService := services2.NewCartService(cartRepository, productService, ruleEngine, currencyService, txManager, writer)
+	orderAccessPolicy := services3.NewOrderAccessPolicy()
+	orderEventRepository := repositories.NewOrderEventRepository(db)
+	inventoryRepository := repositories3.NewInventoryRepository(db)
+	inventoryService := services.NewInventoryService(inventoryRepository)
+	shippingCalculator, err := services3.NewShippingCalculator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	taxCalculator, err := services4.NewTaxCalculator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	couponRepository := repositories4.NewCouponRepository(db)
+	promotionService := services5.NewPromotionService(couponRepository, cartService, productService, currencyService)
+	addressRepository := repositories6.NewAddressRepository(db)
+	addressService := services8.NewAddressService(addressRepository)
+	refundRepository := repositories.NewRefundRepository(db)
+	refundService := services3.NewRefundService(orderRepository, refundRepository, txManager, orderAccessPolicy, orderEventRepository, provider, writer)
+	orderService := services3.NewOrderService(orderRepository, cartService, addressService, productService, inventoryService, shippingCalculator, taxCalculator, promotionService, ruleEngine, currencyService, refundService, provider, txManager, bus, orderAccessPolicy, orderEventRepository, writer)
 	orderHandler := rest.NewOrderHandler(orderService)
 	return orderHandler, nil
 }
 
+func InitializeRefundHandler(db *pgxpool.Pool, provider payment.PaymentProvider) (*rest.RefundHandler, error) {
+	orderRepository := repositories.NewOrderRepository(db)
+	refundRepository := repositories.NewRefundRepository(db)
+	txManager := pgxc.NewTxManager(db)
+	orderAccessPolicy := services3.NewOrderAccessPolicy()
+	orderEventRepository := repositories.NewOrderEventRepository(db)
+	writer := outbox.NewWriter(db)
+	refundService := services3.NewRefundService(orderRepository, refundRepository, txManager, orderAccessPolicy, orderEventRepository, provider, writer)
+	refundHandler := rest.NewRefundHandler(refundService)
+	return refundHandler, nil
+}
+
+func InitializeShipmentHandler(db *pgxpool.Pool) (*rest.ShipmentHandler, error) {
+	orderRepository := repositories.NewOrderRepository(db)
+	shipmentRepository := repositories.NewShipmentRepository(db)
+	inventoryRepository := repositories3.NewInventoryRepository(db)
+	inventoryService := services.NewInventoryService(inventoryRepository)
+	txManager := pgxc.NewTxManager(db)
+	orderEventRepository := repositories.NewOrderEventRepository(db)
+	writer := outbox.NewWriter(db)
+	shipmentService := services3.NewShipmentService(orderRepository, shipmentRepository, inventoryService, txManager, orderEventRepository, writer)
+	shipmentHandler := rest.NewShipmentHandler(shipmentService)
+	return shipmentHandler, nil
+}
+
+func InitializeInvoiceHandler(db *pgxpool.Pool, cfg *config.Config) (*rest.InvoiceHandler, error) {
+	orderRepository := repositories.NewOrderRepository(db)
+	invoiceRepository := repositories.NewInvoiceRepository(db)
+	productRepository := repositories3.NewProductRepository(db)
+	exchangeRateRepository := repositories5.NewExchangeRateRepository(db)
+	txManager := pgxc.NewTxManager(db)
+	currencyService := services7.NewCurrencyService(exchangeRateRepository, txManager)
+	productService := services.NewProductService(productRepository, currencyService)
+	userRepository := repositories6.NewUserRepository(db)
+	invoiceRenderer, err := invoice.NewInvoiceRenderer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	orderAccessPolicy := services3.NewOrderAccessPolicy()
+	orderEventRepository := repositories.NewOrderEventRepository(db)
+	invoiceService := services3.NewInvoiceService(orderRepository, invoiceRepository, productService, userRepository, invoiceRenderer, txManager, orderAccessPolicy, orderEventRepository, cfg)
+	invoiceHandler := rest.NewInvoiceHandler(invoiceService)
+	return invoiceHandler, nil
+}
+
+func InitializeOrderExpiryService(db *pgxpool.Pool, bus eventbus.Bus, cfg *config.Config, provider payment.PaymentProvider) (interfaces.OrderExpiryService, error) {
+	orderRepository := repositories.NewOrderRepository(db)
+	refundRepository := repositories.NewRefundRepository(db)
+	txManager := pgxc.NewTxManager(db)
+	orderAccessPolicy := services3.NewOrderAccessPolicy()
+	orderEventRepository := repositories.NewOrderEventRepository(db)
+	writer := outbox.NewWriter(db)
+	refundService := services3.NewRefundService(orderRepository, refundRepository, txManager, orderAccessPolicy, orderEventRepository, provider, writer)
+	orderExpiryService := services3.NewOrderExpiryService(orderRepository, refundService, txManager, bus, orderEventRepository, writer, provider, cfg)
+	return orderExpiryService, nil
+}
+
+func InitializePaymentHandler(db *pgxpool.Pool, bus eventbus.Bus, cfg *config.Config, provider payment.PaymentProvider) (*rest.PaymentHandler, error) {
+	orderRepository := repositories.NewOrderRepository(db)
+	paymentWebhookRepository := repositories.NewPaymentWebhookRepository(db)
+	cartRepository := repositories2.NewCartRepository(db)
+	productRepository := repositories3.NewProductRepository(db)
+	exchangeRateRepository := repositories5.NewExchangeRateRepository(db)
+	txManager := pgxc.NewTxManager(db)
+	currencyService := services7.NewCurrencyService(exchangeRateRepository, txManager)
+	productService := services.NewProductService(productRepository, currencyService)
+	ruleEngine, err := services6.NewRuleEngine(cfg)
+	if err != nil {
+		return nil, err
+	}
+	writer := outbox.NewWriter(db)
+	cartService := services2.NewCartService(cartRepository, productService, ruleEngine, currencyService, txManager, writer)
+	orderAccessPolicy := services3.NewOrderAccessPolicy()
+	orderEventRepository := repositories.NewOrderEventRepository(db)
+	inventoryRepository := repositories3.NewInventoryRepository(db)
+	inventoryService := services.NewInventoryService(inventoryRepository)
+	shippingCalculator, err := services3.NewShippingCalculator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	taxCalculator, err := services4.NewTaxCalculator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	couponRepository := repositories4.NewCouponRepository(db)
+	promotionService := services5.NewPromotionService(couponRepository, cartService, productService, currencyService)
+	addressRepository := repositories6.NewAddressRepository(db)
+	addressService := services8.NewAddressService(addressRepository)
+	refundRepository := repositories.NewRefundRepository(db)
+	refundService := services3.NewRefundService(orderRepository, refundRepository, txManager, orderAccessPolicy, orderEventRepository, provider, writer)
+	orderService := services3.NewOrderService(orderRepository, cartService, addressService, productService, inventoryService, shippingCalculator, taxCalculator, promotionService, ruleEngine, currencyService, refundService, provider, txManager, bus, orderAccessPolicy, orderEventRepository, writer)
+	paymentService := services3.NewPaymentService(orderRepository, paymentWebhookRepository, orderService, refundService, provider, txManager, orderAccessPolicy, orderEventRepository)
+	paymentHandler := rest.NewPaymentHandler(paymentService)
+	return paymentHandler, nil
+}
+
 // wire.go:
 
-var OrderSet = wire.NewSet(repositories3.NewProductRepository, services.NewProductService, repositories2.NewCartRepository, services2.NewCartService, repositories.NewOrderRepository, services3.NewOrderService, rest.NewOrderHandler)
+var OrderSet = wire.NewSet(pgxc.NewTxManager, outbox.NewWriter, repositories5.NewExchangeRateRepository, services7.NewCurrencyService, repositories3.NewProductRepository, services.NewProductService, repositories3.NewInventoryRepository, services.NewInventoryService, services6.NewRuleEngine, repositories2.NewCartRepository, services2.NewCartService, repositories6.NewAddressRepository, services8.NewAddressService, repositories.NewOrderRepository, repositories.NewOrderEventRepository, repositories.NewRefundRepository, services3.NewOrderAccessPolicy, services3.NewRefundService, services3.NewShippingCalculator, services4.NewTaxCalculator, repositories4.NewCouponRepository, services5.NewPromotionService, services3.NewOrderService, rest.NewOrderHandler)
+
+var RefundSet = wire.NewSet(pgxc.NewTxManager, outbox.NewWriter, repositories.NewOrderRepository, repositories.NewRefundRepository, repositories.NewOrderEventRepository, services3.NewOrderAccessPolicy, services3.NewRefundService, rest.NewRefundHandler)
+
+var ShipmentSet = wire.NewSet(pgxc.NewTxManager, outbox.NewWriter, repositories3.NewInventoryRepository, services.NewInventoryService, repositories.NewOrderRepository, repositories.NewShipmentRepository, repositories.NewOrderEventRepository, services3.NewShipmentService, rest.NewShipmentHandler)
+
+var InvoiceSet = wire.NewSet(pgxc.NewTxManager, repositories5.NewExchangeRateRepository, services7.NewCurrencyService, repositories3.NewProductRepository, services.NewProductService, repositories6.NewUserRepository, repositories.NewOrderRepository, repositories.NewInvoiceRepository, repositories.NewOrderEventRepository, services3.NewOrderAccessPolicy, invoice.NewInvoiceRenderer, services3.NewInvoiceService, rest.NewInvoiceHandler)
+
+var OrderExpirySet = wire.NewSet(pgxc.NewTxManager, outbox.NewWriter, repositories.NewOrderRepository, repositories.NewRefundRepository, repositories.NewOrderEventRepository, services3.NewOrderAccessPolicy, services3.NewRefundService, services3.NewOrderExpiryService)
+
+var PaymentSet = wire.NewSet(OrderSet, repositories.NewPaymentWebhookRepository, services3.NewPaymentService, rest.NewPaymentHandler)
@@ -0,0 +1,167 @@
+package invoice
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	htmltemplate "html/template"
+	"io/fs"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/shared/config"
+	"mallbots/shared/money"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	htmlTemplateName = "invoice.html.tmpl"
+	textTemplateName = "invoice.txt.tmpl"
+
+	defaultTemplateDir = "config/invoices"
+
+	// PDF pages are the text layout set in Courier, which fits its 92
+	// columns between the margins
+	pdfFontSize   = 9
+	pdfLineHeight = 4
+	pdfMargin     = 15
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+type renderer struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// NewInvoiceRenderer parses the invoice layouts, templates found in the
+// configured directory take the place of the built-in ones
+func NewInvoiceRenderer(cfg *config.Config) (interfaces.InvoiceRenderer, error) {
+	dir := cfg.Invoice.TemplateDir
+	if dir == "" {
+		dir = defaultTemplateDir
+	}
+
+	htmlSource, err := loadTemplate(dir, htmlTemplateName)
+	if err != nil {
+		return nil, err
+	}
+
+	textSource, err := loadTemplate(dir, textTemplateName)
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := htmltemplate.New(htmlTemplateName).Funcs(templateFuncs).Parse(htmlSource)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := texttemplate.New(textTemplateName).Funcs(templateFuncs).Parse(textSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return &renderer{html: html, text: text}, nil
+}
+
+func loadTemplate(dir, name string) (string, error) {
+	source, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		source, err = builtinTemplates.ReadFile("templates/" + name)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(source), nil
+}
+
+func (r *renderer) Render(doc *entities.InvoiceDocument, format constants.InvoiceFormat) ([]byte, error) {
+	switch format {
+	case constants.InvoiceFormatHTML:
+		var buf bytes.Buffer
+		if err := r.html.Execute(&buf, doc); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case constants.InvoiceFormatPDF:
+		return r.renderPDF(doc)
+	}
+
+	return nil, errors.New("unsupported invoice format " + format.String())
+}
+
+func (r *renderer) renderPDF(doc *entities.InvoiceDocument) ([]byte, error) {
+	var text bytes.Buffer
+	if err := r.text.Execute(&text, doc); err != nil {
+		return nil, err
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetTitle("Invoice "+doc.Number, true)
+	pdf.SetCreationDate(doc.IssuedAt)
+	pdf.SetModificationDate(doc.IssuedAt)
+	pdf.SetFont("Courier", "", pdfFontSize)
+	pdf.AddPage()
+
+	// The core fonts only cover cp1252, anything outside it prints as a gap
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	for _, line := range strings.Split(strings.TrimRight(text.String(), "\n"), "\n") {
+		pdf.MultiCell(0, pdfLineHeight, translate(line), "", "L", false)
+	}
+
+	var out bytes.Buffer
+	if err := pdf.Output(&out); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+var templateFuncs = map[string]any{
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02")
+	},
+	"percent": func(rate float64) string {
+		return strconv.FormatFloat(rate, 'f', -1, 64) + "%"
+	},
+	"discount": func(m money.Money) string {
+		if m.IsZero() {
+			return ""
+		}
+		return "-" + m.String()
+	},
+	"repeat": strings.Repeat,
+	"lpad":   lpad,
+	"rpad":   rpad,
+}
+
+// lpad right-aligns s in a column of width runes
+func lpad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return strings.Repeat(" ", width-n) + s
+	}
+	return s
+}
+
+// rpad left-aligns s in a column of width runes, cutting it short so at
+// least one space separates it from the next column
+func rpad(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) >= width {
+		runes = runes[:width-1]
+	}
+	return string(runes) + strings.Repeat(" ", width-len(runes))
+}
@@ -0,0 +1,90 @@
+package invoice
+
+import (
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/shared/config"
+	"mallbots/shared/money"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func usd(amount string) money.Money {
+	return money.MustParse(amount, money.USD)
+}
+
+func testDocument() *entities.InvoiceDocument {
+	issuedAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	return &entities.InvoiceDocument{
+		Number:    "INV-2026-000042",
+		IssuedAt:  issuedAt,
+		OrderID:   7,
+		OrderedAt: issuedAt.Add(-time.Hour),
+		Seller:    entities.InvoiceParty{Name: "Mallbots Ltd", TaxID: "GB123"},
+		BillTo:    entities.InvoiceParty{Name: "Test <User>", Email: "test1@example.com"},
+		ShipTo:    entities.InvoiceParty{Address: "123 Test St", City: "Test City", Country: "Test Country", Zip: "12345"},
+		Currency:  "USD",
+		Lines: []entities.InvoiceLine{
+			{ProductID: 1, Description: "Café au lait mug", Quantity: 2, UnitPrice: usd("10.00"), Amount: usd("20.00"), Discount: usd("2.00"), TaxRate: 10, TaxAmount: usd("1.80")},
+		},
+		Subtotal:       usd("20.00"),
+		ShippingAmount: usd("5.00"),
+		DiscountAmount: usd("2.00"),
+		CouponCode:     "SAVE10",
+		TaxAmount:      usd("1.80"),
+		TotalAmount:    usd("24.80"),
+	}
+}
+
+func TestInvoiceRenderer(t *testing.T) {
+	renderer, err := NewInvoiceRenderer(&config.Config{
+		Invoice: config.InvoiceConfig{TemplateDir: t.TempDir()},
+	})
+	require.NoError(t, err)
+
+	t.Run("Render HTML", func(t *testing.T) {
+		out, err := renderer.Render(testDocument(), constants.InvoiceFormatHTML)
+		require.NoError(t, err)
+
+		html := string(out)
+		require.Contains(t, html, "Invoice INV-2026-000042")
+		require.Contains(t, html, "Discount (SAVE10)")
+		require.Contains(t, html, "24.80")
+		require.Contains(t, html, "Test &lt;User&gt;")
+	})
+
+	t.Run("Render PDF", func(t *testing.T) {
+		out, err := renderer.Render(testDocument(), constants.InvoiceFormatPDF)
+		require.NoError(t, err)
+		require.True(t, len(out) > 4 && string(out[:4]) == "%PDF")
+
+		again, err := renderer.Render(testDocument(), constants.InvoiceFormatPDF)
+		require.NoError(t, err)
+		require.Equal(t, out, again, "rendering the same invoice twice should give the same file")
+	})
+}
+
+func TestInvoiceRendererTemplateOverride(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, htmlTemplateName), []byte("<p>{{.Number}} {{.TotalAmount}}</p>"), 0o644)
+	require.NoError(t, err)
+
+	renderer, err := NewInvoiceRenderer(&config.Config{
+		Invoice: config.InvoiceConfig{TemplateDir: dir},
+	})
+	require.NoError(t, err)
+
+	out, err := renderer.Render(testDocument(), constants.InvoiceFormatHTML)
+	require.NoError(t, err)
+	require.Equal(t, "<p>INV-2026-000042 24.80</p>", string(out))
+
+	// The PDF layout was not overridden and keeps the built-in one
+	out, err = renderer.Render(testDocument(), constants.InvoiceFormatPDF)
+	require.NoError(t, err)
+	require.Equal(t, "%PDF", string(out[:4]))
+}
@@ -6,28 +6,126 @@ package gen
 
 import (
 	"time"
+
+	"mallbots/shared/money"
 )
 
+type Invoice struct {
+	ID       int32     `db:"id" json:"id"`
+	OrderID  int32     `db:"order_id" json:"order_id"`
+	Year     int32     `db:"year" json:"year"`
+	Sequence int32     `db:"sequence" json:"sequence"`
+	Number   string    `db:"number" json:"number"`
+	IssuedAt time.Time `db:"issued_at" json:"issued_at"`
+}
+
 type Order struct {
-	ID              int32     `db:"id" json:"id"`
-	UserID          int32     `db:"user_id" json:"user_id"`
-	Status          string    `db:"status" json:"status"`
-	PaymentStatus   string    `db:"payment_status" json:"payment_status"`
-	TotalAmount     float64   `db:"total_amount" json:"total_amount"`
-	ShippingAddress string    `db:"shipping_address" json:"shipping_address"`
-	ShippingCity    string    `db:"shipping_city" json:"shipping_city"`
-	ShippingCountry string    `db:"shipping_country" json:"shipping_country"`
-	ShippingZip     string    `db:"shipping_zip" json:"shipping_zip"`
-	CreatedAt       time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	ID              int32          `db:"id" json:"id"`
+	UserID          int32          `db:"user_id" json:"user_id"`
+	Status          string         `db:"status" json:"status"`
+	PaymentStatus   string         `db:"payment_status" json:"payment_status"`
+	TotalAmount     money.Minor    `db:"total_amount" json:"total_amount"`
+	ShippingAddress string         `db:"shipping_address" json:"shipping_address"`
+	ShippingCity    string         `db:"shipping_city" json:"shipping_city"`
+	ShippingCountry string         `db:"shipping_country" json:"shipping_country"`
+	ShippingZip     string         `db:"shipping_zip" json:"shipping_zip"`
+	CancelReason    *string        `db:"cancel_reason" json:"cancel_reason"`
+	CancelledAt     *time.Time     `db:"cancelled_at" json:"cancelled_at"`
+	PaymentProvider *string        `db:"payment_provider" json:"payment_provider"`
+	PaymentIntentID *string        `db:"payment_intent_id" json:"payment_intent_id"`
+	ShippingAmount  money.Minor    `db:"shipping_amount" json:"shipping_amount"`
+	TaxAmount       money.Minor    `db:"tax_amount" json:"tax_amount"`
+	TaxInclusive    bool           `db:"tax_inclusive" json:"tax_inclusive"`
+	DiscountAmount  money.Minor    `db:"discount_amount" json:"discount_amount"`
+	CouponCode      *string        `db:"coupon_code" json:"coupon_code"`
+	Currency        money.Currency `db:"currency" json:"currency"`
+	ExchangeRateID  *int32         `db:"exchange_rate_id" json:"exchange_rate_id"`
+	ExchangeRate    money.Rate     `db:"exchange_rate" json:"exchange_rate"`
+	BillingAddress  string         `db:"billing_address" json:"billing_address"`
+	BillingCity     string         `db:"billing_city" json:"billing_city"`
+	BillingCountry  string         `db:"billing_country" json:"billing_country"`
+	BillingZip      string         `db:"billing_zip" json:"billing_zip"`
+	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type OrderEvent struct {
+	ID         int32     `db:"id" json:"id"`
+	OrderID    int32     `db:"order_id" json:"order_id"`
+	EventType  string    `db:"event_type" json:"event_type"`
+	FromStatus *string   `db:"from_status" json:"from_status"`
+	ToStatus   *string   `db:"to_status" json:"to_status"`
+	ActorID    *int32    `db:"actor_id" json:"actor_id"`
+	ActorRole  string    `db:"actor_role" json:"actor_role"`
+	Metadata   []byte    `db:"metadata" json:"metadata"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
 }
 
 type OrderItem struct {
-	ID        int32     `db:"id" json:"id"`
-	OrderID   int32     `db:"order_id" json:"order_id"`
-	ProductID int32     `db:"product_id" json:"product_id"`
-	Quantity  int32     `db:"quantity" json:"quantity"`
-	Price     float64   `db:"price" json:"price"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID                 int32          `db:"id" json:"id"`
+	OrderID            int32          `db:"order_id" json:"order_id"`
+	ProductID          int32          `db:"product_id" json:"product_id"`
+	Quantity           int32          `db:"quantity" json:"quantity"`
+	Price              money.Minor    `db:"price" json:"price"`
+	TaxRate            float64        `db:"tax_rate" json:"tax_rate"`
+	TaxAmount          money.Minor    `db:"tax_amount" json:"tax_amount"`
+	DiscountAmount     money.Minor    `db:"discount_amount" json:"discount_amount"`
+	Currency           money.Currency `db:"currency" json:"currency"`
+	ProductPrice       money.Minor    `db:"product_price" json:"product_price"`
+	ProductCurrency    money.Currency `db:"product_currency" json:"product_currency"`
+	ExchangeRateID     *int32         `db:"exchange_rate_id" json:"exchange_rate_id"`
+	ExchangeRate       money.Rate     `db:"exchange_rate" json:"exchange_rate"`
+	ProductName        string         `db:"product_name" json:"product_name"`
+	ProductDescription *string        `db:"product_description" json:"product_description"`
+	CategoryID         *int32         `db:"category_id" json:"category_id"`
+	CategoryName       *string        `db:"category_name" json:"category_name"`
+	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type Refund struct {
+	ID               int32          `db:"id" json:"id"`
+	OrderID          int32          `db:"order_id" json:"order_id"`
+	UserID           int32          `db:"user_id" json:"user_id"`
+	Status           string         `db:"status" json:"status"`
+	Amount           money.Minor    `db:"amount" json:"amount"`
+	Reason           string         `db:"reason" json:"reason"`
+	ApprovedAt       *time.Time     `db:"approved_at" json:"approved_at"`
+	ProcessedAt      *time.Time     `db:"processed_at" json:"processed_at"`
+	RejectedAt       *time.Time     `db:"rejected_at" json:"rejected_at"`
+	ProviderRefundID *string        `db:"provider_refund_id" json:"provider_refund_id"`
+	Currency         money.Currency `db:"currency" json:"currency"`
+	CreatedAt        time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type RefundItem struct {
+	ID          int32          `db:"id" json:"id"`
+	RefundID    int32          `db:"refund_id" json:"refund_id"`
+	OrderItemID int32          `db:"order_item_id" json:"order_item_id"`
+	Quantity    int32          `db:"quantity" json:"quantity"`
+	Amount      money.Minor    `db:"amount" json:"amount"`
+	Currency    money.Currency `db:"currency" json:"currency"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+}
+
+type Shipment struct {
+	ID             int32      `db:"id" json:"id"`
+	OrderID        int32      `db:"order_id" json:"order_id"`
+	Status         string     `db:"status" json:"status"`
+	Carrier        string     `db:"carrier" json:"carrier"`
+	TrackingNumber *string    `db:"tracking_number" json:"tracking_number"`
+	ShippedAt      *time.Time `db:"shipped_at" json:"shipped_at"`
+	DeliveredAt    *time.Time `db:"delivered_at" json:"delivered_at"`
+	CancelledAt    *time.Time `db:"cancelled_at" json:"cancelled_at"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+type ShipmentItem struct {
+	ID          int32     `db:"id" json:"id"`
+	ShipmentID  int32     `db:"shipment_id" json:"shipment_id"`
+	OrderItemID int32     `db:"order_item_id" json:"order_item_id"`
+	Quantity    int32     `db:"quantity" json:"quantity"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 }
@@ -8,14 +8,101 @@ package gen
 import (
 	"context"
 	"time"
+
+	"mallbots/shared/money"
 )
 
+const cancelOrder = `-- name: CancelOrder :exec
+UPDATE orders
+SET status = $2,
+    payment_status = $3,
+    cancel_reason = $4,
+    cancelled_at = $5,
+    updated_at = $6
+WHERE id = $1
+`
+
+type CancelOrderParams struct {
+	ID            int32      `db:"id" json:"id"`
+	Status        string     `db:"status" json:"status"`
+	PaymentStatus string     `db:"payment_status" json:"payment_status"`
+	CancelReason  *string    `db:"cancel_reason" json:"cancel_reason"`
+	CancelledAt   *time.Time `db:"cancelled_at" json:"cancelled_at"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+func (q *Queries) CancelOrder(ctx context.Context, arg CancelOrderParams) error {
+	_, err := q.db.Exec(ctx, cancelOrder,
+		arg.ID,
+		arg.Status,
+		arg.PaymentStatus,
+		arg.CancelReason,
+		arg.CancelledAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
 const countOrdersByUserID = `-- name: CountOrdersByUserID :one
-SELECT COUNT(*) FROM orders WHERE user_id = $1
+SELECT COUNT(*) FROM orders
+WHERE user_id = $1
+  AND ($2::text IS NULL OR status = $2::text)
+  AND ($3::text IS NULL OR payment_status = $3::text)
+  AND ($4::timestamp IS NULL OR created_at >= $4::timestamp)
+  AND ($5::timestamp IS NULL OR created_at < $5::timestamp)
+  AND ($6::text IS NULL OR currency = $6::text)
+  AND ($7::bigint IS NULL OR total_amount >= $7::bigint)
+  AND ($8::bigint IS NULL OR total_amount <= $8::bigint)
+  AND ($9::int IS NULL OR EXISTS (
+      SELECT 1 FROM order_items
+      WHERE order_items.order_id = orders.id
+        AND order_items.product_id = $9::int
+  ))
+`
+
+type CountOrdersByUserIDParams struct {
+	UserID        int32      `db:"user_id" json:"user_id"`
+	Status        *string    `db:"status" json:"status"`
+	PaymentStatus *string    `db:"payment_status" json:"payment_status"`
+	CreatedFrom   *time.Time `db:"created_from" json:"created_from"`
+	CreatedTo     *time.Time `db:"created_to" json:"created_to"`
+	Currency      *string    `db:"currency" json:"currency"`
+	MinTotal      *int64     `db:"min_total" json:"min_total"`
+	MaxTotal      *int64     `db:"max_total" json:"max_total"`
+	ProductID     *int32     `db:"product_id" json:"product_id"`
+}
+
+func (q *Queries) CountOrdersByUserID(ctx context.Context, arg CountOrdersByUserIDParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countOrdersByUserID,
+		arg.UserID,
+		arg.Status,
+		arg.PaymentStatus,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Currency,
+		arg.MinTotal,
+		arg.MaxTotal,
+		arg.ProductID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUserOrdersSince = `-- name: CountUserOrdersSince :one
+SELECT COUNT(*) FROM orders
+WHERE user_id = $1
+  AND created_at >= $2
+  AND status <> 'CANCELLED'
 `
 
-func (q *Queries) CountOrdersByUserID(ctx context.Context, userID int32) (int64, error) {
-	row := q.db.QueryRow(ctx, countOrdersByUserID, userID)
+type CountUserOrdersSinceParams struct {
+	UserID    int32     `db:"user_id" json:"user_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *Queries) CountUserOrdersSince(ctx context.Context, arg CountUserOrdersSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countUserOrdersSince, arg.UserID, arg.CreatedAt)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -27,28 +114,52 @@ INSERT INTO orders (
     status,
     payment_status,
     total_amount,
+    currency,
+    shipping_amount,
+    tax_amount,
+    tax_inclusive,
+    discount_amount,
+    coupon_code,
     shipping_address,
     shipping_city,
     shipping_country,
     shipping_zip,
+    exchange_rate_id,
+    exchange_rate,
+    billing_address,
+    billing_city,
+    billing_country,
+    billing_zip,
     created_at,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
-) RETURNING id, user_id, status, payment_status, total_amount, shipping_address, shipping_city, shipping_country, shipping_zip, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22
+) RETURNING id, user_id, status, payment_status, total_amount, shipping_address, shipping_city, shipping_country, shipping_zip, cancel_reason, cancelled_at, payment_provider, payment_intent_id, shipping_amount, tax_amount, tax_inclusive, discount_amount, coupon_code, currency, exchange_rate_id, exchange_rate, billing_address, billing_city, billing_country, billing_zip, created_at, updated_at
 `
 
 type CreateOrderParams struct {
-	UserID          int32     `db:"user_id" json:"user_id"`
-	Status          string    `db:"status" json:"status"`
-	PaymentStatus   string    `db:"payment_status" json:"payment_status"`
-	TotalAmount     float64   `db:"total_amount" json:"total_amount"`
-	ShippingAddress string    `db:"shipping_address" json:"shipping_address"`
-	ShippingCity    string    `db:"shipping_city" json:"shipping_city"`
-	ShippingCountry string    `db:"shipping_country" json:"shipping_country"`
-	ShippingZip     string    `db:"shipping_zip" json:"shipping_zip"`
-	CreatedAt       time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	UserID          int32          `db:"user_id" json:"user_id"`
+	Status          string         `db:"status" json:"status"`
+	PaymentStatus   string         `db:"payment_status" json:"payment_status"`
+	TotalAmount     money.Minor    `db:"total_amount" json:"total_amount"`
+	Currency        money.Currency `db:"currency" json:"currency"`
+	ShippingAmount  money.Minor    `db:"shipping_amount" json:"shipping_amount"`
+	TaxAmount       money.Minor    `db:"tax_amount" json:"tax_amount"`
+	TaxInclusive    bool           `db:"tax_inclusive" json:"tax_inclusive"`
+	DiscountAmount  money.Minor    `db:"discount_amount" json:"discount_amount"`
+	CouponCode      *string        `db:"coupon_code" json:"coupon_code"`
+	ShippingAddress string         `db:"shipping_address" json:"shipping_address"`
+	ShippingCity    string         `db:"shipping_city" json:"shipping_city"`
+	ShippingCountry string         `db:"shipping_country" json:"shipping_country"`
+	ShippingZip     string         `db:"shipping_zip" json:"shipping_zip"`
+	ExchangeRateID  *int32         `db:"exchange_rate_id" json:"exchange_rate_id"`
+	ExchangeRate    money.Rate     `db:"exchange_rate" json:"exchange_rate"`
+	BillingAddress  string         `db:"billing_address" json:"billing_address"`
+	BillingCity     string         `db:"billing_city" json:"billing_city"`
+	BillingCountry  string         `db:"billing_country" json:"billing_country"`
+	BillingZip      string         `db:"billing_zip" json:"billing_zip"`
+	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (*Order, error) {
@@ -57,10 +168,22 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (*Orde
 		arg.Status,
 		arg.PaymentStatus,
 		arg.TotalAmount,
+		arg.Currency,
+		arg.ShippingAmount,
+		arg.TaxAmount,
+		arg.TaxInclusive,
+		arg.DiscountAmount,
+		arg.CouponCode,
 		arg.ShippingAddress,
 		arg.ShippingCity,
 		arg.ShippingCountry,
 		arg.ShippingZip,
+		arg.ExchangeRateID,
+		arg.ExchangeRate,
+		arg.BillingAddress,
+		arg.BillingCity,
+		arg.BillingCountry,
+		arg.BillingZip,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -75,32 +198,124 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (*Orde
 		&i.ShippingCity,
 		&i.ShippingCountry,
 		&i.ShippingZip,
+		&i.CancelReason,
+		&i.CancelledAt,
+		&i.PaymentProvider,
+		&i.PaymentIntentID,
+		&i.ShippingAmount,
+		&i.TaxAmount,
+		&i.TaxInclusive,
+		&i.DiscountAmount,
+		&i.CouponCode,
+		&i.Currency,
+		&i.ExchangeRateID,
+		&i.ExchangeRate,
+		&i.BillingAddress,
+		&i.BillingCity,
+		&i.BillingCountry,
+		&i.BillingZip,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return &i, err
 }
 
+const createOrderEvent = `-- name: CreateOrderEvent :one
+INSERT INTO order_events (
+    order_id,
+    event_type,
+    from_status,
+    to_status,
+    actor_id,
+    actor_role,
+    metadata,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, order_id, event_type, from_status, to_status, actor_id, actor_role, metadata, created_at
+`
+
+type CreateOrderEventParams struct {
+	OrderID    int32     `db:"order_id" json:"order_id"`
+	EventType  string    `db:"event_type" json:"event_type"`
+	FromStatus *string   `db:"from_status" json:"from_status"`
+	ToStatus   *string   `db:"to_status" json:"to_status"`
+	ActorID    *int32    `db:"actor_id" json:"actor_id"`
+	ActorRole  string    `db:"actor_role" json:"actor_role"`
+	Metadata   []byte    `db:"metadata" json:"metadata"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *Queries) CreateOrderEvent(ctx context.Context, arg CreateOrderEventParams) (*OrderEvent, error) {
+	row := q.db.QueryRow(ctx, createOrderEvent,
+		arg.OrderID,
+		arg.EventType,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.ActorID,
+		arg.ActorRole,
+		arg.Metadata,
+		arg.CreatedAt,
+	)
+	var i OrderEvent
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.EventType,
+		&i.FromStatus,
+		&i.ToStatus,
+		&i.ActorID,
+		&i.ActorRole,
+		&i.Metadata,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
 const createOrderItem = `-- name: CreateOrderItem :one
 INSERT INTO order_items (
     order_id,
     product_id,
     quantity,
     price,
+    currency,
+    tax_rate,
+    tax_amount,
+    discount_amount,
+    product_price,
+    product_currency,
+    exchange_rate_id,
+    exchange_rate,
+    product_name,
+    product_description,
+    category_id,
+    category_name,
     created_at,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
-) RETURNING id, order_id, product_id, quantity, price, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
+) RETURNING id, order_id, product_id, quantity, price, tax_rate, tax_amount, discount_amount, currency, product_price, product_currency, exchange_rate_id, exchange_rate, product_name, product_description, category_id, category_name, created_at, updated_at
 `
 
 type CreateOrderItemParams struct {
-	OrderID   int32     `db:"order_id" json:"order_id"`
-	ProductID int32     `db:"product_id" json:"product_id"`
-	Quantity  int32     `db:"quantity" json:"quantity"`
-	Price     float64   `db:"price" json:"price"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	OrderID            int32          `db:"order_id" json:"order_id"`
+	ProductID          int32          `db:"product_id" json:"product_id"`
+	Quantity           int32          `db:"quantity" json:"quantity"`
+	Price              money.Minor    `db:"price" json:"price"`
+	Currency           money.Currency `db:"currency" json:"currency"`
+	TaxRate            float64        `db:"tax_rate" json:"tax_rate"`
+	TaxAmount          money.Minor    `db:"tax_amount" json:"tax_amount"`
+	DiscountAmount     money.Minor    `db:"discount_amount" json:"discount_amount"`
+	ProductPrice       money.Minor    `db:"product_price" json:"product_price"`
+	ProductCurrency    money.Currency `db:"product_currency" json:"product_currency"`
+	ExchangeRateID     *int32         `db:"exchange_rate_id" json:"exchange_rate_id"`
+	ExchangeRate       money.Rate     `db:"exchange_rate" json:"exchange_rate"`
+	ProductName        string         `db:"product_name" json:"product_name"`
+	ProductDescription *string        `db:"product_description" json:"product_description"`
+	CategoryID         *int32         `db:"category_id" json:"category_id"`
+	CategoryName       *string        `db:"category_name" json:"category_name"`
+	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time      `db:"updated_at" json:"updated_at"`
 }
 
 func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (*OrderItem, error) {
@@ -109,6 +324,18 @@ func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams
 		arg.ProductID,
 		arg.Quantity,
 		arg.Price,
+		arg.Currency,
+		arg.TaxRate,
+		arg.TaxAmount,
+		arg.DiscountAmount,
+		arg.ProductPrice,
+		arg.ProductCurrency,
+		arg.ExchangeRateID,
+		arg.ExchangeRate,
+		arg.ProductName,
+		arg.ProductDescription,
+		arg.CategoryID,
+		arg.CategoryName,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -119,14 +346,76 @@ func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams
 		&i.ProductID,
 		&i.Quantity,
 		&i.Price,
+		&i.TaxRate,
+		&i.TaxAmount,
+		&i.DiscountAmount,
+		&i.Currency,
+		&i.ProductPrice,
+		&i.ProductCurrency,
+		&i.ExchangeRateID,
+		&i.ExchangeRate,
+		&i.ProductName,
+		&i.ProductDescription,
+		&i.CategoryID,
+		&i.CategoryName,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return &i, err
 }
 
+const getExpiredUnpaidOrderIDs = `-- name: GetExpiredUnpaidOrderIDs :many
+SELECT id FROM orders
+WHERE status = 'PENDING'
+  AND payment_status = 'PENDING'
+  AND created_at < $1::timestamp - make_interval(secs => NULLIF(COALESCE(
+      (SELECT ttl.seconds FROM unnest($2::text[], $3::bigint[]) AS ttl(provider, seconds)
+       WHERE ttl.provider = orders.payment_provider),
+      $4::bigint
+  ), 0))
+  AND id <> ALL($5::int[])
+ORDER BY created_at, id
+LIMIT $6
+`
+
+type GetExpiredUnpaidOrderIDsParams struct {
+	Now                time.Time `db:"now" json:"now"`
+	Providers          []string  `db:"providers" json:"providers"`
+	ProviderTtlSeconds []int64   `db:"provider_ttl_seconds" json:"provider_ttl_seconds"`
+	DefaultTtlSeconds  *int64    `db:"default_ttl_seconds" json:"default_ttl_seconds"`
+	SkipIds            []int32   `db:"skip_ids" json:"skip_ids"`
+	RowLimit           int32     `db:"row_limit" json:"row_limit"`
+}
+
+func (q *Queries) GetExpiredUnpaidOrderIDs(ctx context.Context, arg GetExpiredUnpaidOrderIDsParams) ([]int32, error) {
+	rows, err := q.db.Query(ctx, getExpiredUnpaidOrderIDs,
+		arg.Now,
+		arg.Providers,
+		arg.ProviderTtlSeconds,
+		arg.DefaultTtlSeconds,
+		arg.SkipIds,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getOrderByID = `-- name: GetOrderByID :one
-SELECT id, user_id, status, payment_status, total_amount, shipping_address, shipping_city, shipping_country, shipping_zip, created_at, updated_at FROM orders WHERE id = $1
+SELECT id, user_id, status, payment_status, total_amount, shipping_address, shipping_city, shipping_country, shipping_zip, cancel_reason, cancelled_at, payment_provider, payment_intent_id, shipping_amount, tax_amount, tax_inclusive, discount_amount, coupon_code, currency, exchange_rate_id, exchange_rate, billing_address, billing_city, billing_country, billing_zip, created_at, updated_at FROM orders WHERE id = $1
 `
 
 func (q *Queries) GetOrderByID(ctx context.Context, id int32) (*Order, error) {
@@ -142,14 +431,144 @@ func (q *Queries) GetOrderByID(ctx context.Context, id int32) (*Order, error) {
 		&i.ShippingCity,
 		&i.ShippingCountry,
 		&i.ShippingZip,
+		&i.CancelReason,
+		&i.CancelledAt,
+		&i.PaymentProvider,
+		&i.PaymentIntentID,
+		&i.ShippingAmount,
+		&i.TaxAmount,
+		&i.TaxInclusive,
+		&i.DiscountAmount,
+		&i.CouponCode,
+		&i.Currency,
+		&i.ExchangeRateID,
+		&i.ExchangeRate,
+		&i.BillingAddress,
+		&i.BillingCity,
+		&i.BillingCountry,
+		&i.BillingZip,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getOrderByIDForUpdate = `-- name: GetOrderByIDForUpdate :one
+SELECT id, user_id, status, payment_status, total_amount, shipping_address, shipping_city, shipping_country, shipping_zip, cancel_reason, cancelled_at, payment_provider, payment_intent_id, shipping_amount, tax_amount, tax_inclusive, discount_amount, coupon_code, currency, exchange_rate_id, exchange_rate, billing_address, billing_city, billing_country, billing_zip, created_at, updated_at FROM orders WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetOrderByIDForUpdate(ctx context.Context, id int32) (*Order, error) {
+	row := q.db.QueryRow(ctx, getOrderByIDForUpdate, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.PaymentStatus,
+		&i.TotalAmount,
+		&i.ShippingAddress,
+		&i.ShippingCity,
+		&i.ShippingCountry,
+		&i.ShippingZip,
+		&i.CancelReason,
+		&i.CancelledAt,
+		&i.PaymentProvider,
+		&i.PaymentIntentID,
+		&i.ShippingAmount,
+		&i.TaxAmount,
+		&i.TaxInclusive,
+		&i.DiscountAmount,
+		&i.CouponCode,
+		&i.Currency,
+		&i.ExchangeRateID,
+		&i.ExchangeRate,
+		&i.BillingAddress,
+		&i.BillingCity,
+		&i.BillingCountry,
+		&i.BillingZip,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getOrderByPaymentIntentIDForUpdate = `-- name: GetOrderByPaymentIntentIDForUpdate :one
+SELECT id, user_id, status, payment_status, total_amount, shipping_address, shipping_city, shipping_country, shipping_zip, cancel_reason, cancelled_at, payment_provider, payment_intent_id, shipping_amount, tax_amount, tax_inclusive, discount_amount, coupon_code, currency, exchange_rate_id, exchange_rate, billing_address, billing_city, billing_country, billing_zip, created_at, updated_at FROM orders WHERE payment_intent_id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetOrderByPaymentIntentIDForUpdate(ctx context.Context, paymentIntentID *string) (*Order, error) {
+	row := q.db.QueryRow(ctx, getOrderByPaymentIntentIDForUpdate, paymentIntentID)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.PaymentStatus,
+		&i.TotalAmount,
+		&i.ShippingAddress,
+		&i.ShippingCity,
+		&i.ShippingCountry,
+		&i.ShippingZip,
+		&i.CancelReason,
+		&i.CancelledAt,
+		&i.PaymentProvider,
+		&i.PaymentIntentID,
+		&i.ShippingAmount,
+		&i.TaxAmount,
+		&i.TaxInclusive,
+		&i.DiscountAmount,
+		&i.CouponCode,
+		&i.Currency,
+		&i.ExchangeRateID,
+		&i.ExchangeRate,
+		&i.BillingAddress,
+		&i.BillingCity,
+		&i.BillingCountry,
+		&i.BillingZip,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return &i, err
 }
 
+const getOrderEvents = `-- name: GetOrderEvents :many
+SELECT id, order_id, event_type, from_status, to_status, actor_id, actor_role, metadata, created_at FROM order_events
+WHERE order_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) GetOrderEvents(ctx context.Context, orderID int32) ([]*OrderEvent, error) {
+	rows, err := q.db.Query(ctx, getOrderEvents, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*OrderEvent
+	for rows.Next() {
+		var i OrderEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.EventType,
+			&i.FromStatus,
+			&i.ToStatus,
+			&i.ActorID,
+			&i.ActorRole,
+			&i.Metadata,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getOrderItems = `-- name: GetOrderItems :many
-SELECT id, order_id, product_id, quantity, price, created_at, updated_at FROM order_items WHERE order_id = $1
+SELECT id, order_id, product_id, quantity, price, tax_rate, tax_amount, discount_amount, currency, product_price, product_currency, exchange_rate_id, exchange_rate, product_name, product_description, category_id, category_name, created_at, updated_at FROM order_items WHERE order_id = $1
 `
 
 func (q *Queries) GetOrderItems(ctx context.Context, orderID int32) ([]*OrderItem, error) {
@@ -167,6 +586,18 @@ func (q *Queries) GetOrderItems(ctx context.Context, orderID int32) ([]*OrderIte
 			&i.ProductID,
 			&i.Quantity,
 			&i.Price,
+			&i.TaxRate,
+			&i.TaxAmount,
+			&i.DiscountAmount,
+			&i.Currency,
+			&i.ProductPrice,
+			&i.ProductCurrency,
+			&i.ExchangeRateID,
+			&i.ExchangeRate,
+			&i.ProductName,
+			&i.ProductDescription,
+			&i.CategoryID,
+			&i.CategoryName,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -180,21 +611,204 @@ func (q *Queries) GetOrderItems(ctx context.Context, orderID int32) ([]*OrderIte
 	return items, nil
 }
 
-const getOrdersByUserID = `-- name: GetOrdersByUserID :many
-SELECT id, user_id, status, payment_status, total_amount, shipping_address, shipping_city, shipping_country, shipping_zip, created_at, updated_at FROM orders
+const getOrderItemsByOrderIDs = `-- name: GetOrderItemsByOrderIDs :many
+SELECT id, order_id, product_id, quantity, price, tax_rate, tax_amount, discount_amount, currency, product_price, product_currency, exchange_rate_id, exchange_rate, product_name, product_description, category_id, category_name, created_at, updated_at FROM order_items
+WHERE order_id = ANY($1::int[])
+ORDER BY order_id, id
+`
+
+func (q *Queries) GetOrderItemsByOrderIDs(ctx context.Context, dollar_1 []int32) ([]*OrderItem, error) {
+	rows, err := q.db.Query(ctx, getOrderItemsByOrderIDs, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*OrderItem
+	for rows.Next() {
+		var i OrderItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.ProductID,
+			&i.Quantity,
+			&i.Price,
+			&i.TaxRate,
+			&i.TaxAmount,
+			&i.DiscountAmount,
+			&i.Currency,
+			&i.ProductPrice,
+			&i.ProductCurrency,
+			&i.ExchangeRateID,
+			&i.ExchangeRate,
+			&i.ProductName,
+			&i.ProductDescription,
+			&i.CategoryID,
+			&i.CategoryName,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrdersByUserIDCreatedAsc = `-- name: GetOrdersByUserIDCreatedAsc :many
+SELECT id, user_id, status, payment_status, total_amount, shipping_address, shipping_city, shipping_country, shipping_zip, cancel_reason, cancelled_at, payment_provider, payment_intent_id, shipping_amount, tax_amount, tax_inclusive, discount_amount, coupon_code, currency, exchange_rate_id, exchange_rate, billing_address, billing_city, billing_country, billing_zip, created_at, updated_at FROM orders
+WHERE user_id = $1
+  AND ($2::text IS NULL OR status = $2::text)
+  AND ($3::text IS NULL OR payment_status = $3::text)
+  AND ($4::timestamp IS NULL OR created_at >= $4::timestamp)
+  AND ($5::timestamp IS NULL OR created_at < $5::timestamp)
+  AND ($6::text IS NULL OR currency = $6::text)
+  AND ($7::bigint IS NULL OR total_amount >= $7::bigint)
+  AND ($8::bigint IS NULL OR total_amount <= $8::bigint)
+  AND ($9::int IS NULL OR EXISTS (
+      SELECT 1 FROM order_items
+      WHERE order_items.order_id = orders.id
+        AND order_items.product_id = $9::int
+  ))
+  AND ($10::int IS NULL
+      OR (created_at, id) > ($11::timestamp, $10::int))
+ORDER BY created_at ASC, id ASC
+LIMIT $12 OFFSET $13
+`
+
+type GetOrdersByUserIDCreatedAscParams struct {
+	UserID         int32      `db:"user_id" json:"user_id"`
+	Status         *string    `db:"status" json:"status"`
+	PaymentStatus  *string    `db:"payment_status" json:"payment_status"`
+	CreatedFrom    *time.Time `db:"created_from" json:"created_from"`
+	CreatedTo      *time.Time `db:"created_to" json:"created_to"`
+	Currency       *string    `db:"currency" json:"currency"`
+	MinTotal       *int64     `db:"min_total" json:"min_total"`
+	MaxTotal       *int64     `db:"max_total" json:"max_total"`
+	ProductID      *int32     `db:"product_id" json:"product_id"`
+	AfterID        *int32     `db:"after_id" json:"after_id"`
+	AfterCreatedAt *time.Time `db:"after_created_at" json:"after_created_at"`
+	RowLimit       int32      `db:"row_limit" json:"row_limit"`
+	RowOffset      int32      `db:"row_offset" json:"row_offset"`
+}
+
+func (q *Queries) GetOrdersByUserIDCreatedAsc(ctx context.Context, arg GetOrdersByUserIDCreatedAscParams) ([]*Order, error) {
+	rows, err := q.db.Query(ctx, getOrdersByUserIDCreatedAsc,
+		arg.UserID,
+		arg.Status,
+		arg.PaymentStatus,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Currency,
+		arg.MinTotal,
+		arg.MaxTotal,
+		arg.ProductID,
+		arg.AfterID,
+		arg.AfterCreatedAt,
+		arg.RowLimit,
+		arg.RowOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Order
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Status,
+			&i.PaymentStatus,
+			&i.TotalAmount,
+			&i.ShippingAddress,
+			&i.ShippingCity,
+			&i.ShippingCountry,
+			&i.ShippingZip,
+			&i.CancelReason,
+			&i.CancelledAt,
+			&i.PaymentProvider,
+			&i.PaymentIntentID,
+			&i.ShippingAmount,
+			&i.TaxAmount,
+			&i.TaxInclusive,
+			&i.DiscountAmount,
+			&i.CouponCode,
+			&i.Currency,
+			&i.ExchangeRateID,
+			&i.ExchangeRate,
+			&i.BillingAddress,
+			&i.BillingCity,
+			&i.BillingCountry,
+			&i.BillingZip,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrdersByUserIDCreatedDesc = `-- name: GetOrdersByUserIDCreatedDesc :many
+SELECT id, user_id, status, payment_status, total_amount, shipping_address, shipping_city, shipping_country, shipping_zip, cancel_reason, cancelled_at, payment_provider, payment_intent_id, shipping_amount, tax_amount, tax_inclusive, discount_amount, coupon_code, currency, exchange_rate_id, exchange_rate, billing_address, billing_city, billing_country, billing_zip, created_at, updated_at FROM orders
 WHERE user_id = $1
-ORDER BY created_at DESC
-LIMIT $2 OFFSET $3
+  AND ($2::text IS NULL OR status = $2::text)
+  AND ($3::text IS NULL OR payment_status = $3::text)
+  AND ($4::timestamp IS NULL OR created_at >= $4::timestamp)
+  AND ($5::timestamp IS NULL OR created_at < $5::timestamp)
+  AND ($6::text IS NULL OR currency = $6::text)
+  AND ($7::bigint IS NULL OR total_amount >= $7::bigint)
+  AND ($8::bigint IS NULL OR total_amount <= $8::bigint)
+  AND ($9::int IS NULL OR EXISTS (
+      SELECT 1 FROM order_items
+      WHERE order_items.order_id = orders.id
+        AND order_items.product_id = $9::int
+  ))
+  AND ($10::int IS NULL
+      OR (created_at, id) < ($11::timestamp, $10::int))
+ORDER BY created_at DESC, id DESC
+LIMIT $12 OFFSET $13
 `
 
-type GetOrdersByUserIDParams struct {
-	UserID int32 `db:"user_id" json:"user_id"`
-	Limit  int32 `db:"limit" json:"limit"`
-	Offset int32 `db:"offset" json:"offset"`
+type GetOrdersByUserIDCreatedDescParams struct {
+	UserID         int32      `db:"user_id" json:"user_id"`
+	Status         *string    `db:"status" json:"status"`
+	PaymentStatus  *string    `db:"payment_status" json:"payment_status"`
+	CreatedFrom    *time.Time `db:"created_from" json:"created_from"`
+	CreatedTo      *time.Time `db:"created_to" json:"created_to"`
+	Currency       *string    `db:"currency" json:"currency"`
+	MinTotal       *int64     `db:"min_total" json:"min_total"`
+	MaxTotal       *int64     `db:"max_total" json:"max_total"`
+	ProductID      *int32     `db:"product_id" json:"product_id"`
+	AfterID        *int32     `db:"after_id" json:"after_id"`
+	AfterCreatedAt *time.Time `db:"after_created_at" json:"after_created_at"`
+	RowLimit       int32      `db:"row_limit" json:"row_limit"`
+	RowOffset      int32      `db:"row_offset" json:"row_offset"`
 }
 
-func (q *Queries) GetOrdersByUserID(ctx context.Context, arg GetOrdersByUserIDParams) ([]*Order, error) {
-	rows, err := q.db.Query(ctx, getOrdersByUserID, arg.UserID, arg.Limit, arg.Offset)
+func (q *Queries) GetOrdersByUserIDCreatedDesc(ctx context.Context, arg GetOrdersByUserIDCreatedDescParams) ([]*Order, error) {
+	rows, err := q.db.Query(ctx, getOrdersByUserIDCreatedDesc,
+		arg.UserID,
+		arg.Status,
+		arg.PaymentStatus,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Currency,
+		arg.MinTotal,
+		arg.MaxTotal,
+		arg.ProductID,
+		arg.AfterID,
+		arg.AfterCreatedAt,
+		arg.RowLimit,
+		arg.RowOffset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -212,6 +826,22 @@ func (q *Queries) GetOrdersByUserID(ctx context.Context, arg GetOrdersByUserIDPa
 			&i.ShippingCity,
 			&i.ShippingCountry,
 			&i.ShippingZip,
+			&i.CancelReason,
+			&i.CancelledAt,
+			&i.PaymentProvider,
+			&i.PaymentIntentID,
+			&i.ShippingAmount,
+			&i.TaxAmount,
+			&i.TaxInclusive,
+			&i.DiscountAmount,
+			&i.CouponCode,
+			&i.Currency,
+			&i.ExchangeRateID,
+			&i.ExchangeRate,
+			&i.BillingAddress,
+			&i.BillingCity,
+			&i.BillingCountry,
+			&i.BillingZip,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -225,6 +855,238 @@ func (q *Queries) GetOrdersByUserID(ctx context.Context, arg GetOrdersByUserIDPa
 	return items, nil
 }
 
+const getOrdersByUserIDTotalAsc = `-- name: GetOrdersByUserIDTotalAsc :many
+SELECT id, user_id, status, payment_status, total_amount, shipping_address, shipping_city, shipping_country, shipping_zip, cancel_reason, cancelled_at, payment_provider, payment_intent_id, shipping_amount, tax_amount, tax_inclusive, discount_amount, coupon_code, currency, exchange_rate_id, exchange_rate, billing_address, billing_city, billing_country, billing_zip, created_at, updated_at FROM orders
+WHERE user_id = $1
+  AND currency = $2::text
+  AND ($3::text IS NULL OR status = $3::text)
+  AND ($4::text IS NULL OR payment_status = $4::text)
+  AND ($5::timestamp IS NULL OR created_at >= $5::timestamp)
+  AND ($6::timestamp IS NULL OR created_at < $6::timestamp)
+  AND ($7::bigint IS NULL OR total_amount >= $7::bigint)
+  AND ($8::bigint IS NULL OR total_amount <= $8::bigint)
+  AND ($9::int IS NULL OR EXISTS (
+      SELECT 1 FROM order_items
+      WHERE order_items.order_id = orders.id
+        AND order_items.product_id = $9::int
+  ))
+  AND ($10::int IS NULL
+      OR (total_amount, id) > ($11::bigint, $10::int))
+ORDER BY total_amount ASC, id ASC
+LIMIT $12 OFFSET $13
+`
+
+type GetOrdersByUserIDTotalAscParams struct {
+	UserID        int32      `db:"user_id" json:"user_id"`
+	Currency      string     `db:"currency" json:"currency"`
+	Status        *string    `db:"status" json:"status"`
+	PaymentStatus *string    `db:"payment_status" json:"payment_status"`
+	CreatedFrom   *time.Time `db:"created_from" json:"created_from"`
+	CreatedTo     *time.Time `db:"created_to" json:"created_to"`
+	MinTotal      *int64     `db:"min_total" json:"min_total"`
+	MaxTotal      *int64     `db:"max_total" json:"max_total"`
+	ProductID     *int32     `db:"product_id" json:"product_id"`
+	AfterID       *int32     `db:"after_id" json:"after_id"`
+	AfterTotal    *int64     `db:"after_total" json:"after_total"`
+	RowLimit      int32      `db:"row_limit" json:"row_limit"`
+	RowOffset     int32      `db:"row_offset" json:"row_offset"`
+}
+
+func (q *Queries) GetOrdersByUserIDTotalAsc(ctx context.Context, arg GetOrdersByUserIDTotalAscParams) ([]*Order, error) {
+	rows, err := q.db.Query(ctx, getOrdersByUserIDTotalAsc,
+		arg.UserID,
+		arg.Currency,
+		arg.Status,
+		arg.PaymentStatus,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MinTotal,
+		arg.MaxTotal,
+		arg.ProductID,
+		arg.AfterID,
+		arg.AfterTotal,
+		arg.RowLimit,
+		arg.RowOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Order
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Status,
+			&i.PaymentStatus,
+			&i.TotalAmount,
+			&i.ShippingAddress,
+			&i.ShippingCity,
+			&i.ShippingCountry,
+			&i.ShippingZip,
+			&i.CancelReason,
+			&i.CancelledAt,
+			&i.PaymentProvider,
+			&i.PaymentIntentID,
+			&i.ShippingAmount,
+			&i.TaxAmount,
+			&i.TaxInclusive,
+			&i.DiscountAmount,
+			&i.CouponCode,
+			&i.Currency,
+			&i.ExchangeRateID,
+			&i.ExchangeRate,
+			&i.BillingAddress,
+			&i.BillingCity,
+			&i.BillingCountry,
+			&i.BillingZip,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrdersByUserIDTotalDesc = `-- name: GetOrdersByUserIDTotalDesc :many
+SELECT id, user_id, status, payment_status, total_amount, shipping_address, shipping_city, shipping_country, shipping_zip, cancel_reason, cancelled_at, payment_provider, payment_intent_id, shipping_amount, tax_amount, tax_inclusive, discount_amount, coupon_code, currency, exchange_rate_id, exchange_rate, billing_address, billing_city, billing_country, billing_zip, created_at, updated_at FROM orders
+WHERE user_id = $1
+  AND currency = $2::text
+  AND ($3::text IS NULL OR status = $3::text)
+  AND ($4::text IS NULL OR payment_status = $4::text)
+  AND ($5::timestamp IS NULL OR created_at >= $5::timestamp)
+  AND ($6::timestamp IS NULL OR created_at < $6::timestamp)
+  AND ($7::bigint IS NULL OR total_amount >= $7::bigint)
+  AND ($8::bigint IS NULL OR total_amount <= $8::bigint)
+  AND ($9::int IS NULL OR EXISTS (
+      SELECT 1 FROM order_items
+      WHERE order_items.order_id = orders.id
+        AND order_items.product_id = $9::int
+  ))
+  AND ($10::int IS NULL
+      OR (total_amount, id) < ($11::bigint, $10::int))
+ORDER BY total_amount DESC, id DESC
+LIMIT $12 OFFSET $13
+`
+
+type GetOrdersByUserIDTotalDescParams struct {
+	UserID        int32      `db:"user_id" json:"user_id"`
+	Currency      string     `db:"currency" json:"currency"`
+	Status        *string    `db:"status" json:"status"`
+	PaymentStatus *string    `db:"payment_status" json:"payment_status"`
+	CreatedFrom   *time.Time `db:"created_from" json:"created_from"`
+	CreatedTo     *time.Time `db:"created_to" json:"created_to"`
+	MinTotal      *int64     `db:"min_total" json:"min_total"`
+	MaxTotal      *int64     `db:"max_total" json:"max_total"`
+	ProductID     *int32     `db:"product_id" json:"product_id"`
+	AfterID       *int32     `db:"after_id" json:"after_id"`
+	AfterTotal    *int64     `db:"after_total" json:"after_total"`
+	RowLimit      int32      `db:"row_limit" json:"row_limit"`
+	RowOffset     int32      `db:"row_offset" json:"row_offset"`
+}
+
+func (q *Queries) GetOrdersByUserIDTotalDesc(ctx context.Context, arg GetOrdersByUserIDTotalDescParams) ([]*Order, error) {
+	rows, err := q.db.Query(ctx, getOrdersByUserIDTotalDesc,
+		arg.UserID,
+		arg.Currency,
+		arg.Status,
+		arg.PaymentStatus,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MinTotal,
+		arg.MaxTotal,
+		arg.ProductID,
+		arg.AfterID,
+		arg.AfterTotal,
+		arg.RowLimit,
+		arg.RowOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Order
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Status,
+			&i.PaymentStatus,
+			&i.TotalAmount,
+			&i.ShippingAddress,
+			&i.ShippingCity,
+			&i.ShippingCountry,
+			&i.ShippingZip,
+			&i.CancelReason,
+			&i.CancelledAt,
+			&i.PaymentProvider,
+			&i.PaymentIntentID,
+			&i.ShippingAmount,
+			&i.TaxAmount,
+			&i.TaxInclusive,
+			&i.DiscountAmount,
+			&i.CouponCode,
+			&i.Currency,
+			&i.ExchangeRateID,
+			&i.ExchangeRate,
+			&i.BillingAddress,
+			&i.BillingCity,
+			&i.BillingCountry,
+			&i.BillingZip,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockUserOrders = `-- name: LockUserOrders :exec
+SELECT pg_advisory_xact_lock(hashtext('user_orders'), $1::int)
+`
+
+func (q *Queries) LockUserOrders(ctx context.Context, userID int32) error {
+	_, err := q.db.Exec(ctx, lockUserOrders, userID)
+	return err
+}
+
+const setOrderPaymentIntent = `-- name: SetOrderPaymentIntent :exec
+UPDATE orders
+SET payment_provider = $2,
+    payment_intent_id = $3,
+    updated_at = $4
+WHERE id = $1
+`
+
+type SetOrderPaymentIntentParams struct {
+	ID              int32     `db:"id" json:"id"`
+	PaymentProvider *string   `db:"payment_provider" json:"payment_provider"`
+	PaymentIntentID *string   `db:"payment_intent_id" json:"payment_intent_id"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *Queries) SetOrderPaymentIntent(ctx context.Context, arg SetOrderPaymentIntentParams) error {
+	_, err := q.db.Exec(ctx, setOrderPaymentIntent,
+		arg.ID,
+		arg.PaymentProvider,
+		arg.PaymentIntentID,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
 const updateOrderStatus = `-- name: UpdateOrderStatus :exec
 UPDATE orders
 SET status = $2,
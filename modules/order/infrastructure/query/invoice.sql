@@ -0,0 +1,25 @@
+-- name: GetInvoiceByOrderID :one
+SELECT * FROM invoices WHERE order_id = $1;
+
+-- name: LockInvoiceCounter :one
+INSERT INTO invoice_counters (year, last_sequence)
+VALUES ($1, 0)
+ON CONFLICT (year) DO UPDATE
+SET last_sequence = invoice_counters.last_sequence
+RETURNING last_sequence;
+
+-- name: AdvanceInvoiceCounter :exec
+UPDATE invoice_counters
+SET last_sequence = $2
+WHERE year = $1;
+
+-- name: CreateInvoice :one
+INSERT INTO invoices (
+    order_id,
+    year,
+    sequence,
+    number,
+    issued_at
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
@@ -4,14 +4,26 @@ INSERT INTO orders (
     status,
     payment_status,
     total_amount,
+    currency,
+    shipping_amount,
+    tax_amount,
+    tax_inclusive,
+    discount_amount,
+    coupon_code,
     shipping_address,
     shipping_city,
     shipping_country,
     shipping_zip,
+    exchange_rate_id,
+    exchange_rate,
+    billing_address,
+    billing_city,
+    billing_country,
+    billing_zip,
     created_at,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22
 ) RETURNING *;
 
 -- name: CreateOrderItem :one
@@ -20,26 +32,142 @@ INSERT INTO order_items (
     product_id,
     quantity,
     price,
+    currency,
+    tax_rate,
+    tax_amount,
+    discount_amount,
+    product_price,
+    product_currency,
+    exchange_rate_id,
+    exchange_rate,
+    product_name,
+    product_description,
+    category_id,
+    category_name,
     created_at,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
 ) RETURNING *;
 
 -- name: GetOrderByID :one
 SELECT * FROM orders WHERE id = $1;
 
+-- name: GetOrderByIDForUpdate :one
+SELECT * FROM orders WHERE id = $1 FOR UPDATE;
+
 -- name: GetOrderItems :many
 SELECT * FROM order_items WHERE order_id = $1;
 
--- name: GetOrdersByUserID :many
+-- name: GetOrderItemsByOrderIDs :many
+SELECT * FROM order_items
+WHERE order_id = ANY($1::int[])
+ORDER BY order_id, id;
+
+-- name: GetOrdersByUserIDCreatedDesc :many
 SELECT * FROM orders
-WHERE user_id = $1
-ORDER BY created_at DESC
-LIMIT $2 OFFSET $3;
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(payment_status)::text IS NULL OR payment_status = sqlc.narg(payment_status)::text)
+  AND (sqlc.narg(created_from)::timestamp IS NULL OR created_at >= sqlc.narg(created_from)::timestamp)
+  AND (sqlc.narg(created_to)::timestamp IS NULL OR created_at < sqlc.narg(created_to)::timestamp)
+  AND (sqlc.narg(currency)::text IS NULL OR currency = sqlc.narg(currency)::text)
+  AND (sqlc.narg(min_total)::bigint IS NULL OR total_amount >= sqlc.narg(min_total)::bigint)
+  AND (sqlc.narg(max_total)::bigint IS NULL OR total_amount <= sqlc.narg(max_total)::bigint)
+  AND (sqlc.narg(product_id)::int IS NULL OR EXISTS (
+      SELECT 1 FROM order_items
+      WHERE order_items.order_id = orders.id
+        AND order_items.product_id = sqlc.narg(product_id)::int
+  ))
+  AND (sqlc.narg(after_id)::int IS NULL
+      OR (created_at, id) < (sqlc.narg(after_created_at)::timestamp, sqlc.narg(after_id)::int))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: GetOrdersByUserIDCreatedAsc :many
+SELECT * FROM orders
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(payment_status)::text IS NULL OR payment_status = sqlc.narg(payment_status)::text)
+  AND (sqlc.narg(created_from)::timestamp IS NULL OR created_at >= sqlc.narg(created_from)::timestamp)
+  AND (sqlc.narg(created_to)::timestamp IS NULL OR created_at < sqlc.narg(created_to)::timestamp)
+  AND (sqlc.narg(currency)::text IS NULL OR currency = sqlc.narg(currency)::text)
+  AND (sqlc.narg(min_total)::bigint IS NULL OR total_amount >= sqlc.narg(min_total)::bigint)
+  AND (sqlc.narg(max_total)::bigint IS NULL OR total_amount <= sqlc.narg(max_total)::bigint)
+  AND (sqlc.narg(product_id)::int IS NULL OR EXISTS (
+      SELECT 1 FROM order_items
+      WHERE order_items.order_id = orders.id
+        AND order_items.product_id = sqlc.narg(product_id)::int
+  ))
+  AND (sqlc.narg(after_id)::int IS NULL
+      OR (created_at, id) > (sqlc.narg(after_created_at)::timestamp, sqlc.narg(after_id)::int))
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: GetOrdersByUserIDTotalAsc :many
+SELECT * FROM orders
+WHERE user_id = sqlc.arg(user_id)
+  AND currency = sqlc.arg(currency)::text
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(payment_status)::text IS NULL OR payment_status = sqlc.narg(payment_status)::text)
+  AND (sqlc.narg(created_from)::timestamp IS NULL OR created_at >= sqlc.narg(created_from)::timestamp)
+  AND (sqlc.narg(created_to)::timestamp IS NULL OR created_at < sqlc.narg(created_to)::timestamp)
+  AND (sqlc.narg(min_total)::bigint IS NULL OR total_amount >= sqlc.narg(min_total)::bigint)
+  AND (sqlc.narg(max_total)::bigint IS NULL OR total_amount <= sqlc.narg(max_total)::bigint)
+  AND (sqlc.narg(product_id)::int IS NULL OR EXISTS (
+      SELECT 1 FROM order_items
+      WHERE order_items.order_id = orders.id
+        AND order_items.product_id = sqlc.narg(product_id)::int
+  ))
+  AND (sqlc.narg(after_id)::int IS NULL
+      OR (total_amount, id) > (sqlc.narg(after_total)::bigint, sqlc.narg(after_id)::int))
+ORDER BY total_amount ASC, id ASC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: GetOrdersByUserIDTotalDesc :many
+SELECT * FROM orders
+WHERE user_id = sqlc.arg(user_id)
+  AND currency = sqlc.arg(currency)::text
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(payment_status)::text IS NULL OR payment_status = sqlc.narg(payment_status)::text)
+  AND (sqlc.narg(created_from)::timestamp IS NULL OR created_at >= sqlc.narg(created_from)::timestamp)
+  AND (sqlc.narg(created_to)::timestamp IS NULL OR created_at < sqlc.narg(created_to)::timestamp)
+  AND (sqlc.narg(min_total)::bigint IS NULL OR total_amount >= sqlc.narg(min_total)::bigint)
+  AND (sqlc.narg(max_total)::bigint IS NULL OR total_amount <= sqlc.narg(max_total)::bigint)
+  AND (sqlc.narg(product_id)::int IS NULL OR EXISTS (
+      SELECT 1 FROM order_items
+      WHERE order_items.order_id = orders.id
+        AND order_items.product_id = sqlc.narg(product_id)::int
+  ))
+  AND (sqlc.narg(after_id)::int IS NULL
+      OR (total_amount, id) < (sqlc.narg(after_total)::bigint, sqlc.narg(after_id)::int))
+ORDER BY total_amount DESC, id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
 
 -- name: CountOrdersByUserID :one
-SELECT COUNT(*) FROM orders WHERE user_id = $1;
+SELECT COUNT(*) FROM orders
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(payment_status)::text IS NULL OR payment_status = sqlc.narg(payment_status)::text)
+  AND (sqlc.narg(created_from)::timestamp IS NULL OR created_at >= sqlc.narg(created_from)::timestamp)
+  AND (sqlc.narg(created_to)::timestamp IS NULL OR created_at < sqlc.narg(created_to)::timestamp)
+  AND (sqlc.narg(currency)::text IS NULL OR currency = sqlc.narg(currency)::text)
+  AND (sqlc.narg(min_total)::bigint IS NULL OR total_amount >= sqlc.narg(min_total)::bigint)
+  AND (sqlc.narg(max_total)::bigint IS NULL OR total_amount <= sqlc.narg(max_total)::bigint)
+  AND (sqlc.narg(product_id)::int IS NULL OR EXISTS (
+      SELECT 1 FROM order_items
+      WHERE order_items.order_id = orders.id
+        AND order_items.product_id = sqlc.narg(product_id)::int
+  ));
+
+-- name: CountUserOrdersSince :one
+SELECT COUNT(*) FROM orders
+WHERE user_id = $1
+  AND created_at >= $2
+  AND status <> 'CANCELLED';
+
+-- name: LockUserOrders :exec
+SELECT pg_advisory_xact_lock(hashtext('user_orders'), sqlc.arg(user_id)::int);
 
 -- name: UpdateOrderStatus :exec
 UPDATE orders
@@ -52,3 +180,54 @@ UPDATE orders
 SET payment_status = $2,
     updated_at = $3
 WHERE id = $1;
+
+-- name: CancelOrder :exec
+UPDATE orders
+SET status = $2,
+    payment_status = $3,
+    cancel_reason = $4,
+    cancelled_at = $5,
+    updated_at = $6
+WHERE id = $1;
+
+-- name: GetExpiredUnpaidOrderIDs :many
+SELECT id FROM orders
+WHERE status = 'PENDING'
+  AND payment_status = 'PENDING'
+  AND created_at < sqlc.arg(now)::timestamp - make_interval(secs => NULLIF(COALESCE(
+      (SELECT ttl.seconds FROM unnest(sqlc.arg(providers)::text[], sqlc.arg(provider_ttl_seconds)::bigint[]) AS ttl(provider, seconds)
+       WHERE ttl.provider = orders.payment_provider),
+      sqlc.narg(default_ttl_seconds)::bigint
+  ), 0))
+  AND id <> ALL(sqlc.arg(skip_ids)::int[])
+ORDER BY created_at, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: CreateOrderEvent :one
+INSERT INTO order_events (
+    order_id,
+    event_type,
+    from_status,
+    to_status,
+    actor_id,
+    actor_role,
+    metadata,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetOrderEvents :many
+SELECT * FROM order_events
+WHERE order_id = $1
+ORDER BY created_at, id;
+
+-- name: GetOrderByPaymentIntentIDForUpdate :one
+SELECT * FROM orders WHERE payment_intent_id = $1 FOR UPDATE;
+
+-- name: SetOrderPaymentIntent :exec
+UPDATE orders
+SET payment_provider = $2,
+    payment_intent_id = $3,
+    updated_at = $4
+WHERE id = $1;
@@ -0,0 +1,11 @@
+-- name: CreatePaymentWebhookEvent :execrows
+INSERT INTO payment_webhook_events (
+    provider,
+    event_id,
+    event_type,
+    order_id,
+    payload,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) ON CONFLICT (provider, event_id) DO NOTHING;
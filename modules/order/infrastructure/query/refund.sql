@@ -0,0 +1,69 @@
+-- name: CreateRefund :one
+INSERT INTO refunds (
+    order_id,
+    user_id,
+    status,
+    amount,
+    currency,
+    reason,
+    approved_at,
+    processed_at,
+    provider_refund_id,
+    created_at,
+    updated_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING *;
+
+-- name: CreateRefundItem :one
+INSERT INTO refund_items (
+    refund_id,
+    order_item_id,
+    quantity,
+    amount,
+    currency,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetRefundByID :one
+SELECT * FROM refunds WHERE id = $1;
+
+-- name: GetRefundByIDForUpdate :one
+SELECT * FROM refunds WHERE id = $1 FOR UPDATE;
+
+-- name: GetRefundsByOrderID :many
+SELECT * FROM refunds
+WHERE order_id = $1
+ORDER BY created_at DESC;
+
+-- name: GetRefundItems :many
+SELECT * FROM refund_items WHERE refund_id = $1;
+
+-- name: UpdateRefundStatus :exec
+UPDATE refunds
+SET status = $2,
+    approved_at = $3,
+    processed_at = $4,
+    rejected_at = $5,
+    provider_refund_id = $6,
+    updated_at = $7
+WHERE id = $1;
+
+-- name: SumOpenRefundAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total
+FROM refunds
+WHERE order_id = $1 AND status <> 'REJECTED';
+
+-- name: SumProcessedRefundAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total
+FROM refunds
+WHERE order_id = $1 AND status = 'PROCESSED';
+
+-- name: GetRefundedQuantities :many
+SELECT ri.order_item_id, SUM(ri.quantity)::int AS quantity
+FROM refund_items ri
+JOIN refunds r ON r.id = ri.refund_id
+WHERE r.order_id = $1 AND r.status <> 'REJECTED'
+GROUP BY ri.order_item_id;
@@ -0,0 +1,53 @@
+-- name: CreateShipment :one
+INSERT INTO shipments (
+    order_id,
+    status,
+    carrier,
+    tracking_number,
+    created_at,
+    updated_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: CreateShipmentItem :one
+INSERT INTO shipment_items (
+    shipment_id,
+    order_item_id,
+    quantity,
+    created_at
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetShipmentByID :one
+SELECT * FROM shipments WHERE id = $1;
+
+-- name: GetShipmentByIDForUpdate :one
+SELECT * FROM shipments WHERE id = $1 FOR UPDATE;
+
+-- name: GetShipmentsByOrderID :many
+SELECT * FROM shipments
+WHERE order_id = $1
+ORDER BY created_at, id;
+
+-- name: GetShipmentsByOrderIDs :many
+SELECT * FROM shipments
+WHERE order_id = ANY($1::int[])
+ORDER BY order_id, created_at, id;
+
+-- name: GetShipmentItemsByShipmentIDs :many
+SELECT * FROM shipment_items
+WHERE shipment_id = ANY($1::int[])
+ORDER BY shipment_id, id;
+
+-- name: UpdateShipment :exec
+UPDATE shipments
+SET status = $2,
+    carrier = $3,
+    tracking_number = $4,
+    shipped_at = $5,
+    delivered_at = $6,
+    cancelled_at = $7,
+    updated_at = $8
+WHERE id = $1;
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/modules/order/infrastructure/query/gen"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type invoiceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewInvoiceRepository(db *pgxpool.Pool) interfaces.InvoiceRepository {
+	return &invoiceRepository{db: db}
+}
+
+func (r *invoiceRepository) GetByOrderID(ctx context.Context, orderID int32) (*entities.Invoice, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbInvoice, err := queries.GetInvoiceByOrderID(ctx, orderID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+
+	return toInvoice(dbInvoice), nil
+}
+
+func (r *invoiceRepository) LockYear(ctx context.Context, year int32) (int32, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	// The upsert row-locks the counter, creating it on the first invoice of the year
+	return queries.LockInvoiceCounter(ctx, year)
+}
+
+func (r *invoiceRepository) Create(ctx context.Context, invoice *entities.Invoice) (*entities.Invoice, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	err := queries.AdvanceInvoiceCounter(ctx, gen.AdvanceInvoiceCounterParams{
+		Year:         invoice.Year,
+		LastSequence: invoice.Sequence,
+	})
+	if err != nil {
+		return nil, errorx.ErrCannotIssueInvoice
+	}
+
+	dbInvoice, err := queries.CreateInvoice(ctx, gen.CreateInvoiceParams{
+		OrderID:  invoice.OrderID,
+		Year:     invoice.Year,
+		Sequence: invoice.Sequence,
+		Number:   invoice.Number,
+		IssuedAt: invoice.IssuedAt,
+	})
+	if err != nil {
+		return nil, errorx.ErrCannotIssueInvoice
+	}
+
+	return toInvoice(dbInvoice), nil
+}
+
+func toInvoice(dbInvoice *gen.Invoice) *entities.Invoice {
+	return &entities.Invoice{
+		ID:       dbInvoice.ID,
+		OrderID:  dbInvoice.OrderID,
+		Year:     dbInvoice.Year,
+		Sequence: dbInvoice.Sequence,
+		Number:   dbInvoice.Number,
+		IssuedAt: dbInvoice.IssuedAt,
+	}
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvoiceRepository(t *testing.T) {
+	db := createTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	err := createTestUsers(ctx, db)
+	require.NoError(t, err, "failed to create test users")
+
+	orderRepo := NewOrderRepository(db)
+	repo := NewInvoiceRepository(db)
+	txManager := pgxc.NewTxManager(db)
+
+	createOrder := func(t *testing.T) *entities.Order {
+		order, err := orderRepo.Create(ctx, &entities.Order{
+			UserID:          1,
+			Status:          constants.OrderStatusConfirmed,
+			PaymentStatus:   constants.PaymentStatusPaid,
+			Currency:        money.USD,
+			TotalAmount:     usd("40.00"),
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "12345",
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		})
+		require.NoError(t, err)
+		return order
+	}
+
+	issue := func(t *testing.T, orderID int32, issuedAt time.Time) (*entities.Invoice, error) {
+		var invoice *entities.Invoice
+		err := txManager.WithTx(ctx, func(ctx context.Context) error {
+			last, err := repo.LockYear(ctx, int32(issuedAt.Year()))
+			if err != nil {
+				return err
+			}
+
+			invoice, err = repo.Create(ctx, entities.NewInvoice(orderID, "INV", last+1, issuedAt))
+			return err
+		})
+		return invoice, err
+	}
+
+	t.Run("Numbers Run Without Gaps Per Year", func(t *testing.T) {
+		issuedAt := time.Date(2030, 3, 1, 10, 0, 0, 0, time.UTC)
+
+		first, err := issue(t, createOrder(t).ID, issuedAt)
+		require.NoError(t, err)
+		require.Equal(t, "INV-2030-000001", first.Number)
+
+		// A failed issue gives its number back
+		_, err = issue(t, first.OrderID, issuedAt)
+		require.ErrorIs(t, err, errorx.ErrCannotIssueInvoice)
+
+		second, err := issue(t, createOrder(t).ID, issuedAt)
+		require.NoError(t, err)
+		require.Equal(t, "INV-2030-000002", second.Number)
+
+		nextYear, err := issue(t, createOrder(t).ID, issuedAt.AddDate(1, 0, 0))
+		require.NoError(t, err)
+		require.Equal(t, "INV-2031-000001", nextYear.Number)
+
+		found, err := repo.GetByOrderID(ctx, second.OrderID)
+		require.NoError(t, err)
+		require.Equal(t, second.Number, found.Number)
+	})
+
+	t.Run("Get Missing Invoice", func(t *testing.T) {
+		_, err := repo.GetByOrderID(ctx, createOrder(t).ID)
+		require.ErrorIs(t, err, errorx.ErrInvoiceNotFound)
+	})
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/modules/order/infrastructure/query/gen"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type orderEventRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOrderEventRepository(db *pgxpool.Pool) interfaces.OrderEventRepository {
+	return &orderEventRepository{db: db}
+}
+
+func (r *orderEventRepository) Append(ctx context.Context, event *entities.OrderEvent) error {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return errorx.ErrCannotRecordOrderEvent
+	}
+
+	dbEvent, err := queries.CreateOrderEvent(ctx, gen.CreateOrderEventParams{
+		OrderID:    event.OrderID,
+		EventType:  event.Type.String(),
+		FromStatus: event.FromStatus,
+		ToStatus:   event.ToStatus,
+		ActorID:    event.ActorID,
+		ActorRole:  event.ActorRole,
+		Metadata:   metadata,
+		CreatedAt:  event.CreatedAt,
+	})
+	if err != nil {
+		return errorx.ErrCannotRecordOrderEvent
+	}
+
+	event.ID = dbEvent.ID
+
+	return nil
+}
+
+func (r *orderEventRepository) GetByOrderID(ctx context.Context, orderID int32) ([]*entities.OrderEvent, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbEvents, err := queries.GetOrderEvents(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*entities.OrderEvent
+	for _, dbEvent := range dbEvents {
+		event, err := toOrderEvent(dbEvent)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func toOrderEvent(dbEvent *gen.OrderEvent) (*entities.OrderEvent, error) {
+	metadata := map[string]any{}
+	if err := json.Unmarshal(dbEvent.Metadata, &metadata); err != nil {
+		return nil, err
+	}
+
+	return &entities.OrderEvent{
+		ID:         dbEvent.ID,
+		OrderID:    dbEvent.OrderID,
+		Type:       constants.OrderEventType(dbEvent.EventType),
+		FromStatus: dbEvent.FromStatus,
+		ToStatus:   dbEvent.ToStatus,
+		ActorID:    dbEvent.ActorID,
+		ActorRole:  dbEvent.ActorRole,
+		Metadata:   metadata,
+		CreatedAt:  dbEvent.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/shared/common"
+	"mallbots/shared/money"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderEventRepository(t *testing.T) {
+	db := createTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	err := createTestUsers(ctx, db)
+	require.NoError(t, err, "failed to create test users")
+
+	orderRepo := NewOrderRepository(db)
+	repo := NewOrderEventRepository(db)
+
+	order, err := orderRepo.Create(ctx, &entities.Order{
+		UserID:          1,
+		Status:          constants.OrderStatusPending,
+		PaymentStatus:   constants.PaymentStatusPending,
+		Currency:        money.USD,
+		TotalAmount:     usd("50.00"),
+		ShippingAddress: "123 Test St",
+		ShippingCity:    "Test City",
+		ShippingCountry: "Test Country",
+		ShippingZip:     "12345",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	})
+	require.NoError(t, err)
+
+	t.Run("Append And List In Order", func(t *testing.T) {
+		customer := entities.Caller{UserID: 1, Role: common.RoleUser}
+
+		created := entities.NewOrderEvent(order.ID, constants.OrderEventCreated, customer).
+			WithTransition("", constants.OrderStatusPending.String()).
+			With("item_count", 2)
+		require.NoError(t, repo.Append(ctx, created))
+		require.NotZero(t, created.ID)
+
+		confirmed := entities.NewOrderEvent(order.ID, constants.OrderEventStatusChanged, entities.SystemCaller).
+			WithTransition(constants.OrderStatusPending.String(), constants.OrderStatusConfirmed.String())
+		require.NoError(t, repo.Append(ctx, confirmed))
+
+		events, err := repo.GetByOrderID(ctx, order.ID)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+
+		require.Equal(t, constants.OrderEventCreated, events[0].Type)
+		require.Nil(t, events[0].FromStatus)
+		require.Equal(t, int32(1), *events[0].ActorID)
+		require.Equal(t, float64(2), events[0].Metadata["item_count"])
+
+		require.Equal(t, constants.OrderEventStatusChanged, events[1].Type)
+		require.Nil(t, events[1].ActorID)
+		require.Equal(t, common.RoleSystem, events[1].ActorRole)
+	})
+
+	t.Run("Empty Timeline", func(t *testing.T) {
+		events, err := repo.GetByOrderID(ctx, 99999)
+		require.NoError(t, err)
+		require.Empty(t, events)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"mallbots/modules/order/domain/entities"
 	"mallbots/modules/order/domain/interfaces"
 	"mallbots/modules/order/infrastructure/query/gen"
+	"mallbots/plugins/pgxc"
 	"mallbots/shared/errorx"
 	"time"
 
@@ -23,25 +24,29 @@ func NewOrderRepository(db *pgxpool.Pool) interfaces.OrderRepository {
 }
 
 func (r *orderRepository) Create(ctx context.Context, order *entities.Order) (*entities.Order, error) {
-	queries := gen.New(r.db)
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
 
-	tx, err := r.db.Begin(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer tx.Rollback(ctx)
-
-	qtx := queries.WithTx(tx)
-
-	dbOrder, err := qtx.CreateOrder(ctx, gen.CreateOrderParams{
+	dbOrder, err := queries.CreateOrder(ctx, gen.CreateOrderParams{
 		UserID:          order.UserID,
 		Status:          order.Status.String(),
 		PaymentStatus:   order.PaymentStatus.String(),
-		TotalAmount:     order.TotalAmount,
+		TotalAmount:     order.TotalAmount.Minor(),
+		Currency:        order.Currency,
+		ShippingAmount:  order.ShippingAmount.Minor(),
+		TaxAmount:       order.TaxAmount.Minor(),
+		TaxInclusive:    order.TaxInclusive,
+		DiscountAmount:  order.DiscountAmount.Minor(),
+		CouponCode:      order.CouponCode,
 		ShippingAddress: order.ShippingAddress,
 		ShippingCity:    order.ShippingCity,
 		ShippingCountry: order.ShippingCountry,
 		ShippingZip:     order.ShippingZip,
+		BillingAddress:  order.BillingAddress,
+		BillingCity:     order.BillingCity,
+		BillingCountry:  order.BillingCountry,
+		BillingZip:      order.BillingZip,
+		ExchangeRateID:  order.ExchangeRateID,
+		ExchangeRate:    order.ExchangeRate,
 		CreatedAt:       order.CreatedAt,
 		UpdatedAt:       order.UpdatedAt,
 	})
@@ -49,55 +54,45 @@ func (r *orderRepository) Create(ctx context.Context, order *entities.Order) (*e
 		return nil, errorx.ErrCannotCreateOrder
 	}
 
-	if err = tx.Commit(ctx); err != nil {
-		return nil, err
-	}
-
-	return &entities.Order{
-		ID:              dbOrder.ID,
-		UserID:          dbOrder.UserID,
-		Status:          constants.OrderStatus(dbOrder.Status),
-		PaymentStatus:   constants.PaymentStatus(dbOrder.PaymentStatus),
-		TotalAmount:     dbOrder.TotalAmount,
-		ShippingAddress: dbOrder.ShippingAddress,
-		ShippingCity:    dbOrder.ShippingCity,
-		ShippingCountry: dbOrder.ShippingCountry,
-		ShippingZip:     dbOrder.ShippingZip,
-		CreatedAt:       dbOrder.CreatedAt,
-		UpdatedAt:       dbOrder.UpdatedAt,
-	}, nil
+	return toOrder(dbOrder), nil
 }
 
 func (r *orderRepository) CreateOrderItems(ctx context.Context, orderID int32, items []*entities.OrderItem) error {
-	queries := gen.New(r.db)
-
-	tx, err := r.db.Begin(ctx)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback(ctx)
-
-	qtx := queries.WithTx(tx)
-
-	for _, item := range items {
-		_, err := qtx.CreateOrderItem(ctx, gen.CreateOrderItemParams{
-			OrderID:   orderID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-			CreatedAt: item.CreatedAt,
-			UpdatedAt: item.UpdatedAt,
-		})
-		if err != nil {
-			return errorx.ErrCannotCreateOrderItems
+	return pgxc.WithTx(ctx, r.db, func(ctx context.Context) error {
+		queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+		for _, item := range items {
+			_, err := queries.CreateOrderItem(ctx, gen.CreateOrderItemParams{
+				OrderID:            orderID,
+				ProductID:          item.ProductID,
+				Quantity:           item.Quantity,
+				Price:              item.Price.Minor(),
+				Currency:           item.Price.Currency(),
+				TaxRate:            item.TaxRate,
+				TaxAmount:          item.TaxAmount.Minor(),
+				DiscountAmount:     item.DiscountAmount.Minor(),
+				ProductPrice:       item.ProductPrice.Minor(),
+				ProductCurrency:    item.ProductPrice.Currency(),
+				ExchangeRateID:     item.ExchangeRateID,
+				ExchangeRate:       item.ExchangeRate,
+				ProductName:        item.ProductName,
+				ProductDescription: item.ProductDescription,
+				CategoryID:         item.CategoryID,
+				CategoryName:       item.CategoryName,
+				CreatedAt:          item.CreatedAt,
+				UpdatedAt:          item.UpdatedAt,
+			})
+			if err != nil {
+				return errorx.ErrCannotCreateOrderItems
+			}
 		}
-	}
 
-	return tx.Commit(ctx)
+		return nil
+	})
 }
 
 func (r *orderRepository) GetByID(ctx context.Context, id int32) (*entities.Order, error) {
-	queries := gen.New(r.db)
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
 
 	dbOrder, err := queries.GetOrderByID(ctx, id)
 	if err != nil {
@@ -108,103 +103,236 @@ func (r *orderRepository) GetByID(ctx context.Context, id int32) (*entities.Orde
 	}
 
 	// Get order items
-	dbItems, err := queries.GetOrderItems(ctx, id)
+	items, err := r.GetItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	order := toOrder(dbOrder)
+	order.Items = items
+
+	order.Shipments, err = getShipmentsByOrderID(ctx, queries, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *orderRepository) GetByIDForUpdate(ctx context.Context, id int32) (*entities.Order, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbOrder, err := queries.GetOrderByIDForUpdate(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	return toOrder(dbOrder), nil
+}
+
+func (r *orderRepository) GetItems(ctx context.Context, orderID int32) ([]*entities.OrderItem, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbItems, err := queries.GetOrderItems(ctx, orderID)
 	if err != nil {
 		return nil, err
 	}
 
 	var items []*entities.OrderItem
 	for _, dbItem := range dbItems {
-		items = append(items, &entities.OrderItem{
-			ID:        dbItem.ID,
-			OrderID:   dbItem.OrderID,
-			ProductID: dbItem.ProductID,
-			Quantity:  dbItem.Quantity,
-			Price:     dbItem.Price,
-			CreatedAt: dbItem.CreatedAt,
-			UpdatedAt: dbItem.UpdatedAt,
-		})
+		items = append(items, toOrderItem(dbItem))
 	}
 
-	return &entities.Order{
-		ID:              dbOrder.ID,
-		UserID:          dbOrder.UserID,
-		Status:          constants.OrderStatus(dbOrder.Status),
-		PaymentStatus:   constants.PaymentStatus(dbOrder.PaymentStatus),
-		TotalAmount:     dbOrder.TotalAmount,
-		ShippingAddress: dbOrder.ShippingAddress,
-		ShippingCity:    dbOrder.ShippingCity,
-		ShippingCountry: dbOrder.ShippingCountry,
-		ShippingZip:     dbOrder.ShippingZip,
-		CreatedAt:       dbOrder.CreatedAt,
-		UpdatedAt:       dbOrder.UpdatedAt,
-		Items:           items,
-	}, nil
+	return items, nil
+}
+
+func (r *orderRepository) CountUserOrdersSince(ctx context.Context, userID int32, since time.Time) (int64, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	return queries.CountUserOrdersSince(ctx, gen.CountUserOrdersSinceParams{
+		UserID:    userID,
+		CreatedAt: since,
+	})
+}
+
+func (r *orderRepository) LockUserOrders(ctx context.Context, userID int32) error {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	return queries.LockUserOrders(ctx, userID)
 }
 
-func (r *orderRepository) GetByUserID(ctx context.Context, userID int32, paging *core.Paging) ([]*entities.Order, error) {
-	queries := gen.New(r.db)
+func (r *orderRepository) GetByUserID(ctx context.Context, userID int32, filter *interfaces.OrderFilter, paging *core.Paging) ([]*entities.Order, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	where := gen.CountOrdersByUserIDParams{
+		UserID:      userID,
+		CreatedFrom: filter.CreatedFrom,
+		CreatedTo:   filter.CreatedTo,
+		ProductID:   filter.ProductID,
+	}
+	if filter.Status != nil {
+		status := filter.Status.String()
+		where.Status = &status
+	}
+	if filter.PaymentStatus != nil {
+		status := filter.PaymentStatus.String()
+		where.PaymentStatus = &status
+	}
+	if filter.Currency != nil {
+		currency := filter.Currency.String()
+		where.Currency = &currency
+	}
+	if filter.MinTotal != nil {
+		minTotal := int64(filter.MinTotal.Minor())
+		where.MinTotal = &minTotal
+	}
+	if filter.MaxTotal != nil {
+		maxTotal := int64(filter.MaxTotal.Minor())
+		where.MaxTotal = &maxTotal
+	}
 
 	// Get total count for pagination
-	total, err := queries.CountOrdersByUserID(ctx, userID)
+	total, err := queries.CountOrdersByUserID(ctx, where)
 	if err != nil {
 		return nil, err
 	}
 	paging.Total = total
 
-	offset := (paging.Page - 1) * paging.Limit
+	page := orderPage{
+		// One row past the page tells whether another page follows
+		limit: int32(paging.Limit) + 1,
+	}
+	// A cursor replaces the page number, the keyset picks up where it left
+	if after := filter.After; after != nil {
+		afterTotal := int64(after.Total)
+		page.afterID = &after.ID
+		page.afterCreatedAt = &after.CreatedAt
+		page.afterTotal = &afterTotal
+	} else {
+		page.offset = int32((paging.Page - 1) * paging.Limit)
+	}
 
-	dbOrders, err := queries.GetOrdersByUserID(ctx, gen.GetOrdersByUserIDParams{
-		UserID: userID,
-		Limit:  int32(paging.Limit),
-		Offset: int32(offset),
-	})
+	dbOrders, err := listUserOrders(ctx, queries, filter.SortBy, where, page)
 	if err != nil {
 		return nil, err
 	}
 
+	paging.NextCursor = ""
+	if len(dbOrders) > paging.Limit {
+		dbOrders = dbOrders[:paging.Limit]
+		last := toOrder(dbOrders[len(dbOrders)-1])
+		paging.NextCursor = entities.CursorAfter(last, filter.SortBy).Encode()
+	}
+
 	var orders []*entities.Order
 	for _, dbOrder := range dbOrders {
-		// Get order items for each order
-		dbItems, err := queries.GetOrderItems(ctx, dbOrder.ID)
-		if err != nil {
+		orders = append(orders, toOrder(dbOrder))
+	}
+
+	if !filter.SkipItems {
+		if err := r.loadItems(ctx, queries, orders); err != nil {
 			return nil, err
 		}
+	}
 
-		var items []*entities.OrderItem
-		for _, dbItem := range dbItems {
-			items = append(items, &entities.OrderItem{
-				ID:        dbItem.ID,
-				OrderID:   dbItem.OrderID,
-				ProductID: dbItem.ProductID,
-				Quantity:  dbItem.Quantity,
-				Price:     dbItem.Price,
-				CreatedAt: dbItem.CreatedAt,
-				UpdatedAt: dbItem.UpdatedAt,
-			})
+	if err := loadShipments(ctx, queries, orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// orderPage is the slice of the listing to read, by keyset or by offset
+type orderPage struct {
+	afterID        *int32
+	afterCreatedAt *time.Time
+	afterTotal     *int64
+	limit          int32
+	offset         int32
+}
+
+// listUserOrders runs the query written for the sort, each one walks the
+// index matching its order instead of sorting every order of the user
+func listUserOrders(ctx context.Context, queries *gen.Queries, sortBy constants.OrderSort, where gen.CountOrdersByUserIDParams, page orderPage) ([]*gen.Order, error) {
+	if sortBy.ByTotal() {
+		// Totals only compare within one currency
+		if where.Currency == nil {
+			return nil, errorx.ErrInvalidOrderFilter
+		}
+
+		params := gen.GetOrdersByUserIDTotalAscParams{
+			UserID:        where.UserID,
+			Currency:      *where.Currency,
+			Status:        where.Status,
+			PaymentStatus: where.PaymentStatus,
+			CreatedFrom:   where.CreatedFrom,
+			CreatedTo:     where.CreatedTo,
+			MinTotal:      where.MinTotal,
+			MaxTotal:      where.MaxTotal,
+			ProductID:     where.ProductID,
+			AfterID:       page.afterID,
+			AfterTotal:    page.afterTotal,
+			RowLimit:      page.limit,
+			RowOffset:     page.offset,
+		}
+		if sortBy == constants.OrderSortTotalDesc {
+			return queries.GetOrdersByUserIDTotalDesc(ctx, gen.GetOrdersByUserIDTotalDescParams(params))
 		}
+		return queries.GetOrdersByUserIDTotalAsc(ctx, params)
+	}
+
+	params := gen.GetOrdersByUserIDCreatedDescParams{
+		UserID:         where.UserID,
+		Status:         where.Status,
+		PaymentStatus:  where.PaymentStatus,
+		CreatedFrom:    where.CreatedFrom,
+		CreatedTo:      where.CreatedTo,
+		Currency:       where.Currency,
+		MinTotal:       where.MinTotal,
+		MaxTotal:       where.MaxTotal,
+		ProductID:      where.ProductID,
+		AfterID:        page.afterID,
+		AfterCreatedAt: page.afterCreatedAt,
+		RowLimit:       page.limit,
+		RowOffset:      page.offset,
+	}
+	if sortBy == constants.OrderSortCreatedAtAsc {
+		return queries.GetOrdersByUserIDCreatedAsc(ctx, gen.GetOrdersByUserIDCreatedAscParams(params))
+	}
+	return queries.GetOrdersByUserIDCreatedDesc(ctx, params)
+}
 
-		orders = append(orders, &entities.Order{
-			ID:              dbOrder.ID,
-			UserID:          dbOrder.UserID,
-			Status:          constants.OrderStatus(dbOrder.Status),
-			PaymentStatus:   constants.PaymentStatus(dbOrder.PaymentStatus),
-			TotalAmount:     dbOrder.TotalAmount,
-			ShippingAddress: dbOrder.ShippingAddress,
-			ShippingCity:    dbOrder.ShippingCity,
-			ShippingCountry: dbOrder.ShippingCountry,
-			ShippingZip:     dbOrder.ShippingZip,
-			CreatedAt:       dbOrder.CreatedAt,
-			UpdatedAt:       dbOrder.UpdatedAt,
-			Items:           items,
-		})
+// loadItems fills in the items of all the orders with a single query
+func (r *orderRepository) loadItems(ctx context.Context, queries *gen.Queries, orders []*entities.Order) error {
+	if len(orders) == 0 {
+		return nil
 	}
 
-	return orders, nil
+	byID := make(map[int32]*entities.Order, len(orders))
+	orderIDs := make([]int32, 0, len(orders))
+	for _, order := range orders {
+		byID[order.ID] = order
+		orderIDs = append(orderIDs, order.ID)
+	}
+
+	dbItems, err := queries.GetOrderItemsByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, dbItem := range dbItems {
+		order := byID[dbItem.OrderID]
+		order.Items = append(order.Items, toOrderItem(dbItem))
+	}
+
+	return nil
 }
 
 func (r *orderRepository) UpdateStatus(ctx context.Context, id int32, status constants.OrderStatus) error {
-	queries := gen.New(r.db)
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
 
 	err := queries.UpdateOrderStatus(ctx, gen.UpdateOrderStatusParams{
 		ID:        id,
@@ -219,7 +347,7 @@ func (r *orderRepository) UpdateStatus(ctx context.Context, id int32, status con
 }
 
 func (r *orderRepository) UpdatePaymentStatus(ctx context.Context, id int32, status constants.PaymentStatus) error {
-	queries := gen.New(r.db)
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
 
 	err := queries.UpdatePaymentStatus(ctx, gen.UpdatePaymentStatusParams{
 		ID:            id,
@@ -232,3 +360,128 @@ func (r *orderRepository) UpdatePaymentStatus(ctx context.Context, id int32, sta
 
 	return nil
 }
+
+func (r *orderRepository) Cancel(ctx context.Context, order *entities.Order) error {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	err := queries.CancelOrder(ctx, gen.CancelOrderParams{
+		ID:            order.ID,
+		Status:        order.Status.String(),
+		PaymentStatus: order.PaymentStatus.String(),
+		CancelReason:  order.CancelReason,
+		CancelledAt:   order.CancelledAt,
+		UpdatedAt:     order.UpdatedAt,
+	})
+	if err != nil {
+		return errorx.ErrCannotUpdateOrder
+	}
+
+	return nil
+}
+
+func (r *orderRepository) GetExpiredUnpaidIDs(ctx context.Context, now time.Time, ttl time.Duration, providerTTLs map[string]time.Duration, skipIDs []int32, limit int32) ([]int32, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	params := gen.GetExpiredUnpaidOrderIDsParams{
+		Now:                now,
+		Providers:          make([]string, 0, len(providerTTLs)),
+		ProviderTtlSeconds: make([]int64, 0, len(providerTTLs)),
+		// A nil slice is sent as NULL, which would leave every order out
+		SkipIds:  append([]int32{}, skipIDs...),
+		RowLimit: limit,
+	}
+	for provider, providerTTL := range providerTTLs {
+		params.Providers = append(params.Providers, provider)
+		params.ProviderTtlSeconds = append(params.ProviderTtlSeconds, int64(providerTTL.Seconds()))
+	}
+	if ttl > 0 {
+		seconds := int64(ttl.Seconds())
+		params.DefaultTtlSeconds = &seconds
+	}
+
+	return queries.GetExpiredUnpaidOrderIDs(ctx, params)
+}
+
+func (r *orderRepository) GetByPaymentIntentIDForUpdate(ctx context.Context, intentID string) (*entities.Order, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbOrder, err := queries.GetOrderByPaymentIntentIDForUpdate(ctx, &intentID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	return toOrder(dbOrder), nil
+}
+
+func (r *orderRepository) SetPaymentIntent(ctx context.Context, id int32, provider, intentID string) error {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	err := queries.SetOrderPaymentIntent(ctx, gen.SetOrderPaymentIntentParams{
+		ID:              id,
+		PaymentProvider: &provider,
+		PaymentIntentID: &intentID,
+		UpdatedAt:       time.Now(),
+	})
+	if err != nil {
+		return errorx.ErrCannotUpdateOrder
+	}
+
+	return nil
+}
+
+func toOrder(dbOrder *gen.Order) *entities.Order {
+	return &entities.Order{
+		ID:              dbOrder.ID,
+		UserID:          dbOrder.UserID,
+		Status:          constants.OrderStatus(dbOrder.Status),
+		PaymentStatus:   constants.PaymentStatus(dbOrder.PaymentStatus),
+		Currency:        dbOrder.Currency,
+		TotalAmount:     dbOrder.TotalAmount.In(dbOrder.Currency),
+		ShippingAmount:  dbOrder.ShippingAmount.In(dbOrder.Currency),
+		TaxAmount:       dbOrder.TaxAmount.In(dbOrder.Currency),
+		TaxInclusive:    dbOrder.TaxInclusive,
+		DiscountAmount:  dbOrder.DiscountAmount.In(dbOrder.Currency),
+		CouponCode:      dbOrder.CouponCode,
+		ShippingAddress: dbOrder.ShippingAddress,
+		ShippingCity:    dbOrder.ShippingCity,
+		ShippingCountry: dbOrder.ShippingCountry,
+		ShippingZip:     dbOrder.ShippingZip,
+		BillingAddress:  dbOrder.BillingAddress,
+		BillingCity:     dbOrder.BillingCity,
+		BillingCountry:  dbOrder.BillingCountry,
+		BillingZip:      dbOrder.BillingZip,
+		ExchangeRateID:  dbOrder.ExchangeRateID,
+		ExchangeRate:    dbOrder.ExchangeRate,
+		CancelReason:    dbOrder.CancelReason,
+		CancelledAt:     dbOrder.CancelledAt,
+		PaymentProvider: dbOrder.PaymentProvider,
+		PaymentIntentID: dbOrder.PaymentIntentID,
+		CreatedAt:       dbOrder.CreatedAt,
+		UpdatedAt:       dbOrder.UpdatedAt,
+	}
+}
+
+func toOrderItem(dbItem *gen.OrderItem) *entities.OrderItem {
+	return &entities.OrderItem{
+		ID:                 dbItem.ID,
+		OrderID:            dbItem.OrderID,
+		ProductID:          dbItem.ProductID,
+		Quantity:           dbItem.Quantity,
+		Price:              dbItem.Price.In(dbItem.Currency),
+		TaxRate:            dbItem.TaxRate,
+		TaxAmount:          dbItem.TaxAmount.In(dbItem.Currency),
+		DiscountAmount:     dbItem.DiscountAmount.In(dbItem.Currency),
+		ProductPrice:       dbItem.ProductPrice.In(dbItem.ProductCurrency),
+		ExchangeRateID:     dbItem.ExchangeRateID,
+		ExchangeRate:       dbItem.ExchangeRate,
+		ProductName:        dbItem.ProductName,
+		ProductDescription: dbItem.ProductDescription,
+		CategoryID:         dbItem.CategoryID,
+		CategoryName:       dbItem.CategoryName,
+		CreatedAt:          dbItem.CreatedAt,
+		UpdatedAt:          dbItem.UpdatedAt,
+	}
+}
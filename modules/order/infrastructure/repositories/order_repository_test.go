@@ -2,13 +2,20 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"mallbots/modules/order/domain/constants"
 	"mallbots/modules/order/domain/entities"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/phathdt/service-context/core"
 	"github.com/stretchr/testify/require"
@@ -17,7 +24,12 @@ import (
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
-func createContainer(t *testing.T) (*postgres.PostgresContainer, error) {
+// usd is a test amount in US dollars
+func usd(amount string) money.Money {
+	return money.MustParse(amount, money.USD)
+}
+
+func createContainer(t testing.TB) (*postgres.PostgresContainer, error) {
 	ctx := context.Background()
 	dbUsername := "postgres"
 	dbPassword := "123123123"
@@ -50,7 +62,7 @@ func createContainer(t *testing.T) (*postgres.PostgresContainer, error) {
 	return postgresContainer, nil
 }
 
-func createTestDB(t *testing.T) *pgxpool.Pool {
+func createTestDB(t testing.TB) *pgxpool.Pool {
 	ctx := context.Background()
 	container, err := createContainer(t)
 	require.NoError(t, err, "failed to create container")
@@ -111,7 +123,9 @@ func TestOrderRepository(t *testing.T) {
 			UserID:          1,
 			Status:          constants.OrderStatusPending,
 			PaymentStatus:   constants.PaymentStatusPending,
-			TotalAmount:     100.00,
+			Currency:        money.USD,
+			TotalAmount:     usd("100.00"),
+			ShippingAmount:  usd("9.99"),
 			ShippingAddress: "123 Test St",
 			ShippingCity:    "Test City",
 			ShippingCountry: "Test Country",
@@ -126,24 +140,31 @@ func TestOrderRepository(t *testing.T) {
 		require.NotZero(t, createdOrder.ID)
 		require.Equal(t, order.UserID, createdOrder.UserID)
 		require.Equal(t, order.TotalAmount, createdOrder.TotalAmount)
+		require.Equal(t, order.ShippingAmount, createdOrder.ShippingAmount)
 		require.Equal(t, order.Status, createdOrder.Status)
 		require.Equal(t, order.PaymentStatus, createdOrder.PaymentStatus)
 
 		// Create order items
+		categoryID, categoryName := int32(1), "Smartphones"
 		items := []*entities.OrderItem{
 			{
-				OrderID:   createdOrder.ID,
-				ProductID: 1,
-				Quantity:  2,
-				Price:     25.00,
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
+				OrderID:      createdOrder.ID,
+				ProductID:    1,
+				Quantity:     2,
+				Price:        usd("25.00"),
+				TaxRate:      10,
+				TaxAmount:    usd("5.00"),
+				ProductName:  "iPhone 15 Pro",
+				CategoryID:   &categoryID,
+				CategoryName: &categoryName,
+				CreatedAt:    time.Now(),
+				UpdatedAt:    time.Now(),
 			},
 			{
 				OrderID:   createdOrder.ID,
 				ProductID: 2,
 				Quantity:  1,
-				Price:     50.00,
+				Price:     usd("50.00"),
 				CreatedAt: time.Now(),
 				UpdatedAt: time.Now(),
 			},
@@ -160,6 +181,11 @@ func TestOrderRepository(t *testing.T) {
 		require.Equal(t, items[0].ProductID, fetchedOrder.Items[0].ProductID)
 		require.Equal(t, items[0].Quantity, fetchedOrder.Items[0].Quantity)
 		require.Equal(t, items[0].Price, fetchedOrder.Items[0].Price)
+		require.Equal(t, items[0].TaxRate, fetchedOrder.Items[0].TaxRate)
+		require.Equal(t, items[0].TaxAmount, fetchedOrder.Items[0].TaxAmount)
+		require.Equal(t, "iPhone 15 Pro", fetchedOrder.Items[0].ProductName)
+		require.Equal(t, &categoryName, fetchedOrder.Items[0].CategoryName)
+		require.Nil(t, fetchedOrder.Items[1].CategoryID)
 	})
 
 	t.Run("Get User Orders with Pagination", func(t *testing.T) {
@@ -171,7 +197,8 @@ func TestOrderRepository(t *testing.T) {
 				UserID:          userID,
 				Status:          constants.OrderStatusPending,
 				PaymentStatus:   constants.PaymentStatusPending,
-				TotalAmount:     100.00,
+				Currency:        money.USD,
+				TotalAmount:     usd("100.00"),
 				ShippingAddress: "123 Test St",
 				ShippingCity:    "Test City",
 				ShippingCountry: "Test Country",
@@ -183,7 +210,8 @@ func TestOrderRepository(t *testing.T) {
 				UserID:          userID,
 				Status:          constants.OrderStatusConfirmed,
 				PaymentStatus:   constants.PaymentStatusPaid,
-				TotalAmount:     200.00,
+				Currency:        money.USD,
+				TotalAmount:     usd("200.00"),
 				ShippingAddress: "456 Test St",
 				ShippingCity:    "Test City",
 				ShippingCountry: "Test Country",
@@ -203,7 +231,7 @@ func TestOrderRepository(t *testing.T) {
 					OrderID:   createdOrder.ID,
 					ProductID: 1,
 					Quantity:  1,
-					Price:     50.00,
+					Price:     usd("50.00"),
 					CreatedAt: time.Now(),
 					UpdatedAt: time.Now(),
 				},
@@ -218,17 +246,112 @@ func TestOrderRepository(t *testing.T) {
 			Limit: 1,
 		}
 
+		newest := &interfaces.OrderFilter{SortBy: constants.OrderSortCreatedAtDesc, SkipItems: true}
+
 		// Get first page
-		userOrders, err := repo.GetByUserID(ctx, userID, paging)
+		userOrders, err := repo.GetByUserID(ctx, userID, newest, paging)
 		require.NoError(t, err)
 		require.Len(t, userOrders, 1)
 		require.Equal(t, int64(2), paging.Total)
+		require.NotEmpty(t, paging.NextCursor)
+		require.Empty(t, userOrders[0].Items)
 
 		// Get second page
 		paging.Page = 2
-		userOrders, err = repo.GetByUserID(ctx, userID, paging)
+		userOrders, err = repo.GetByUserID(ctx, userID, newest, paging)
 		require.NoError(t, err)
 		require.Len(t, userOrders, 1)
+		require.Empty(t, paging.NextCursor)
+
+		// Items come along with the orders unless skipped
+		paging = &core.Paging{Page: 1, Limit: 10}
+		userOrders, err = repo.GetByUserID(ctx, userID, &interfaces.OrderFilter{
+			SortBy: constants.OrderSortCreatedAtDesc,
+		}, paging)
+		require.NoError(t, err)
+		require.Len(t, userOrders, 2)
+		for _, order := range userOrders {
+			require.Len(t, order.Items, 1)
+			require.Equal(t, order.ID, order.Items[0].OrderID)
+		}
+	})
+
+	t.Run("Get User Orders with Filters and Cursor", func(t *testing.T) {
+		userID := int32(2)
+
+		// Filter by status
+		paid := constants.PaymentStatusPaid
+		paging := &core.Paging{Page: 1, Limit: 10}
+		userOrders, err := repo.GetByUserID(ctx, userID, &interfaces.OrderFilter{
+			PaymentStatus: &paid,
+			SortBy:        constants.OrderSortCreatedAtDesc,
+		}, paging)
+		require.NoError(t, err)
+		require.Len(t, userOrders, 1)
+		require.Equal(t, int64(1), paging.Total)
+		require.Equal(t, constants.PaymentStatusPaid, userOrders[0].PaymentStatus)
+
+		// Filter by total, only orders in the currency of the bound match
+		minTotal := usd("150")
+		userOrders, err = repo.GetByUserID(ctx, userID, &interfaces.OrderFilter{
+			MinTotal: &minTotal,
+			SortBy:   constants.OrderSortCreatedAtDesc,
+		}, paging)
+		require.NoError(t, err)
+		require.Len(t, userOrders, 1)
+		require.Equal(t, usd("200.00"), userOrders[0].TotalAmount)
+
+		minInEuros := money.MustParse("150", money.EUR)
+		userOrders, err = repo.GetByUserID(ctx, userID, &interfaces.OrderFilter{
+			MinTotal: &minInEuros,
+			SortBy:   constants.OrderSortCreatedAtDesc,
+		}, paging)
+		require.NoError(t, err)
+		require.Empty(t, userOrders)
+
+		// Filter by product contained
+		productID := int32(1)
+		missingID := int32(999)
+		userOrders, err = repo.GetByUserID(ctx, userID, &interfaces.OrderFilter{
+			ProductID: &productID,
+			SortBy:    constants.OrderSortCreatedAtDesc,
+		}, paging)
+		require.NoError(t, err)
+		require.Len(t, userOrders, 2)
+		userOrders, err = repo.GetByUserID(ctx, userID, &interfaces.OrderFilter{
+			ProductID: &missingID,
+			SortBy:    constants.OrderSortCreatedAtDesc,
+		}, paging)
+		require.NoError(t, err)
+		require.Empty(t, userOrders)
+
+		// Walk the orders by total with the cursor
+		dollars := money.USD
+		byTotal := &interfaces.OrderFilter{Currency: &dollars, SortBy: constants.OrderSortTotalDesc}
+		paging = &core.Paging{Page: 1, Limit: 1}
+		firstPage, err := repo.GetByUserID(ctx, userID, byTotal, paging)
+		require.NoError(t, err)
+		require.Len(t, firstPage, 1)
+		require.Equal(t, usd("200.00"), firstPage[0].TotalAmount)
+		require.NotEmpty(t, paging.NextCursor)
+
+		byTotal.After, err = entities.ParseOrderCursor(paging.NextCursor)
+		require.NoError(t, err)
+		secondPage, err := repo.GetByUserID(ctx, userID, byTotal, paging)
+		require.NoError(t, err)
+		require.Len(t, secondPage, 1)
+		require.Equal(t, usd("100.00"), secondPage[0].TotalAmount)
+		require.Empty(t, paging.NextCursor)
+
+		// Totals in another currency are not sorted along
+		euros := money.EUR
+		paging = &core.Paging{Page: 1, Limit: 10}
+		userOrders, err = repo.GetByUserID(ctx, userID, &interfaces.OrderFilter{Currency: &euros, SortBy: constants.OrderSortTotalAsc}, paging)
+		require.NoError(t, err)
+		require.Empty(t, userOrders)
+
+		_, err = repo.GetByUserID(ctx, userID, &interfaces.OrderFilter{SortBy: constants.OrderSortTotalAsc}, paging)
+		require.ErrorIs(t, err, errorx.ErrInvalidOrderFilter)
 	})
 
 	t.Run("Update Order Status", func(t *testing.T) {
@@ -237,7 +360,8 @@ func TestOrderRepository(t *testing.T) {
 			UserID:          3,
 			Status:          constants.OrderStatusPending,
 			PaymentStatus:   constants.PaymentStatusPending,
-			TotalAmount:     100.00,
+			Currency:        money.USD,
+			TotalAmount:     usd("100.00"),
 			ShippingAddress: "123 Test St",
 			ShippingCity:    "Test City",
 			ShippingCountry: "Test Country",
@@ -265,7 +389,8 @@ func TestOrderRepository(t *testing.T) {
 			UserID:          4,
 			Status:          constants.OrderStatusConfirmed,
 			PaymentStatus:   constants.PaymentStatusPending,
-			TotalAmount:     100.00,
+			Currency:        money.USD,
+			TotalAmount:     usd("100.00"),
 			ShippingAddress: "123 Test St",
 			ShippingCity:    "Test City",
 			ShippingCountry: "Test Country",
@@ -287,8 +412,299 @@ func TestOrderRepository(t *testing.T) {
 		require.Equal(t, constants.PaymentStatusPaid, updatedOrder.PaymentStatus)
 	})
 
+	t.Run("Cancel Order", func(t *testing.T) {
+		order := &entities.Order{
+			UserID:          3,
+			Status:          constants.OrderStatusPending,
+			PaymentStatus:   constants.PaymentStatusPending,
+			Currency:        money.USD,
+			TotalAmount:     usd("100.00"),
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "12345",
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+
+		createdOrder, err := repo.Create(ctx, order)
+		require.NoError(t, err)
+
+		createdOrder.Cancel("changed my mind", time.Now())
+		err = repo.Cancel(ctx, createdOrder)
+		require.NoError(t, err)
+
+		cancelledOrder, err := repo.GetByID(ctx, createdOrder.ID)
+		require.NoError(t, err)
+		require.Equal(t, constants.OrderStatusCancelled, cancelledOrder.Status)
+		require.Equal(t, constants.PaymentStatusVoided, cancelledOrder.PaymentStatus)
+		require.NotNil(t, cancelledOrder.CancelReason)
+		require.Equal(t, "changed my mind", *cancelledOrder.CancelReason)
+		require.NotNil(t, cancelledOrder.CancelledAt)
+	})
+
+	t.Run("Rollback Shared Transaction", func(t *testing.T) {
+		order := &entities.Order{
+			UserID:          4,
+			Status:          constants.OrderStatusPending,
+			PaymentStatus:   constants.PaymentStatusPending,
+			Currency:        money.USD,
+			TotalAmount:     usd("100.00"),
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "12345",
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+
+		var orderID int32
+		txManager := pgxc.NewTxManager(db)
+		err := txManager.WithTx(ctx, func(ctx context.Context) error {
+			createdOrder, err := repo.Create(ctx, order)
+			if err != nil {
+				return err
+			}
+			orderID = createdOrder.ID
+
+			return errors.New("abort checkout")
+		})
+		require.Error(t, err)
+		require.NotZero(t, orderID)
+
+		// Order must not survive the rolled back transaction
+		_, err = repo.GetByID(ctx, orderID)
+		require.ErrorIs(t, err, errorx.ErrOrderNotFound)
+	})
+
+	t.Run("Count User Orders Since", func(t *testing.T) {
+		userID := int32(4)
+		since := time.Now().Add(-time.Minute)
+
+		// Other tests placed orders for the same user
+		before, err := repo.CountUserOrdersSince(ctx, userID, since)
+		require.NoError(t, err)
+
+		var orders []*entities.Order
+		for i := 0; i < 2; i++ {
+			order, err := repo.Create(ctx, &entities.Order{
+				UserID:          userID,
+				Status:          constants.OrderStatusPending,
+				PaymentStatus:   constants.PaymentStatusPending,
+				Currency:        money.USD,
+				TotalAmount:     usd("10.00"),
+				ShippingAddress: "123 Test St",
+				ShippingCity:    "Test City",
+				ShippingCountry: "Test Country",
+				ShippingZip:     "12345",
+				CreatedAt:       time.Now(),
+				UpdatedAt:       time.Now(),
+			})
+			require.NoError(t, err)
+			orders = append(orders, order)
+		}
+
+		count, err := repo.CountUserOrdersSince(ctx, userID, since)
+		require.NoError(t, err)
+		require.Equal(t, before+2, count)
+
+		// Cancelled orders do not count
+		err = repo.UpdateStatus(ctx, orders[0].ID, constants.OrderStatusCancelled)
+		require.NoError(t, err)
+
+		count, err = repo.CountUserOrdersSince(ctx, userID, since)
+		require.NoError(t, err)
+		require.Equal(t, before+1, count)
+
+		count, err = repo.CountUserOrdersSince(ctx, userID, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+
+	t.Run("Lock User Orders", func(t *testing.T) {
+		txManager := pgxc.NewTxManager(db)
+		locked := make(chan struct{})
+		release := make(chan struct{})
+		done := make(chan error, 1)
+
+		go func() {
+			done <- txManager.WithTx(ctx, func(ctx context.Context) error {
+				if err := repo.LockUserOrders(ctx, 4); err != nil {
+					return err
+				}
+				close(locked)
+				<-release
+				return nil
+			})
+		}()
+		<-locked
+
+		// A checkout of the same user waits for the lock, other users do not
+		waitCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer cancel()
+		err := txManager.WithTx(waitCtx, func(ctx context.Context) error {
+			return repo.LockUserOrders(ctx, 4)
+		})
+		require.Error(t, err)
+
+		err = txManager.WithTx(ctx, func(ctx context.Context) error {
+			return repo.LockUserOrders(ctx, 5)
+		})
+		require.NoError(t, err)
+
+		close(release)
+		require.NoError(t, <-done)
+
+		// The lock ends with the transaction
+		err = txManager.WithTx(ctx, func(ctx context.Context) error {
+			return repo.LockUserOrders(ctx, 4)
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Get Expired Unpaid Orders", func(t *testing.T) {
+		// Placed long ago so orders created by other tests are never expired
+		day := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		now := day.Add(25 * time.Hour)
+
+		create := func(createdAt time.Time, paymentStatus constants.PaymentStatus, provider string) int32 {
+			order, err := repo.Create(ctx, &entities.Order{
+				UserID:          1,
+				Status:          constants.OrderStatusPending,
+				PaymentStatus:   paymentStatus,
+				Currency:        money.USD,
+				TotalAmount:     usd("10.00"),
+				ShippingAddress: "123 Test St",
+				ShippingCity:    "Test City",
+				ShippingCountry: "Test Country",
+				ShippingZip:     "12345",
+				CreatedAt:       createdAt,
+				UpdatedAt:       createdAt,
+			})
+			require.NoError(t, err)
+			if provider != "" {
+				err = repo.SetPaymentIntent(ctx, order.ID, provider, fmt.Sprintf("pi_expiry_%d", order.ID))
+				require.NoError(t, err)
+			}
+			return order.ID
+		}
+
+		pastDefaultTTL := create(day, constants.PaymentStatusPending, "")
+		pastProviderTTL := create(day.Add(24*time.Hour), constants.PaymentStatusPending, "fake")
+		withinProviderTTL := create(day.Add(24*time.Hour+50*time.Minute), constants.PaymentStatusPending, "fake")
+		paid := create(day, constants.PaymentStatusPaid, "")
+		neverCancelled := create(day, constants.PaymentStatusPending, "invoice")
+
+		ids, err := repo.GetExpiredUnpaidIDs(ctx, now, 24*time.Hour, map[string]time.Duration{"fake": 30 * time.Minute, "invoice": 0}, nil, 10)
+		require.NoError(t, err)
+		require.Equal(t, []int32{pastDefaultTTL, pastProviderTTL}, ids)
+		require.NotContains(t, ids, withinProviderTTL)
+		require.NotContains(t, ids, paid)
+		require.NotContains(t, ids, neverCancelled)
+
+		// Without a default TTL only orders paying through a provider expire
+		ids, err = repo.GetExpiredUnpaidIDs(ctx, now, 0, map[string]time.Duration{"fake": 30 * time.Minute}, nil, 10)
+		require.NoError(t, err)
+		require.Equal(t, []int32{pastProviderTTL}, ids)
+
+		ids, err = repo.GetExpiredUnpaidIDs(ctx, now, 24*time.Hour, map[string]time.Duration{"fake": 30 * time.Minute}, []int32{pastDefaultTTL}, 10)
+		require.NoError(t, err)
+		require.Equal(t, []int32{pastProviderTTL}, ids)
+	})
+
 	t.Run("Get Non-existent Order", func(t *testing.T) {
 		_, err := repo.GetByID(ctx, 99999)
 		require.Error(t, err)
 	})
 }
+
+// queryCounter counts the queries run on the connections it traces
+type queryCounter struct {
+	queries atomic.Int64
+}
+
+func (c *queryCounter) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	c.queries.Add(1)
+	return ctx
+}
+
+func (c *queryCounter) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
+
+func BenchmarkGetUserOrdersWithItems(b *testing.B) {
+	ctx := context.Background()
+	container, err := createContainer(b)
+	require.NoError(b, err, "failed to create container")
+
+	connStr, err := container.ConnectionString(ctx)
+	require.NoError(b, err, "failed to get connection string")
+
+	poolConfig, err := pgxpool.ParseConfig(connStr)
+	require.NoError(b, err, "failed to parse connection string")
+
+	counter := &queryCounter{}
+	poolConfig.ConnConfig.Tracer = counter
+
+	db, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	require.NoError(b, err, "failed to create connection pool")
+	defer db.Close()
+
+	require.NoError(b, createTestUsers(ctx, db), "failed to create test users")
+
+	repo := NewOrderRepository(db)
+	userID := int32(1)
+
+	// Enough orders, two items each, to fill the largest page
+	for i := 0; i < 200; i++ {
+		order, err := repo.Create(ctx, &entities.Order{
+			UserID:          userID,
+			Status:          constants.OrderStatusPending,
+			PaymentStatus:   constants.PaymentStatusPending,
+			Currency:        money.USD,
+			TotalAmount:     usd("100.00"),
+			ShippingAddress: "123 Test St",
+			ShippingCity:    "Test City",
+			ShippingCountry: "Test Country",
+			ShippingZip:     "12345",
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		})
+		require.NoError(b, err)
+
+		err = repo.CreateOrderItems(ctx, order.ID, []*entities.OrderItem{
+			{OrderID: order.ID, ProductID: 1, Quantity: 1, Price: usd("40.00"), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			{OrderID: order.ID, ProductID: 2, Quantity: 1, Price: usd("60.00"), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		})
+		require.NoError(b, err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		filter *interfaces.OrderFilter
+		// The count and the page, plus one query for the items of the
+		// whole page unless they are skipped
+		queries int64
+	}{
+		{name: "with_items", filter: &interfaces.OrderFilter{SortBy: constants.OrderSortCreatedAtDesc}, queries: 3},
+		{name: "without_items", filter: &interfaces.OrderFilter{SortBy: constants.OrderSortCreatedAtDesc, SkipItems: true}, queries: 2},
+	} {
+		for _, pageSize := range []int{10, 50, 200} {
+			b.Run(fmt.Sprintf("%s/page_size_%d", tc.name, pageSize), func(b *testing.B) {
+				counter.queries.Store(0)
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					orders, err := repo.GetByUserID(ctx, userID, tc.filter, &core.Paging{Page: 1, Limit: pageSize})
+					require.NoError(b, err)
+					require.Len(b, orders, pageSize)
+				}
+
+				b.StopTimer()
+				perPage := counter.queries.Load() / int64(b.N)
+				b.ReportMetric(float64(perPage), "queries/op")
+
+				// Whatever the page size
+				require.Equal(b, tc.queries, perPage)
+			})
+		}
+	}
+}
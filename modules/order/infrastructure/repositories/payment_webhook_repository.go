@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/modules/order/infrastructure/query/gen"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type paymentWebhookRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPaymentWebhookRepository(db *pgxpool.Pool) interfaces.PaymentWebhookRepository {
+	return &paymentWebhookRepository{db: db}
+}
+
+func (r *paymentWebhookRepository) Record(ctx context.Context, event *entities.PaymentWebhookEvent) (bool, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	inserted, err := queries.CreatePaymentWebhookEvent(ctx, gen.CreatePaymentWebhookEventParams{
+		Provider:  event.Provider,
+		EventID:   event.EventID,
+		EventType: event.EventType,
+		OrderID:   event.OrderID,
+		Payload:   event.Payload,
+		CreatedAt: event.CreatedAt,
+	})
+	if err != nil {
+		return false, errorx.ErrCannotRecordWebhook
+	}
+
+	return inserted == 1, nil
+}
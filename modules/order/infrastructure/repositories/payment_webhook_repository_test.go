@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentWebhookRepository(t *testing.T) {
+	db := createTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	err := createTestUsers(ctx, db)
+	require.NoError(t, err, "failed to create test users")
+
+	orderRepo := NewOrderRepository(db)
+	repo := NewPaymentWebhookRepository(db)
+
+	order, err := orderRepo.Create(ctx, &entities.Order{
+		UserID:          1,
+		Status:          constants.OrderStatusPending,
+		PaymentStatus:   constants.PaymentStatusPending,
+		Currency:        money.USD,
+		TotalAmount:     usd("40.00"),
+		ShippingAddress: "123 Test St",
+		ShippingCity:    "Test City",
+		ShippingCountry: "Test Country",
+		ShippingZip:     "12345",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	})
+	require.NoError(t, err)
+
+	t.Run("Set And Find Payment Intent", func(t *testing.T) {
+		err := orderRepo.SetPaymentIntent(ctx, order.ID, "fake", "pi_fake_1")
+		require.NoError(t, err)
+
+		found, err := orderRepo.GetByPaymentIntentIDForUpdate(ctx, "pi_fake_1")
+		require.NoError(t, err)
+		require.Equal(t, order.ID, found.ID)
+		require.Equal(t, "fake", *found.PaymentProvider)
+
+		_, err = orderRepo.GetByPaymentIntentIDForUpdate(ctx, "pi_missing")
+		require.ErrorIs(t, err, errorx.ErrOrderNotFound)
+	})
+
+	t.Run("Record Is Idempotent Per Event", func(t *testing.T) {
+		event := &entities.PaymentWebhookEvent{
+			Provider:  "fake",
+			EventID:   "evt_1",
+			EventType: "payment.succeeded",
+			OrderID:   order.ID,
+			Payload:   []byte(`{"id":"evt_1"}`),
+			CreatedAt: time.Now(),
+		}
+
+		recorded, err := repo.Record(ctx, event)
+		require.NoError(t, err)
+		require.True(t, recorded)
+
+		recorded, err = repo.Record(ctx, event)
+		require.NoError(t, err)
+		require.False(t, recorded)
+	})
+}
@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	"context"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/modules/order/infrastructure/query/gen"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type refundRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRefundRepository(db *pgxpool.Pool) interfaces.RefundRepository {
+	return &refundRepository{db: db}
+}
+
+func (r *refundRepository) Create(ctx context.Context, refund *entities.Refund) (*entities.Refund, error) {
+	var created *entities.Refund
+
+	err := pgxc.WithTx(ctx, r.db, func(ctx context.Context) error {
+		queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+		dbRefund, err := queries.CreateRefund(ctx, gen.CreateRefundParams{
+			OrderID:          refund.OrderID,
+			UserID:           refund.UserID,
+			Status:           refund.Status.String(),
+			Amount:           refund.Amount.Minor(),
+			Currency:         refund.Amount.Currency(),
+			Reason:           refund.Reason,
+			ApprovedAt:       refund.ApprovedAt,
+			ProcessedAt:      refund.ProcessedAt,
+			ProviderRefundID: refund.ProviderRefundID,
+			CreatedAt:        refund.CreatedAt,
+			UpdatedAt:        refund.UpdatedAt,
+		})
+		if err != nil {
+			return errorx.ErrCannotCreateRefund
+		}
+
+		created = toRefund(dbRefund)
+
+		for _, item := range refund.Items {
+			dbItem, err := queries.CreateRefundItem(ctx, gen.CreateRefundItemParams{
+				RefundID:    created.ID,
+				OrderItemID: item.OrderItemID,
+				Quantity:    item.Quantity,
+				Amount:      item.Amount.Minor(),
+				Currency:    item.Amount.Currency(),
+				CreatedAt:   item.CreatedAt,
+			})
+			if err != nil {
+				return errorx.ErrCannotCreateRefund
+			}
+
+			created.Items = append(created.Items, toRefundItem(dbItem))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (r *refundRepository) GetByID(ctx context.Context, id int32) (*entities.Refund, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbRefund, err := queries.GetRefundByID(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrRefundNotFound
+		}
+		return nil, err
+	}
+
+	refund := toRefund(dbRefund)
+	refund.Items, err = r.getItems(ctx, queries, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+func (r *refundRepository) GetByIDForUpdate(ctx context.Context, id int32) (*entities.Refund, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbRefund, err := queries.GetRefundByIDForUpdate(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrRefundNotFound
+		}
+		return nil, err
+	}
+
+	return toRefund(dbRefund), nil
+}
+
+func (r *refundRepository) GetByOrderID(ctx context.Context, orderID int32) ([]*entities.Refund, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbRefunds, err := queries.GetRefundsByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var refunds []*entities.Refund
+	for _, dbRefund := range dbRefunds {
+		refund := toRefund(dbRefund)
+		refund.Items, err = r.getItems(ctx, queries, refund.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		refunds = append(refunds, refund)
+	}
+
+	return refunds, nil
+}
+
+func (r *refundRepository) UpdateStatus(ctx context.Context, refund *entities.Refund) error {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	err := queries.UpdateRefundStatus(ctx, gen.UpdateRefundStatusParams{
+		ID:               refund.ID,
+		Status:           refund.Status.String(),
+		ApprovedAt:       refund.ApprovedAt,
+		ProcessedAt:      refund.ProcessedAt,
+		RejectedAt:       refund.RejectedAt,
+		ProviderRefundID: refund.ProviderRefundID,
+		UpdatedAt:        refund.UpdatedAt,
+	})
+	if err != nil {
+		return errorx.ErrCannotUpdateRefund
+	}
+
+	return nil
+}
+
+func (r *refundRepository) SumOpenAmount(ctx context.Context, orderID int32, currency money.Currency) (money.Money, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	total, err := queries.SumOpenRefundAmount(ctx, orderID)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	return money.New(total, currency), nil
+}
+
+func (r *refundRepository) SumProcessedAmount(ctx context.Context, orderID int32, currency money.Currency) (money.Money, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	total, err := queries.SumProcessedRefundAmount(ctx, orderID)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	return money.New(total, currency), nil
+}
+
+func (r *refundRepository) GetRefundedQuantities(ctx context.Context, orderID int32) (map[int32]int32, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	rows, err := queries.GetRefundedQuantities(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	quantities := make(map[int32]int32, len(rows))
+	for _, row := range rows {
+		quantities[row.OrderItemID] = row.Quantity
+	}
+
+	return quantities, nil
+}
+
+func (r *refundRepository) getItems(ctx context.Context, queries *gen.Queries, refundID int32) ([]*entities.RefundItem, error) {
+	dbItems, err := queries.GetRefundItems(ctx, refundID)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*entities.RefundItem
+	for _, dbItem := range dbItems {
+		items = append(items, toRefundItem(dbItem))
+	}
+
+	return items, nil
+}
+
+func toRefund(dbRefund *gen.Refund) *entities.Refund {
+	return &entities.Refund{
+		ID:               dbRefund.ID,
+		OrderID:          dbRefund.OrderID,
+		UserID:           dbRefund.UserID,
+		Status:           constants.RefundStatus(dbRefund.Status),
+		Amount:           dbRefund.Amount.In(dbRefund.Currency),
+		Reason:           dbRefund.Reason,
+		ApprovedAt:       dbRefund.ApprovedAt,
+		ProcessedAt:      dbRefund.ProcessedAt,
+		RejectedAt:       dbRefund.RejectedAt,
+		ProviderRefundID: dbRefund.ProviderRefundID,
+		CreatedAt:        dbRefund.CreatedAt,
+		UpdatedAt:        dbRefund.UpdatedAt,
+	}
+}
+
+func toRefundItem(dbItem *gen.RefundItem) *entities.RefundItem {
+	return &entities.RefundItem{
+		ID:          dbItem.ID,
+		RefundID:    dbItem.RefundID,
+		OrderItemID: dbItem.OrderItemID,
+		Quantity:    dbItem.Quantity,
+		Amount:      dbItem.Amount.In(dbItem.Currency),
+		CreatedAt:   dbItem.CreatedAt,
+	}
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefundRepository(t *testing.T) {
+	db := createTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	err := createTestUsers(ctx, db)
+	require.NoError(t, err, "failed to create test users")
+
+	orderRepo := NewOrderRepository(db)
+	repo := NewRefundRepository(db)
+
+	order, err := orderRepo.Create(ctx, &entities.Order{
+		UserID:          1,
+		Status:          constants.OrderStatusDelivered,
+		PaymentStatus:   constants.PaymentStatusPaid,
+		Currency:        money.USD,
+		TotalAmount:     usd("40.00"),
+		ShippingAddress: "123 Test St",
+		ShippingCity:    "Test City",
+		ShippingCountry: "Test Country",
+		ShippingZip:     "12345",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	})
+	require.NoError(t, err)
+
+	err = orderRepo.CreateOrderItems(ctx, order.ID, []*entities.OrderItem{
+		{ProductID: 1, Quantity: 2, Price: usd("10.00"), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ProductID: 2, Quantity: 1, Price: usd("20.00"), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	})
+	require.NoError(t, err)
+
+	items, err := orderRepo.GetItems(ctx, order.ID)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	t.Run("Create Refund with Items", func(t *testing.T) {
+		refund, err := repo.Create(ctx, &entities.Refund{
+			OrderID: order.ID,
+			UserID:  order.UserID,
+			Status:  constants.RefundStatusRequested,
+			Amount:  usd("10.00"),
+			Reason:  "damaged",
+			Items: []*entities.RefundItem{
+				{OrderItemID: items[0].ID, Quantity: 1, Amount: usd("10.00"), CreatedAt: time.Now()},
+			},
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+		require.NotZero(t, refund.ID)
+		require.Len(t, refund.Items, 1)
+
+		found, err := repo.GetByID(ctx, refund.ID)
+		require.NoError(t, err)
+		require.Equal(t, constants.RefundStatusRequested, found.Status)
+		require.Len(t, found.Items, 1)
+		require.Equal(t, items[0].ID, found.Items[0].OrderItemID)
+	})
+
+	t.Run("Sum And Quantities Ignore Rejected Refunds", func(t *testing.T) {
+		rejected, err := repo.Create(ctx, &entities.Refund{
+			OrderID: order.ID,
+			UserID:  order.UserID,
+			Status:  constants.RefundStatusRequested,
+			Amount:  usd("20.00"),
+			Reason:  "wrong size",
+			Items: []*entities.RefundItem{
+				{OrderItemID: items[1].ID, Quantity: 1, Amount: usd("20.00"), CreatedAt: time.Now()},
+			},
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+
+		require.True(t, rejected.TransitionTo(constants.RefundStatusRejected, time.Now()))
+		require.NoError(t, repo.UpdateStatus(ctx, rejected))
+
+		open, err := repo.SumOpenAmount(ctx, order.ID, money.USD)
+		require.NoError(t, err)
+		require.Equal(t, usd("10.00"), open)
+
+		quantities, err := repo.GetRefundedQuantities(ctx, order.ID)
+		require.NoError(t, err)
+		require.Equal(t, map[int32]int32{items[0].ID: 1}, quantities)
+	})
+
+	t.Run("Processed Amount", func(t *testing.T) {
+		refunds, err := repo.GetByOrderID(ctx, order.ID)
+		require.NoError(t, err)
+		require.Len(t, refunds, 2)
+
+		var requested *entities.Refund
+		for _, refund := range refunds {
+			if refund.Status == constants.RefundStatusRequested {
+				requested = refund
+			}
+		}
+		require.NotNil(t, requested)
+
+		require.True(t, requested.TransitionTo(constants.RefundStatusApproved, time.Now()))
+		require.NoError(t, repo.UpdateStatus(ctx, requested))
+		require.True(t, requested.TransitionTo(constants.RefundStatusProcessing, time.Now()))
+		require.NoError(t, repo.UpdateStatus(ctx, requested))
+		require.True(t, requested.TransitionTo(constants.RefundStatusProcessed, time.Now()))
+		require.NoError(t, repo.UpdateStatus(ctx, requested))
+
+		processed, err := repo.SumProcessedAmount(ctx, order.ID, money.USD)
+		require.NoError(t, err)
+		require.Equal(t, usd("10.00"), processed)
+
+		found, err := repo.GetByID(ctx, requested.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found.ApprovedAt)
+		require.NotNil(t, found.ProcessedAt)
+	})
+
+	t.Run("Get Non-existent Refund", func(t *testing.T) {
+		_, err := repo.GetByID(ctx, 99999)
+		require.ErrorIs(t, err, errorx.ErrRefundNotFound)
+	})
+}
@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"context"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/modules/order/infrastructure/query/gen"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type shipmentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewShipmentRepository(db *pgxpool.Pool) interfaces.ShipmentRepository {
+	return &shipmentRepository{db: db}
+}
+
+func (r *shipmentRepository) Create(ctx context.Context, shipment *entities.Shipment) (*entities.Shipment, error) {
+	var created *entities.Shipment
+
+	err := pgxc.WithTx(ctx, r.db, func(ctx context.Context) error {
+		queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+		dbShipment, err := queries.CreateShipment(ctx, gen.CreateShipmentParams{
+			OrderID:        shipment.OrderID,
+			Status:         shipment.Status.String(),
+			Carrier:        shipment.Carrier,
+			TrackingNumber: shipment.TrackingNumber,
+			CreatedAt:      shipment.CreatedAt,
+			UpdatedAt:      shipment.UpdatedAt,
+		})
+		if err != nil {
+			return errorx.ErrCannotCreateShipment
+		}
+
+		created = toShipment(dbShipment)
+
+		for _, item := range shipment.Items {
+			dbItem, err := queries.CreateShipmentItem(ctx, gen.CreateShipmentItemParams{
+				ShipmentID:  created.ID,
+				OrderItemID: item.OrderItemID,
+				Quantity:    item.Quantity,
+				CreatedAt:   item.CreatedAt,
+			})
+			if err != nil {
+				return errorx.ErrCannotCreateShipment
+			}
+
+			created.Items = append(created.Items, toShipmentItem(dbItem))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (r *shipmentRepository) GetByID(ctx context.Context, id int32) (*entities.Shipment, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbShipment, err := queries.GetShipmentByID(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrShipmentNotFound
+		}
+		return nil, err
+	}
+
+	shipments := []*entities.Shipment{toShipment(dbShipment)}
+	if err := loadShipmentItems(ctx, queries, shipments); err != nil {
+		return nil, err
+	}
+
+	return shipments[0], nil
+}
+
+func (r *shipmentRepository) GetByIDForUpdate(ctx context.Context, id int32) (*entities.Shipment, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbShipment, err := queries.GetShipmentByIDForUpdate(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrShipmentNotFound
+		}
+		return nil, err
+	}
+
+	return toShipment(dbShipment), nil
+}
+
+func (r *shipmentRepository) GetByOrderID(ctx context.Context, orderID int32) ([]*entities.Shipment, error) {
+	return getShipmentsByOrderID(ctx, gen.New(pgxc.GetDB(ctx, r.db)), orderID)
+}
+
+func (r *shipmentRepository) Update(ctx context.Context, shipment *entities.Shipment) error {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	err := queries.UpdateShipment(ctx, gen.UpdateShipmentParams{
+		ID:             shipment.ID,
+		Status:         shipment.Status.String(),
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		ShippedAt:      shipment.ShippedAt,
+		DeliveredAt:    shipment.DeliveredAt,
+		CancelledAt:    shipment.CancelledAt,
+		UpdatedAt:      shipment.UpdatedAt,
+	})
+	if err != nil {
+		return errorx.ErrCannotUpdateShipment
+	}
+
+	return nil
+}
+
+// getShipmentsByOrderID loads the shipments of an order with their items in
+// two queries, the order repository shares it to fill Order.Shipments
+func getShipmentsByOrderID(ctx context.Context, queries *gen.Queries, orderID int32) ([]*entities.Shipment, error) {
+	dbShipments, err := queries.GetShipmentsByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	shipments := make([]*entities.Shipment, 0, len(dbShipments))
+	for _, dbShipment := range dbShipments {
+		shipments = append(shipments, toShipment(dbShipment))
+	}
+
+	if err := loadShipmentItems(ctx, queries, shipments); err != nil {
+		return nil, err
+	}
+
+	return shipments, nil
+}
+
+// loadShipments fills Order.Shipments for a page of orders in two queries,
+// like the order repository loads their items
+func loadShipments(ctx context.Context, queries *gen.Queries, orders []*entities.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[int32]*entities.Order, len(orders))
+	orderIDs := make([]int32, 0, len(orders))
+	for _, order := range orders {
+		byID[order.ID] = order
+		orderIDs = append(orderIDs, order.ID)
+	}
+
+	dbShipments, err := queries.GetShipmentsByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		return err
+	}
+
+	shipments := make([]*entities.Shipment, 0, len(dbShipments))
+	for _, dbShipment := range dbShipments {
+		shipment := toShipment(dbShipment)
+		order := byID[shipment.OrderID]
+		order.Shipments = append(order.Shipments, shipment)
+		shipments = append(shipments, shipment)
+	}
+
+	return loadShipmentItems(ctx, queries, shipments)
+}
+
+func loadShipmentItems(ctx context.Context, queries *gen.Queries, shipments []*entities.Shipment) error {
+	if len(shipments) == 0 {
+		return nil
+	}
+
+	byID := make(map[int32]*entities.Shipment, len(shipments))
+	shipmentIDs := make([]int32, 0, len(shipments))
+	for _, shipment := range shipments {
+		byID[shipment.ID] = shipment
+		shipmentIDs = append(shipmentIDs, shipment.ID)
+	}
+
+	dbItems, err := queries.GetShipmentItemsByShipmentIDs(ctx, shipmentIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, dbItem := range dbItems {
+		shipment := byID[dbItem.ShipmentID]
+		shipment.Items = append(shipment.Items, toShipmentItem(dbItem))
+	}
+
+	return nil
+}
+
+func toShipment(dbShipment *gen.Shipment) *entities.Shipment {
+	return &entities.Shipment{
+		ID:             dbShipment.ID,
+		OrderID:        dbShipment.OrderID,
+		Status:         constants.ShipmentStatus(dbShipment.Status),
+		Carrier:        dbShipment.Carrier,
+		TrackingNumber: dbShipment.TrackingNumber,
+		ShippedAt:      dbShipment.ShippedAt,
+		DeliveredAt:    dbShipment.DeliveredAt,
+		CancelledAt:    dbShipment.CancelledAt,
+		CreatedAt:      dbShipment.CreatedAt,
+		UpdatedAt:      dbShipment.UpdatedAt,
+	}
+}
+
+func toShipmentItem(dbItem *gen.ShipmentItem) *entities.ShipmentItem {
+	return &entities.ShipmentItem{
+		ID:          dbItem.ID,
+		ShipmentID:  dbItem.ShipmentID,
+		OrderItemID: dbItem.OrderItemID,
+		Quantity:    dbItem.Quantity,
+		CreatedAt:   dbItem.CreatedAt,
+	}
+}
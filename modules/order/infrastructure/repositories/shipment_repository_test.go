@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/entities"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"testing"
+	"time"
+
+	"github.com/phathdt/service-context/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShipmentRepository(t *testing.T) {
+	db := createTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	err := createTestUsers(ctx, db)
+	require.NoError(t, err, "failed to create test users")
+
+	orderRepo := NewOrderRepository(db)
+	repo := NewShipmentRepository(db)
+
+	order, err := orderRepo.Create(ctx, &entities.Order{
+		UserID:          1,
+		Status:          constants.OrderStatusProcessing,
+		PaymentStatus:   constants.PaymentStatusPaid,
+		Currency:        money.USD,
+		TotalAmount:     usd("40.00"),
+		ShippingAddress: "123 Test St",
+		ShippingCity:    "Test City",
+		ShippingCountry: "Test Country",
+		ShippingZip:     "12345",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	})
+	require.NoError(t, err)
+
+	err = orderRepo.CreateOrderItems(ctx, order.ID, []*entities.OrderItem{
+		{ProductID: 1, Quantity: 2, Price: usd("10.00"), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ProductID: 2, Quantity: 1, Price: usd("20.00"), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	})
+	require.NoError(t, err)
+
+	items, err := orderRepo.GetItems(ctx, order.ID)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	t.Run("Create Shipment with Items", func(t *testing.T) {
+		tracking := "1Z999"
+		shipment, err := repo.Create(ctx, &entities.Shipment{
+			OrderID:        order.ID,
+			Status:         constants.ShipmentStatusPending,
+			Carrier:        "DHL",
+			TrackingNumber: &tracking,
+			Items: []*entities.ShipmentItem{
+				{OrderItemID: items[0].ID, Quantity: 1, CreatedAt: time.Now()},
+				{OrderItemID: items[1].ID, Quantity: 1, CreatedAt: time.Now()},
+			},
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+		require.NotZero(t, shipment.ID)
+		require.Len(t, shipment.Items, 2)
+
+		found, err := repo.GetByID(ctx, shipment.ID)
+		require.NoError(t, err)
+		require.Equal(t, constants.ShipmentStatusPending, found.Status)
+		require.Equal(t, tracking, *found.TrackingNumber)
+		require.Len(t, found.Items, 2)
+	})
+
+	t.Run("Update Shipment Status", func(t *testing.T) {
+		shipments, err := repo.GetByOrderID(ctx, order.ID)
+		require.NoError(t, err)
+		require.Len(t, shipments, 1)
+
+		shipment, err := repo.GetByIDForUpdate(ctx, shipments[0].ID)
+		require.NoError(t, err)
+		require.Empty(t, shipment.Items)
+
+		require.True(t, shipment.TransitionTo(constants.ShipmentStatusShipped, time.Now()))
+		require.NoError(t, repo.Update(ctx, shipment))
+
+		found, err := repo.GetByID(ctx, shipment.ID)
+		require.NoError(t, err)
+		require.Equal(t, constants.ShipmentStatusShipped, found.Status)
+		require.NotNil(t, found.ShippedAt)
+	})
+
+	t.Run("Order Loads Its Shipments", func(t *testing.T) {
+		found, err := orderRepo.GetByID(ctx, order.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Shipments, 1)
+		require.Len(t, found.Shipments[0].Items, 2)
+
+		orders, err := orderRepo.GetByUserID(ctx, 1, &interfaces.OrderFilter{SortBy: constants.OrderSortCreatedAtDesc}, &core.Paging{Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, orders, 1)
+		require.Len(t, orders[0].Shipments, 1)
+		require.Len(t, orders[0].Shipments[0].Items, 2)
+	})
+
+	t.Run("Get Missing Shipment", func(t *testing.T) {
+		_, err := repo.GetByID(ctx, 9999)
+		require.ErrorIs(t, err, errorx.ErrShipmentNotFound)
+	})
+}
@@ -0,0 +1,17 @@
+package rest
+
+import (
+	"mallbots/modules/order/domain/entities"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// callerFromCtx reads the user set by the RequiredAuth middleware
+func callerFromCtx(c *fiber.Ctx) entities.Caller {
+	role, _ := c.Context().UserValue("role").(string)
+
+	return entities.Caller{
+		UserID: c.Context().UserValue("userId").(int32),
+		Role:   role,
+	}
+}
@@ -0,0 +1,57 @@
+package rest
+
+import (
+	"fmt"
+	"mallbots/modules/order/domain/constants"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/shared/errorx"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/phathdt/service-context/core"
+)
+
+var errNotAcceptable = core.DefaultError{
+	StatusField: http.StatusText(http.StatusNotAcceptable),
+	ErrorField:  "The requested representation is not available",
+	CodeField:   http.StatusNotAcceptable,
+}
+
+// invoiceFormats maps the media types invoices are offered in, in order of
+// preference, to their format
+var invoiceFormats = map[string]constants.InvoiceFormat{
+	"text/html":       constants.InvoiceFormatHTML,
+	"application/pdf": constants.InvoiceFormatPDF,
+}
+
+type InvoiceHandler struct {
+	service interfaces.InvoiceService
+}
+
+func NewInvoiceHandler(service interfaces.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{service: service}
+}
+
+func (h *InvoiceHandler) GetInvoice(c *fiber.Ctx) error {
+	orderID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	format, ok := invoiceFormats[c.Accepts("text/html", "application/pdf")]
+	if !ok {
+		panic(errNotAcceptable.WithError(errorx.ErrInvoiceNotAcceptable.Error()))
+	}
+
+	file, err := h.service.GetInvoice(c.Context(), callerFromCtx(c), int32(orderID), format)
+	if err != nil {
+		panic(err)
+	}
+
+	c.Vary(fiber.HeaderAccept)
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", file.Filename))
+
+	return c.Status(http.StatusOK).Send(file.Content)
+}
@@ -3,6 +3,7 @@ package rest
 import (
 	"mallbots/modules/order/application/dto"
 	"mallbots/modules/order/domain/interfaces"
+	"mallbots/shared/common"
 	"net/http"
 	"strconv"
 
@@ -29,9 +30,11 @@ func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
 		panic(err)
 	}
 
-	userID := c.Context().UserValue("userId").(int32)
+	if req.Currency == "" {
+		req.Currency = c.Get(common.HeaderAcceptCurrency)
+	}
 
-	order, err := h.service.CreateOrder(c.Context(), userID, &req)
+	order, err := h.service.CreateOrder(c.Context(), callerFromCtx(c), &req)
 	if err != nil {
 		panic(err)
 	}
@@ -39,13 +42,35 @@ func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
 	return c.Status(http.StatusCreated).JSON(core.SimpleSuccessResponse(order))
 }
 
+func (h *OrderHandler) QuoteShipping(c *fiber.Ctx) error {
+	var req dto.ShippingQuoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := validation.Validate(req); err != nil {
+		panic(err)
+	}
+
+	if req.Currency == "" {
+		req.Currency = c.Get(common.HeaderAcceptCurrency)
+	}
+
+	quote, err := h.service.QuoteShipping(c.Context(), callerFromCtx(c), &req)
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(quote))
+}
+
 func (h *OrderHandler) GetOrder(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
 		panic(core.ErrBadRequest.WithError(err.Error()))
 	}
 
-	order, err := h.service.GetOrder(c.Context(), int32(id))
+	order, err := h.service.GetOrder(c.Context(), callerFromCtx(c), int32(id))
 	if err != nil {
 		panic(err)
 	}
@@ -55,6 +80,7 @@ func (h *OrderHandler) GetOrder(c *fiber.Ctx) error {
 
 func (h *OrderHandler) GetUserOrders(c *fiber.Ctx) error {
 	type reqParam struct {
+		dto.OrderListRequest
 		core.Paging
 	}
 
@@ -67,10 +93,107 @@ func (h *OrderHandler) GetUserOrders(c *fiber.Ctx) error {
 
 	userID := c.Context().UserValue("userId").(int32)
 
-	orders, err := h.service.GetUserOrders(c.Context(), userID, &rp.Paging)
+	orders, err := h.service.GetUserOrders(c.Context(), userID, &rp.OrderListRequest, &rp.Paging)
 	if err != nil {
 		panic(err)
 	}
 
 	return c.Status(http.StatusOK).JSON(core.ResponseWithPaging(orders, nil, &rp.Paging))
 }
+
+func (h *OrderHandler) CancelOrder(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	var req dto.CancelOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := validation.Validate(req); err != nil {
+		panic(err)
+	}
+
+	order, err := h.service.CancelOrder(c.Context(), callerFromCtx(c), int32(id), &req)
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(order))
+}
+
+func (h *OrderHandler) UpdateOrderStatus(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	var req dto.UpdateOrderStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := validation.Validate(req); err != nil {
+		panic(err)
+	}
+
+	order, err := h.service.UpdateOrderStatus(c.Context(), callerFromCtx(c), int32(id), &req)
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(order))
+}
+
+func (h *OrderHandler) UpdatePaymentStatus(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	var req dto.UpdatePaymentStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := validation.Validate(req); err != nil {
+		panic(err)
+	}
+
+	order, err := h.service.UpdatePaymentStatus(c.Context(), callerFromCtx(c), int32(id), &req)
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(order))
+}
+
+func (h *OrderHandler) GetOrderTimeline(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	events, err := h.service.GetOrderTimeline(c.Context(), callerFromCtx(c), int32(id))
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(events))
+}
+
+func (h *OrderHandler) Reorder(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	summary, err := h.service.Reorder(c.Context(), callerFromCtx(c), int32(id))
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(summary))
+}
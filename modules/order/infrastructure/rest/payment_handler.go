@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"errors"
+	"mallbots/modules/order/domain/interfaces"
+	"mallbots/plugins/payment"
+	"mallbots/shared/errorx"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/phathdt/service-context/core"
+)
+
+type PaymentHandler struct {
+	service interfaces.PaymentService
+}
+
+func NewPaymentHandler(service interfaces.PaymentService) *PaymentHandler {
+	return &PaymentHandler{service: service}
+}
+
+func (h *PaymentHandler) CreatePaymentIntent(c *fiber.Ctx) error {
+	orderID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	intent, err := h.service.CreatePaymentIntent(c.Context(), callerFromCtx(c), int32(orderID))
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(core.SimpleSuccessResponse(intent))
+}
+
+func (h *PaymentHandler) CapturePayment(c *fiber.Ctx) error {
+	orderID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	order, err := h.service.CapturePayment(c.Context(), callerFromCtx(c), int32(orderID))
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(order))
+}
+
+// HandleWebhook is called by the provider, not by a signed in user. The raw
+// body is passed through untouched because the signature covers its bytes.
+func (h *PaymentHandler) HandleWebhook(c *fiber.Ctx) error {
+	err := h.service.HandleWebhook(c.Context(), c.Body(), c.Get(payment.SignatureHeader))
+	if errors.Is(err, errorx.ErrPaymentAlreadyProcessed) {
+		return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(fiber.Map{"duplicate": true}))
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(fiber.Map{"duplicate": false}))
+}
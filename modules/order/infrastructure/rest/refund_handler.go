@@ -0,0 +1,99 @@
+package rest
+
+import (
+	"mallbots/modules/order/application/dto"
+	"mallbots/modules/order/domain/interfaces"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/phathdt/service-context/component/validation"
+	"github.com/phathdt/service-context/core"
+)
+
+type RefundHandler struct {
+	service interfaces.RefundService
+}
+
+func NewRefundHandler(service interfaces.RefundService) *RefundHandler {
+	return &RefundHandler{service: service}
+}
+
+func (h *RefundHandler) RequestRefund(c *fiber.Ctx) error {
+	orderID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	var req dto.CreateRefundRequest
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := validation.Validate(req); err != nil {
+		panic(err)
+	}
+
+	refund, err := h.service.RequestRefund(c.Context(), callerFromCtx(c), int32(orderID), &req)
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(core.SimpleSuccessResponse(refund))
+}
+
+func (h *RefundHandler) GetOrderRefunds(c *fiber.Ctx) error {
+	orderID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	refunds, err := h.service.GetOrderRefunds(c.Context(), callerFromCtx(c), int32(orderID))
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(refunds))
+}
+
+func (h *RefundHandler) ApproveRefund(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	refund, err := h.service.ApproveRefund(c.Context(), callerFromCtx(c), int32(id))
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(refund))
+}
+
+func (h *RefundHandler) RejectRefund(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	refund, err := h.service.RejectRefund(c.Context(), callerFromCtx(c), int32(id))
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(refund))
+}
+
+func (h *RefundHandler) ProcessRefund(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	refund, err := h.service.ProcessRefund(c.Context(), callerFromCtx(c), int32(id))
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(refund))
+}
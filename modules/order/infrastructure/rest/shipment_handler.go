@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"mallbots/modules/order/application/dto"
+	"mallbots/modules/order/domain/interfaces"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/phathdt/service-context/component/validation"
+	"github.com/phathdt/service-context/core"
+)
+
+type ShipmentHandler struct {
+	service interfaces.ShipmentService
+}
+
+func NewShipmentHandler(service interfaces.ShipmentService) *ShipmentHandler {
+	return &ShipmentHandler{service: service}
+}
+
+func (h *ShipmentHandler) CreateShipment(c *fiber.Ctx) error {
+	orderID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	var req dto.CreateShipmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := validation.Validate(req); err != nil {
+		panic(err)
+	}
+
+	shipment, err := h.service.CreateShipment(c.Context(), callerFromCtx(c), int32(orderID), &req)
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(core.SimpleSuccessResponse(shipment))
+}
+
+func (h *ShipmentHandler) UpdateShipment(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	var req dto.UpdateShipmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := validation.Validate(req); err != nil {
+		panic(err)
+	}
+
+	shipment, err := h.service.UpdateShipment(c.Context(), callerFromCtx(c), int32(id), &req)
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(shipment))
+}
@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+type StockLine struct {
+	ProductID int32
+	Quantity  int32
+}
+
+type SetStockRequest struct {
+	OnHand int32 `json:"on_hand" validate:"min=0"`
+}
+
+type InventoryResponse struct {
+	ProductID int32     `json:"product_id"`
+	OnHand    int32     `json:"on_hand"`
+	Reserved  int32     `json:"reserved"`
+	Available int32     `json:"available"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
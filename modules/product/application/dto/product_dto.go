@@ -1,21 +1,37 @@
 package dto
 
-import "time"
+import (
+	"mallbots/shared/money"
+	"time"
+)
 
 type ProductResponse struct {
-	ID          int32     `json:"id"`
-	Name        string    `json:"name"`
-	Description *string   `json:"description,omitempty"`
-	Price       float64   `json:"price"`
-	CategoryID  int32     `json:"category_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int32   `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	// Price is in Currency, the one asked for, converted from BasePrice when
+	// it is not the product's own
+	Price        money.Money `json:"price"`
+	Currency     string      `json:"currency"`
+	BasePrice    money.Money `json:"base_price"`
+	BaseCurrency string      `json:"base_currency"`
+	CategoryID   int32       `json:"category_id"`
+	CategoryName string      `json:"category_name"`
+	Weight       int32       `json:"weight"`
+	Stock        int32       `json:"stock"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
 }
 
+// ProductListRequest filters and sorts the catalog. MinPrice and MaxPrice are
+// amounts in Currency, the default one unless given, and like a price sort
+// only match the products priced in it.
 type ProductListRequest struct {
-	Search   string   `query:"search"`
-	MinPrice *float64 `query:"min_price"`
-	MaxPrice *float64 `query:"max_price"`
-	Category *int32   `query:"category"`
-	SortBy   string   `query:"sort_by"`
+	Search   string  `query:"search"`
+	MinPrice *string `query:"min_price"`
+	MaxPrice *string `query:"max_price"`
+	Category *int32  `query:"category"`
+	SortBy   string  `query:"sort_by"`
+	// Currency prices the products in another currency than their own
+	Currency string `query:"currency"`
 }
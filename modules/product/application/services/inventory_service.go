@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mallbots/modules/product/application/dto"
+	"mallbots/modules/product/domain/entities"
+	"mallbots/modules/product/domain/interfaces"
+	"mallbots/shared/errorx"
+	"sort"
+
+	"github.com/phathdt/service-context/core"
+)
+
+type InventoryService struct {
+	repo interfaces.InventoryRepository
+}
+
+func NewInventoryService(repo interfaces.InventoryRepository) interfaces.InventoryService {
+	return &InventoryService{repo: repo}
+}
+
+func (s *InventoryService) GetInventory(ctx context.Context, productID int32) (*dto.InventoryResponse, error) {
+	inventory, err := s.repo.GetByProductID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, errorx.ErrInventoryNotFound) {
+			return nil, core.ErrNotFound.WithError(errorx.ErrInventoryNotFound.Error())
+		}
+		return nil, err
+	}
+
+	return toInventoryResponse(inventory), nil
+}
+
+func (s *InventoryService) SetStock(ctx context.Context, productID int32, req *dto.SetStockRequest) (*dto.InventoryResponse, error) {
+	updated, err := s.repo.SetOnHand(ctx, productID, req.OnHand)
+	if err != nil {
+		return nil, err
+	}
+
+	if !updated {
+		return nil, core.ErrConflict.
+			WithError(errorx.ErrStockBelowReserved.Error()).
+			WithReasonf("product %d has more units reserved than %d", productID, req.OnHand)
+	}
+
+	return s.GetInventory(ctx, productID)
+}
+
+func (s *InventoryService) Reserve(ctx context.Context, lines []dto.StockLine) error {
+	for _, line := range mergeStockLines(lines) {
+		reserved, err := s.repo.Reserve(ctx, line.ProductID, line.Quantity)
+		if err != nil {
+			return err
+		}
+
+		if !reserved {
+			return s.outOfStock(ctx, line)
+		}
+	}
+
+	return nil
+}
+
+// Release and Commit skip lines without a matching reservation: orders placed
+// before stock was tracked never reserved anything
+func (s *InventoryService) Release(ctx context.Context, lines []dto.StockLine) error {
+	for _, line := range mergeStockLines(lines) {
+		if _, err := s.repo.Release(ctx, line.ProductID, line.Quantity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *InventoryService) Commit(ctx context.Context, lines []dto.StockLine) error {
+	for _, line := range mergeStockLines(lines) {
+		if _, err := s.repo.Commit(ctx, line.ProductID, line.Quantity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *InventoryService) outOfStock(ctx context.Context, line dto.StockLine) error {
+	var available int32
+	inventory, err := s.repo.GetByProductID(ctx, line.ProductID)
+	if err == nil {
+		available = inventory.Available()
+	} else if !errors.Is(err, errorx.ErrInventoryNotFound) {
+		return err
+	}
+
+	return core.ErrConflict.
+		WithError(errorx.ErrProductOutOfStock.Error()).
+		WithReasonf("product %d has %d available, %d requested", line.ProductID, available, line.Quantity).
+		WithDetail("product_id", line.ProductID).
+		WithDetail("available", available)
+}
+
+// mergeStockLines sums quantities per product and orders the result by
+// product ID, so concurrent checkouts lock inventory rows in the same order
+// and cannot deadlock each other
+func mergeStockLines(lines []dto.StockLine) []dto.StockLine {
+	quantities := make(map[int32]int32, len(lines))
+	for _, line := range lines {
+		quantities[line.ProductID] += line.Quantity
+	}
+
+	merged := make([]dto.StockLine, 0, len(quantities))
+	for productID, quantity := range quantities {
+		merged = append(merged, dto.StockLine{ProductID: productID, Quantity: quantity})
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].ProductID < merged[j].ProductID
+	})
+
+	return merged
+}
+
+func toInventoryResponse(inventory *entities.Inventory) *dto.InventoryResponse {
+	return &dto.InventoryResponse{
+		ProductID: inventory.ProductID,
+		OnHand:    inventory.OnHand,
+		Reserved:  inventory.Reserved,
+		Available: inventory.Available(),
+		UpdatedAt: inventory.UpdatedAt,
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"mallbots/modules/product/application/dto"
+	"mallbots/modules/product/domain/entities"
+	"mallbots/shared/errorx"
+	"net/http"
+	"testing"
+
+	"github.com/phathdt/service-context/core"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockInventoryRepo struct {
+	mock.Mock
+}
+
+func (m *MockInventoryRepo) GetByProductID(ctx context.Context, productID int32) (*entities.Inventory, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Inventory), args.Error(1)
+}
+
+func (m *MockInventoryRepo) GetByProductIDs(ctx context.Context, productIDs []int32) (map[int32]*entities.Inventory, error) {
+	args := m.Called(ctx, productIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int32]*entities.Inventory), args.Error(1)
+}
+
+func (m *MockInventoryRepo) SetOnHand(ctx context.Context, productID, onHand int32) (bool, error) {
+	args := m.Called(ctx, productID, onHand)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockInventoryRepo) Reserve(ctx context.Context, productID, quantity int32) (bool, error) {
+	args := m.Called(ctx, productID, quantity)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockInventoryRepo) Release(ctx context.Context, productID, quantity int32) (bool, error) {
+	args := m.Called(ctx, productID, quantity)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockInventoryRepo) Commit(ctx context.Context, productID, quantity int32) (bool, error) {
+	args := m.Called(ctx, productID, quantity)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestInventoryService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Reserve - Merges Lines In Product Order", func(t *testing.T) {
+		repo := new(MockInventoryRepo)
+		service := NewInventoryService(repo)
+
+		first := repo.On("Reserve", ctx, int32(1), int32(3)).Return(true, nil)
+		repo.On("Reserve", ctx, int32(2), int32(1)).Return(true, nil).NotBefore(first)
+
+		err := service.Reserve(ctx, []dto.StockLine{
+			{ProductID: 2, Quantity: 1},
+			{ProductID: 1, Quantity: 1},
+			{ProductID: 1, Quantity: 2},
+		})
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Reserve - Out Of Stock", func(t *testing.T) {
+		repo := new(MockInventoryRepo)
+		service := NewInventoryService(repo)
+
+		repo.On("Reserve", ctx, int32(1), int32(5)).Return(false, nil)
+		repo.On("GetByProductID", ctx, int32(1)).Return(&entities.Inventory{ProductID: 1, OnHand: 10, Reserved: 8}, nil)
+
+		err := service.Reserve(ctx, []dto.StockLine{{ProductID: 1, Quantity: 5}})
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrProductOutOfStock.Error(), appErr.Error())
+		require.Equal(t, int32(2), appErr.Details()["available"])
+	})
+
+	t.Run("Release - Tolerates Missing Reservation", func(t *testing.T) {
+		repo := new(MockInventoryRepo)
+		service := NewInventoryService(repo)
+
+		repo.On("Release", ctx, int32(1), int32(2)).Return(false, nil)
+
+		err := service.Release(ctx, []dto.StockLine{{ProductID: 1, Quantity: 2}})
+		require.NoError(t, err)
+	})
+
+	t.Run("Set Stock - Below Reserved", func(t *testing.T) {
+		repo := new(MockInventoryRepo)
+		service := NewInventoryService(repo)
+
+		repo.On("SetOnHand", ctx, int32(1), int32(1)).Return(false, nil)
+
+		inventory, err := service.SetStock(ctx, 1, &dto.SetStockRequest{OnHand: 1})
+		require.Nil(t, inventory)
+
+		var appErr *core.DefaultError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, http.StatusConflict, appErr.StatusCode())
+		require.Equal(t, errorx.ErrStockBelowReserved.Error(), appErr.Error())
+	})
+
+	t.Run("Set Stock - Success", func(t *testing.T) {
+		repo := new(MockInventoryRepo)
+		service := NewInventoryService(repo)
+
+		repo.On("SetOnHand", ctx, int32(1), int32(20)).Return(true, nil)
+		repo.On("GetByProductID", ctx, int32(1)).Return(&entities.Inventory{ProductID: 1, OnHand: 20, Reserved: 4}, nil)
+
+		inventory, err := service.SetStock(ctx, 1, &dto.SetStockRequest{OnHand: 20})
+		require.NoError(t, err)
+		require.Equal(t, int32(16), inventory.Available)
+	})
+}
@@ -2,28 +2,50 @@ package services
 
 import (
 	"context"
+	currencyInterfaces "mallbots/modules/currency/domain/interfaces"
 	"mallbots/modules/product/application/dto"
+	"mallbots/modules/product/domain/entities"
 	"mallbots/modules/product/domain/interfaces"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
 
 	"github.com/phathdt/service-context/core"
 )
 
 type ProductService struct {
-	repo interfaces.ProductRepository
+	repo       interfaces.ProductRepository
+	currencies currencyInterfaces.CurrencyService
 }
 
-func NewProductService(repo interfaces.ProductRepository) interfaces.ProductService {
-	return &ProductService{repo: repo}
+func NewProductService(repo interfaces.ProductRepository, currencies currencyInterfaces.CurrencyService) interfaces.ProductService {
+	return &ProductService{repo: repo, currencies: currencies}
 }
 
 func (s *ProductService) GetProducts(ctx context.Context, req *dto.ProductListRequest, paging *core.Paging) ([]*dto.ProductResponse, error) {
+	currency, err := s.currencies.ParseCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	minPrice, err := parsePriceFilter("min_price", req.MinPrice, currency)
+	if err != nil {
+		return nil, err
+	}
+	maxPrice, err := parsePriceFilter("max_price", req.MaxPrice, currency)
+	if err != nil {
+		return nil, err
+	}
+
 	filter := interfaces.ProductFilter{
 		Search:   req.Search,
-		MinPrice: req.MinPrice,
-		MaxPrice: req.MaxPrice,
+		MinPrice: minPrice,
+		MaxPrice: maxPrice,
 		Category: req.Category,
 		SortBy:   req.SortBy,
 	}
+	if minPrice != nil || maxPrice != nil || sortsByPrice(req.SortBy) {
+		filter.Currency = &currency
+	}
 
 	products, err := s.repo.GetProducts(ctx, &filter, paging)
 	if err != nil {
@@ -32,15 +54,11 @@ func (s *ProductService) GetProducts(ctx context.Context, req *dto.ProductListRe
 
 	var response []*dto.ProductResponse
 	for _, p := range products {
-		response = append(response, &dto.ProductResponse{
-			ID:          p.ID,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       p.Price,
-			CategoryID:  p.CategoryID,
-			CreatedAt:   p.CreatedAt,
-			UpdatedAt:   p.UpdatedAt,
-		})
+		response = append(response, toProductResponse(p))
+	}
+
+	if err := s.priceIn(ctx, req.Currency, response...); err != nil {
+		return nil, err
 	}
 
 	return response, nil
@@ -52,13 +70,88 @@ func (s *ProductService) GetProduct(ctx context.Context, id int32) (*dto.Product
 		return nil, err
 	}
 
+	return toProductResponse(product), nil
+}
+
+func (s *ProductService) GetProductIn(ctx context.Context, id int32, currency string) (*dto.ProductResponse, error) {
+	product, err := s.GetProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.priceIn(ctx, currency, product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// priceIn converts the prices of the products into the currency the caller
+// asked for, at the current rates
+func (s *ProductService) priceIn(ctx context.Context, code string, products ...*dto.ProductResponse) error {
+	if code == "" {
+		return nil
+	}
+
+	currency, err := s.currencies.ParseCurrency(code)
+	if err != nil {
+		return err
+	}
+
+	currencies := []money.Currency{currency}
+	for _, product := range products {
+		currencies = append(currencies, product.BasePrice.Currency())
+	}
+
+	exchange, err := s.currencies.Exchange(ctx, currencies...)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		product.Price = exchange.Convert(product.BasePrice, currency)
+		product.Currency = currency.String()
+	}
+
+	return nil
+}
+
+// parsePriceFilter reads an optional price bound in currency from the query
+// string
+func parsePriceFilter(name string, value *string, currency money.Currency) (*money.Money, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+
+	price, err := money.Parse(*value, currency)
+	if err != nil {
+		return nil, core.ErrBadRequest.
+			WithError(errorx.ErrInvalidAmount.Error()).
+			WithReasonf("%s %q is not a valid amount", name, *value)
+	}
+
+	return &price, nil
+}
+
+// sortsByPrice tells whether the products are listed by their own price
+func sortsByPrice(sortBy string) bool {
+	return sortBy == "price_asc" || sortBy == "price_desc"
+}
+
+func toProductResponse(product *entities.Product) *dto.ProductResponse {
 	return &dto.ProductResponse{
-		ID:          product.ID,
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price,
-		CategoryID:  product.CategoryID,
-		CreatedAt:   product.CreatedAt,
-		UpdatedAt:   product.UpdatedAt,
-	}, nil
+		ID:           product.ID,
+		Name:         product.Name,
+		Description:  product.Description,
+		Price:        product.Price,
+		Currency:     product.Price.Currency().String(),
+		BasePrice:    product.Price,
+		BaseCurrency: product.Price.Currency().String(),
+		CategoryID:   product.CategoryID,
+		CategoryName: product.CategoryName,
+		Weight:       product.Weight,
+		Stock:        product.Stock,
+		CreatedAt:    product.CreatedAt,
+		UpdatedAt:    product.UpdatedAt,
+	}
 }
@@ -2,9 +2,13 @@ package services
 
 import (
 	"context"
+	currencyServices "mallbots/modules/currency/application/services"
+	currencyEntities "mallbots/modules/currency/domain/entities"
 	"mallbots/modules/product/application/dto"
 	"mallbots/modules/product/domain/entities"
 	"mallbots/modules/product/domain/interfaces"
+	"mallbots/shared/money"
+	"net/http"
 	"testing"
 
 	"github.com/phathdt/service-context/core"
@@ -27,15 +31,38 @@ func (m *MockProductRepo) GetProduct(ctx context.Context, id int32) (*entities.P
 	return args.Get(0).(*entities.Product), args.Error(1)
 }
 
+type MockExchangeRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateRepository) Create(ctx context.Context, rate *currencyEntities.ExchangeRate) (*currencyEntities.ExchangeRate, error) {
+	args := m.Called(ctx, rate)
+	return args.Get(0).(*currencyEntities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) GetLatest(ctx context.Context, currencies []money.Currency) ([]*currencyEntities.ExchangeRate, error) {
+	args := m.Called(ctx, currencies)
+	return args.Get(0).([]*currencyEntities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) ListLatest(ctx context.Context) ([]*currencyEntities.ExchangeRate, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*currencyEntities.ExchangeRate), args.Error(1)
+}
+
+func newTestProductService(repo *MockProductRepo, rateRepo *MockExchangeRateRepository) *ProductService {
+	return NewProductService(repo, currencyServices.NewCurrencyService(rateRepo, nil)).(*ProductService)
+}
+
 func TestGetProduct(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockProductRepo)
-	service := NewProductService(mockRepo)
+	service := newTestProductService(mockRepo, new(MockExchangeRateRepository))
 
 	expectedProduct := &entities.Product{
 		ID:         1,
 		Name:       "Test Product",
-		Price:      99.99,
+		Price:      money.MustParse("99.99", money.USD),
 		CategoryID: 1,
 	}
 
@@ -55,12 +82,12 @@ func TestGetProduct(t *testing.T) {
 func TestGetProducts(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockProductRepo)
-	service := NewProductService(mockRepo)
+	service := newTestProductService(mockRepo, new(MockExchangeRateRepository))
 
 	req := &dto.ProductListRequest{
 		Search:   "test",
-		MinPrice: &[]float64{10.0}[0],
-		MaxPrice: &[]float64{100.0}[0],
+		MinPrice: &[]string{"10"}[0],
+		MaxPrice: &[]string{"100.50"}[0],
 	}
 
 	paging := &core.Paging{
@@ -69,11 +96,14 @@ func TestGetProducts(t *testing.T) {
 	}
 
 	expectedProducts := []*entities.Product{
-		{ID: 1, Name: "Test 1", Price: 50.0},
-		{ID: 2, Name: "Test 2", Price: 75.0},
+		{ID: 1, Name: "Test 1", Price: money.New(5000, money.USD)},
+		{ID: 2, Name: "Test 2", Price: money.New(7500, money.USD)},
 	}
 
-	mockRepo.On("GetProducts", mock.Anything, mock.Anything, paging).Return(expectedProducts, nil)
+	mockRepo.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter *interfaces.ProductFilter) bool {
+		return filter.MinPrice.Minor() == 1000 && filter.MaxPrice.Minor() == 10050 &&
+			*filter.Currency == money.USD
+	}), paging).Return(expectedProducts, nil)
 
 	// Act
 	results, err := service.GetProducts(context.Background(), req, paging)
@@ -82,5 +112,104 @@ func TestGetProducts(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, results, 2)
 	assert.Equal(t, expectedProducts[0].Name, results[0].Name)
+	assert.Equal(t, "USD", results[0].Currency)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestGetProductsInvalidPrice(t *testing.T) {
+	mockRepo := new(MockProductRepo)
+	service := newTestProductService(mockRepo, new(MockExchangeRateRepository))
+
+	req := &dto.ProductListRequest{MinPrice: &[]string{"10.001"}[0]}
+
+	_, err := service.GetProducts(context.Background(), req, &core.Paging{Page: 1, Limit: 10})
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "GetProducts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetProductsPriceFilterCurrency(t *testing.T) {
+	mockRepo := new(MockProductRepo)
+	rateRepo := new(MockExchangeRateRepository)
+	service := newTestProductService(mockRepo, rateRepo)
+
+	paging := &core.Paging{Page: 1, Limit: 10}
+	rateRepo.On("GetLatest", mock.Anything, []money.Currency{money.JPY}).Return([]*currencyEntities.ExchangeRate{
+		{ID: 4, Currency: money.JPY, Rate: money.MustParseRate("150")},
+	}, nil)
+
+	t.Run("Bounds and price sorts keep to the currency", func(t *testing.T) {
+		mockRepo.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter *interfaces.ProductFilter) bool {
+			return *filter.MinPrice == money.MustParse("1000", money.JPY) && *filter.Currency == money.JPY
+		}), paging).Return([]*entities.Product{}, nil).Once()
+		mockRepo.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter *interfaces.ProductFilter) bool {
+			return filter.MinPrice == nil && *filter.Currency == money.USD
+		}), paging).Return([]*entities.Product{}, nil).Once()
+
+		_, err := service.GetProducts(context.Background(), &dto.ProductListRequest{MinPrice: &[]string{"1000"}[0], Currency: "JPY"}, paging)
+		assert.NoError(t, err)
+
+		_, err = service.GetProducts(context.Background(), &dto.ProductListRequest{SortBy: "price_desc"}, paging)
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Other listings span every currency", func(t *testing.T) {
+		mockRepo.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter *interfaces.ProductFilter) bool {
+			return filter.Currency == nil
+		}), paging).Return([]*entities.Product{}, nil).Once()
+
+		_, err := service.GetProducts(context.Background(), &dto.ProductListRequest{Search: "shirt"}, paging)
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid currency", func(t *testing.T) {
+		_, err := service.GetProducts(context.Background(), &dto.ProductListRequest{MinPrice: &[]string{"10"}[0], Currency: "dollars"}, paging)
+
+		var appErr *core.DefaultError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+	})
+}
+
+func TestGetProductsInCurrency(t *testing.T) {
+	mockRepo := new(MockProductRepo)
+	rateRepo := new(MockExchangeRateRepository)
+	service := newTestProductService(mockRepo, rateRepo)
+
+	paging := &core.Paging{Page: 1, Limit: 10}
+	mockRepo.On("GetProducts", mock.Anything, mock.Anything, paging).Return([]*entities.Product{
+		{ID: 1, Name: "Shirt", Price: money.MustParse("20", money.USD)},
+		{ID: 2, Name: "Scarf", Price: money.MustParse("9.20", money.EUR)},
+	}, nil)
+	rateRepo.On("GetLatest", mock.Anything, []money.Currency{money.EUR}).Return([]*currencyEntities.ExchangeRate{
+		{ID: 3, Currency: money.EUR, Rate: money.MustParseRate("0.92")},
+	}, nil)
+
+	results, err := service.GetProducts(context.Background(), &dto.ProductListRequest{Currency: "eur"}, paging)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "EUR", results[0].Currency)
+	assert.Equal(t, "18.40", results[0].Price.String())
+	assert.Equal(t, "USD", results[0].BaseCurrency)
+	assert.Equal(t, "20.00", results[0].BasePrice.String())
+	assert.Equal(t, "9.20", results[1].Price.String())
+	assert.Equal(t, "EUR", results[1].BaseCurrency)
+}
+
+func TestGetProductInUnknownCurrency(t *testing.T) {
+	mockRepo := new(MockProductRepo)
+	rateRepo := new(MockExchangeRateRepository)
+	service := newTestProductService(mockRepo, rateRepo)
+
+	mockRepo.On("GetProduct", mock.Anything, int32(1)).Return(&entities.Product{ID: 1, Price: money.MustParse("20", money.USD)}, nil)
+	rateRepo.On("GetLatest", mock.Anything, []money.Currency{money.GBP}).Return([]*currencyEntities.ExchangeRate{}, nil)
+
+	_, err := service.GetProductIn(context.Background(), 1, "GBP")
+
+	assert.Error(t, err)
+}
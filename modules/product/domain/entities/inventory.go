@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// Inventory holds the stock of one product. Reserved units are held by open
+// orders and leave OnHand once the order ships.
+type Inventory struct {
+	ProductID int32
+	OnHand    int32
+	Reserved  int32
+	UpdatedAt time.Time
+}
+
+func (i *Inventory) Available() int32 {
+	return i.OnHand - i.Reserved
+}
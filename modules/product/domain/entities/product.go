@@ -1,15 +1,23 @@
 package entities
 
-import "time"
+import (
+	"mallbots/shared/money"
+	"time"
+)
 
 type Product struct {
-	ID          int32
-	Name        string
-	Description *string
-	Price       float64
-	CategoryID  int32
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID           int32
+	Name         string
+	Description  *string
+	Price        money.Money
+	CategoryID   int32
+	CategoryName string
+	// Weight is the shipping weight in grams
+	Weight int32
+	// Stock is the quantity still available to new orders
+	Stock     int32
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 type Category struct {
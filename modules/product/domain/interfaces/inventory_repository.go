@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/product/domain/entities"
+)
+
+// InventoryRepository changes stock with conditional updates, so each call is
+// atomic on its own and reports false instead of overselling
+type InventoryRepository interface {
+	GetByProductID(ctx context.Context, productID int32) (*entities.Inventory, error)
+	GetByProductIDs(ctx context.Context, productIDs []int32) (map[int32]*entities.Inventory, error)
+	SetOnHand(ctx context.Context, productID, onHand int32) (bool, error)
+	Reserve(ctx context.Context, productID, quantity int32) (bool, error)
+	Release(ctx context.Context, productID, quantity int32) (bool, error)
+	Commit(ctx context.Context, productID, quantity int32) (bool, error)
+}
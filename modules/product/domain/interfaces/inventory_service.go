@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/product/application/dto"
+)
+
+type InventoryService interface {
+	GetInventory(ctx context.Context, productID int32) (*dto.InventoryResponse, error)
+	SetStock(ctx context.Context, productID int32, req *dto.SetStockRequest) (*dto.InventoryResponse, error)
+	// Reserve holds stock for every line or fails with ErrProductOutOfStock.
+	// Callers run it inside their transaction so a failure undoes all lines.
+	Reserve(ctx context.Context, lines []dto.StockLine) error
+	// Release returns reserved stock when an order will not be fulfilled
+	Release(ctx context.Context, lines []dto.StockLine) error
+	// Commit takes reserved stock off hand once the goods leave the warehouse
+	Commit(ctx context.Context, lines []dto.StockLine) error
+}
@@ -3,6 +3,7 @@ package interfaces
 import (
 	"context"
 	"mallbots/modules/product/domain/entities"
+	"mallbots/shared/money"
 
 	"github.com/phathdt/service-context/core"
 )
@@ -14,8 +15,11 @@ type ProductRepository interface {
 
 type ProductFilter struct {
 	Search   string
-	MinPrice *float64
-	MaxPrice *float64
+	MinPrice *money.Money
+	MaxPrice *money.Money
 	Category *int32
 	SortBy   string
+	// Currency restricts the products to those priced in it, prices in
+	// different currencies do not compare
+	Currency *money.Currency
 }
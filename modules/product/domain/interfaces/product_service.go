@@ -9,5 +9,9 @@ import (
 
 type ProductService interface {
 	GetProducts(ctx context.Context, req *dto.ProductListRequest, paging *core.Paging) ([]*dto.ProductResponse, error)
+	// GetProduct returns the product priced in its own currency
 	GetProduct(ctx context.Context, id int32) (*dto.ProductResponse, error)
+	// GetProductIn returns the product priced in the currency, its own when
+	// the currency is empty
+	GetProductIn(ctx context.Context, id int32, currency string) (*dto.ProductResponse, error)
 }
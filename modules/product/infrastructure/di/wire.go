@@ -3,21 +3,45 @@
 package di
 
 import (
+	currencyService "mallbots/modules/currency/application/services"
+	currencyRepo "mallbots/modules/currency/infrastructure/repositories"
 	"mallbots/modules/product/application/services"
 	"mallbots/modules/product/infrastructure/repositories"
 	"mallbots/modules/product/infrastructure/rest"
+	"mallbots/modules/product/infrastructure/subscribers"
+	"mallbots/plugins/pgxc"
 
 	"github.com/google/wire"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var ProductSet = wire.NewSet(
+	pgxc.NewTxManager,
+	currencyRepo.NewExchangeRateRepository,
+	currencyService.NewCurrencyService,
 	repositories.NewProductRepository,
 	services.NewProductService,
 	rest.NewProductHandler,
 )
 
+var InventorySet = wire.NewSet(
+	repositories.NewInventoryRepository,
+	services.NewInventoryService,
+	rest.NewInventoryHandler,
+	subscribers.NewOrderSubscriber,
+)
+
 func InitializeProductHandler(db *pgxpool.Pool) (*rest.ProductHandler, error) {
 	wire.Build(ProductSet)
 	return &rest.ProductHandler{}, nil
 }
+
+func InitializeInventoryHandler(db *pgxpool.Pool) (*rest.InventoryHandler, error) {
+	wire.Build(InventorySet)
+	return &rest.InventoryHandler{}, nil
+}
+
+func InitializeOrderSubscriber(db *pgxpool.Pool) (*subscribers.OrderSubscriber, error) {
+	wire.Build(InventorySet)
+	return &subscribers.OrderSubscriber{}, nil
+}
@@ -9,20 +9,43 @@ package di
 import (
 	"github.com/google/wire"
 	"github.com/jackc/pgx/v5/pgxpool"
+	services2 "mallbots/modules/currency/application/services"
+	repositories2 "mallbots/modules/currency/infrastructure/repositories"
 	"mallbots/modules/product/application/services"
 	"mallbots/modules/product/infrastructure/repositories"
 	"mallbots/modules/product/infrastructure/rest"
+	"mallbots/modules/product/infrastructure/subscribers"
+	"mallbots/plugins/pgxc"
 )
 
 // Injectors from wire.go:
 
 func InitializeProductHandler(db *pgxpool.Pool) (*rest.ProductHandler, error) {
 	productRepository := repositories.NewProductRepository(db)
-	productService := services.NewProductService(productRepository)
+	exchangeRateRepository := repositories2.NewExchangeRateRepository(db)
+	txManager := pgxc.NewTxManager(db)
+	currencyService := services2.NewCurrencyService(exchangeRateRepository, txManager)
+	productService := services.NewProductService(productRepository, currencyService)
 	productHandler := rest.NewProductHandler(productService)
 	return productHandler, nil
 }
 
+func InitializeInventoryHandler(db *pgxpool.Pool) (*rest.InventoryHandler, error) {
+	inventoryRepository := repositories.NewInventoryRepository(db)
+	inventoryService := services.NewInventoryService(inventoryRepository)
+	inventoryHandler := rest.NewInventoryHandler(inventoryService)
+	return inventoryHandler, nil
+}
+
+func InitializeOrderSubscriber(db *pgxpool.Pool) (*subscribers.OrderSubscriber, error) {
+	inventoryRepository := repositories.NewInventoryRepository(db)
+	inventoryService := services.NewInventoryService(inventoryRepository)
+	orderSubscriber := subscribers.NewOrderSubscriber(inventoryService)
+	return orderSubscriber, nil
+}
+
 // wire.go:
 
-var ProductSet = wire.NewSet(repositories.NewProductRepository, services.NewProductService, rest.NewProductHandler)
+var ProductSet = wire.NewSet(pgxc.NewTxManager, repositories2.NewExchangeRateRepository, services2.NewCurrencyService, repositories.NewProductRepository, services.NewProductService, rest.NewProductHandler)
+
+var InventorySet = wire.NewSet(repositories.NewInventoryRepository, services.NewInventoryService, rest.NewInventoryHandler, subscribers.NewOrderSubscriber)
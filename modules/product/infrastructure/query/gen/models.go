@@ -6,6 +6,8 @@ package gen
 
 import (
 	"time"
+
+	"mallbots/shared/money"
 )
 
 type Category struct {
@@ -15,12 +17,21 @@ type Category struct {
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
+type Inventory struct {
+	ProductID int32     `db:"product_id" json:"product_id"`
+	OnHand    int32     `db:"on_hand" json:"on_hand"`
+	Reserved  int32     `db:"reserved" json:"reserved"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type Product struct {
-	ID          int32     `db:"id" json:"id"`
-	Name        string    `db:"name" json:"name"`
-	Description *string   `db:"description" json:"description"`
-	Price       float64   `db:"price" json:"price"`
-	CategoryID  int32     `db:"category_id" json:"category_id"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	ID          int32          `db:"id" json:"id"`
+	Name        string         `db:"name" json:"name"`
+	Description *string        `db:"description" json:"description"`
+	Price       money.Minor    `db:"price" json:"price"`
+	Weight      int32          `db:"weight" json:"weight"`
+	CategoryID  int32          `db:"category_id" json:"category_id"`
+	Currency    money.Currency `db:"currency" json:"currency"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
 }
@@ -7,6 +7,8 @@ package gen
 
 import (
 	"context"
+
+	"mallbots/shared/money"
 )
 
 const countProducts = `-- name: CountProducts :one
@@ -16,6 +18,7 @@ WHERE
     AND ($2 = 0 OR category_id = $2)
     AND ($3 = 0 OR price >= $3)
     AND ($4 = 0 OR price <= $4)
+    AND ($5::text = '' OR currency = $5::text)
 `
 
 type CountProductsParams struct {
@@ -23,6 +26,7 @@ type CountProductsParams struct {
 	Column2 interface{} `db:"column_2" json:"column_2"`
 	Column3 interface{} `db:"column_3" json:"column_3"`
 	Column4 interface{} `db:"column_4" json:"column_4"`
+	Column5 string      `db:"column_5" json:"column_5"`
 }
 
 func (q *Queries) CountProducts(ctx context.Context, arg CountProductsParams) (int64, error) {
@@ -31,6 +35,7 @@ func (q *Queries) CountProducts(ctx context.Context, arg CountProductsParams) (i
 		arg.Column2,
 		arg.Column3,
 		arg.Column4,
+		arg.Column5,
 	)
 	var count int64
 	err := row.Scan(&count)
@@ -42,19 +47,21 @@ INSERT INTO products (
     name,
     description,
     price,
+    currency,
     category_id,
     created_at,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, NOW(), NOW()
-) RETURNING id, name, description, price, category_id, created_at, updated_at
+    $1, $2, $3, $4, $5, NOW(), NOW()
+) RETURNING id, name, description, price, weight, category_id, currency, created_at, updated_at
 `
 
 type CreateProductParams struct {
-	Name        string  `db:"name" json:"name"`
-	Description *string `db:"description" json:"description"`
-	Price       float64 `db:"price" json:"price"`
-	CategoryID  int32   `db:"category_id" json:"category_id"`
+	Name        string         `db:"name" json:"name"`
+	Description *string        `db:"description" json:"description"`
+	Price       money.Minor    `db:"price" json:"price"`
+	Currency    money.Currency `db:"currency" json:"currency"`
+	CategoryID  int32          `db:"category_id" json:"category_id"`
 }
 
 func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (*Product, error) {
@@ -62,6 +69,7 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (*
 		arg.Name,
 		arg.Description,
 		arg.Price,
+		arg.Currency,
 		arg.CategoryID,
 	)
 	var i Product
@@ -70,7 +78,9 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (*
 		&i.Name,
 		&i.Description,
 		&i.Price,
+		&i.Weight,
 		&i.CategoryID,
+		&i.Currency,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -160,7 +170,7 @@ func (q *Queries) GetCategory(ctx context.Context, id int32) (*Category, error)
 }
 
 const getProduct = `-- name: GetProduct :one
-SELECT id, name, description, price, category_id, created_at, updated_at FROM products WHERE id = $1
+SELECT id, name, description, price, weight, category_id, currency, created_at, updated_at FROM products WHERE id = $1
 `
 
 func (q *Queries) GetProduct(ctx context.Context, id int32) (*Product, error) {
@@ -171,7 +181,9 @@ func (q *Queries) GetProduct(ctx context.Context, id int32) (*Product, error) {
 		&i.Name,
 		&i.Description,
 		&i.Price,
+		&i.Weight,
 		&i.CategoryID,
+		&i.Currency,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -179,20 +191,21 @@ func (q *Queries) GetProduct(ctx context.Context, id int32) (*Product, error) {
 }
 
 const getProducts = `-- name: GetProducts :many
-SELECT id, name, description, price, category_id, created_at, updated_at FROM products
+SELECT id, name, description, price, weight, category_id, currency, created_at, updated_at FROM products
 WHERE
     (NULLIF(TRIM($1), '') IS NULL OR name ILIKE '%' || $1 || '%' OR description ILIKE '%' || $1 || '%')
     AND ($2 = 0 OR category_id = $2)
     AND ($3 = 0 OR price >= $3)
     AND ($4 = 0 OR price <= $4)
+    AND ($5::text = '' OR currency = $5::text)
 ORDER BY
-    CASE $5::text
+    CASE $6::text
         WHEN 'price_asc' THEN price
         WHEN 'price_desc' THEN price * -1
         ELSE extract(epoch from created_at) * -1
     END,
     id DESC
-LIMIT $6 OFFSET $7
+LIMIT $7 OFFSET $8
 `
 
 type GetProductsParams struct {
@@ -201,6 +214,7 @@ type GetProductsParams struct {
 	Column3 interface{} `db:"column_3" json:"column_3"`
 	Column4 interface{} `db:"column_4" json:"column_4"`
 	Column5 string      `db:"column_5" json:"column_5"`
+	Column6 string      `db:"column_6" json:"column_6"`
 	Limit   int32       `db:"limit" json:"limit"`
 	Offset  int32       `db:"offset" json:"offset"`
 }
@@ -212,6 +226,7 @@ func (q *Queries) GetProducts(ctx context.Context, arg GetProductsParams) ([]*Pr
 		arg.Column3,
 		arg.Column4,
 		arg.Column5,
+		arg.Column6,
 		arg.Limit,
 		arg.Offset,
 	)
@@ -227,7 +242,9 @@ func (q *Queries) GetProducts(ctx context.Context, arg GetProductsParams) ([]*Pr
 			&i.Name,
 			&i.Description,
 			&i.Price,
+			&i.Weight,
 			&i.CategoryID,
+			&i.Currency,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -242,7 +259,7 @@ func (q *Queries) GetProducts(ctx context.Context, arg GetProductsParams) ([]*Pr
 }
 
 const getProductsByCategory = `-- name: GetProductsByCategory :many
-SELECT id, name, description, price, category_id, created_at, updated_at FROM products
+SELECT id, name, description, price, weight, category_id, currency, created_at, updated_at FROM products
 WHERE category_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -268,7 +285,9 @@ func (q *Queries) GetProductsByCategory(ctx context.Context, arg GetProductsByCa
 			&i.Name,
 			&i.Description,
 			&i.Price,
+			&i.Weight,
 			&i.CategoryID,
+			&i.Currency,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
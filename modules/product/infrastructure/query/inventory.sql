@@ -0,0 +1,42 @@
+-- name: GetInventory :one
+SELECT * FROM inventory WHERE product_id = $1;
+
+-- name: GetInventoriesByProductIDs :many
+SELECT * FROM inventory
+WHERE product_id = ANY($1::int[]);
+
+-- name: UpsertInventoryOnHand :execrows
+INSERT INTO inventory (
+    product_id,
+    on_hand,
+    reserved,
+    updated_at
+) VALUES (
+    $1, $2, 0, $3
+)
+ON CONFLICT (product_id) DO UPDATE
+SET on_hand = EXCLUDED.on_hand,
+    updated_at = EXCLUDED.updated_at
+WHERE inventory.reserved <= EXCLUDED.on_hand;
+
+-- name: ReserveStock :execrows
+UPDATE inventory
+SET reserved = reserved + sqlc.arg(quantity)::int,
+    updated_at = sqlc.arg(updated_at)
+WHERE product_id = sqlc.arg(product_id)
+  AND on_hand - reserved >= sqlc.arg(quantity)::int;
+
+-- name: ReleaseStock :execrows
+UPDATE inventory
+SET reserved = reserved - sqlc.arg(quantity)::int,
+    updated_at = sqlc.arg(updated_at)
+WHERE product_id = sqlc.arg(product_id)
+  AND reserved >= sqlc.arg(quantity)::int;
+
+-- name: CommitStock :execrows
+UPDATE inventory
+SET on_hand = on_hand - sqlc.arg(quantity)::int,
+    reserved = reserved - sqlc.arg(quantity)::int,
+    updated_at = sqlc.arg(updated_at)
+WHERE product_id = sqlc.arg(product_id)
+  AND reserved >= sqlc.arg(quantity)::int;
@@ -3,11 +3,12 @@ INSERT INTO products (
     name,
     description,
     price,
+    currency,
     category_id,
     created_at,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, NOW(), NOW()
+    $1, $2, $3, $4, $5, NOW(), NOW()
 ) RETURNING *;
 
 -- name: GetProduct :one
@@ -19,7 +20,8 @@ WHERE
     (NULLIF(TRIM($1), '') IS NULL OR name ILIKE '%' || $1 || '%' OR description ILIKE '%' || $1 || '%')
     AND ($2 = 0 OR category_id = $2)
     AND ($3 = 0 OR price >= $3)
-    AND ($4 = 0 OR price <= $4);
+    AND ($4 = 0 OR price <= $4)
+    AND ($5::text = '' OR currency = $5::text);
 
 -- name: GetProducts :many
 SELECT * FROM products
@@ -28,14 +30,15 @@ WHERE
     AND ($2 = 0 OR category_id = $2)
     AND ($3 = 0 OR price >= $3)
     AND ($4 = 0 OR price <= $4)
+    AND ($5::text = '' OR currency = $5::text)
 ORDER BY
-    CASE $5::text
+    CASE $6::text
         WHEN 'price_asc' THEN price
         WHEN 'price_desc' THEN price * -1
         ELSE extract(epoch from created_at) * -1
     END,
     id DESC
-LIMIT $6 OFFSET $7;
+LIMIT $7 OFFSET $8;
 
 -- name: GetCategory :one
 SELECT * FROM categories WHERE id = $1;
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"mallbots/modules/product/domain/entities"
+	"mallbots/modules/product/domain/interfaces"
+	"mallbots/modules/product/infrastructure/query/gen"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type inventoryRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewInventoryRepository(db *pgxpool.Pool) interfaces.InventoryRepository {
+	return &inventoryRepository{db: db}
+}
+
+func (r *inventoryRepository) GetByProductID(ctx context.Context, productID int32) (*entities.Inventory, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	inventory, err := queries.GetInventory(ctx, productID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrInventoryNotFound
+		}
+		return nil, err
+	}
+
+	return toInventory(inventory), nil
+}
+
+func (r *inventoryRepository) GetByProductIDs(ctx context.Context, productIDs []int32) (map[int32]*entities.Inventory, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	inventories, err := queries.GetInventoriesByProductIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int32]*entities.Inventory, len(inventories))
+	for _, inventory := range inventories {
+		result[inventory.ProductID] = toInventory(inventory)
+	}
+
+	return result, nil
+}
+
+func (r *inventoryRepository) SetOnHand(ctx context.Context, productID, onHand int32) (bool, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	rows, err := queries.UpsertInventoryOnHand(ctx, gen.UpsertInventoryOnHandParams{
+		ProductID: productID,
+		OnHand:    onHand,
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return false, errorx.ErrCannotUpdateInventory
+	}
+
+	return rows == 1, nil
+}
+
+func (r *inventoryRepository) Reserve(ctx context.Context, productID, quantity int32) (bool, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	rows, err := queries.ReserveStock(ctx, gen.ReserveStockParams{
+		Quantity:  quantity,
+		UpdatedAt: time.Now(),
+		ProductID: productID,
+	})
+	if err != nil {
+		return false, errorx.ErrCannotUpdateInventory
+	}
+
+	return rows == 1, nil
+}
+
+func (r *inventoryRepository) Release(ctx context.Context, productID, quantity int32) (bool, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	rows, err := queries.ReleaseStock(ctx, gen.ReleaseStockParams{
+		Quantity:  quantity,
+		UpdatedAt: time.Now(),
+		ProductID: productID,
+	})
+	if err != nil {
+		return false, errorx.ErrCannotUpdateInventory
+	}
+
+	return rows == 1, nil
+}
+
+func (r *inventoryRepository) Commit(ctx context.Context, productID, quantity int32) (bool, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	rows, err := queries.CommitStock(ctx, gen.CommitStockParams{
+		Quantity:  quantity,
+		UpdatedAt: time.Now(),
+		ProductID: productID,
+	})
+	if err != nil {
+		return false, errorx.ErrCannotUpdateInventory
+	}
+
+	return rows == 1, nil
+}
+
+func toInventory(inventory *gen.Inventory) *entities.Inventory {
+	return &entities.Inventory{
+		ProductID: inventory.ProductID,
+		OnHand:    inventory.OnHand,
+		Reserved:  inventory.Reserved,
+		UpdatedAt: inventory.UpdatedAt,
+	}
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"mallbots/shared/errorx"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventoryRepository(t *testing.T) {
+	db := createTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	repo := NewInventoryRepository(db)
+
+	t.Run("Reserve Release And Commit", func(t *testing.T) {
+		reserved, err := repo.Reserve(ctx, 1, 30)
+		require.NoError(t, err)
+		require.True(t, reserved)
+
+		released, err := repo.Release(ctx, 1, 10)
+		require.NoError(t, err)
+		require.True(t, released)
+
+		committed, err := repo.Commit(ctx, 1, 20)
+		require.NoError(t, err)
+		require.True(t, committed)
+
+		inventory, err := repo.GetByProductID(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, int32(80), inventory.OnHand)
+		require.Equal(t, int32(0), inventory.Reserved)
+	})
+
+	t.Run("Reserve Never Oversells", func(t *testing.T) {
+		ok, err := repo.SetOnHand(ctx, 2, 5)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		var wg sync.WaitGroup
+		var succeeded atomic.Int32
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if reserved, err := repo.Reserve(ctx, 2, 1); err == nil && reserved {
+					succeeded.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		require.Equal(t, int32(5), succeeded.Load())
+
+		inventory, err := repo.GetByProductID(ctx, 2)
+		require.NoError(t, err)
+		require.Equal(t, int32(0), inventory.Available())
+	})
+
+	t.Run("Set On Hand Below Reserved", func(t *testing.T) {
+		ok, err := repo.SetOnHand(ctx, 2, 3)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("Get By Product IDs", func(t *testing.T) {
+		inventories, err := repo.GetByProductIDs(ctx, []int32{1, 2, 99999})
+		require.NoError(t, err)
+		require.Len(t, inventories, 2)
+	})
+
+	t.Run("Get Non-existent Inventory", func(t *testing.T) {
+		_, err := repo.GetByProductID(ctx, 99999)
+		require.ErrorIs(t, err, errorx.ErrInventoryNotFound)
+	})
+}
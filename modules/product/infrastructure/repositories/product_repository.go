@@ -5,7 +5,11 @@ import (
 	"mallbots/modules/product/domain/entities"
 	"mallbots/modules/product/domain/interfaces"
 	"mallbots/modules/product/infrastructure/query/gen"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/phathdt/service-context/core"
 )
@@ -19,7 +23,7 @@ func NewProductRepository(db *pgxpool.Pool) interfaces.ProductRepository {
 }
 
 func (r *productRepository) GetProducts(ctx context.Context, filter *interfaces.ProductFilter, paging *core.Paging) ([]*entities.Product, error) {
-	queries := gen.New(r.db)
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
 
 	offset := (paging.Page - 1) * paging.Limit
 
@@ -29,14 +33,19 @@ func (r *productRepository) GetProducts(ctx context.Context, filter *interfaces.
 		categoryID = *filter.Category
 	}
 
-	minPrice := float64(0)
+	minPrice := money.Minor(0)
 	if filter.MinPrice != nil {
-		minPrice = *filter.MinPrice
+		minPrice = filter.MinPrice.Minor()
 	}
 
-	maxPrice := float64(0)
+	maxPrice := money.Minor(0)
 	if filter.MaxPrice != nil {
-		maxPrice = *filter.MaxPrice
+		maxPrice = filter.MaxPrice.Minor()
+	}
+
+	currency := ""
+	if filter.Currency != nil {
+		currency = filter.Currency.String()
 	}
 
 	// Get total count for pagination
@@ -45,6 +54,7 @@ func (r *productRepository) GetProducts(ctx context.Context, filter *interfaces.
 		Column2: categoryID,
 		Column3: minPrice,
 		Column4: maxPrice,
+		Column5: currency,
 	})
 	if err != nil {
 		return nil, err
@@ -57,7 +67,8 @@ func (r *productRepository) GetProducts(ctx context.Context, filter *interfaces.
 		Column2: categoryID,
 		Column3: minPrice,
 		Column4: maxPrice,
-		Column5: filter.SortBy,
+		Column5: currency,
+		Column6: filter.SortBy,
 		Limit:   int32(paging.Limit),
 		Offset:  int32(offset),
 	})
@@ -65,16 +76,49 @@ func (r *productRepository) GetProducts(ctx context.Context, filter *interfaces.
 		return nil, err
 	}
 
+	ids := make([]int32, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+
+	inventories, err := queries.GetInventoriesByProductIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	stock := make(map[int32]int32, len(inventories))
+	for _, inventory := range inventories {
+		stock[inventory.ProductID] = inventory.OnHand - inventory.Reserved
+	}
+
+	categoryIDs := make([]int32, len(products))
+	for i, p := range products {
+		categoryIDs[i] = p.CategoryID
+	}
+
+	categories, err := queries.GetCategoriesByIds(ctx, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryNames := make(map[int32]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+
 	result := make([]*entities.Product, len(products))
 	for i, p := range products {
 		result[i] = &entities.Product{
-			ID:          p.ID,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       p.Price,
-			CategoryID:  p.CategoryID,
-			CreatedAt:   p.CreatedAt,
-			UpdatedAt:   p.UpdatedAt,
+			ID:           p.ID,
+			Name:         p.Name,
+			Description:  p.Description,
+			Price:        p.Price.In(p.Currency),
+			CategoryID:   p.CategoryID,
+			CategoryName: categoryNames[p.CategoryID],
+			Weight:       p.Weight,
+			Stock:        stock[p.ID],
+			CreatedAt:    p.CreatedAt,
+			UpdatedAt:    p.UpdatedAt,
 		}
 	}
 
@@ -82,20 +126,40 @@ func (r *productRepository) GetProducts(ctx context.Context, filter *interfaces.
 }
 
 func (r *productRepository) GetProduct(ctx context.Context, id int32) (*entities.Product, error) {
-	queries := gen.New(r.db)
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
 
 	product, err := queries.GetProduct(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	// Products that were never counted have no inventory row and no stock
+	var stock int32
+	inventory, err := queries.GetInventory(ctx, id)
+	if err == nil {
+		stock = inventory.OnHand - inventory.Reserved
+	} else if err != pgx.ErrNoRows {
+		return nil, err
+	}
+
+	category, err := queries.GetCategory(ctx, product.CategoryID)
 	if err != nil {
 		return nil, err
 	}
 
 	return &entities.Product{
-		ID:          product.ID,
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price,
-		CategoryID:  product.CategoryID,
-		CreatedAt:   product.CreatedAt,
-		UpdatedAt:   product.UpdatedAt,
+		ID:           product.ID,
+		Name:         product.Name,
+		Description:  product.Description,
+		Price:        product.Price.In(product.Currency),
+		CategoryID:   product.CategoryID,
+		CategoryName: category.Name,
+		Weight:       product.Weight,
+		Stock:        stock,
+		CreatedAt:    product.CreatedAt,
+		UpdatedAt:    product.UpdatedAt,
 	}, nil
 }
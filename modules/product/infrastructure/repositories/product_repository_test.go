@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"mallbots/modules/product/domain/interfaces"
+	"mallbots/shared/money"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/phathdt/service-context/core"
@@ -81,7 +82,9 @@ func TestGetProduct(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, product)
 	require.Equal(t, "iPhone 15 Pro", product.Name)
-	require.Equal(t, 999.99, product.Price)
+	require.Equal(t, "Smartphones", product.CategoryName)
+	require.Equal(t, money.MustParse("999.99", money.USD), product.Price)
+	require.Equal(t, int32(100), product.Stock)
 }
 
 func TestGetProducts(t *testing.T) {
@@ -100,8 +103,9 @@ func TestGetProducts(t *testing.T) {
 		{
 			name: "Filter by price range",
 			filter: &interfaces.ProductFilter{
-				MinPrice: &[]float64{900.0}[0],
-				MaxPrice: &[]float64{1000.0}[0],
+				MinPrice: &[]money.Money{money.MustParse("900", money.USD)}[0],
+				MaxPrice: &[]money.Money{money.MustParse("1000", money.USD)}[0],
+				Currency: &[]money.Currency{money.USD}[0],
 			},
 			paging: &core.Paging{
 				Page:  1,
@@ -136,7 +140,8 @@ func TestGetProducts(t *testing.T) {
 		{
 			name: "Sort by price ascending",
 			filter: &interfaces.ProductFilter{
-				SortBy: "price_asc",
+				SortBy:   "price_asc",
+				Currency: &[]money.Currency{money.USD}[0],
 			},
 			paging: &core.Paging{
 				Page:  1,
@@ -159,7 +164,7 @@ func TestGetProducts(t *testing.T) {
 
 			if tc.filter.SortBy == "price_asc" {
 				for i := 0; i < len(products)-1; i++ {
-					require.LessOrEqual(t, products[i].Price, products[i+1].Price)
+					require.False(t, products[i+1].Price.LessThan(products[i].Price))
 				}
 			}
 		})
@@ -0,0 +1,57 @@
+package rest
+
+import (
+	"mallbots/modules/product/application/dto"
+	"mallbots/modules/product/domain/interfaces"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/phathdt/service-context/component/validation"
+	"github.com/phathdt/service-context/core"
+)
+
+type InventoryHandler struct {
+	service interfaces.InventoryService
+}
+
+func NewInventoryHandler(service interfaces.InventoryService) *InventoryHandler {
+	return &InventoryHandler{service: service}
+}
+
+func (h *InventoryHandler) GetInventory(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	inventory, err := h.service.GetInventory(c.Context(), int32(id))
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(inventory))
+}
+
+func (h *InventoryHandler) SetStock(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	var req dto.SetStockRequest
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := validation.Validate(req); err != nil {
+		panic(err)
+	}
+
+	inventory, err := h.service.SetStock(c.Context(), int32(id), &req)
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(inventory))
+}
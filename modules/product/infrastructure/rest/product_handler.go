@@ -3,6 +3,7 @@ package rest
 import (
 	"mallbots/modules/product/application/dto"
 	"mallbots/modules/product/domain/interfaces"
+	"mallbots/shared/common"
 	"net/http"
 	"strconv"
 
@@ -32,6 +33,10 @@ func (h *ProductHandler) GetProducts(c *fiber.Ctx) error {
 
 	rp.Paging.Process()
 
+	if rp.Currency == "" {
+		rp.Currency = c.Get(common.HeaderAcceptCurrency)
+	}
+
 	products, err := h.service.GetProducts(c.Context(), &rp.ProductListRequest, &rp.Paging)
 	if err != nil {
 		panic(err)
@@ -46,7 +51,9 @@ func (h *ProductHandler) GetProduct(c *fiber.Ctx) error {
 		panic(err)
 	}
 
-	product, err := h.service.GetProduct(c.Context(), int32(id))
+	currency := c.Query("currency", c.Get(common.HeaderAcceptCurrency))
+
+	product, err := h.service.GetProductIn(c.Context(), int32(id), currency)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,35 @@
+package subscribers
+
+import (
+	"context"
+	"mallbots/modules/order/domain/events"
+	"mallbots/modules/product/application/dto"
+	"mallbots/modules/product/domain/interfaces"
+	"mallbots/plugins/eventbus"
+)
+
+// OrderSubscriber keeps inventory in step with the order lifecycle
+type OrderSubscriber struct {
+	inventory interfaces.InventoryService
+}
+
+func NewOrderSubscriber(inventory interfaces.InventoryService) *OrderSubscriber {
+	return &OrderSubscriber{inventory: inventory}
+}
+
+func (s *OrderSubscriber) Register(bus eventbus.Bus) {
+	bus.Subscribe(events.OrderCancelledEvent, s.releaseCancelledStock)
+}
+
+// releaseCancelledStock runs inside the cancellation transaction, so the stock
+// only comes back if the cancellation commits
+func (s *OrderSubscriber) releaseCancelledStock(ctx context.Context, event eventbus.Event) error {
+	cancelled := event.(*events.OrderCancelled)
+
+	lines := make([]dto.StockLine, 0, len(cancelled.Items))
+	for _, item := range cancelled.Items {
+		lines = append(lines, dto.StockLine{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+
+	return s.inventory.Release(ctx, lines)
+}
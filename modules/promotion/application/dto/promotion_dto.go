@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"encoding/json"
+	"mallbots/shared/money"
+	"time"
+)
+
+type CreateCouponRequest struct {
+	Code string `json:"code" validate:"required,max=64"`
+	Type string `json:"type" validate:"required"`
+	// Value is the percentage off of PERCENTAGE and BUY_X_GET_Y coupons
+	Value float64 `json:"value" validate:"min=0"`
+	// Amount and MinSpend are decimals in Currency, strings or numbers
+	Amount       json.Number `json:"amount"`
+	MinSpend     json.Number `json:"min_spend"`
+	Currency     string      `json:"currency"`
+	BuyQuantity  int32       `json:"buy_quantity" validate:"min=0"`
+	GetQuantity  int32       `json:"get_quantity" validate:"min=0"`
+	ProductIDs   []int32     `json:"product_ids"`
+	CategoryIDs  []int32     `json:"category_ids"`
+	UsageLimit   *int32      `json:"usage_limit" validate:"omitempty,min=1"`
+	PerUserLimit *int32      `json:"per_user_limit" validate:"omitempty,min=1"`
+	StartsAt     *time.Time  `json:"starts_at"`
+	EndsAt       *time.Time  `json:"ends_at"`
+}
+
+type CouponResponse struct {
+	ID           int32        `json:"id"`
+	Code         string       `json:"code"`
+	Type         string       `json:"type"`
+	Value        float64      `json:"value,omitempty"`
+	Amount       *money.Money `json:"amount,omitempty"`
+	Currency     string       `json:"currency"`
+	BuyQuantity  int32        `json:"buy_quantity,omitempty"`
+	GetQuantity  int32        `json:"get_quantity,omitempty"`
+	MinSpend     money.Money  `json:"min_spend"`
+	ProductIDs   []int32      `json:"product_ids"`
+	CategoryIDs  []int32      `json:"category_ids"`
+	UsageLimit   *int32       `json:"usage_limit,omitempty"`
+	PerUserLimit *int32       `json:"per_user_limit,omitempty"`
+	UsedCount    int32        `json:"used_count"`
+	StartsAt     *time.Time   `json:"starts_at,omitempty"`
+	EndsAt       *time.Time   `json:"ends_at,omitempty"`
+	Active       bool         `json:"active"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+type ApplyCouponRequest struct {
+	Code string `json:"code" validate:"required"`
+	// Currency prices the discount, the base currency when empty
+	Currency string `json:"currency"`
+}
+
+type ItemDiscountResponse struct {
+	ProductID      int32       `json:"product_id"`
+	DiscountAmount money.Money `json:"discount_amount"`
+}
+
+type CartCouponResponse struct {
+	Code           string                 `json:"code"`
+	Type           string                 `json:"type"`
+	Currency       string                 `json:"currency"`
+	DiscountAmount money.Money            `json:"discount_amount"`
+	FreeShipping   bool                   `json:"free_shipping"`
+	Items          []ItemDiscountResponse `json:"items"`
+}
+
+// DiscountLine is one cart line a coupon may discount
+type DiscountLine struct {
+	ProductID  int32
+	CategoryID int32
+	Price      money.Money
+	Quantity   int32
+}
+
+// DiscountRequest is priced in Currency, which every amount of it is in
+type DiscountRequest struct {
+	Currency       money.Currency
+	Lines          []DiscountLine
+	ShippingAmount money.Money
+}
+
+type DiscountResult struct {
+	CouponID int32
+	Code     string
+	Type     string
+	// LineDiscounts follow the order of the request lines
+	LineDiscounts    []money.Money
+	ShippingDiscount money.Money
+	// Amount is the whole discount, shipping included
+	Amount money.Money
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"mallbots/modules/promotion/application/dto"
+	"mallbots/modules/promotion/domain/constants"
+	"mallbots/modules/promotion/domain/entities"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"sort"
+
+	"github.com/phathdt/service-context/core"
+)
+
+// priceCoupon works out what the coupon takes off the request. The line
+// discounts always add up to the total.
+func priceCoupon(coupon *entities.Coupon, req dto.DiscountRequest) (*dto.DiscountResult, error) {
+	subtotal := money.Zero(req.Currency)
+	for _, line := range req.Lines {
+		subtotal = subtotal.Add(line.Price.Mul(int64(line.Quantity)))
+	}
+	if subtotal.LessThan(coupon.MinSpend) {
+		return nil, core.ErrBadRequest.
+			WithError(errorx.ErrCouponMinimumSpendNotMet.Error()).
+			WithReasonf("coupon %s needs a spend of at least %s", coupon.Code, coupon.MinSpend)
+	}
+
+	result := &dto.DiscountResult{
+		CouponID:         coupon.ID,
+		Code:             coupon.Code,
+		Type:             coupon.Type.String(),
+		LineDiscounts:    make([]money.Money, len(req.Lines)),
+		ShippingDiscount: money.Zero(req.Currency),
+	}
+	for i := range result.LineDiscounts {
+		result.LineDiscounts[i] = money.Zero(req.Currency)
+	}
+
+	switch coupon.Type {
+	case constants.CouponTypePercentage:
+		for i, line := range req.Lines {
+			if coupon.Targets(line.ProductID, line.CategoryID) {
+				result.LineDiscounts[i] = line.Price.Mul(int64(line.Quantity)).Percent(coupon.Value)
+			}
+		}
+	case constants.CouponTypeFixedAmount:
+		splitFixed(coupon, req, result.LineDiscounts)
+	case constants.CouponTypeFreeShipping:
+		result.ShippingDiscount = req.ShippingAmount
+	case constants.CouponTypeBuyXGetY:
+		splitBuyXGetY(coupon, req.Lines, result.LineDiscounts)
+	}
+
+	result.Amount = money.Sum(req.Currency, result.LineDiscounts...).Add(result.ShippingDiscount)
+
+	// A free shipping coupon stays on the cart even when shipping is already
+	// free, the other types must discount something
+	if result.Amount.IsZero() && coupon.Type != constants.CouponTypeFreeShipping {
+		return nil, core.ErrBadRequest.
+			WithError(errorx.ErrCouponNotApplicable.Error()).
+			WithReasonf("coupon %s does not apply to any item in the cart", coupon.Code)
+	}
+
+	return result, nil
+}
+
+// splitFixed spreads the fixed amount over the targeted lines in proportion
+// to their value. The rounding remainder goes to the largest line.
+func splitFixed(coupon *entities.Coupon, req dto.DiscountRequest, discounts []money.Money) {
+	eligible := money.Zero(req.Currency)
+	weights := make([]int64, len(req.Lines))
+	for i, line := range req.Lines {
+		if !coupon.Targets(line.ProductID, line.CategoryID) {
+			continue
+		}
+		amount := line.Price.Mul(int64(line.Quantity))
+		eligible = eligible.Add(amount)
+		weights[i] = int64(amount.Minor())
+	}
+	if !eligible.IsPositive() {
+		return
+	}
+
+	copy(discounts, money.Min(coupon.Amount, eligible).Allocate(weights))
+}
+
+// splitBuyXGetY gives the cheapest GetQuantity units of every
+// BuyQuantity + GetQuantity targeted units Value percent off. The free units
+// are counted per line, cheapest line first, so the work does not grow with
+// the quantities ordered.
+func splitBuyXGetY(coupon *entities.Coupon, lines []dto.DiscountLine, discounts []money.Money) {
+	group := int64(coupon.BuyQuantity) + int64(coupon.GetQuantity)
+	if group == 0 {
+		return
+	}
+
+	var targeted []int
+	var units int64
+	for i, line := range lines {
+		if !coupon.Targets(line.ProductID, line.CategoryID) || line.Quantity <= 0 {
+			continue
+		}
+		targeted = append(targeted, i)
+		units += int64(line.Quantity)
+	}
+	free := units / group * int64(coupon.GetQuantity)
+
+	sort.SliceStable(targeted, func(a, b int) bool { return lines[targeted[a]].Price.LessThan(lines[targeted[b]].Price) })
+	for _, i := range targeted {
+		if free == 0 {
+			return
+		}
+		n := min(free, int64(lines[i].Quantity))
+		discounts[i] = discounts[i].Add(lines[i].Price.Percent(coupon.Value).Mul(n))
+		free -= n
+	}
+}
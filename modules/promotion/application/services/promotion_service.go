@@ -0,0 +1,435 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	cartInterfaces "mallbots/modules/cart/domain/interfaces"
+	currencyInterfaces "mallbots/modules/currency/domain/interfaces"
+	productDto "mallbots/modules/product/application/dto"
+	productInterfaces "mallbots/modules/product/domain/interfaces"
+	"mallbots/modules/promotion/application/dto"
+	"mallbots/modules/promotion/domain/constants"
+	"mallbots/modules/promotion/domain/entities"
+	"mallbots/modules/promotion/domain/interfaces"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"strings"
+	"time"
+
+	"github.com/phathdt/service-context/core"
+)
+
+type promotionService struct {
+	couponRepo     interfaces.CouponRepository
+	cartService    cartInterfaces.CartService
+	productService productInterfaces.ProductService
+	currencies     currencyInterfaces.CurrencyService
+}
+
+func NewPromotionService(
+	couponRepo interfaces.CouponRepository,
+	cartService cartInterfaces.CartService,
+	productService productInterfaces.ProductService,
+	currencies currencyInterfaces.CurrencyService,
+) interfaces.PromotionService {
+	return &promotionService{
+		couponRepo:     couponRepo,
+		cartService:    cartService,
+		productService: productService,
+		currencies:     currencies,
+	}
+}
+
+func (s *promotionService) CreateCoupon(ctx context.Context, req *dto.CreateCouponRequest) (*dto.CouponResponse, error) {
+	couponType := constants.CouponType(strings.ToUpper(req.Type))
+	if !couponType.IsValid() {
+		return nil, core.ErrBadRequest.
+			WithError(errorx.ErrInvalidCoupon.Error()).
+			WithReasonf("unknown coupon type %s", req.Type)
+	}
+
+	currency := money.DefaultCurrency
+	if req.Currency != "" {
+		parsed, err := money.ParseCurrency(req.Currency)
+		if err != nil {
+			return nil, core.ErrBadRequest.
+				WithError(errorx.ErrInvalidCurrency.Error()).
+				WithReasonf("unknown currency %s", req.Currency)
+		}
+		currency = parsed
+	}
+
+	amount, err := parseCouponAmount("amount", req.Amount, currency)
+	if err != nil {
+		return nil, err
+	}
+	minSpend, err := parseCouponAmount("min_spend", req.MinSpend, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	coupon := &entities.Coupon{
+		Code:         normalizeCode(req.Code),
+		Type:         couponType,
+		Value:        req.Value,
+		Amount:       amount,
+		BuyQuantity:  req.BuyQuantity,
+		GetQuantity:  req.GetQuantity,
+		MinSpend:     minSpend,
+		ProductIDs:   req.ProductIDs,
+		CategoryIDs:  req.CategoryIDs,
+		UsageLimit:   req.UsageLimit,
+		PerUserLimit: req.PerUserLimit,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		Active:       true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if coupon.ProductIDs == nil {
+		coupon.ProductIDs = []int32{}
+	}
+	if coupon.CategoryIDs == nil {
+		coupon.CategoryIDs = []int32{}
+	}
+	// Buy X get Y gives the Y units away unless told otherwise
+	if coupon.Type == constants.CouponTypeBuyXGetY && coupon.Value == 0 {
+		coupon.Value = 100
+	}
+
+	if err := validateCoupon(coupon); err != nil {
+		return nil, err
+	}
+
+	created, err := s.couponRepo.Create(ctx, coupon)
+	if err != nil {
+		if errors.Is(err, errorx.ErrCouponCodeTaken) {
+			return nil, core.ErrConflict.
+				WithError(errorx.ErrCouponCodeTaken.Error()).
+				WithReasonf("coupon %s already exists", coupon.Code)
+		}
+		return nil, err
+	}
+
+	return toCouponResponse(created), nil
+}
+
+func (s *promotionService) ListCoupons(ctx context.Context, paging *core.Paging) ([]*dto.CouponResponse, error) {
+	coupons, err := s.couponRepo.List(ctx, paging)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.CouponResponse, 0, len(coupons))
+	for _, coupon := range coupons {
+		responses = append(responses, toCouponResponse(coupon))
+	}
+
+	return responses, nil
+}
+
+func (s *promotionService) ApplyCoupon(ctx context.Context, userID int32, req *dto.ApplyCouponRequest) (*dto.CartCouponResponse, error) {
+	coupon, err := s.couponRepo.GetByCode(ctx, normalizeCode(req.Code))
+	if err != nil {
+		return nil, wrapCouponNotFound(err)
+	}
+
+	now := time.Now()
+	if err := s.checkRedeemable(ctx, coupon, userID, now); err != nil {
+		return nil, err
+	}
+
+	currency, err := s.currencies.ParseCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	cartReq, err := s.cartDiscountRequest(ctx, userID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	priced, err := s.couponIn(ctx, coupon, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := priceCoupon(priced, cartReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.couponRepo.SetCartCoupon(ctx, userID, coupon.ID, now); err != nil {
+		return nil, err
+	}
+
+	response := &dto.CartCouponResponse{
+		Code:           coupon.Code,
+		Type:           coupon.Type.String(),
+		Currency:       cartReq.Currency.String(),
+		DiscountAmount: result.Amount,
+		FreeShipping:   coupon.Type == constants.CouponTypeFreeShipping,
+		Items:          make([]dto.ItemDiscountResponse, 0, len(cartReq.Lines)),
+	}
+	for i, line := range cartReq.Lines {
+		if result.LineDiscounts[i].IsPositive() {
+			response.Items = append(response.Items, dto.ItemDiscountResponse{
+				ProductID:      line.ProductID,
+				DiscountAmount: result.LineDiscounts[i],
+			})
+		}
+	}
+
+	return response, nil
+}
+
+func (s *promotionService) RemoveCoupon(ctx context.Context, userID int32) error {
+	return s.couponRepo.ClearCartCoupon(ctx, userID)
+}
+
+func (s *promotionService) PriceCartCoupon(ctx context.Context, userID int32, req dto.DiscountRequest) (*dto.DiscountResult, error) {
+	coupon, err := s.couponRepo.GetCartCoupon(ctx, userID)
+	if err != nil {
+		if errors.Is(err, errorx.ErrCouponNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// The coupon may have expired or run out since it was applied
+	if err := s.checkRedeemable(ctx, coupon, userID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	coupon, err = s.couponIn(ctx, coupon, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return priceCoupon(coupon, req)
+}
+
+func (s *promotionService) Redeem(ctx context.Context, userID, orderID int32, discount *dto.DiscountResult) error {
+	now := time.Now()
+
+	// The conditional increment locks the coupon row, so concurrent checkouts
+	// with the same coupon queue up here and see each other's redemptions
+	ok, err := s.couponRepo.IncrementUsage(ctx, discount.CouponID, now)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return core.ErrConflict.
+			WithError(errorx.ErrCouponUsageLimitReached.Error()).
+			WithReasonf("coupon %s has been used up", discount.Code)
+	}
+
+	coupon, err := s.couponRepo.GetByID(ctx, discount.CouponID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkUserLimit(ctx, coupon, userID); err != nil {
+		return err
+	}
+
+	if err := s.couponRepo.CreateRedemption(ctx, &entities.CouponRedemption{
+		CouponID:       discount.CouponID,
+		UserID:         userID,
+		OrderID:        orderID,
+		DiscountAmount: discount.Amount,
+		CreatedAt:      now,
+	}); err != nil {
+		return err
+	}
+
+	return s.couponRepo.ClearCartCoupon(ctx, userID)
+}
+
+func (s *promotionService) ReleaseOrder(ctx context.Context, orderID int32) error {
+	redemption, err := s.couponRepo.DeleteRedemptionByOrderID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, errorx.ErrCouponRedemptionNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return s.couponRepo.DecrementUsage(ctx, redemption.CouponID, time.Now())
+}
+
+// checkRedeemable rejects a coupon the user cannot use right now
+func (s *promotionService) checkRedeemable(ctx context.Context, coupon *entities.Coupon, userID int32, now time.Time) error {
+	if !coupon.IsRedeemableAt(now) {
+		return core.ErrBadRequest.
+			WithError(errorx.ErrCouponNotActive.Error()).
+			WithReasonf("coupon %s is not valid at this time", coupon.Code)
+	}
+
+	if coupon.UsageLimit != nil && coupon.UsedCount >= *coupon.UsageLimit {
+		return core.ErrConflict.
+			WithError(errorx.ErrCouponUsageLimitReached.Error()).
+			WithReasonf("coupon %s has been used up", coupon.Code)
+	}
+
+	return s.checkUserLimit(ctx, coupon, userID)
+}
+
+func (s *promotionService) checkUserLimit(ctx context.Context, coupon *entities.Coupon, userID int32) error {
+	if coupon.PerUserLimit == nil {
+		return nil
+	}
+
+	used, err := s.couponRepo.CountUserRedemptions(ctx, coupon.ID, userID)
+	if err != nil {
+		return err
+	}
+	if used >= int64(*coupon.PerUserLimit) {
+		return core.ErrConflict.
+			WithError(errorx.ErrCouponUserLimitReached.Error()).
+			WithReasonf("coupon %s can be used %d time(s) per customer", coupon.Code, *coupon.PerUserLimit)
+	}
+
+	return nil
+}
+
+// couponIn returns the coupon with its amounts in the currency, converted at
+// the current rates when the coupon was made out in another one
+func (s *promotionService) couponIn(ctx context.Context, coupon *entities.Coupon, currency money.Currency) (*entities.Coupon, error) {
+	if coupon.MinSpend.Currency() == currency {
+		return coupon, nil
+	}
+
+	exchange, err := s.currencies.Exchange(ctx, coupon.MinSpend.Currency(), currency)
+	if err != nil {
+		return nil, err
+	}
+
+	converted := *coupon
+	converted.Amount = exchange.Convert(coupon.Amount, currency)
+	converted.MinSpend = exchange.Convert(coupon.MinSpend, currency)
+	return &converted, nil
+}
+
+// cartDiscountRequest prices the user's cart at current product prices,
+// converted into the currency
+func (s *promotionService) cartDiscountRequest(ctx context.Context, userID int32, currency money.Currency) (dto.DiscountRequest, error) {
+	cartItems, err := s.cartService.GetItems(ctx, userID)
+	if err != nil {
+		return dto.DiscountRequest{}, err
+	}
+	if len(cartItems) == 0 {
+		return dto.DiscountRequest{}, core.ErrBadRequest.WithError(errorx.ErrCartEmpty.Error())
+	}
+
+	products := make([]*productDto.ProductResponse, 0, len(cartItems))
+	currencies := []money.Currency{currency}
+	for _, item := range cartItems {
+		product, err := s.productService.GetProduct(ctx, item.ProductID)
+		if err != nil {
+			return dto.DiscountRequest{}, err
+		}
+		products = append(products, product)
+		currencies = append(currencies, product.Price.Currency())
+	}
+
+	exchange, err := s.currencies.Exchange(ctx, currencies...)
+	if err != nil {
+		return dto.DiscountRequest{}, err
+	}
+
+	req := dto.DiscountRequest{
+		Currency: currency,
+		Lines:    make([]dto.DiscountLine, 0, len(cartItems)),
+	}
+	for i, item := range cartItems {
+		req.Lines = append(req.Lines, dto.DiscountLine{
+			ProductID:  item.ProductID,
+			CategoryID: products[i].CategoryID,
+			Price:      exchange.Convert(products[i].Price, currency),
+			Quantity:   item.Quantity,
+		})
+	}
+
+	return req, nil
+}
+
+func validateCoupon(coupon *entities.Coupon) error {
+	var reason string
+	switch {
+	case coupon.Code == "":
+		reason = "code is required"
+	case coupon.Type == constants.CouponTypePercentage && (coupon.Value <= 0 || coupon.Value > 100):
+		reason = "percentage must be above 0 and at most 100"
+	case coupon.Type == constants.CouponTypeFixedAmount && !coupon.Amount.IsPositive():
+		reason = "fixed amount must be above 0"
+	case coupon.MinSpend.IsNegative():
+		reason = "min_spend cannot be negative"
+	case coupon.Type == constants.CouponTypeBuyXGetY && (coupon.BuyQuantity < 1 || coupon.GetQuantity < 1):
+		reason = "buy and get quantities must be at least 1"
+	case coupon.Type == constants.CouponTypeBuyXGetY && coupon.Value > 100:
+		reason = "percentage off the free units must be at most 100"
+	case coupon.StartsAt != nil && coupon.EndsAt != nil && !coupon.EndsAt.After(*coupon.StartsAt):
+		reason = "ends_at must be after starts_at"
+	default:
+		return nil
+	}
+
+	return core.ErrBadRequest.
+		WithError(errorx.ErrInvalidCoupon.Error()).
+		WithReason(reason)
+}
+
+// parseCouponAmount reads an optional decimal of the coupon's currency
+func parseCouponAmount(field string, value json.Number, currency money.Currency) (money.Money, error) {
+	if value == "" {
+		return money.Zero(currency), nil
+	}
+
+	amount, err := money.Parse(value.String(), currency)
+	if err != nil {
+		return money.Money{}, core.ErrBadRequest.
+			WithError(errorx.ErrInvalidAmount.Error()).
+			WithReasonf("%s %q is not a valid %s amount", field, value, currency)
+	}
+	return amount, nil
+}
+
+// wrapCouponNotFound turns a missing coupon into a 404 response error
+func wrapCouponNotFound(err error) error {
+	if errors.Is(err, errorx.ErrCouponNotFound) {
+		return core.ErrNotFound.WithError(errorx.ErrCouponNotFound.Error())
+	}
+	return err
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func toCouponResponse(coupon *entities.Coupon) *dto.CouponResponse {
+	response := &dto.CouponResponse{
+		ID:           coupon.ID,
+		Code:         coupon.Code,
+		Type:         coupon.Type.String(),
+		Value:        coupon.Value,
+		Currency:     coupon.MinSpend.Currency().String(),
+		BuyQuantity:  coupon.BuyQuantity,
+		GetQuantity:  coupon.GetQuantity,
+		MinSpend:     coupon.MinSpend,
+		ProductIDs:   coupon.ProductIDs,
+		CategoryIDs:  coupon.CategoryIDs,
+		UsageLimit:   coupon.UsageLimit,
+		PerUserLimit: coupon.PerUserLimit,
+		UsedCount:    coupon.UsedCount,
+		StartsAt:     coupon.StartsAt,
+		EndsAt:       coupon.EndsAt,
+		Active:       coupon.Active,
+		CreatedAt:    coupon.CreatedAt,
+	}
+	if coupon.Type == constants.CouponTypeFixedAmount {
+		response.Amount = &coupon.Amount
+	}
+	return response
+}
@@ -0,0 +1,543 @@
+package services
+
+import (
+	"context"
+	cartDto "mallbots/modules/cart/application/dto"
+	currencyServices "mallbots/modules/currency/application/services"
+	currencyEntities "mallbots/modules/currency/domain/entities"
+	productDto "mallbots/modules/product/application/dto"
+	"mallbots/modules/promotion/application/dto"
+	"mallbots/modules/promotion/domain/constants"
+	"mallbots/modules/promotion/domain/entities"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/phathdt/service-context/core"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// usd is a test amount in US dollars
+func usd(amount string) money.Money {
+	return money.MustParse(amount, money.USD)
+}
+
+type MockCouponRepository struct {
+	mock.Mock
+}
+
+func (m *MockCouponRepository) Create(ctx context.Context, coupon *entities.Coupon) (*entities.Coupon, error) {
+	args := m.Called(ctx, coupon)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Coupon), args.Error(1)
+}
+
+func (m *MockCouponRepository) List(ctx context.Context, paging *core.Paging) ([]*entities.Coupon, error) {
+	args := m.Called(ctx, paging)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Coupon), args.Error(1)
+}
+
+func (m *MockCouponRepository) GetByID(ctx context.Context, id int32) (*entities.Coupon, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Coupon), args.Error(1)
+}
+
+func (m *MockCouponRepository) GetByCode(ctx context.Context, code string) (*entities.Coupon, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Coupon), args.Error(1)
+}
+
+func (m *MockCouponRepository) IncrementUsage(ctx context.Context, id int32, at time.Time) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCouponRepository) DecrementUsage(ctx context.Context, id int32, at time.Time) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCouponRepository) CountUserRedemptions(ctx context.Context, couponID, userID int32) (int64, error) {
+	args := m.Called(ctx, couponID, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCouponRepository) CreateRedemption(ctx context.Context, redemption *entities.CouponRedemption) error {
+	args := m.Called(ctx, redemption)
+	return args.Error(0)
+}
+
+func (m *MockCouponRepository) DeleteRedemptionByOrderID(ctx context.Context, orderID int32) (*entities.CouponRedemption, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.CouponRedemption), args.Error(1)
+}
+
+func (m *MockCouponRepository) GetCartCoupon(ctx context.Context, userID int32) (*entities.Coupon, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Coupon), args.Error(1)
+}
+
+func (m *MockCouponRepository) SetCartCoupon(ctx context.Context, userID, couponID int32, at time.Time) error {
+	args := m.Called(ctx, userID, couponID)
+	return args.Error(0)
+}
+
+func (m *MockCouponRepository) ClearCartCoupon(ctx context.Context, userID int32) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type MockCartService struct {
+	mock.Mock
+}
+
+func (m *MockCartService) AddItem(ctx context.Context, userID int32, req *cartDto.CartItemRequest) (*cartDto.CartItemResponse, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cartDto.CartItemResponse), args.Error(1)
+}
+
+func (m *MockCartService) UpdateQuantity(ctx context.Context, userID int32, req *cartDto.CartItemRequest) (*cartDto.CartItemResponse, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cartDto.CartItemResponse), args.Error(1)
+}
+
+func (m *MockCartService) RemoveItem(ctx context.Context, userID, productID int32) error {
+	args := m.Called(ctx, userID, productID)
+	return args.Error(0)
+}
+
+func (m *MockCartService) RemoveAllItems(ctx context.Context, userID int32) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockCartService) GetItems(ctx context.Context, userID int32) ([]*cartDto.CartItemResponse, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*cartDto.CartItemResponse), args.Error(1)
+}
+
+func (m *MockCartService) GetItemsIn(ctx context.Context, userID int32, currency string) ([]*cartDto.CartItemResponse, error) {
+	args := m.Called(ctx, userID, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*cartDto.CartItemResponse), args.Error(1)
+}
+
+type MockProductService struct {
+	mock.Mock
+}
+
+func (m *MockProductService) GetProducts(ctx context.Context, req *productDto.ProductListRequest, paging *core.Paging) ([]*productDto.ProductResponse, error) {
+	args := m.Called(ctx, req, paging)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*productDto.ProductResponse), args.Error(1)
+}
+
+func (m *MockProductService) GetProduct(ctx context.Context, id int32) (*productDto.ProductResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*productDto.ProductResponse), args.Error(1)
+}
+
+func (m *MockProductService) GetProductIn(ctx context.Context, id int32, currency string) (*productDto.ProductResponse, error) {
+	args := m.Called(ctx, id, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*productDto.ProductResponse), args.Error(1)
+}
+
+type MockExchangeRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateRepository) Create(ctx context.Context, rate *currencyEntities.ExchangeRate) (*currencyEntities.ExchangeRate, error) {
+	args := m.Called(ctx, rate)
+	return args.Get(0).(*currencyEntities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) GetLatest(ctx context.Context, currencies []money.Currency) ([]*currencyEntities.ExchangeRate, error) {
+	args := m.Called(ctx, currencies)
+	return args.Get(0).([]*currencyEntities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) ListLatest(ctx context.Context) ([]*currencyEntities.ExchangeRate, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*currencyEntities.ExchangeRate), args.Error(1)
+}
+
+func requireAppError(t *testing.T, err error, status int, cause error) {
+	t.Helper()
+
+	var appErr *core.DefaultError
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, status, appErr.StatusCode())
+	require.Equal(t, cause.Error(), appErr.Error())
+}
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
+
+func TestPriceCoupon(t *testing.T) {
+	// Product 1 is in category 1, products 2 and 3 in category 2
+	lines := []dto.DiscountLine{
+		{ProductID: 1, CategoryID: 1, Price: usd("10.00"), Quantity: 2},
+		{ProductID: 2, CategoryID: 2, Price: usd("5.00"), Quantity: 3},
+		{ProductID: 3, CategoryID: 2, Price: usd("1.99"), Quantity: 1},
+	}
+	req := dto.DiscountRequest{Currency: money.USD, Lines: lines, ShippingAmount: usd("4.99")}
+
+	testCases := []struct {
+		name          string
+		coupon        entities.Coupon
+		lineDiscounts []money.Money
+		shipping      money.Money
+		amount        money.Money
+	}{
+		{
+			name:          "Percentage off every item",
+			coupon:        entities.Coupon{Type: constants.CouponTypePercentage, Value: 10},
+			lineDiscounts: []money.Money{usd("2.00"), usd("1.50"), usd("0.20")},
+			amount:        usd("3.70"),
+		},
+		{
+			name:          "Percentage off a category",
+			coupon:        entities.Coupon{Type: constants.CouponTypePercentage, Value: 50, CategoryIDs: []int32{2}},
+			lineDiscounts: []money.Money{usd("0.00"), usd("7.50"), usd("1.00")},
+			amount:        usd("8.50"),
+		},
+		{
+			name:          "Fixed amount split by line value",
+			coupon:        entities.Coupon{Type: constants.CouponTypeFixedAmount, Amount: usd("10"), ProductIDs: []int32{1, 2}},
+			lineDiscounts: []money.Money{usd("5.72"), usd("4.28"), usd("0.00")},
+			amount:        usd("10.00"),
+		},
+		{
+			name:          "Fixed amount capped at the targeted items",
+			coupon:        entities.Coupon{Type: constants.CouponTypeFixedAmount, Amount: usd("50"), ProductIDs: []int32{3}},
+			lineDiscounts: []money.Money{usd("0.00"), usd("0.00"), usd("1.99")},
+			amount:        usd("1.99"),
+		},
+		{
+			name:          "Free shipping",
+			coupon:        entities.Coupon{Type: constants.CouponTypeFreeShipping},
+			lineDiscounts: []money.Money{usd("0.00"), usd("0.00"), usd("0.00")},
+			shipping:      usd("4.99"),
+			amount:        usd("4.99"),
+		},
+		{
+			name:          "Buy two get the cheapest free",
+			coupon:        entities.Coupon{Type: constants.CouponTypeBuyXGetY, Value: 100, BuyQuantity: 2, GetQuantity: 1, CategoryIDs: []int32{2}},
+			lineDiscounts: []money.Money{usd("0.00"), usd("0.00"), usd("1.99")},
+			amount:        usd("1.99"),
+		},
+		{
+			name:          "Buy one get one half price",
+			coupon:        entities.Coupon{Type: constants.CouponTypeBuyXGetY, Value: 50, BuyQuantity: 1, GetQuantity: 1},
+			lineDiscounts: []money.Money{usd("0.00"), usd("5.00"), usd("1.00")},
+			amount:        usd("6.00"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			coupon := tc.coupon
+			coupon.Code = "TEST"
+
+			result, err := priceCoupon(&coupon, req)
+			require.NoError(t, err)
+			require.Equal(t, tc.lineDiscounts, result.LineDiscounts)
+			require.Equal(t, tc.shipping.String(), result.ShippingDiscount.String())
+			require.Equal(t, tc.amount, result.Amount)
+		})
+	}
+
+	t.Run("Buy two get one free across large quantities", func(t *testing.T) {
+		coupon := &entities.Coupon{Code: "BULK", Type: constants.CouponTypeBuyXGetY, Value: 100, BuyQuantity: 2, GetQuantity: 1}
+		bulk := dto.DiscountRequest{Currency: money.USD, Lines: []dto.DiscountLine{
+			{ProductID: 1, CategoryID: 1, Price: usd("10.00"), Quantity: 1_000_000},
+			{ProductID: 3, CategoryID: 2, Price: usd("1.99"), Quantity: 3},
+		}}
+
+		// 333334 units are free, the three cheap ones first
+		result, err := priceCoupon(coupon, bulk)
+		require.NoError(t, err)
+		require.Equal(t, []money.Money{usd("3333310.00"), usd("5.97")}, result.LineDiscounts)
+		require.Equal(t, usd("3333315.97"), result.Amount)
+	})
+
+	t.Run("Minimum spend not met", func(t *testing.T) {
+		coupon := &entities.Coupon{Code: "BIG", Type: constants.CouponTypePercentage, Value: 10, MinSpend: usd("100")}
+
+		_, err := priceCoupon(coupon, req)
+		requireAppError(t, err, http.StatusBadRequest, errorx.ErrCouponMinimumSpendNotMet)
+	})
+
+	t.Run("No targeted item in the cart", func(t *testing.T) {
+		coupon := &entities.Coupon{Code: "OTHER", Type: constants.CouponTypePercentage, Value: 10, ProductIDs: []int32{99}}
+
+		_, err := priceCoupon(coupon, req)
+		requireAppError(t, err, http.StatusBadRequest, errorx.ErrCouponNotApplicable)
+	})
+}
+
+type testSuite struct {
+	couponRepo     *MockCouponRepository
+	cartService    *MockCartService
+	productService *MockProductService
+	rateRepo       *MockExchangeRateRepository
+	service        *promotionService
+	ctx            context.Context
+}
+
+func setupTest() *testSuite {
+	couponRepo := new(MockCouponRepository)
+	cartService := new(MockCartService)
+	productService := new(MockProductService)
+	rateRepo := new(MockExchangeRateRepository)
+	currencies := currencyServices.NewCurrencyService(rateRepo, nil)
+
+	return &testSuite{
+		couponRepo:     couponRepo,
+		cartService:    cartService,
+		productService: productService,
+		rateRepo:       rateRepo,
+		service:        NewPromotionService(couponRepo, cartService, productService, currencies).(*promotionService),
+		ctx:            context.Background(),
+	}
+}
+
+func TestPromotionService(t *testing.T) {
+	userID := int32(1)
+	activeCoupon := func() *entities.Coupon {
+		return &entities.Coupon{ID: 7, Code: "SAVE10", Type: constants.CouponTypePercentage, Value: 10, MinSpend: usd("0"), Active: true}
+	}
+
+	t.Run("Create Coupon - Normalizes Code", func(t *testing.T) {
+		ts := setupTest()
+
+		ts.couponRepo.On("Create", ts.ctx, mock.MatchedBy(func(coupon *entities.Coupon) bool {
+			return coupon.Code == "SUMMER" && coupon.Type == constants.CouponTypeBuyXGetY && coupon.Value == 100
+		})).Return(&entities.Coupon{ID: 1, Code: "SUMMER", Type: constants.CouponTypeBuyXGetY}, nil)
+
+		coupon, err := ts.service.CreateCoupon(ts.ctx, &dto.CreateCouponRequest{
+			Code:        " summer ",
+			Type:        "buy_x_get_y",
+			BuyQuantity: 2,
+			GetQuantity: 1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "SUMMER", coupon.Code)
+	})
+
+	t.Run("Create Coupon - Invalid Percentage", func(t *testing.T) {
+		ts := setupTest()
+
+		_, err := ts.service.CreateCoupon(ts.ctx, &dto.CreateCouponRequest{Code: "TOO-MUCH", Type: "PERCENTAGE", Value: 120})
+		requireAppError(t, err, http.StatusBadRequest, errorx.ErrInvalidCoupon)
+		ts.couponRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Create Coupon - Fixed Amount", func(t *testing.T) {
+		ts := setupTest()
+
+		ts.couponRepo.On("Create", ts.ctx, mock.MatchedBy(func(coupon *entities.Coupon) bool {
+			return coupon.Amount == money.MustParse("5.50", money.EUR) && coupon.MinSpend == money.MustParse("30", money.EUR)
+		})).Return(&entities.Coupon{ID: 1, Code: "FIVE", Type: constants.CouponTypeFixedAmount}, nil)
+
+		_, err := ts.service.CreateCoupon(ts.ctx, &dto.CreateCouponRequest{
+			Code:     "five",
+			Type:     "FIXED_AMOUNT",
+			Amount:   "5.50",
+			MinSpend: "30",
+			Currency: "eur",
+		})
+		require.NoError(t, err)
+
+		_, err = ts.service.CreateCoupon(ts.ctx, &dto.CreateCouponRequest{Code: "FIVE", Type: "FIXED_AMOUNT", Amount: "5.505"})
+		requireAppError(t, err, http.StatusBadRequest, errorx.ErrInvalidAmount)
+	})
+
+	t.Run("Create Coupon - Code Taken", func(t *testing.T) {
+		ts := setupTest()
+
+		ts.couponRepo.On("Create", ts.ctx, mock.Anything).Return(nil, errorx.ErrCouponCodeTaken)
+
+		_, err := ts.service.CreateCoupon(ts.ctx, &dto.CreateCouponRequest{Code: "SAVE10", Type: "PERCENTAGE", Value: 10})
+		requireAppError(t, err, http.StatusConflict, errorx.ErrCouponCodeTaken)
+	})
+
+	t.Run("Apply Coupon - Success", func(t *testing.T) {
+		ts := setupTest()
+
+		ts.couponRepo.On("GetByCode", ts.ctx, "SAVE10").Return(activeCoupon(), nil)
+		ts.cartService.On("GetItems", ts.ctx, userID).Return([]*cartDto.CartItemResponse{
+			{ProductID: 1, Quantity: 2, Price: usd("9.00")},
+		}, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(1)).
+			Return(&productDto.ProductResponse{ID: 1, Price: usd("10.00"), CategoryID: 1}, nil)
+		ts.couponRepo.On("SetCartCoupon", ts.ctx, userID, int32(7)).Return(nil)
+
+		applied, err := ts.service.ApplyCoupon(ts.ctx, userID, &dto.ApplyCouponRequest{Code: "save10"})
+		require.NoError(t, err)
+		require.Equal(t, &dto.CartCouponResponse{
+			Code:           "SAVE10",
+			Type:           "PERCENTAGE",
+			Currency:       "USD",
+			DiscountAmount: usd("2.00"),
+			Items:          []dto.ItemDiscountResponse{{ProductID: 1, DiscountAmount: usd("2.00")}},
+		}, applied)
+
+		ts.couponRepo.AssertExpectations(t)
+	})
+
+	t.Run("Apply Coupon - In Another Currency", func(t *testing.T) {
+		ts := setupTest()
+
+		coupon := &entities.Coupon{ID: 8, Code: "TENOFF", Type: constants.CouponTypeFixedAmount, Amount: usd("10"), MinSpend: usd("20"), Active: true}
+		ts.couponRepo.On("GetByCode", ts.ctx, "TENOFF").Return(coupon, nil)
+		ts.cartService.On("GetItems", ts.ctx, userID).Return([]*cartDto.CartItemResponse{
+			{ProductID: 1, Quantity: 1, Price: usd("30.00")},
+		}, nil)
+		ts.productService.On("GetProduct", ts.ctx, int32(1)).
+			Return(&productDto.ProductResponse{ID: 1, Price: usd("30.00"), CategoryID: 1}, nil)
+		ts.rateRepo.On("GetLatest", ts.ctx, []money.Currency{money.EUR}).Return([]*currencyEntities.ExchangeRate{
+			{ID: 2, Currency: money.EUR, Rate: money.MustParseRate("0.92")},
+		}, nil)
+		ts.couponRepo.On("SetCartCoupon", ts.ctx, userID, int32(8)).Return(nil)
+
+		applied, err := ts.service.ApplyCoupon(ts.ctx, userID, &dto.ApplyCouponRequest{Code: "TENOFF", Currency: "EUR"})
+		require.NoError(t, err)
+		require.Equal(t, "EUR", applied.Currency)
+		require.Equal(t, "9.20", applied.DiscountAmount.String())
+	})
+
+	t.Run("Apply Coupon - Unknown Code", func(t *testing.T) {
+		ts := setupTest()
+
+		ts.couponRepo.On("GetByCode", ts.ctx, "NOPE").Return(nil, errorx.ErrCouponNotFound)
+
+		_, err := ts.service.ApplyCoupon(ts.ctx, userID, &dto.ApplyCouponRequest{Code: "nope"})
+		requireAppError(t, err, http.StatusNotFound, errorx.ErrCouponNotFound)
+	})
+
+	t.Run("Apply Coupon - Expired", func(t *testing.T) {
+		ts := setupTest()
+
+		coupon := activeCoupon()
+		ended := time.Now().Add(-time.Hour)
+		coupon.EndsAt = &ended
+		ts.couponRepo.On("GetByCode", ts.ctx, "SAVE10").Return(coupon, nil)
+
+		_, err := ts.service.ApplyCoupon(ts.ctx, userID, &dto.ApplyCouponRequest{Code: "SAVE10"})
+		requireAppError(t, err, http.StatusBadRequest, errorx.ErrCouponNotActive)
+		ts.couponRepo.AssertNotCalled(t, "SetCartCoupon", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Apply Coupon - Used Up By The Customer", func(t *testing.T) {
+		ts := setupTest()
+
+		coupon := activeCoupon()
+		coupon.PerUserLimit = int32Ptr(1)
+		ts.couponRepo.On("GetByCode", ts.ctx, "SAVE10").Return(coupon, nil)
+		ts.couponRepo.On("CountUserRedemptions", ts.ctx, int32(7), userID).Return(int64(1), nil)
+
+		_, err := ts.service.ApplyCoupon(ts.ctx, userID, &dto.ApplyCouponRequest{Code: "SAVE10"})
+		requireAppError(t, err, http.StatusConflict, errorx.ErrCouponUserLimitReached)
+	})
+
+	t.Run("Price Cart Coupon - No Coupon", func(t *testing.T) {
+		ts := setupTest()
+
+		ts.couponRepo.On("GetCartCoupon", ts.ctx, userID).Return(nil, errorx.ErrCouponNotFound)
+
+		result, err := ts.service.PriceCartCoupon(ts.ctx, userID, dto.DiscountRequest{})
+		require.NoError(t, err)
+		require.Nil(t, result)
+	})
+
+	t.Run("Redeem - Success", func(t *testing.T) {
+		ts := setupTest()
+
+		coupon := activeCoupon()
+		coupon.PerUserLimit = int32Ptr(2)
+		ts.couponRepo.On("IncrementUsage", ts.ctx, int32(7)).Return(true, nil)
+		ts.couponRepo.On("GetByID", ts.ctx, int32(7)).Return(coupon, nil)
+		ts.couponRepo.On("CountUserRedemptions", ts.ctx, int32(7), userID).Return(int64(1), nil)
+		ts.couponRepo.On("CreateRedemption", ts.ctx, mock.MatchedBy(func(redemption *entities.CouponRedemption) bool {
+			return redemption.OrderID == 42 && redemption.DiscountAmount == usd("3.50")
+		})).Return(nil)
+		ts.couponRepo.On("ClearCartCoupon", ts.ctx, userID).Return(nil)
+
+		err := ts.service.Redeem(ts.ctx, userID, 42, &dto.DiscountResult{CouponID: 7, Code: "SAVE10", Amount: usd("3.50")})
+		require.NoError(t, err)
+
+		ts.couponRepo.AssertExpectations(t)
+	})
+
+	t.Run("Redeem - Global Limit Reached", func(t *testing.T) {
+		ts := setupTest()
+
+		ts.couponRepo.On("IncrementUsage", ts.ctx, int32(7)).Return(false, nil)
+
+		err := ts.service.Redeem(ts.ctx, userID, 42, &dto.DiscountResult{CouponID: 7, Code: "SAVE10", Amount: usd("3.50")})
+		requireAppError(t, err, http.StatusConflict, errorx.ErrCouponUsageLimitReached)
+		ts.couponRepo.AssertNotCalled(t, "CreateRedemption", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Release Order - Gives The Use Back", func(t *testing.T) {
+		ts := setupTest()
+
+		ts.couponRepo.On("DeleteRedemptionByOrderID", ts.ctx, int32(42)).
+			Return(&entities.CouponRedemption{CouponID: 7, OrderID: 42}, nil)
+		ts.couponRepo.On("DecrementUsage", ts.ctx, int32(7)).Return(nil)
+
+		require.NoError(t, ts.service.ReleaseOrder(ts.ctx, 42))
+		ts.couponRepo.AssertExpectations(t)
+	})
+
+	t.Run("Release Order - No Coupon Used", func(t *testing.T) {
+		ts := setupTest()
+
+		ts.couponRepo.On("DeleteRedemptionByOrderID", ts.ctx, int32(42)).
+			Return(nil, errorx.ErrCouponRedemptionNotFound)
+
+		require.NoError(t, ts.service.ReleaseOrder(ts.ctx, 42))
+		ts.couponRepo.AssertNotCalled(t, "DecrementUsage", mock.Anything, mock.Anything)
+	})
+}
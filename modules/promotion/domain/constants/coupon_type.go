@@ -0,0 +1,31 @@
+package constants
+
+// CouponType is how a coupon discounts a cart
+type CouponType string
+
+const (
+	// CouponTypePercentage takes Value percent off the targeted items
+	CouponTypePercentage CouponType = "PERCENTAGE"
+	// CouponTypeFixedAmount takes Value off the targeted items
+	CouponTypeFixedAmount CouponType = "FIXED_AMOUNT"
+	// CouponTypeFreeShipping waives the shipping fee
+	CouponTypeFreeShipping CouponType = "FREE_SHIPPING"
+	// CouponTypeBuyXGetY takes Value percent off the cheapest GetQuantity
+	// units of every BuyQuantity + GetQuantity targeted units
+	CouponTypeBuyXGetY CouponType = "BUY_X_GET_Y"
+)
+
+// IsValid checks if the coupon type is valid
+func (t CouponType) IsValid() bool {
+	switch t {
+	case CouponTypePercentage, CouponTypeFixedAmount,
+		CouponTypeFreeShipping, CouponTypeBuyXGetY:
+		return true
+	}
+	return false
+}
+
+// String returns the string representation of the CouponType
+func (t CouponType) String() string {
+	return string(t)
+}
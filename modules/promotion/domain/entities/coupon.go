@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"mallbots/modules/promotion/domain/constants"
+	"mallbots/shared/money"
+	"slices"
+	"time"
+)
+
+type Coupon struct {
+	ID   int32
+	Code string
+	Type constants.CouponType
+	// Value is the percentage off of PERCENTAGE and BUY_X_GET_Y coupons
+	Value float64
+	// Amount is what a FIXED_AMOUNT coupon takes off
+	Amount      money.Money
+	BuyQuantity int32
+	GetQuantity int32
+	MinSpend    money.Money
+	// ProductIDs and CategoryIDs target the coupon, it applies to every item
+	// when both are empty
+	ProductIDs   []int32
+	CategoryIDs  []int32
+	UsageLimit   *int32
+	PerUserLimit *int32
+	UsedCount    int32
+	StartsAt     *time.Time
+	EndsAt       *time.Time
+	Active       bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// IsRedeemableAt reports whether the coupon is enabled and inside its
+// validity window
+func (c *Coupon) IsRedeemableAt(now time.Time) bool {
+	if !c.Active {
+		return false
+	}
+	if c.StartsAt != nil && now.Before(*c.StartsAt) {
+		return false
+	}
+	if c.EndsAt != nil && !now.Before(*c.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// Targets reports whether the coupon applies to a product
+func (c *Coupon) Targets(productID, categoryID int32) bool {
+	if len(c.ProductIDs) == 0 && len(c.CategoryIDs) == 0 {
+		return true
+	}
+	return slices.Contains(c.ProductIDs, productID) || slices.Contains(c.CategoryIDs, categoryID)
+}
+
+// CouponRedemption is one use of a coupon by an order
+type CouponRedemption struct {
+	ID             int32
+	CouponID       int32
+	UserID         int32
+	OrderID        int32
+	DiscountAmount money.Money
+	CreatedAt      time.Time
+}
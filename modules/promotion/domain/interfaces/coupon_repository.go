@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/promotion/domain/entities"
+	"time"
+
+	"github.com/phathdt/service-context/core"
+)
+
+type CouponRepository interface {
+	Create(ctx context.Context, coupon *entities.Coupon) (*entities.Coupon, error)
+	List(ctx context.Context, paging *core.Paging) ([]*entities.Coupon, error)
+	GetByID(ctx context.Context, id int32) (*entities.Coupon, error)
+	GetByCode(ctx context.Context, code string) (*entities.Coupon, error)
+	// IncrementUsage counts one more use unless the global limit is reached,
+	// and holds the coupon row lock until the transaction ends
+	IncrementUsage(ctx context.Context, id int32, at time.Time) (bool, error)
+	DecrementUsage(ctx context.Context, id int32, at time.Time) error
+	CountUserRedemptions(ctx context.Context, couponID, userID int32) (int64, error)
+	CreateRedemption(ctx context.Context, redemption *entities.CouponRedemption) error
+	DeleteRedemptionByOrderID(ctx context.Context, orderID int32) (*entities.CouponRedemption, error)
+	GetCartCoupon(ctx context.Context, userID int32) (*entities.Coupon, error)
+	SetCartCoupon(ctx context.Context, userID, couponID int32, at time.Time) error
+	ClearCartCoupon(ctx context.Context, userID int32) error
+}
@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/promotion/application/dto"
+
+	"github.com/phathdt/service-context/core"
+)
+
+type PromotionService interface {
+	CreateCoupon(ctx context.Context, req *dto.CreateCouponRequest) (*dto.CouponResponse, error)
+	ListCoupons(ctx context.Context, paging *core.Paging) ([]*dto.CouponResponse, error)
+	ApplyCoupon(ctx context.Context, userID int32, req *dto.ApplyCouponRequest) (*dto.CartCouponResponse, error)
+	RemoveCoupon(ctx context.Context, userID int32) error
+	// PriceCartCoupon works out the discount of the coupon applied to the
+	// user's cart, nil when there is none
+	PriceCartCoupon(ctx context.Context, userID int32, req dto.DiscountRequest) (*dto.DiscountResult, error)
+	// Redeem records the use of the discount by an order and takes the coupon
+	// off the cart. It must run in the checkout transaction.
+	Redeem(ctx context.Context, userID, orderID int32, discount *dto.DiscountResult) error
+	// ReleaseOrder gives back the coupon use of an order, if it made one
+	ReleaseOrder(ctx context.Context, orderID int32) error
+}
@@ -0,0 +1,49 @@
+//go:build wireinject
+
+package di
+
+import (
+	cartService "mallbots/modules/cart/application/services"
+	cartRepo "mallbots/modules/cart/infrastructure/repositories"
+	currencyService "mallbots/modules/currency/application/services"
+	currencyRepo "mallbots/modules/currency/infrastructure/repositories"
+	productService "mallbots/modules/product/application/services"
+	productRepo "mallbots/modules/product/infrastructure/repositories"
+	"mallbots/modules/promotion/application/services"
+	"mallbots/modules/promotion/infrastructure/repositories"
+	"mallbots/modules/promotion/infrastructure/rest"
+	"mallbots/modules/promotion/infrastructure/subscribers"
+	ruleService "mallbots/modules/rules/application/services"
+	"mallbots/plugins/outbox"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/config"
+
+	"github.com/google/wire"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var PromotionSet = wire.NewSet(
+	pgxc.NewTxManager,
+	outbox.NewWriter,
+	currencyRepo.NewExchangeRateRepository,
+	currencyService.NewCurrencyService,
+	productRepo.NewProductRepository,
+	productService.NewProductService,
+	ruleService.NewRuleEngine,
+	cartRepo.NewCartRepository,
+	cartService.NewCartService,
+	repositories.NewCouponRepository,
+	services.NewPromotionService,
+	rest.NewPromotionHandler,
+	subscribers.NewOrderSubscriber,
+)
+
+func InitializePromotionHandler(db *pgxpool.Pool, cfg *config.Config) (*rest.PromotionHandler, error) {
+	wire.Build(PromotionSet)
+	return &rest.PromotionHandler{}, nil
+}
+
+func InitializeOrderSubscriber(db *pgxpool.Pool, cfg *config.Config) (*subscribers.OrderSubscriber, error) {
+	wire.Build(PromotionSet)
+	return &subscribers.OrderSubscriber{}, nil
+}
@@ -0,0 +1,89 @@
+-- name: CreateCoupon :one
+INSERT INTO coupons (
+    code,
+    type,
+    value,
+    amount,
+    currency,
+    buy_quantity,
+    get_quantity,
+    min_spend,
+    product_ids,
+    category_ids,
+    usage_limit,
+    per_user_limit,
+    starts_at,
+    ends_at,
+    active,
+    created_at,
+    updated_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+) RETURNING *;
+
+-- name: GetCouponByID :one
+SELECT * FROM coupons WHERE id = $1;
+
+-- name: GetCouponByCode :one
+SELECT * FROM coupons WHERE code = $1;
+
+-- name: ListCoupons :many
+SELECT * FROM coupons
+ORDER BY id DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CountCoupons :one
+SELECT COUNT(*) FROM coupons;
+
+-- name: IncrementCouponUsage :execrows
+UPDATE coupons
+SET used_count = used_count + 1,
+    updated_at = $2
+WHERE id = $1
+  AND (usage_limit IS NULL OR used_count < usage_limit);
+
+-- name: DecrementCouponUsage :exec
+UPDATE coupons
+SET used_count = used_count - 1,
+    updated_at = $2
+WHERE id = $1
+  AND used_count > 0;
+
+-- name: CountUserCouponRedemptions :one
+SELECT COUNT(*) FROM coupon_redemptions
+WHERE coupon_id = $1 AND user_id = $2;
+
+-- name: CreateCouponRedemption :one
+INSERT INTO coupon_redemptions (
+    coupon_id,
+    user_id,
+    order_id,
+    discount_amount,
+    currency,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: DeleteCouponRedemptionByOrderID :one
+DELETE FROM coupon_redemptions
+WHERE order_id = $1
+RETURNING *;
+
+-- name: GetCartCoupon :one
+SELECT * FROM cart_coupons WHERE user_id = $1;
+
+-- name: UpsertCartCoupon :exec
+INSERT INTO cart_coupons (
+    user_id,
+    coupon_id,
+    applied_at
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (user_id) DO UPDATE
+SET coupon_id = EXCLUDED.coupon_id,
+    applied_at = EXCLUDED.applied_at;
+
+-- name: DeleteCartCoupon :exec
+DELETE FROM cart_coupons WHERE user_id = $1;
@@ -0,0 +1,241 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mallbots/modules/promotion/domain/constants"
+	"mallbots/modules/promotion/domain/entities"
+	"mallbots/modules/promotion/domain/interfaces"
+	"mallbots/modules/promotion/infrastructure/query/gen"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/phathdt/service-context/core"
+)
+
+const uniqueViolation = "23505"
+
+type couponRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCouponRepository(db *pgxpool.Pool) interfaces.CouponRepository {
+	return &couponRepository{db: db}
+}
+
+func (r *couponRepository) Create(ctx context.Context, coupon *entities.Coupon) (*entities.Coupon, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbCoupon, err := queries.CreateCoupon(ctx, gen.CreateCouponParams{
+		Code:         coupon.Code,
+		Type:         coupon.Type.String(),
+		Value:        coupon.Value,
+		Amount:       coupon.Amount.Minor(),
+		Currency:     coupon.MinSpend.Currency(),
+		BuyQuantity:  coupon.BuyQuantity,
+		GetQuantity:  coupon.GetQuantity,
+		MinSpend:     coupon.MinSpend.Minor(),
+		ProductIds:   coupon.ProductIDs,
+		CategoryIds:  coupon.CategoryIDs,
+		UsageLimit:   coupon.UsageLimit,
+		PerUserLimit: coupon.PerUserLimit,
+		StartsAt:     coupon.StartsAt,
+		EndsAt:       coupon.EndsAt,
+		Active:       coupon.Active,
+		CreatedAt:    coupon.CreatedAt,
+		UpdatedAt:    coupon.UpdatedAt,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return nil, errorx.ErrCouponCodeTaken
+		}
+		return nil, errorx.ErrCannotCreateCoupon
+	}
+
+	return toCoupon(dbCoupon), nil
+}
+
+func (r *couponRepository) List(ctx context.Context, paging *core.Paging) ([]*entities.Coupon, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	total, err := queries.CountCoupons(ctx)
+	if err != nil {
+		return nil, err
+	}
+	paging.Total = total
+
+	offset := (paging.Page - 1) * paging.Limit
+
+	dbCoupons, err := queries.ListCoupons(ctx, gen.ListCouponsParams{
+		Limit:  int32(paging.Limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	coupons := make([]*entities.Coupon, 0, len(dbCoupons))
+	for _, dbCoupon := range dbCoupons {
+		coupons = append(coupons, toCoupon(dbCoupon))
+	}
+
+	return coupons, nil
+}
+
+func (r *couponRepository) GetByID(ctx context.Context, id int32) (*entities.Coupon, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbCoupon, err := queries.GetCouponByID(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrCouponNotFound
+		}
+		return nil, err
+	}
+
+	return toCoupon(dbCoupon), nil
+}
+
+func (r *couponRepository) GetByCode(ctx context.Context, code string) (*entities.Coupon, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbCoupon, err := queries.GetCouponByCode(ctx, code)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrCouponNotFound
+		}
+		return nil, err
+	}
+
+	return toCoupon(dbCoupon), nil
+}
+
+func (r *couponRepository) IncrementUsage(ctx context.Context, id int32, at time.Time) (bool, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	rows, err := queries.IncrementCouponUsage(ctx, gen.IncrementCouponUsageParams{
+		ID:        id,
+		UpdatedAt: at,
+	})
+	if err != nil {
+		return false, errorx.ErrCannotRedeemCoupon
+	}
+
+	return rows == 1, nil
+}
+
+func (r *couponRepository) DecrementUsage(ctx context.Context, id int32, at time.Time) error {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	return queries.DecrementCouponUsage(ctx, gen.DecrementCouponUsageParams{
+		ID:        id,
+		UpdatedAt: at,
+	})
+}
+
+func (r *couponRepository) CountUserRedemptions(ctx context.Context, couponID, userID int32) (int64, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	return queries.CountUserCouponRedemptions(ctx, gen.CountUserCouponRedemptionsParams{
+		CouponID: couponID,
+		UserID:   userID,
+	})
+}
+
+func (r *couponRepository) CreateRedemption(ctx context.Context, redemption *entities.CouponRedemption) error {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbRedemption, err := queries.CreateCouponRedemption(ctx, gen.CreateCouponRedemptionParams{
+		CouponID:       redemption.CouponID,
+		UserID:         redemption.UserID,
+		OrderID:        redemption.OrderID,
+		DiscountAmount: redemption.DiscountAmount.Minor(),
+		Currency:       redemption.DiscountAmount.Currency(),
+		CreatedAt:      redemption.CreatedAt,
+	})
+	if err != nil {
+		return errorx.ErrCannotRedeemCoupon
+	}
+
+	redemption.ID = dbRedemption.ID
+	return nil
+}
+
+func (r *couponRepository) DeleteRedemptionByOrderID(ctx context.Context, orderID int32) (*entities.CouponRedemption, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	dbRedemption, err := queries.DeleteCouponRedemptionByOrderID(ctx, orderID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrCouponRedemptionNotFound
+		}
+		return nil, err
+	}
+
+	return &entities.CouponRedemption{
+		ID:             dbRedemption.ID,
+		CouponID:       dbRedemption.CouponID,
+		UserID:         dbRedemption.UserID,
+		OrderID:        dbRedemption.OrderID,
+		DiscountAmount: dbRedemption.DiscountAmount.In(dbRedemption.Currency),
+		CreatedAt:      dbRedemption.CreatedAt,
+	}, nil
+}
+
+func (r *couponRepository) GetCartCoupon(ctx context.Context, userID int32) (*entities.Coupon, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	cartCoupon, err := queries.GetCartCoupon(ctx, userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrCouponNotFound
+		}
+		return nil, err
+	}
+
+	return r.GetByID(ctx, cartCoupon.CouponID)
+}
+
+func (r *couponRepository) SetCartCoupon(ctx context.Context, userID, couponID int32, at time.Time) error {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	return queries.UpsertCartCoupon(ctx, gen.UpsertCartCouponParams{
+		UserID:    userID,
+		CouponID:  couponID,
+		AppliedAt: at,
+	})
+}
+
+func (r *couponRepository) ClearCartCoupon(ctx context.Context, userID int32) error {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	return queries.DeleteCartCoupon(ctx, userID)
+}
+
+func toCoupon(dbCoupon *gen.Coupon) *entities.Coupon {
+	return &entities.Coupon{
+		ID:           dbCoupon.ID,
+		Code:         dbCoupon.Code,
+		Type:         constants.CouponType(dbCoupon.Type),
+		Value:        dbCoupon.Value,
+		Amount:       dbCoupon.Amount.In(dbCoupon.Currency),
+		BuyQuantity:  dbCoupon.BuyQuantity,
+		GetQuantity:  dbCoupon.GetQuantity,
+		MinSpend:     dbCoupon.MinSpend.In(dbCoupon.Currency),
+		ProductIDs:   dbCoupon.ProductIds,
+		CategoryIDs:  dbCoupon.CategoryIds,
+		UsageLimit:   dbCoupon.UsageLimit,
+		PerUserLimit: dbCoupon.PerUserLimit,
+		UsedCount:    dbCoupon.UsedCount,
+		StartsAt:     dbCoupon.StartsAt,
+		EndsAt:       dbCoupon.EndsAt,
+		Active:       dbCoupon.Active,
+		CreatedAt:    dbCoupon.CreatedAt,
+		UpdatedAt:    dbCoupon.UpdatedAt,
+	}
+}
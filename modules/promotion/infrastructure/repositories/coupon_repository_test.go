@@ -0,0 +1,251 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"mallbots/modules/promotion/domain/constants"
+	"mallbots/modules/promotion/domain/entities"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// usd is a test amount in US dollars
+func usd(amount string) money.Money {
+	return money.MustParse(amount, money.USD)
+}
+
+func createContainer(t *testing.T) (*postgres.PostgresContainer, error) {
+	ctx := context.Background()
+	dbUsername := "postgres"
+	dbPassword := "123123123"
+	dbName := "mallbots_test"
+
+	schemaFile := filepath.Join("../../../../schema.gen.sql")
+	seedFile := filepath.Join("../../../../seed.sql")
+
+	postgresContainer, err := postgres.Run(ctx,
+		"docker.io/postgres:16-alpine",
+		postgres.WithInitScripts(schemaFile, seedFile),
+		postgres.WithDatabase(dbName),
+		postgres.WithUsername(dbUsername),
+		postgres.WithPassword(dbPassword),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(5*time.Second)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start container: %w", err)
+	}
+
+	t.Cleanup(func() {
+		if err := postgresContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %v", err)
+		}
+	})
+
+	return postgresContainer, nil
+}
+
+func createTestDB(t *testing.T) *pgxpool.Pool {
+	ctx := context.Background()
+	container, err := createContainer(t)
+	require.NoError(t, err, "failed to create container")
+
+	connStr, err := container.ConnectionString(ctx)
+	require.NoError(t, err, "failed to get connection string")
+
+	poolConfig, err := pgxpool.ParseConfig(connStr)
+	require.NoError(t, err, "failed to parse connection string")
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	require.NoError(t, err, "failed to create connection pool")
+
+	err = pool.Ping(ctx)
+	require.NoError(t, err, "failed to ping database")
+
+	err = createTestUsers(ctx, pool)
+	require.NoError(t, err, "failed to create test users")
+
+	return pool
+}
+
+func createTestUsers(ctx context.Context, db *pgxpool.Pool) error {
+	testUsers := []struct {
+		email    string
+		password string
+		fullName string
+	}{
+		{"test1@example.com", "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy", "Test User 1"},
+		{"test2@example.com", "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy", "Test User 2"},
+	}
+
+	for _, user := range testUsers {
+		_, err := db.Exec(ctx,
+			"INSERT INTO users (email, password, full_name, created_at, updated_at) VALUES ($1, $2, $3, NOW(), NOW())",
+			user.email, user.password, user.fullName)
+		if err != nil {
+			return fmt.Errorf("failed to create test user: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func createTestOrder(t *testing.T, db *pgxpool.Pool, userID int32) int32 {
+	var id int32
+	err := db.QueryRow(context.Background(),
+		`INSERT INTO orders (user_id, total_amount, shipping_address, shipping_city, shipping_country, shipping_zip, updated_at)
+		VALUES ($1, 100, '123 Test St', 'Test City', 'Test Country', '12345', NOW()) RETURNING id`,
+		userID).Scan(&id)
+	require.NoError(t, err, "failed to create test order")
+	return id
+}
+
+func newTestCoupon(code string, usageLimit *int32) *entities.Coupon {
+	now := time.Now()
+	return &entities.Coupon{
+		Code:        code,
+		Type:        constants.CouponTypePercentage,
+		Value:       10,
+		ProductIDs:  []int32{1, 2},
+		CategoryIDs: []int32{},
+		UsageLimit:  usageLimit,
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+func TestCouponRepository(t *testing.T) {
+	db := createTestDB(t)
+	defer db.Close()
+
+	repo := NewCouponRepository(db)
+	ctx := context.Background()
+
+	t.Run("Create And Get By Code", func(t *testing.T) {
+		created, err := repo.Create(ctx, newTestCoupon("WELCOME10", nil))
+		require.NoError(t, err)
+		require.NotZero(t, created.ID)
+
+		coupon, err := repo.GetByCode(ctx, "WELCOME10")
+		require.NoError(t, err)
+		require.Equal(t, created.ID, coupon.ID)
+		require.Equal(t, constants.CouponTypePercentage, coupon.Type)
+		require.Equal(t, []int32{1, 2}, coupon.ProductIDs)
+		require.Nil(t, coupon.UsageLimit)
+	})
+
+	t.Run("Duplicate Code", func(t *testing.T) {
+		_, err := repo.Create(ctx, newTestCoupon("TWICE", nil))
+		require.NoError(t, err)
+
+		_, err = repo.Create(ctx, newTestCoupon("TWICE", nil))
+		require.ErrorIs(t, err, errorx.ErrCouponCodeTaken)
+	})
+
+	t.Run("Missing Coupon", func(t *testing.T) {
+		_, err := repo.GetByCode(ctx, "NOPE")
+		require.ErrorIs(t, err, errorx.ErrCouponNotFound)
+	})
+
+	t.Run("Cart Coupon", func(t *testing.T) {
+		coupon, err := repo.Create(ctx, newTestCoupon("CART", nil))
+		require.NoError(t, err)
+
+		_, err = repo.GetCartCoupon(ctx, 1)
+		require.ErrorIs(t, err, errorx.ErrCouponNotFound)
+
+		require.NoError(t, repo.SetCartCoupon(ctx, 1, coupon.ID, time.Now()))
+		applied, err := repo.GetCartCoupon(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "CART", applied.Code)
+
+		require.NoError(t, repo.ClearCartCoupon(ctx, 1))
+		_, err = repo.GetCartCoupon(ctx, 1)
+		require.ErrorIs(t, err, errorx.ErrCouponNotFound)
+	})
+
+	t.Run("Redemption Released", func(t *testing.T) {
+		limit := int32(1)
+		coupon, err := repo.Create(ctx, newTestCoupon("ONCE", &limit))
+		require.NoError(t, err)
+		orderID := createTestOrder(t, db, 1)
+
+		ok, err := repo.IncrementUsage(ctx, coupon.ID, time.Now())
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.NoError(t, repo.CreateRedemption(ctx, &entities.CouponRedemption{
+			CouponID: coupon.ID, UserID: 1, OrderID: orderID, DiscountAmount: usd("5"), CreatedAt: time.Now(),
+		}))
+
+		ok, err = repo.IncrementUsage(ctx, coupon.ID, time.Now())
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		count, err := repo.CountUserRedemptions(ctx, coupon.ID, 1)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+
+		redemption, err := repo.DeleteRedemptionByOrderID(ctx, orderID)
+		require.NoError(t, err)
+		require.NoError(t, repo.DecrementUsage(ctx, redemption.CouponID, time.Now()))
+
+		coupon, err = repo.GetByID(ctx, coupon.ID)
+		require.NoError(t, err)
+		require.Equal(t, int32(0), coupon.UsedCount)
+
+		_, err = repo.DeleteRedemptionByOrderID(ctx, orderID)
+		require.ErrorIs(t, err, errorx.ErrCouponRedemptionNotFound)
+	})
+
+	t.Run("Concurrent Redemptions Respect The Limit", func(t *testing.T) {
+		limit := int32(3)
+		coupon, err := repo.Create(ctx, newTestCoupon("RUSH", &limit))
+		require.NoError(t, err)
+
+		txManager := pgxc.NewTxManager(db)
+		orderIDs := make([]int32, 10)
+		for i := range orderIDs {
+			orderIDs[i] = createTestOrder(t, db, 2)
+		}
+
+		var wg sync.WaitGroup
+		for _, orderID := range orderIDs {
+			wg.Add(1)
+			go func(orderID int32) {
+				defer wg.Done()
+				_ = txManager.WithTx(ctx, func(ctx context.Context) error {
+					ok, err := repo.IncrementUsage(ctx, coupon.ID, time.Now())
+					if err != nil || !ok {
+						return errorx.ErrCouponUsageLimitReached
+					}
+					return repo.CreateRedemption(ctx, &entities.CouponRedemption{
+						CouponID: coupon.ID, UserID: 2, OrderID: orderID, DiscountAmount: usd("1"), CreatedAt: time.Now(),
+					})
+				})
+			}(orderID)
+		}
+		wg.Wait()
+
+		coupon, err = repo.GetByID(ctx, coupon.ID)
+		require.NoError(t, err)
+		require.Equal(t, limit, coupon.UsedCount)
+
+		count, err := repo.CountUserRedemptions(ctx, coupon.ID, 2)
+		require.NoError(t, err)
+		require.Equal(t, int64(limit), count)
+	})
+}
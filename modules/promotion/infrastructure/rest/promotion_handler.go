@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"mallbots/modules/promotion/application/dto"
+	"mallbots/modules/promotion/domain/interfaces"
+	"mallbots/shared/common"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/phathdt/service-context/component/validation"
+	"github.com/phathdt/service-context/core"
+)
+
+type PromotionHandler struct {
+	service interfaces.PromotionService
+}
+
+func NewPromotionHandler(service interfaces.PromotionService) *PromotionHandler {
+	return &PromotionHandler{service: service}
+}
+
+func (h *PromotionHandler) ApplyCoupon(c *fiber.Ctx) error {
+	var req dto.ApplyCouponRequest
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := validation.Validate(req); err != nil {
+		panic(err)
+	}
+
+	if req.Currency == "" {
+		req.Currency = c.Get(common.HeaderAcceptCurrency)
+	}
+
+	userID := c.Context().UserValue("userId").(int32)
+
+	coupon, err := h.service.ApplyCoupon(c.Context(), userID, &req)
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(coupon))
+}
+
+func (h *PromotionHandler) RemoveCoupon(c *fiber.Ctx) error {
+	userID := c.Context().UserValue("userId").(int32)
+
+	if err := h.service.RemoveCoupon(c.Context(), userID); err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(true))
+}
+
+func (h *PromotionHandler) CreateCoupon(c *fiber.Ctx) error {
+	var req dto.CreateCouponRequest
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := validation.Validate(req); err != nil {
+		panic(err)
+	}
+
+	coupon, err := h.service.CreateCoupon(c.Context(), &req)
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(core.SimpleSuccessResponse(coupon))
+}
+
+func (h *PromotionHandler) ListCoupons(c *fiber.Ctx) error {
+	type reqParam struct {
+		core.Paging
+	}
+
+	var rp reqParam
+	if err := c.QueryParser(&rp); err != nil {
+		panic(err)
+	}
+
+	rp.Paging.Process()
+
+	coupons, err := h.service.ListCoupons(c.Context(), &rp.Paging)
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.ResponseWithPaging(coupons, nil, &rp.Paging))
+}
@@ -0,0 +1,29 @@
+package subscribers
+
+import (
+	"context"
+	"mallbots/modules/order/domain/events"
+	"mallbots/modules/promotion/domain/interfaces"
+	"mallbots/plugins/eventbus"
+)
+
+// OrderSubscriber gives coupon uses back when their order is cancelled
+type OrderSubscriber struct {
+	promotions interfaces.PromotionService
+}
+
+func NewOrderSubscriber(promotions interfaces.PromotionService) *OrderSubscriber {
+	return &OrderSubscriber{promotions: promotions}
+}
+
+func (s *OrderSubscriber) Register(bus eventbus.Bus) {
+	bus.Subscribe(events.OrderCancelledEvent, s.releaseCancelledCoupon)
+}
+
+// releaseCancelledCoupon runs inside the cancellation transaction, so the use
+// only comes back if the cancellation commits
+func (s *OrderSubscriber) releaseCancelledCoupon(ctx context.Context, event eventbus.Event) error {
+	cancelled := event.(*events.OrderCancelled)
+
+	return s.promotions.ReleaseOrder(ctx, cancelled.OrderID)
+}
@@ -0,0 +1,13 @@
+package dto
+
+// ViolationResponse is one broken order rule, returned in the "violations"
+// detail of the error
+type ViolationResponse struct {
+	Rule      string `json:"rule"`
+	Error     string `json:"error"`
+	Message   string `json:"message"`
+	ProductID int32  `json:"product_id,omitempty"`
+	// Limit and Actual are numbers, or decimal strings for amounts
+	Limit  any `json:"limit"`
+	Actual any `json:"actual"`
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"mallbots/modules/rules/domain/constants"
+	"mallbots/modules/rules/domain/entities"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+)
+
+const anyCountry = "*"
+
+// minimumAmountRule rejects orders below the minimum of their destination
+type minimumAmountRule struct {
+	amounts map[string]money.Money
+}
+
+func (r *minimumAmountRule) Name() string { return "minimum_amount" }
+
+func (r *minimumAmountRule) Evaluate(ctx context.Context, check entities.RuleCheck) ([]entities.Violation, error) {
+	if check.Stage != constants.StageCheckout {
+		return nil, nil
+	}
+
+	minimum, ok := r.amounts[normalizeCountry(check.Country)]
+	if !ok {
+		minimum, ok = r.amounts[anyCountry]
+	}
+	if !ok || !check.Subtotal.LessThan(minimum) {
+		return nil, nil
+	}
+
+	return []entities.Violation{{
+		Rule:    r.Name(),
+		Err:     errorx.ErrMinimumOrderAmountNotMet,
+		Message: fmt.Sprintf("orders to %s must be at least %s", check.Country, minimum),
+		Limit:   minimum,
+		Actual:  check.Subtotal,
+	}}, nil
+}
+
+// productQuantityRule caps the units of each product
+type productQuantityRule struct {
+	max       int32
+	overrides map[int32]int32
+}
+
+func (r *productQuantityRule) Name() string { return "max_quantity_per_product" }
+
+func (r *productQuantityRule) Evaluate(ctx context.Context, check entities.RuleCheck) ([]entities.Violation, error) {
+	var violations []entities.Violation
+	for _, line := range check.Lines {
+		max := r.max
+		if override, ok := r.overrides[line.ProductID]; ok {
+			max = override
+		}
+		if max == 0 || line.Quantity <= max {
+			continue
+		}
+
+		violations = append(violations, entities.Violation{
+			Rule:      r.Name(),
+			Err:       errorx.ErrMaximumOrderQuantityExceeded,
+			Message:   fmt.Sprintf("at most %d of product %d can be ordered at once", max, line.ProductID),
+			ProductID: line.ProductID,
+			Limit:     int64(max),
+			Actual:    int64(line.Quantity),
+		})
+	}
+	return violations, nil
+}
+
+// orderQuantityRule caps the units across the whole order
+type orderQuantityRule struct {
+	max int32
+}
+
+func (r *orderQuantityRule) Name() string { return "max_quantity_per_order" }
+
+func (r *orderQuantityRule) Evaluate(ctx context.Context, check entities.RuleCheck) ([]entities.Violation, error) {
+	var total int64
+	for _, line := range check.Lines {
+		total += int64(line.Quantity)
+	}
+	if total <= int64(r.max) {
+		return nil, nil
+	}
+
+	return []entities.Violation{{
+		Rule:    r.Name(),
+		Err:     errorx.ErrMaximumOrderQuantityExceeded,
+		Message: fmt.Sprintf("an order can hold at most %d items", r.max),
+		Limit:   int64(r.max),
+		Actual:  total,
+	}}, nil
+}
+
+// dailyOrdersRule caps the orders a customer places per day
+type dailyOrdersRule struct {
+	max int32
+}
+
+func (r *dailyOrdersRule) Name() string { return "max_orders_per_day" }
+
+func (r *dailyOrdersRule) Evaluate(ctx context.Context, check entities.RuleCheck) ([]entities.Violation, error) {
+	if check.Stage != constants.StageCheckout || check.OrdersToday == nil {
+		return nil, nil
+	}
+
+	placed, err := check.OrdersToday(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if placed < int64(r.max) {
+		return nil, nil
+	}
+
+	return []entities.Violation{{
+		Rule:    r.Name(),
+		Err:     errorx.ErrMaximumDailyOrdersExceeded,
+		Message: fmt.Sprintf("at most %d orders can be placed per day", r.max),
+		Limit:   int64(r.max),
+		Actual:  placed,
+	}}, nil
+}
+
+// blockedCountryRule refuses to ship to some countries
+type blockedCountryRule struct {
+	countries map[string]bool
+}
+
+func (r *blockedCountryRule) Name() string { return "blocked_country" }
+
+func (r *blockedCountryRule) Evaluate(ctx context.Context, check entities.RuleCheck) ([]entities.Violation, error) {
+	if check.Stage != constants.StageCheckout || !r.countries[normalizeCountry(check.Country)] {
+		return nil, nil
+	}
+
+	return []entities.Violation{{
+		Rule:    r.Name(),
+		Err:     errorx.ErrInvalidShippingCountry,
+		Message: fmt.Sprintf("orders cannot be shipped to %s", check.Country),
+	}}, nil
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"mallbots/modules/rules/application/dto"
+	"mallbots/modules/rules/domain/entities"
+	"mallbots/modules/rules/domain/interfaces"
+	"mallbots/shared/config"
+	"mallbots/shared/money"
+	"strings"
+
+	"github.com/phathdt/service-context/core"
+)
+
+type ruleEngine struct {
+	rules []interfaces.Rule
+}
+
+// NewRuleEngine builds the rules switched on in the config. Mistakes in the
+// config are reported here rather than on the first checkout.
+func NewRuleEngine(cfg *config.Config) (interfaces.RuleEngine, error) {
+	rulesCfg := cfg.OrderRules
+
+	var rules []interfaces.Rule
+
+	if len(rulesCfg.MinimumAmounts) > 0 {
+		amounts := make(map[string]money.Money, len(rulesCfg.MinimumAmounts))
+		for _, minimum := range rulesCfg.MinimumAmounts {
+			if minimum.Country == "" {
+				return nil, fmt.Errorf("order rules: minimum amount without country")
+			}
+			if minimum.Amount.IsNegative() {
+				return nil, fmt.Errorf("order rules: negative minimum amount for %s", minimum.Country)
+			}
+			amounts[normalizeCountry(minimum.Country)] = minimum.Amount
+		}
+		rules = append(rules, &minimumAmountRule{amounts: amounts})
+	}
+
+	if rulesCfg.MaxQuantityPerProduct < 0 || rulesCfg.MaxQuantityPerOrder < 0 || rulesCfg.MaxOrdersPerDay < 0 {
+		return nil, fmt.Errorf("order rules: limits cannot be negative")
+	}
+	for productID, max := range rulesCfg.ProductMaxQuantities {
+		if max < 1 {
+			return nil, fmt.Errorf("order rules: max quantity of product %d must be at least 1", productID)
+		}
+	}
+
+	if rulesCfg.MaxQuantityPerProduct > 0 || len(rulesCfg.ProductMaxQuantities) > 0 {
+		rules = append(rules, &productQuantityRule{
+			max:       rulesCfg.MaxQuantityPerProduct,
+			overrides: rulesCfg.ProductMaxQuantities,
+		})
+	}
+	if rulesCfg.MaxQuantityPerOrder > 0 {
+		rules = append(rules, &orderQuantityRule{max: rulesCfg.MaxQuantityPerOrder})
+	}
+	if rulesCfg.MaxOrdersPerDay > 0 {
+		rules = append(rules, &dailyOrdersRule{max: rulesCfg.MaxOrdersPerDay})
+	}
+
+	if len(rulesCfg.BlockedCountries) > 0 {
+		countries := make(map[string]bool, len(rulesCfg.BlockedCountries))
+		for _, country := range rulesCfg.BlockedCountries {
+			countries[normalizeCountry(country)] = true
+		}
+		rules = append(rules, &blockedCountryRule{countries: countries})
+	}
+
+	return &ruleEngine{rules: rules}, nil
+}
+
+func (e *ruleEngine) Check(ctx context.Context, check entities.RuleCheck) error {
+	var violations []entities.Violation
+	for _, rule := range e.rules {
+		found, err := rule.Evaluate(ctx, check)
+		if err != nil {
+			return err
+		}
+		violations = append(violations, found...)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	responses := make([]dto.ViolationResponse, 0, len(violations))
+	for _, violation := range violations {
+		responses = append(responses, dto.ViolationResponse{
+			Rule:      violation.Rule,
+			Error:     violation.Err.Error(),
+			Message:   violation.Message,
+			ProductID: violation.ProductID,
+			Limit:     violation.Limit,
+			Actual:    violation.Actual,
+		})
+	}
+
+	// The first violation names the error, the detail lists all of them
+	return core.ErrBadRequest.
+		WithError(violations[0].Err.Error()).
+		WithReasonf("%d order rule(s) violated", len(violations)).
+		WithDetail("violations", responses)
+}
+
+func normalizeCountry(country string) string {
+	return strings.ToUpper(strings.TrimSpace(country))
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mallbots/modules/rules/application/dto"
+	"mallbots/modules/rules/domain/constants"
+	"mallbots/modules/rules/domain/entities"
+	"mallbots/shared/config"
+	"mallbots/shared/errorx"
+	"mallbots/shared/money"
+	"net/http"
+	"testing"
+
+	"github.com/phathdt/service-context/core"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRuleEngine(t *testing.T) *ruleEngine {
+	engine, err := NewRuleEngine(&config.Config{
+		OrderRules: config.OrderRulesConfig{
+			MinimumAmounts: []config.MinimumAmountConfig{
+				{Country: "VN", Amount: usd("5")},
+				{Country: "*", Amount: usd("20")},
+			},
+			MaxQuantityPerProduct: 10,
+			ProductMaxQuantities:  map[int32]int32{1: 2},
+			MaxQuantityPerOrder:   15,
+			MaxOrdersPerDay:       3,
+			BlockedCountries:      []string{"kp"},
+		},
+	})
+	require.NoError(t, err)
+	return engine.(*ruleEngine)
+}
+
+func usd(amount string) money.Money {
+	return money.MustParse(amount, money.USD)
+}
+
+func ordersToday(count int64) func(ctx context.Context) (int64, error) {
+	return func(ctx context.Context) (int64, error) {
+		return count, nil
+	}
+}
+
+func violationsOf(t *testing.T, err error) []dto.ViolationResponse {
+	t.Helper()
+
+	var appErr *core.DefaultError
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+
+	violations, ok := appErr.Details()["violations"].([]dto.ViolationResponse)
+	require.True(t, ok)
+	return violations
+}
+
+func TestRuleEngine(t *testing.T) {
+	ctx := context.Background()
+	engine := newTestRuleEngine(t)
+
+	t.Run("Passing Checkout", func(t *testing.T) {
+		err := engine.Check(ctx, entities.RuleCheck{
+			Stage:       constants.StageCheckout,
+			Country:     "VN",
+			Subtotal:    usd("5"),
+			Lines:       []entities.RuleLine{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 10}},
+			OrdersToday: ordersToday(2),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Minimum Amount Falls Back To Any Country", func(t *testing.T) {
+		err := engine.Check(ctx, entities.RuleCheck{
+			Stage:       constants.StageCheckout,
+			Country:     "US",
+			Subtotal:    usd("19.99"),
+			Lines:       []entities.RuleLine{{ProductID: 2, Quantity: 1}},
+			OrdersToday: ordersToday(0),
+		})
+
+		violations := violationsOf(t, err)
+		require.Equal(t, []dto.ViolationResponse{{
+			Rule:    "minimum_amount",
+			Error:   errorx.ErrMinimumOrderAmountNotMet.Error(),
+			Message: "orders to US must be at least 20.00",
+			Limit:   usd("20"),
+			Actual:  usd("19.99"),
+		}}, violations)
+	})
+
+	t.Run("Reports Every Violation", func(t *testing.T) {
+		err := engine.Check(ctx, entities.RuleCheck{
+			Stage:       constants.StageCheckout,
+			Country:     "KP",
+			Subtotal:    usd("100"),
+			Lines:       []entities.RuleLine{{ProductID: 1, Quantity: 3}, {ProductID: 2, Quantity: 13}},
+			OrdersToday: ordersToday(3),
+		})
+		require.Equal(t, errorx.ErrMaximumOrderQuantityExceeded.Error(), err.Error())
+
+		var rules []string
+		for _, violation := range violationsOf(t, err) {
+			rules = append(rules, violation.Rule)
+		}
+		require.Equal(t, []string{
+			"max_quantity_per_product", "max_quantity_per_product",
+			"max_quantity_per_order", "max_orders_per_day", "blocked_country",
+		}, rules)
+	})
+
+	t.Run("Cart Stage Skips Checkout Rules", func(t *testing.T) {
+		err := engine.Check(ctx, entities.RuleCheck{
+			Stage: constants.StageCart,
+			Lines: []entities.RuleLine{{ProductID: 1, Quantity: 1}},
+		})
+		require.NoError(t, err)
+
+		err = engine.Check(ctx, entities.RuleCheck{
+			Stage: constants.StageCart,
+			Lines: []entities.RuleLine{{ProductID: 1, Quantity: 3}},
+		})
+		violations := violationsOf(t, err)
+		require.Len(t, violations, 1)
+		require.Equal(t, int32(1), violations[0].ProductID)
+		require.Equal(t, int64(2), violations[0].Limit)
+	})
+
+	t.Run("Order Count Failure", func(t *testing.T) {
+		failure := errors.New("database down")
+		err := engine.Check(ctx, entities.RuleCheck{
+			Stage:    constants.StageCheckout,
+			Country:  "VN",
+			Subtotal: usd("50"),
+			OrdersToday: func(ctx context.Context) (int64, error) {
+				return 0, failure
+			},
+		})
+		require.ErrorIs(t, err, failure)
+	})
+
+	t.Run("No Rules Configured", func(t *testing.T) {
+		engine, err := NewRuleEngine(&config.Config{})
+		require.NoError(t, err)
+
+		err = engine.Check(ctx, entities.RuleCheck{
+			Stage: constants.StageCheckout,
+			Lines: []entities.RuleLine{{ProductID: 1, Quantity: 1000}},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Invalid Config", func(t *testing.T) {
+		_, err := NewRuleEngine(&config.Config{
+			OrderRules: config.OrderRulesConfig{ProductMaxQuantities: map[int32]int32{1: 0}},
+		})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,16 @@
+package constants
+
+// Stage is the step of the buying flow order rules are checked at
+type Stage string
+
+const (
+	// StageCart checks cart changes, before the destination is known
+	StageCart Stage = "CART"
+	// StageCheckout checks the order about to be placed
+	StageCheckout Stage = "CHECKOUT"
+)
+
+// String returns the string representation of the Stage
+func (s Stage) String() string {
+	return string(s)
+}
@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"context"
+	"mallbots/modules/rules/domain/constants"
+	"mallbots/shared/money"
+)
+
+// RuleLine is one product of the cart or order being checked
+type RuleLine struct {
+	ProductID int32
+	Quantity  int32
+}
+
+// RuleCheck is what the rules see of an order in the making. Country and
+// OrdersToday are only set at checkout.
+type RuleCheck struct {
+	Stage   constants.Stage
+	UserID  int32
+	Country string
+	// Subtotal is the value of the lines after item discounts
+	Subtotal money.Money
+	Lines    []RuleLine
+	// OrdersToday counts the orders the user already placed today. It is
+	// only called by rules that need it.
+	OrdersToday func(ctx context.Context) (int64, error)
+}
+
+// Violation is one broken rule
+type Violation struct {
+	Rule string
+	Err  error
+	// Message explains the violation to the customer
+	Message string
+	// ProductID is set when the violation concerns a single product
+	ProductID int32
+	// Limit and Actual are counts, or money.Money for amount rules
+	Limit  any
+	Actual any
+}
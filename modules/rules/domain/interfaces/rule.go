@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/rules/domain/entities"
+)
+
+// Rule is one order business rule. Rules ignore checks they lack the data
+// for, such as a destination rule at the cart stage.
+type Rule interface {
+	Name() string
+	Evaluate(ctx context.Context, check entities.RuleCheck) ([]entities.Violation, error)
+}
+
+// RuleEngine runs every configured rule against a cart or order
+type RuleEngine interface {
+	// Check fails with a bad request listing every violation
+	Check(ctx context.Context, check entities.RuleCheck) error
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"mallbots/modules/tax/domain/entities"
+	"mallbots/modules/tax/domain/interfaces"
+	"mallbots/shared/config"
+	"mallbots/shared/money"
+	"strings"
+)
+
+const anyCountry = "*"
+
+type tableTaxCalculator struct {
+	rules []config.TaxRuleConfig
+}
+
+// NewTaxCalculator taxes orders from the rule table in the config. Mistakes
+// in the table are reported here rather than on the first checkout.
+func NewTaxCalculator(cfg *config.Config) (interfaces.TaxCalculator, error) {
+	rules := make([]config.TaxRuleConfig, 0, len(cfg.Tax.Rules))
+	for i, rule := range cfg.Tax.Rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %d", i+1)
+		}
+
+		if rule.Country == "" {
+			return nil, fmt.Errorf("tax %s: no country", rule.Name)
+		}
+		if rule.Rate < 0 {
+			return nil, fmt.Errorf("tax %s: negative rate", rule.Name)
+		}
+		for categoryID, rate := range rule.CategoryRates {
+			if rate < 0 {
+				return nil, fmt.Errorf("tax %s: negative rate for category %d", rule.Name, categoryID)
+			}
+		}
+
+		rule.Country = normalizeCountry(rule.Country)
+		rules = append(rules, rule)
+	}
+
+	return &tableTaxCalculator{rules: rules}, nil
+}
+
+func (c *tableTaxCalculator) Calculate(ctx context.Context, req entities.TaxRequest) (*entities.TaxResult, error) {
+	result := &entities.TaxResult{
+		Amount: money.Zero(req.Currency),
+		Lines:  make([]entities.LineTax, 0, len(req.Lines)),
+	}
+
+	rule := c.findRule(req.Country, req.Zip)
+	if rule == nil {
+		for _, line := range req.Lines {
+			result.Lines = append(result.Lines, entities.LineTax{
+				ProductID: line.ProductID,
+				Amount:    money.Zero(req.Currency),
+			})
+		}
+		return result, nil
+	}
+
+	result.Rule = rule.Name
+	result.Inclusive = rule.Inclusive
+
+	for _, line := range req.Lines {
+		rate := rule.Rate
+		if categoryRate, ok := rule.CategoryRates[line.CategoryID]; ok {
+			rate = categoryRate
+		}
+
+		amount := lineTax(line.Amount, rate, rule.Inclusive)
+		result.Amount = result.Amount.Add(amount)
+		result.Lines = append(result.Lines, entities.LineTax{
+			ProductID: line.ProductID,
+			Rate:      rate,
+			Amount:    amount,
+		})
+	}
+	return result, nil
+}
+
+// findRule returns the first rule for the country whose zip prefixes, if
+// any, match the zip code
+func (c *tableTaxCalculator) findRule(country, zip string) *config.TaxRuleConfig {
+	country = normalizeCountry(country)
+	zip = strings.ToUpper(strings.ReplaceAll(zip, " ", ""))
+
+	for i := range c.rules {
+		rule := &c.rules[i]
+		if rule.Country != country && rule.Country != anyCountry {
+			continue
+		}
+
+		if len(rule.ZipPrefixes) == 0 {
+			return rule
+		}
+		for _, prefix := range rule.ZipPrefixes {
+			if strings.HasPrefix(zip, strings.ToUpper(prefix)) {
+				return rule
+			}
+		}
+	}
+
+	return nil
+}
+
+// lineTax is the tax added on top of an exclusive amount, or the part of an
+// inclusive amount that is tax, rounded to the minor unit
+func lineTax(amount money.Money, rate float64, inclusive bool) money.Money {
+	if inclusive {
+		return amount.Scale(rate / (100 + rate))
+	}
+	return amount.Percent(rate)
+}
+
+func normalizeCountry(country string) string {
+	return strings.ToUpper(strings.TrimSpace(country))
+}
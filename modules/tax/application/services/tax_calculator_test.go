@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"mallbots/modules/tax/domain/entities"
+	"mallbots/shared/config"
+	"mallbots/shared/money"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaxCalculator(t *testing.T) {
+	ctx := context.Background()
+
+	calculator, err := NewTaxCalculator(&config.Config{
+		Tax: config.TaxConfig{
+			Rules: []config.TaxRuleConfig{
+				{Name: "us-ca", Country: "US", ZipPrefixes: []string{"9"}, Rate: 7.25},
+				{Name: "us", Country: "us", Rate: 0},
+				{Name: "de", Country: "DE", Rate: 19, Inclusive: true, CategoryRates: map[int32]float64{3: 7}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("Exclusive Regional Rate", func(t *testing.T) {
+		result, err := calculator.Calculate(ctx, entities.TaxRequest{
+			Currency: money.USD,
+			Country:  "US",
+			Zip:      "94105",
+			Lines: []entities.TaxableLine{
+				{ProductID: 1, CategoryID: 1, Amount: usd("21.98")},
+				{ProductID: 2, CategoryID: 3, Amount: usd("20.99")},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "us-ca", result.Rule)
+		require.False(t, result.Inclusive)
+		require.Equal(t, []entities.LineTax{
+			{ProductID: 1, Rate: 7.25, Amount: usd("1.59")},
+			{ProductID: 2, Rate: 7.25, Amount: usd("1.52")},
+		}, result.Lines)
+		require.Equal(t, usd("3.11"), result.Amount)
+	})
+
+	t.Run("Falls Back To Country Rule", func(t *testing.T) {
+		result, err := calculator.Calculate(ctx, entities.TaxRequest{
+			Currency: money.USD,
+			Country:  "US",
+			Zip:      "10001",
+			Lines:    []entities.TaxableLine{{ProductID: 1, Amount: usd("100")}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "us", result.Rule)
+		require.True(t, result.Amount.IsZero())
+	})
+
+	t.Run("Inclusive With Category Override", func(t *testing.T) {
+		result, err := calculator.Calculate(ctx, entities.TaxRequest{
+			Currency: money.USD,
+			Country:  "de",
+			Lines: []entities.TaxableLine{
+				{ProductID: 1, CategoryID: 1, Amount: usd("119")},
+				{ProductID: 2, CategoryID: 3, Amount: usd("10.70")},
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, result.Inclusive)
+		require.Equal(t, []entities.LineTax{
+			{ProductID: 1, Rate: 19, Amount: usd("19")},
+			{ProductID: 2, Rate: 7, Amount: usd("0.70")},
+		}, result.Lines)
+		require.Equal(t, usd("19.70"), result.Amount)
+	})
+
+	t.Run("No Rule Means No Tax", func(t *testing.T) {
+		result, err := calculator.Calculate(ctx, entities.TaxRequest{
+			Currency: money.USD,
+			Country:  "VN",
+			Lines:    []entities.TaxableLine{{ProductID: 1, Amount: usd("50")}},
+		})
+		require.NoError(t, err)
+		require.Empty(t, result.Rule)
+		require.True(t, result.Amount.IsZero())
+		require.Equal(t, []entities.LineTax{{ProductID: 1, Amount: usd("0")}}, result.Lines)
+	})
+
+	t.Run("Negative Rate", func(t *testing.T) {
+		_, err := NewTaxCalculator(&config.Config{
+			Tax: config.TaxConfig{
+				Rules: []config.TaxRuleConfig{
+					{Name: "broken", Country: "*", CategoryRates: map[int32]float64{1: -5}},
+				},
+			},
+		})
+		require.ErrorContains(t, err, "negative rate for category 1")
+	})
+}
+
+func usd(amount string) money.Money {
+	return money.MustParse(amount, money.USD)
+}
@@ -0,0 +1,35 @@
+package entities
+
+import "mallbots/shared/money"
+
+// TaxableLine is one order line to be taxed
+type TaxableLine struct {
+	ProductID  int32
+	CategoryID int32
+	// Amount is the line price times its quantity
+	Amount money.Money
+}
+
+type TaxRequest struct {
+	Country  string
+	Zip      string
+	Currency money.Currency
+	Lines    []TaxableLine
+}
+
+type LineTax struct {
+	ProductID int32
+	// Rate is a percentage, 10 means 10%
+	Rate   float64
+	Amount money.Money
+}
+
+type TaxResult struct {
+	// Rule is the name of the rule that applied, empty when none did
+	Rule string
+	// Inclusive means the line amounts already contained the tax
+	Inclusive bool
+	Amount    money.Money
+	// Lines are in the same order as the request lines
+	Lines []LineTax
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/tax/domain/entities"
+)
+
+// TaxCalculator works out the tax owed on every line shipped to a destination
+type TaxCalculator interface {
+	Calculate(ctx context.Context, req entities.TaxRequest) (*entities.TaxResult, error)
+}
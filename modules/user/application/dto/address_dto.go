@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+type AddressRequest struct {
+	Label   *string `json:"label" validate:"omitempty,max=100"`
+	Address string  `json:"address" validate:"required"`
+	City    string  `json:"city" validate:"required"`
+	Country string  `json:"country" validate:"required"`
+	Zip     string  `json:"zip" validate:"required"`
+	// Making an address a default takes the flag away from the user's
+	// previous default
+	IsDefaultShipping bool `json:"is_default_shipping"`
+	IsDefaultBilling  bool `json:"is_default_billing"`
+}
+
+type AddressResponse struct {
+	ID                int32     `json:"id"`
+	Label             *string   `json:"label,omitempty"`
+	Address           string    `json:"address"`
+	City              string    `json:"city"`
+	Country           string    `json:"country"`
+	Zip               string    `json:"zip"`
+	IsDefaultShipping bool      `json:"is_default_shipping"`
+	IsDefaultBilling  bool      `json:"is_default_billing"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
@@ -6,6 +6,7 @@ type UserResponse struct {
 	ID        int32     `json:"id"`
 	Email     string    `json:"email"`
 	FullName  string    `json:"full_name"`
+	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mallbots/modules/user/application/dto"
+	"mallbots/modules/user/domain/entities"
+	"mallbots/modules/user/domain/interfaces"
+	"mallbots/shared/errorx"
+	"time"
+
+	"github.com/phathdt/service-context/core"
+)
+
+type AddressService struct {
+	repo interfaces.AddressRepository
+}
+
+func NewAddressService(repo interfaces.AddressRepository) interfaces.AddressService {
+	return &AddressService{repo: repo}
+}
+
+func (s *AddressService) CreateAddress(ctx context.Context, userID int32, req *dto.AddressRequest) (*dto.AddressResponse, error) {
+	address := &entities.Address{
+		UserID:            userID,
+		Label:             req.Label,
+		Address:           req.Address,
+		City:              req.City,
+		Country:           req.Country,
+		Zip:               req.Zip,
+		IsDefaultShipping: req.IsDefaultShipping,
+		IsDefaultBilling:  req.IsDefaultBilling,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+
+	created, err := s.repo.Create(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAddressResponse(created), nil
+}
+
+func (s *AddressService) GetAddresses(ctx context.Context, userID int32) ([]*dto.AddressResponse, error) {
+	addresses, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.AddressResponse, 0, len(addresses))
+	for _, address := range addresses {
+		responses = append(responses, toAddressResponse(address))
+	}
+
+	return responses, nil
+}
+
+func (s *AddressService) GetAddress(ctx context.Context, userID, id int32) (*dto.AddressResponse, error) {
+	address, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, wrapAddressNotFound(err)
+	}
+
+	return toAddressResponse(address), nil
+}
+
+func (s *AddressService) UpdateAddress(ctx context.Context, userID, id int32, req *dto.AddressRequest) (*dto.AddressResponse, error) {
+	address := &entities.Address{
+		ID:                id,
+		UserID:            userID,
+		Label:             req.Label,
+		Address:           req.Address,
+		City:              req.City,
+		Country:           req.Country,
+		Zip:               req.Zip,
+		IsDefaultShipping: req.IsDefaultShipping,
+		IsDefaultBilling:  req.IsDefaultBilling,
+		UpdatedAt:         time.Now(),
+	}
+
+	updated, err := s.repo.Update(ctx, address)
+	if err != nil {
+		return nil, wrapAddressNotFound(err)
+	}
+
+	return toAddressResponse(updated), nil
+}
+
+func (s *AddressService) DeleteAddress(ctx context.Context, userID, id int32) error {
+	return wrapAddressNotFound(s.repo.Delete(ctx, userID, id))
+}
+
+func (s *AddressService) GetDefaultShippingAddress(ctx context.Context, userID int32) (*dto.AddressResponse, error) {
+	return defaultAddress(s.repo.GetDefaultShipping(ctx, userID))
+}
+
+func (s *AddressService) GetDefaultBillingAddress(ctx context.Context, userID int32) (*dto.AddressResponse, error) {
+	return defaultAddress(s.repo.GetDefaultBilling(ctx, userID))
+}
+
+func defaultAddress(address *entities.Address, err error) (*dto.AddressResponse, error) {
+	if errors.Is(err, errorx.ErrAddressNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toAddressResponse(address), nil
+}
+
+// wrapAddressNotFound answers 404 for addresses that do not exist or belong
+// to another user alike
+func wrapAddressNotFound(err error) error {
+	if errors.Is(err, errorx.ErrAddressNotFound) {
+		return core.ErrNotFound.WithError(errorx.ErrAddressNotFound.Error())
+	}
+	return err
+}
+
+func toAddressResponse(address *entities.Address) *dto.AddressResponse {
+	return &dto.AddressResponse{
+		ID:                address.ID,
+		Label:             address.Label,
+		Address:           address.Address,
+		City:              address.City,
+		Country:           address.Country,
+		Zip:               address.Zip,
+		IsDefaultShipping: address.IsDefaultShipping,
+		IsDefaultBilling:  address.IsDefaultBilling,
+		CreatedAt:         address.CreatedAt,
+		UpdatedAt:         address.UpdatedAt,
+	}
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"mallbots/modules/user/application/dto"
+	"mallbots/modules/user/domain/entities"
+	"mallbots/shared/errorx"
+
+	"github.com/phathdt/service-context/core"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock address repository
+type MockAddressRepo struct {
+	mock.Mock
+}
+
+func (m *MockAddressRepo) Create(ctx context.Context, address *entities.Address) (*entities.Address, error) {
+	args := m.Called(ctx, address)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Address), args.Error(1)
+}
+
+func (m *MockAddressRepo) Update(ctx context.Context, address *entities.Address) (*entities.Address, error) {
+	args := m.Called(ctx, address)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Address), args.Error(1)
+}
+
+func (m *MockAddressRepo) GetByID(ctx context.Context, userID, id int32) (*entities.Address, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Address), args.Error(1)
+}
+
+func (m *MockAddressRepo) GetByUserID(ctx context.Context, userID int32) ([]*entities.Address, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Address), args.Error(1)
+}
+
+func (m *MockAddressRepo) GetDefaultShipping(ctx context.Context, userID int32) (*entities.Address, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Address), args.Error(1)
+}
+
+func (m *MockAddressRepo) GetDefaultBilling(ctx context.Context, userID int32) (*entities.Address, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Address), args.Error(1)
+}
+
+func (m *MockAddressRepo) Delete(ctx context.Context, userID, id int32) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func homeAddress() *entities.Address {
+	return &entities.Address{
+		ID:                1,
+		UserID:            1,
+		Address:           "123 Test St",
+		City:              "Test City",
+		Country:           "Test Country",
+		Zip:               "12345",
+		IsDefaultShipping: true,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+}
+
+func TestAddressService_CreateAddress(t *testing.T) {
+	mockRepo := new(MockAddressRepo)
+	service := NewAddressService(mockRepo)
+
+	req := &dto.AddressRequest{
+		Address:           "123 Test St",
+		City:              "Test City",
+		Country:           "Test Country",
+		Zip:               "12345",
+		IsDefaultShipping: true,
+	}
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(address *entities.Address) bool {
+		return address.UserID == 1 && address.City == req.City && address.IsDefaultShipping && !address.IsDefaultBilling
+	})).Return(homeAddress(), nil).Once()
+
+	address, err := service.CreateAddress(context.Background(), 1, req)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), address.ID)
+	require.True(t, address.IsDefaultShipping)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddressService_NotFound(t *testing.T) {
+	mockRepo := new(MockAddressRepo)
+	service := NewAddressService(mockRepo)
+
+	testCases := []struct {
+		name  string
+		setup func()
+		call  func() error
+	}{
+		{
+			name: "Get another user's address",
+			setup: func() {
+				mockRepo.On("GetByID", mock.Anything, int32(2), int32(1)).
+					Return(nil, errorx.ErrAddressNotFound).Once()
+			},
+			call: func() error {
+				_, err := service.GetAddress(context.Background(), 2, 1)
+				return err
+			},
+		},
+		{
+			name: "Update missing address",
+			setup: func() {
+				mockRepo.On("Update", mock.Anything, mock.Anything).
+					Return(nil, errorx.ErrAddressNotFound).Once()
+			},
+			call: func() error {
+				_, err := service.UpdateAddress(context.Background(), 1, 999, &dto.AddressRequest{})
+				return err
+			},
+		},
+		{
+			name: "Delete missing address",
+			setup: func() {
+				mockRepo.On("Delete", mock.Anything, int32(1), int32(999)).
+					Return(errorx.ErrAddressNotFound).Once()
+			},
+			call: func() error {
+				return service.DeleteAddress(context.Background(), 1, 999)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setup()
+
+			err := tc.call()
+
+			var appErr *core.DefaultError
+			require.ErrorAs(t, err, &appErr)
+			require.Equal(t, http.StatusNotFound, appErr.StatusCode())
+			require.Equal(t, errorx.ErrAddressNotFound.Error(), appErr.Error())
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddressService_GetDefaultShippingAddress(t *testing.T) {
+	mockRepo := new(MockAddressRepo)
+	service := NewAddressService(mockRepo)
+
+	testCases := []struct {
+		name    string
+		setup   func()
+		wantNil bool
+		wantErr error
+	}{
+		{
+			name: "Has a default",
+			setup: func() {
+				mockRepo.On("GetDefaultShipping", mock.Anything, int32(1)).
+					Return(homeAddress(), nil).Once()
+			},
+		},
+		{
+			name: "No default",
+			setup: func() {
+				mockRepo.On("GetDefaultShipping", mock.Anything, int32(1)).
+					Return(nil, errorx.ErrAddressNotFound).Once()
+			},
+			wantNil: true,
+		},
+		{
+			name: "Store failure",
+			setup: func() {
+				mockRepo.On("GetDefaultShipping", mock.Anything, int32(1)).
+					Return(nil, errors.New("connection reset")).Once()
+			},
+			wantNil: true,
+			wantErr: errors.New("connection reset"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setup()
+
+			address, err := service.GetDefaultShippingAddress(context.Background(), 1)
+
+			if tc.wantErr != nil {
+				require.EqualError(t, err, tc.wantErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			if tc.wantNil {
+				require.Nil(t, address)
+			} else {
+				require.Equal(t, "Test City", address.City)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -78,6 +78,7 @@ func (s *UserService) GetProfile(ctx context.Context, userID int32) (*dto.UserRe
 		ID:        user.ID,
 		Email:     user.Email,
 		FullName:  user.FullName,
+		Role:      user.Role,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 	}, nil
@@ -91,6 +92,7 @@ func (s *UserService) generateToken(user *entities.User) (string, error) {
 		UserId:   user.ID,
 		Email:    user.Email,
 		SubToken: subToken,
+		Role:     user.Role,
 	}
 
 	expiredTime := 3600 * 24 * 30
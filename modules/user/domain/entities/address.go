@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// Address is an entry of a user's address book. A user has at most one
+// default shipping and one default billing address.
+type Address struct {
+	ID                int32
+	UserID            int32
+	Label             *string
+	Address           string
+	City              string
+	Country           string
+	Zip               string
+	IsDefaultShipping bool
+	IsDefaultBilling  bool
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
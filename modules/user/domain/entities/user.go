@@ -7,6 +7,7 @@ type User struct {
 	Email     string
 	Password  string
 	FullName  string
+	Role      string
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/user/domain/entities"
+)
+
+// AddressRepository only reaches the addresses of the given user, the ones
+// of other users are reported as ErrAddressNotFound
+type AddressRepository interface {
+	// Create and Update take the default flags over from the user's other
+	// addresses when the address is a default
+	Create(ctx context.Context, address *entities.Address) (*entities.Address, error)
+	Update(ctx context.Context, address *entities.Address) (*entities.Address, error)
+	GetByID(ctx context.Context, userID, id int32) (*entities.Address, error)
+	GetByUserID(ctx context.Context, userID int32) ([]*entities.Address, error)
+	GetDefaultShipping(ctx context.Context, userID int32) (*entities.Address, error)
+	GetDefaultBilling(ctx context.Context, userID int32) (*entities.Address, error)
+	Delete(ctx context.Context, userID, id int32) error
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"mallbots/modules/user/application/dto"
+)
+
+type AddressService interface {
+	CreateAddress(ctx context.Context, userID int32, req *dto.AddressRequest) (*dto.AddressResponse, error)
+	GetAddresses(ctx context.Context, userID int32) ([]*dto.AddressResponse, error)
+	GetAddress(ctx context.Context, userID, id int32) (*dto.AddressResponse, error)
+	UpdateAddress(ctx context.Context, userID, id int32, req *dto.AddressRequest) (*dto.AddressResponse, error)
+	DeleteAddress(ctx context.Context, userID, id int32) error
+	// GetDefaultShippingAddress and GetDefaultBillingAddress return nil when
+	// the user has no such default
+	GetDefaultShippingAddress(ctx context.Context, userID int32) (*dto.AddressResponse, error)
+	GetDefaultBillingAddress(ctx context.Context, userID int32) (*dto.AddressResponse, error)
+}
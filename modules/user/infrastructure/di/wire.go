@@ -18,7 +18,18 @@ var UserSet = wire.NewSet(
 	rest.NewUserHandler,
 )
 
+var AddressSet = wire.NewSet(
+	repositories.NewAddressRepository,
+	services.NewAddressService,
+	rest.NewAddressHandler,
+)
+
 func InitializeUserHandler(db *pgxpool.Pool, provider tokenprovider.Provider) (*rest.UserHandler, error) {
 	wire.Build(UserSet)
 	return &rest.UserHandler{}, nil
 }
+
+func InitializeAddressHandler(db *pgxpool.Pool) (*rest.AddressHandler, error) {
+	wire.Build(AddressSet)
+	return &rest.AddressHandler{}, nil
+}
@@ -24,6 +24,15 @@ func InitializeUserHandler(db *pgxpool.Pool, provider tokenprovider.Provider) (*
 	return userHandler, nil
 }
 
+func InitializeAddressHandler(db *pgxpool.Pool) (*rest.AddressHandler, error) {
+	addressRepository := repositories.NewAddressRepository(db)
+	addressService := services.NewAddressService(addressRepository)
+	addressHandler := rest.NewAddressHandler(addressService)
+	return addressHandler, nil
+}
+
 // wire.go:
 
 var UserSet = wire.NewSet(repositories.NewUserRepository, services.NewUserService, rest.NewUserHandler)
+
+var AddressSet = wire.NewSet(repositories.NewAddressRepository, services.NewAddressService, rest.NewAddressHandler)
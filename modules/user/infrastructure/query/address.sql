@@ -0,0 +1,53 @@
+-- name: CreateAddress :one
+INSERT INTO addresses (
+    user_id,
+    label,
+    address,
+    city,
+    country,
+    zip,
+    is_default_shipping,
+    is_default_billing,
+    created_at,
+    updated_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING *;
+
+-- name: GetAddress :one
+SELECT * FROM addresses WHERE id = $1 AND user_id = $2;
+
+-- name: GetAddressesByUserID :many
+SELECT * FROM addresses WHERE user_id = $1 ORDER BY id;
+
+-- name: GetDefaultShippingAddress :one
+SELECT * FROM addresses WHERE user_id = $1 AND is_default_shipping;
+
+-- name: GetDefaultBillingAddress :one
+SELECT * FROM addresses WHERE user_id = $1 AND is_default_billing;
+
+-- name: UpdateAddress :one
+UPDATE addresses
+SET label = $3,
+    address = $4,
+    city = $5,
+    country = $6,
+    zip = $7,
+    is_default_shipping = $8,
+    is_default_billing = $9,
+    updated_at = $10
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteAddress :execrows
+DELETE FROM addresses WHERE id = $1 AND user_id = $2;
+
+-- name: ClearDefaultShippingAddress :exec
+UPDATE addresses
+SET is_default_shipping = false, updated_at = $2
+WHERE user_id = $1 AND is_default_shipping;
+
+-- name: ClearDefaultBillingAddress :exec
+UPDATE addresses
+SET is_default_billing = false, updated_at = $2
+WHERE user_id = $1 AND is_default_billing;
@@ -8,6 +8,20 @@ import (
 	"time"
 )
 
+type Address struct {
+	ID                int32     `db:"id" json:"id"`
+	UserID            int32     `db:"user_id" json:"user_id"`
+	Label             *string   `db:"label" json:"label"`
+	Address           string    `db:"address" json:"address"`
+	City              string    `db:"city" json:"city"`
+	Country           string    `db:"country" json:"country"`
+	Zip               string    `db:"zip" json:"zip"`
+	IsDefaultShipping bool      `db:"is_default_shipping" json:"is_default_shipping"`
+	IsDefaultBilling  bool      `db:"is_default_billing" json:"is_default_billing"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type User struct {
 	ID        int32     `db:"id" json:"id"`
 	Email     string    `db:"email" json:"email"`
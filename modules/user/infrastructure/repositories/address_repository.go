@@ -0,0 +1,208 @@
+package repositories
+
+import (
+	"context"
+	"mallbots/modules/user/domain/entities"
+	"mallbots/modules/user/domain/interfaces"
+	"mallbots/modules/user/infrastructure/query/gen"
+	"mallbots/plugins/pgxc"
+	"mallbots/shared/errorx"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type addressRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAddressRepository(db *pgxpool.Pool) interfaces.AddressRepository {
+	return &addressRepository{db: db}
+}
+
+func (r *addressRepository) Create(ctx context.Context, address *entities.Address) (*entities.Address, error) {
+	var created *gen.Address
+
+	err := pgxc.WithTx(ctx, r.db, func(ctx context.Context) error {
+		queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+		if err := r.clearDefaults(ctx, queries, address); err != nil {
+			return err
+		}
+
+		var err error
+		created, err = queries.CreateAddress(ctx, gen.CreateAddressParams{
+			UserID:            address.UserID,
+			Label:             address.Label,
+			Address:           address.Address,
+			City:              address.City,
+			Country:           address.Country,
+			Zip:               address.Zip,
+			IsDefaultShipping: address.IsDefaultShipping,
+			IsDefaultBilling:  address.IsDefaultBilling,
+			CreatedAt:         address.CreatedAt,
+			UpdatedAt:         address.UpdatedAt,
+		})
+		if err != nil {
+			return errorx.ErrCannotSaveAddress
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toAddress(created), nil
+}
+
+func (r *addressRepository) Update(ctx context.Context, address *entities.Address) (*entities.Address, error) {
+	var updated *gen.Address
+
+	err := pgxc.WithTx(ctx, r.db, func(ctx context.Context) error {
+		queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+		if err := r.clearDefaults(ctx, queries, address); err != nil {
+			return err
+		}
+
+		var err error
+		updated, err = queries.UpdateAddress(ctx, gen.UpdateAddressParams{
+			ID:                address.ID,
+			UserID:            address.UserID,
+			Label:             address.Label,
+			Address:           address.Address,
+			City:              address.City,
+			Country:           address.Country,
+			Zip:               address.Zip,
+			IsDefaultShipping: address.IsDefaultShipping,
+			IsDefaultBilling:  address.IsDefaultBilling,
+			UpdatedAt:         address.UpdatedAt,
+		})
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return errorx.ErrAddressNotFound
+			}
+			return errorx.ErrCannotSaveAddress
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toAddress(updated), nil
+}
+
+// clearDefaults takes the default flags the address is about to get away
+// from the user's other addresses
+func (r *addressRepository) clearDefaults(ctx context.Context, queries *gen.Queries, address *entities.Address) error {
+	if address.IsDefaultShipping {
+		err := queries.ClearDefaultShippingAddress(ctx, gen.ClearDefaultShippingAddressParams{
+			UserID:    address.UserID,
+			UpdatedAt: address.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if address.IsDefaultBilling {
+		err := queries.ClearDefaultBillingAddress(ctx, gen.ClearDefaultBillingAddressParams{
+			UserID:    address.UserID,
+			UpdatedAt: address.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *addressRepository) GetByID(ctx context.Context, userID, id int32) (*entities.Address, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	address, err := queries.GetAddress(ctx, gen.GetAddressParams{ID: id, UserID: userID})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrAddressNotFound
+		}
+		return nil, err
+	}
+
+	return toAddress(address), nil
+}
+
+func (r *addressRepository) GetByUserID(ctx context.Context, userID int32) ([]*entities.Address, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	addresses, err := queries.GetAddressesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entities.Address, len(addresses))
+	for i, address := range addresses {
+		result[i] = toAddress(address)
+	}
+
+	return result, nil
+}
+
+func (r *addressRepository) GetDefaultShipping(ctx context.Context, userID int32) (*entities.Address, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	address, err := queries.GetDefaultShippingAddress(ctx, userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrAddressNotFound
+		}
+		return nil, err
+	}
+
+	return toAddress(address), nil
+}
+
+func (r *addressRepository) GetDefaultBilling(ctx context.Context, userID int32) (*entities.Address, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	address, err := queries.GetDefaultBillingAddress(ctx, userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errorx.ErrAddressNotFound
+		}
+		return nil, err
+	}
+
+	return toAddress(address), nil
+}
+
+func (r *addressRepository) Delete(ctx context.Context, userID, id int32) error {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	deleted, err := queries.DeleteAddress(ctx, gen.DeleteAddressParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errorx.ErrAddressNotFound
+	}
+
+	return nil
+}
+
+func toAddress(address *gen.Address) *entities.Address {
+	return &entities.Address{
+		ID:                address.ID,
+		UserID:            address.UserID,
+		Label:             address.Label,
+		Address:           address.Address,
+		City:              address.City,
+		Country:           address.Country,
+		Zip:               address.Zip,
+		IsDefaultShipping: address.IsDefaultShipping,
+		IsDefaultBilling:  address.IsDefaultBilling,
+		CreatedAt:         address.CreatedAt,
+		UpdatedAt:         address.UpdatedAt,
+	}
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mallbots/modules/user/domain/entities"
+	"mallbots/shared/errorx"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressRepository(t *testing.T) {
+	db := createTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	repo := NewAddressRepository(db)
+
+	newUser := func(email string) *entities.User {
+		user, err := users.Create(ctx, &entities.User{
+			Email:     email,
+			Password:  "hashedpassword",
+			FullName:  "Address Owner",
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+		return user
+	}
+	newAddress := func(userID int32, city string, defaultShipping bool) *entities.Address {
+		address, err := repo.Create(ctx, &entities.Address{
+			UserID:            userID,
+			Address:           "123 Test St",
+			City:              city,
+			Country:           "Test Country",
+			Zip:               "12345",
+			IsDefaultShipping: defaultShipping,
+			CreatedAt:         time.Now(),
+			UpdatedAt:         time.Now(),
+		})
+		require.NoError(t, err)
+		return address
+	}
+
+	owner := newUser("owner@example.com")
+	other := newUser("other@example.com")
+
+	t.Run("New default takes the flag over", func(t *testing.T) {
+		first := newAddress(owner.ID, "First City", true)
+		second := newAddress(owner.ID, "Second City", true)
+
+		def, err := repo.GetDefaultShipping(ctx, owner.ID)
+		require.NoError(t, err)
+		require.Equal(t, second.ID, def.ID)
+
+		first, err = repo.GetByID(ctx, owner.ID, first.ID)
+		require.NoError(t, err)
+		require.False(t, first.IsDefaultShipping)
+
+		addresses, err := repo.GetByUserID(ctx, owner.ID)
+		require.NoError(t, err)
+		require.Len(t, addresses, 2)
+	})
+
+	t.Run("No default", func(t *testing.T) {
+		_, err := repo.GetDefaultBilling(ctx, owner.ID)
+		require.ErrorIs(t, err, errorx.ErrAddressNotFound)
+	})
+
+	t.Run("Other users cannot reach the address", func(t *testing.T) {
+		address := newAddress(owner.ID, "Private City", false)
+
+		_, err := repo.GetByID(ctx, other.ID, address.ID)
+		require.ErrorIs(t, err, errorx.ErrAddressNotFound)
+
+		address.UserID = other.ID
+		_, err = repo.Update(ctx, address)
+		require.ErrorIs(t, err, errorx.ErrAddressNotFound)
+
+		err = repo.Delete(ctx, other.ID, address.ID)
+		require.ErrorIs(t, err, errorx.ErrAddressNotFound)
+
+		require.NoError(t, repo.Delete(ctx, owner.ID, address.ID))
+	})
+}
@@ -36,6 +36,7 @@ func (r *userRepository) Create(ctx context.Context, user *entities.User) (*enti
 		Email:     dbUser.Email,
 		Password:  dbUser.Password,
 		FullName:  dbUser.FullName,
+		Role:      dbUser.Role,
 		CreatedAt: dbUser.CreatedAt,
 		UpdatedAt: dbUser.UpdatedAt,
 	}, nil
@@ -54,6 +55,7 @@ func (r *userRepository) GetByID(ctx context.Context, id int32) (*entities.User,
 		Email:     dbUser.Email,
 		Password:  dbUser.Password,
 		FullName:  dbUser.FullName,
+		Role:      dbUser.Role,
 		CreatedAt: dbUser.CreatedAt,
 		UpdatedAt: dbUser.UpdatedAt,
 	}, nil
@@ -72,6 +74,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entitie
 		Email:     dbUser.Email,
 		Password:  dbUser.Password,
 		FullName:  dbUser.FullName,
+		Role:      dbUser.Role,
 		CreatedAt: dbUser.CreatedAt,
 		UpdatedAt: dbUser.UpdatedAt,
 	}, nil
@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"github.com/phathdt/service-context/component/validation"
+	"mallbots/modules/user/application/dto"
+	"mallbots/modules/user/domain/interfaces"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/phathdt/service-context/core"
+)
+
+type AddressHandler struct {
+	service interfaces.AddressService
+}
+
+func NewAddressHandler(service interfaces.AddressService) *AddressHandler {
+	return &AddressHandler{service: service}
+}
+
+func (h *AddressHandler) CreateAddress(c *fiber.Ctx) error {
+	userID := c.Context().UserValue("userId").(int32)
+
+	var req dto.AddressRequest
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := validation.Validate(req); err != nil {
+		panic(err)
+	}
+
+	address, err := h.service.CreateAddress(c.Context(), userID, &req)
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(core.SimpleSuccessResponse(address))
+}
+
+func (h *AddressHandler) GetAddresses(c *fiber.Ctx) error {
+	userID := c.Context().UserValue("userId").(int32)
+
+	addresses, err := h.service.GetAddresses(c.Context(), userID)
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(addresses))
+}
+
+func (h *AddressHandler) GetAddress(c *fiber.Ctx) error {
+	userID := c.Context().UserValue("userId").(int32)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	address, err := h.service.GetAddress(c.Context(), userID, int32(id))
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(address))
+}
+
+func (h *AddressHandler) UpdateAddress(c *fiber.Ctx) error {
+	userID := c.Context().UserValue("userId").(int32)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	var req dto.AddressRequest
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := validation.Validate(req); err != nil {
+		panic(err)
+	}
+
+	address, err := h.service.UpdateAddress(c.Context(), userID, int32(id), &req)
+	if err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(address))
+}
+
+func (h *AddressHandler) DeleteAddress(c *fiber.Ctx) error {
+	userID := c.Context().UserValue("userId").(int32)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		panic(core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	if err := h.service.DeleteAddress(c.Context(), userID, int32(id)); err != nil {
+		panic(err)
+	}
+
+	return c.Status(http.StatusOK).JSON(core.SimpleSuccessResponse(true))
+}
@@ -0,0 +1,77 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+
+	sctx "github.com/phathdt/service-context"
+)
+
+// Event is a domain fact other modules can react to.
+type Event interface {
+	EventName() string
+}
+
+type Handler func(ctx context.Context, event Event) error
+
+type Bus interface {
+	Subscribe(eventName string, handler Handler)
+	Publish(ctx context.Context, event Event) error
+}
+
+type eventBus struct {
+	id       string
+	logger   sctx.Logger
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func New(id string) *eventBus {
+	return &eventBus{
+		id:       id,
+		handlers: make(map[string][]Handler),
+	}
+}
+
+func (b *eventBus) ID() string {
+	return b.id
+}
+
+func (b *eventBus) InitFlags() {}
+
+func (b *eventBus) Activate(_ sctx.ServiceContext) error {
+	b.logger = sctx.GlobalLogger().GetLogger(b.id)
+	return nil
+}
+
+func (b *eventBus) Stop() error {
+	return nil
+}
+
+func (b *eventBus) Subscribe(eventName string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[eventName] = append(b.handlers[eventName], handler)
+}
+
+// Publish runs the subscribed handlers synchronously, in subscription order,
+// and stops at the first error. Handlers receive the publisher's context so
+// they join its database transaction; handlers with side effects that must not
+// block the publisher should log their own errors and return nil.
+func (b *eventBus) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	handlers := b.handlers[event.EventName()]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			if b.logger != nil {
+				b.logger.Errorf("handle %s: %v", event.EventName(), err)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"mallbots/plugins/idempotency/query/gen"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresStore struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresStore(db *pgxpool.Pool) Store {
+	return &postgresStore{db: db}
+}
+
+// Reserve inserts a fresh key, or takes over one whose previous use has
+// expired. A live record holding the key is returned as it is.
+func (s *postgresStore) Reserve(ctx context.Context, userID int32, key, fingerprint string) (*Record, bool, error) {
+	queries := gen.New(s.db)
+	now := time.Now()
+
+	_, err := queries.ReserveIdempotencyKey(ctx, gen.ReserveIdempotencyKeyParams{
+		UserID:      userID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(TTL),
+	})
+	if err == nil {
+		return &Record{Fingerprint: fingerprint}, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, err
+	}
+
+	row, err := queries.GetIdempotencyKey(ctx, gen.GetIdempotencyKeyParams{UserID: userID, Key: key})
+	if err != nil {
+		return nil, false, err
+	}
+
+	record := Record{Fingerprint: row.Fingerprint, Body: row.ResponseBody}
+	if row.StatusCode != nil {
+		record.StatusCode = int(*row.StatusCode)
+	}
+	if row.ContentType != nil {
+		record.ContentType = *row.ContentType
+	}
+
+	return &record, false, nil
+}
+
+func (s *postgresStore) Complete(ctx context.Context, userID int32, key string, record *Record) error {
+	statusCode := int32(record.StatusCode)
+
+	return gen.New(s.db).CompleteIdempotencyKey(ctx, gen.CompleteIdempotencyKeyParams{
+		UserID:       userID,
+		Key:          key,
+		StatusCode:   &statusCode,
+		ContentType:  &record.ContentType,
+		ResponseBody: record.Body,
+	})
+}
+
+func (s *postgresStore) Release(ctx context.Context, userID int32, key string) error {
+	return gen.New(s.db).ReleaseIdempotencyKey(ctx, gen.ReleaseIdempotencyKeyParams{UserID: userID, Key: key})
+}
@@ -0,0 +1,63 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type redisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) Store {
+	return &redisStore{client: client}
+}
+
+func redisKey(userID int32, key string) string {
+	return fmt.Sprintf("idempotency:%d:%s", userID, key)
+}
+
+func (s *redisStore) Reserve(ctx context.Context, userID int32, key, fingerprint string) (*Record, bool, error) {
+	record := &Record{Fingerprint: fingerprint}
+
+	value, err := json.Marshal(record)
+	if err != nil {
+		return nil, false, err
+	}
+
+	reserved, err := s.client.SetNX(ctx, redisKey(userID, key), value, TTL).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if reserved {
+		return record, true, nil
+	}
+
+	stored, err := s.client.Get(ctx, redisKey(userID, key)).Bytes()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var existing Record
+	if err := json.Unmarshal(stored, &existing); err != nil {
+		return nil, false, err
+	}
+
+	return &existing, false, nil
+}
+
+func (s *redisStore) Complete(ctx context.Context, userID int32, key string, record *Record) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return s.client.SetArgs(ctx, redisKey(userID, key), value, redis.SetArgs{KeepTTL: true}).Err()
+}
+
+func (s *redisStore) Release(ctx context.Context, userID int32, key string) error {
+	return s.client.Del(ctx, redisKey(userID, key)).Err()
+}
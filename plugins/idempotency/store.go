@@ -0,0 +1,32 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// TTL is how long a stored response is replayed before the key can be reused
+const TTL = 24 * time.Hour
+
+// Record is what a store keeps for one key. StatusCode is zero while the
+// first request is still being handled.
+type Record struct {
+	Fingerprint string `json:"fingerprint"`
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+func (r *Record) Completed() bool {
+	return r.StatusCode != 0
+}
+
+type Store interface {
+	// Reserve claims key for userID. When the key is already taken it returns
+	// the existing record and false instead.
+	Reserve(ctx context.Context, userID int32, key, fingerprint string) (*Record, bool, error)
+	// Complete saves the response of a reserved key for replay
+	Complete(ctx context.Context, userID int32, key string, record *Record) error
+	// Release frees a reserved key whose request failed, so it can be retried
+	Release(ctx context.Context, userID int32, key string) error
+}
@@ -0,0 +1,69 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"mallbots/plugins/outbox/query/gen"
+	"mallbots/plugins/pgxc"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Event is a domain fact other systems can learn about. It is stored as
+// JSON, so its exported fields make up the payload.
+type Event interface {
+	EventName() string
+}
+
+// Message is an event as it leaves the outbox. ID stays the same when a
+// delivery is retried, so consumers can drop the duplicates at-least-once
+// delivery brings.
+type Message struct {
+	ID        int64           `json:"id"`
+	Name      string          `json:"name"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func (m *Message) EventName() string {
+	return m.Name
+}
+
+// Writer records events in the outbox table
+type Writer interface {
+	// Add joins the transaction carried by ctx, so the events are only
+	// relayed once the state change they describe commits
+	Add(ctx context.Context, events ...Event) error
+}
+
+type writer struct {
+	db *pgxpool.Pool
+}
+
+func NewWriter(db *pgxpool.Pool) Writer {
+	return &writer{db: db}
+}
+
+func (w *writer) Add(ctx context.Context, events ...Event) error {
+	queries := gen.New(pgxc.GetDB(ctx, w.db))
+	now := time.Now()
+
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		err = queries.InsertOutboxMessage(ctx, gen.InsertOutboxMessageParams{
+			EventName: event.EventName(),
+			Payload:   payload,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
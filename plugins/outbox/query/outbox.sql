@@ -0,0 +1,25 @@
+-- name: InsertOutboxMessage :exec
+INSERT INTO outbox (event_name, payload, created_at, next_attempt_at)
+VALUES ($1, $2, $3, $3);
+
+-- name: ClaimDueOutboxMessages :many
+UPDATE outbox
+SET next_attempt_at = sqlc.arg(lease_until)
+WHERE id IN (
+    SELECT id FROM outbox
+    WHERE published_at IS NULL AND next_attempt_at <= sqlc.arg(now)
+    ORDER BY id
+    LIMIT sqlc.arg(row_limit)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, event_name, payload, attempts, created_at;
+
+-- name: MarkOutboxPublished :exec
+UPDATE outbox
+SET published_at = $2, attempts = attempts + 1, last_error = NULL
+WHERE id = $1;
+
+-- name: MarkOutboxFailed :exec
+UPDATE outbox
+SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+WHERE id = $1;
@@ -0,0 +1,164 @@
+package outbox
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"mallbots/plugins/outbox/query/gen"
+	"mallbots/plugins/pgxc"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	sctx "github.com/phathdt/service-context"
+)
+
+const (
+	DefaultBatchSize       = 100
+	DefaultRetryBackoff    = time.Second
+	DefaultMaxRetryBackoff = 10 * time.Minute
+	DefaultLease           = 5 * time.Minute
+)
+
+// RelayOptions tune the relay, zero values fall back to the defaults
+type RelayOptions struct {
+	BatchSize int32
+	// RetryBackoff is the wait after the first failed delivery, it doubles
+	// with every further failure up to MaxRetryBackoff
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// Lease is how long the messages of a batch stay with the relay that
+	// claimed them, it should outlast the delivery of a whole batch
+	Lease time.Duration
+}
+
+// Relay hands recorded messages to the sinks. A message counts as published
+// once every sink accepted it; when one fails the whole message is retried
+// later, so sinks that already took it see it again.
+type Relay struct {
+	db      *pgxpool.Pool
+	sinks   []Sink
+	options RelayOptions
+	logger  sctx.Logger
+}
+
+func NewRelay(db *pgxpool.Pool, options RelayOptions, sinks ...Sink) *Relay {
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultBatchSize
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = DefaultRetryBackoff
+	}
+	if options.MaxRetryBackoff <= 0 {
+		options.MaxRetryBackoff = DefaultMaxRetryBackoff
+	}
+	if options.Lease <= 0 {
+		options.Lease = DefaultLease
+	}
+
+	return &Relay{
+		db:      db,
+		sinks:   sinks,
+		options: options,
+		logger:  sctx.GlobalLogger().GetLogger("outbox"),
+	}
+}
+
+// Run publishes the due messages batch by batch until none is left. Relays
+// running at the same time, e.g. a manual run next to the scheduler job,
+// skip the batches the others claimed, so no message is delivered by both
+// while the lease lasts.
+func (r *Relay) Run(ctx context.Context) error {
+	for {
+		count, err := r.runBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if count < int(r.options.BatchSize) {
+			return nil
+		}
+	}
+}
+
+// runBatch delivers the oldest due messages. Claiming them pushes their next
+// attempt past the lease in a statement of its own, so no transaction stays
+// open while the sinks deliver; messages a relay claimed but never marked,
+// because it stopped or outlived the lease, are delivered again afterwards.
+func (r *Relay) runBatch(ctx context.Context) (int, error) {
+	queries := gen.New(pgxc.GetDB(ctx, r.db))
+
+	now := time.Now()
+	batch, err := queries.ClaimDueOutboxMessages(ctx, gen.ClaimDueOutboxMessagesParams{
+		LeaseUntil: now.Add(r.options.Lease),
+		Now:        now,
+		RowLimit:   r.options.BatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+	slices.SortFunc(batch, func(a, b *gen.ClaimDueOutboxMessagesRow) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	for _, due := range batch {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		message := &Message{
+			ID:        due.ID,
+			Name:      due.EventName,
+			Payload:   due.Payload,
+			CreatedAt: due.CreatedAt,
+		}
+
+		if err := r.deliver(ctx, message); err != nil {
+			retryAt := time.Now().Add(r.backoff(due.Attempts))
+			r.logger.Errorf("deliver %s #%d, retrying at %s: %v", message.Name, message.ID, retryAt.Format(time.RFC3339), err)
+
+			lastError := err.Error()
+			err = queries.MarkOutboxFailed(ctx, gen.MarkOutboxFailedParams{
+				ID:            message.ID,
+				LastError:     &lastError,
+				NextAttemptAt: retryAt,
+			})
+			if err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		publishedAt := time.Now()
+		err := queries.MarkOutboxPublished(ctx, gen.MarkOutboxPublishedParams{
+			ID:          message.ID,
+			PublishedAt: &publishedAt,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(batch), nil
+}
+
+func (r *Relay) deliver(ctx context.Context, message *Message) error {
+	var errs []error
+	for _, sink := range r.sinks {
+		if err := sink.Publish(ctx, message); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// backoff doubles the retry wait with every failed attempt
+func (r *Relay) backoff(attempts int32) time.Duration {
+	wait := r.options.RetryBackoff
+	for i := int32(0); i < attempts && wait < r.options.MaxRetryBackoff; i++ {
+		wait *= 2
+	}
+
+	return min(wait, r.options.MaxRetryBackoff)
+}
@@ -0,0 +1,146 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mallbots/plugins/eventbus"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// SignatureHeader carries the hex encoded HMAC-SHA256 of the webhook body
+	SignatureHeader = "X-Outbox-Signature"
+	// MessageIDHeader repeats the message ID so receivers can drop duplicates
+	// without parsing the body
+	MessageIDHeader = "X-Outbox-Message-ID"
+
+	// maxNotifyPayload keeps NOTIFY payloads under the 8000 byte Postgres limit
+	maxNotifyPayload = 7999
+
+	defaultWebhookTimeout = 10 * time.Second
+)
+
+// Sink is somewhere relayed messages go
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, message *Message) error
+}
+
+type busSink struct {
+	bus eventbus.Bus
+}
+
+// NewBusSink hands messages to in-process subscribers. They receive a
+// *Message named after the event and decode its payload themselves.
+func NewBusSink(bus eventbus.Bus) Sink {
+	return &busSink{bus: bus}
+}
+
+func (s *busSink) Name() string {
+	return "bus"
+}
+
+func (s *busSink) Publish(ctx context.Context, message *Message) error {
+	return s.bus.Publish(ctx, message)
+}
+
+type notifySink struct {
+	db      *pgxpool.Pool
+	channel string
+}
+
+// NewNotifySink sends messages as JSON on a Postgres LISTEN/NOTIFY channel.
+// Messages too large for a notification go out without their payload;
+// listeners read it from the outbox row instead.
+func NewNotifySink(db *pgxpool.Pool, channel string) Sink {
+	return &notifySink{db: db, channel: channel}
+}
+
+func (s *notifySink) Name() string {
+	return "notify:" + s.channel
+}
+
+func (s *notifySink) Publish(ctx context.Context, message *Message) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	if len(body) > maxNotifyPayload {
+		body, err = json.Marshal(&Message{ID: message.ID, Name: message.Name, CreatedAt: message.CreatedAt})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = s.db.Exec(ctx, "SELECT pg_notify($1, $2)", s.channel, string(body))
+	return err
+}
+
+type webhookSink struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookSink POSTs messages as JSON to url. With a secret the body is
+// signed in SignatureHeader. Any status outside 2xx counts as a failure.
+func NewWebhookSink(url, secret string, timeout time.Duration) Sink {
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	return &webhookSink{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *webhookSink) Name() string {
+	return "webhook:" + s.url
+}
+
+func (s *webhookSink) Publish(ctx context.Context, message *Message) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(MessageIDHeader, strconv.FormatInt(message.ID, 10))
+	if s.secret != "" {
+		req.Header.Set(SignatureHeader, sign(s.secret, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
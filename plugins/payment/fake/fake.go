@@ -0,0 +1,107 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"mallbots/plugins/payment"
+	"mallbots/shared/money"
+	"strings"
+
+	sctx "github.com/phathdt/service-context"
+)
+
+const intentPrefix = "pi_fake_"
+
+// fakeProvider is a deterministic, in-process provider for local development
+// and tests. Every intent succeeds, identifiers are derived from the input and
+// webhooks are signed with the configured secret.
+type fakeProvider struct {
+	id     string
+	secret string
+}
+
+func New(id string) *fakeProvider {
+	return &fakeProvider{id: id}
+}
+
+// NewWithSecret builds a provider without going through the service context
+func NewWithSecret(id, secret string) *fakeProvider {
+	return &fakeProvider{id: id, secret: secret}
+}
+
+func (p *fakeProvider) ID() string {
+	return p.id
+}
+
+func (p *fakeProvider) InitFlags() {
+	flag.StringVar(&p.secret, "payment-webhook-secret", "fake-webhook-secret", "Secret used to sign payment webhooks")
+}
+
+func (p *fakeProvider) Activate(_ sctx.ServiceContext) error {
+	return nil
+}
+
+func (p *fakeProvider) Stop() error {
+	return nil
+}
+
+func (p *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeProvider) CreateIntent(_ context.Context, req payment.IntentRequest) (*payment.Intent, error) {
+	intentID := fmt.Sprintf("%s%d", intentPrefix, req.OrderID)
+
+	return &payment.Intent{
+		ID:           intentID,
+		Amount:       req.Amount,
+		Status:       payment.IntentStatusRequiresCapture,
+		ClientSecret: intentID + "_secret",
+	}, nil
+}
+
+func (p *fakeProvider) Capture(_ context.Context, intentID string) (*payment.Intent, error) {
+	if !strings.HasPrefix(intentID, intentPrefix) {
+		return nil, payment.ErrIntentNotFound
+	}
+
+	return &payment.Intent{
+		ID:     intentID,
+		Status: payment.IntentStatusSucceeded,
+	}, nil
+}
+
+func (p *fakeProvider) Refund(_ context.Context, intentID string, amount money.Money) (*payment.Refund, error) {
+	if !strings.HasPrefix(intentID, intentPrefix) {
+		return nil, payment.ErrIntentNotFound
+	}
+
+	return &payment.Refund{
+		ID:       fmt.Sprintf("re_fake_%s_%d", strings.TrimPrefix(intentID, intentPrefix), amount.Minor()),
+		IntentID: intentID,
+		Amount:   amount,
+	}, nil
+}
+
+func (p *fakeProvider) CancelIntent(_ context.Context, intentID string) error {
+	if !strings.HasPrefix(intentID, intentPrefix) {
+		return payment.ErrIntentNotFound
+	}
+
+	return nil
+}
+
+func (p *fakeProvider) VerifyWebhook(payload []byte, signature string) (*payment.WebhookEvent, error) {
+	if !payment.VerifySignature(p.secret, payload, signature) {
+		return nil, payment.ErrInvalidSignature
+	}
+
+	var event payment.WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil || event.ID == "" || event.IntentID == "" {
+		return nil, payment.ErrInvalidPayload
+	}
+
+	return &event, nil
+}
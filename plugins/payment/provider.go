@@ -0,0 +1,96 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"mallbots/shared/money"
+)
+
+// SignatureHeader carries the hex encoded HMAC-SHA256 of the webhook body
+const SignatureHeader = "X-Payment-Signature"
+
+type PaymentProvider interface {
+	Name() string
+	CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error)
+	Capture(ctx context.Context, intentID string) (*Intent, error)
+	Refund(ctx context.Context, intentID string, amount money.Money) (*Refund, error)
+	// CancelIntent stops an intent that has not been captured from being
+	// paid, later payment attempts on it fail
+	CancelIntent(ctx context.Context, intentID string) error
+	// VerifyWebhook checks the signature of a webhook body and decodes it
+	VerifyWebhook(payload []byte, signature string) (*WebhookEvent, error)
+}
+
+type IntentStatus string
+
+const (
+	IntentStatusRequiresCapture IntentStatus = "REQUIRES_CAPTURE"
+	IntentStatusSucceeded       IntentStatus = "SUCCEEDED"
+)
+
+type EventType string
+
+const (
+	EventPaymentSucceeded EventType = "payment.succeeded"
+	EventPaymentFailed    EventType = "payment.failed"
+	EventPaymentRefunded  EventType = "payment.refunded"
+)
+
+// IntentRequest charges Amount, in the currency it carries
+type IntentRequest struct {
+	OrderID int32
+	Amount  money.Money
+}
+
+type Intent struct {
+	ID           string
+	Amount       money.Money
+	Status       IntentStatus
+	ClientSecret string
+}
+
+type Refund struct {
+	ID       string
+	IntentID string
+	Amount   money.Money
+}
+
+// WebhookEvent is a verified notification from the provider. ID is unique per
+// event and stays the same when the provider retries a delivery. Amount is a
+// decimal in the currency of the intent; for payment.refunded it is the total
+// refunded on the intent so far.
+type WebhookEvent struct {
+	ID       string      `json:"id"`
+	Type     EventType   `json:"type"`
+	IntentID string      `json:"intent_id"`
+	Amount   json.Number `json:"amount"`
+}
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrInvalidPayload   = errors.New("invalid webhook payload")
+	ErrIntentNotFound   = errors.New("payment intent not found")
+)
+
+// Sign returns the signature a provider sends for payload
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature compares signature with the expected one in constant time
+func VerifySignature(secret string, payload []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
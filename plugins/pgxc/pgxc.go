@@ -59,6 +59,7 @@ func (p *pgxComp) Activate(_ sctx.ServiceContext) error {
 		Logger:   &PgxLogAdapter{logger: p.logger},
 		LogLevel: tracelog.LogLevelDebug,
 	}
+	config.AfterConnect = registerUTCTimestamp
 
 	pool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
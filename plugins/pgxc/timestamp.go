@@ -0,0 +1,48 @@
+package pgxc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// registerUTCTimestamp makes the connection write timestamp columns in UTC.
+// A timestamp without time zone keeps the wall clock it is given, pgx would
+// otherwise store the clock of whatever zone the time was taken in, and
+// times taken with time.Now() would not compare with the UTC ones.
+func registerUTCTimestamp(_ context.Context, conn *pgx.Conn) error {
+	conn.TypeMap().RegisterType(&pgtype.Type{
+		Name:  "timestamp",
+		OID:   pgtype.TimestampOID,
+		Codec: &utcTimestampCodec{},
+	})
+	return nil
+}
+
+type utcTimestampCodec struct {
+	pgtype.TimestampCodec
+}
+
+func (c *utcTimestampCodec) PlanEncode(m *pgtype.Map, oid uint32, format int16, value any) pgtype.EncodePlan {
+	plan := c.TimestampCodec.PlanEncode(m, oid, format, value)
+	if _, ok := value.(pgtype.TimestampValuer); !ok || plan == nil {
+		return plan
+	}
+
+	return &utcTimestampEncodePlan{next: plan}
+}
+
+type utcTimestampEncodePlan struct {
+	next pgtype.EncodePlan
+}
+
+func (p *utcTimestampEncodePlan) Encode(value any, buf []byte) ([]byte, error) {
+	ts, err := value.(pgtype.TimestampValuer).TimestampValue()
+	if err != nil {
+		return nil, err
+	}
+
+	ts.Time = ts.Time.UTC()
+	return p.next.Encode(ts, buf)
+}
@@ -0,0 +1,77 @@
+package pgxc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DBTX is the subset of pgx shared by *pgxpool.Pool and pgx.Tx that the
+// sqlc generated queries need.
+type DBTX interface {
+	Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error)
+	Query(context.Context, string, ...interface{}) (pgx.Rows, error)
+	QueryRow(context.Context, string, ...interface{}) pgx.Row
+}
+
+type txKey struct{}
+
+// TxManager runs a unit of work inside a single database transaction.
+type TxManager interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txManager struct {
+	pool *pgxpool.Pool
+}
+
+func NewTxManager(pool *pgxpool.Pool) TxManager {
+	return &txManager{pool: pool}
+}
+
+func (m *txManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return WithTx(ctx, m.pool, fn)
+}
+
+// WithTx begins a transaction on pool, stores it in the context passed to fn
+// and commits when fn returns nil. When ctx already carries a transaction fn
+// joins it, so the outermost caller decides when to commit or roll back.
+func WithTx(ctx context.Context, pool *pgxpool.Pool, fn func(ctx context.Context) error) error {
+	if _, ok := TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(ContextWithTx(ctx, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ContextWithTx returns a copy of ctx carrying tx.
+func ContextWithTx(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the transaction stored in ctx, if any.
+func TxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// GetDB returns the transaction stored in ctx, falling back to pool.
+func GetDB(ctx context.Context, pool *pgxpool.Pool) DBTX {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+
+	return pool
+}
@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"context"
+	"hash/fnv"
+	"mallbots/plugins/pgxc"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	sctx "github.com/phathdt/service-context"
+)
+
+// Job is a unit of background work run again every Interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Scheduler interface {
+	// Schedule starts running the job until the service stops
+	Schedule(job Job)
+}
+
+type scheduler struct {
+	id     string
+	pgxKey string
+	logger sctx.Logger
+	pool   *pgxpool.Pool
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a scheduler that runs each job on a single replica at a time.
+// Before every run the replica takes a Postgres advisory lock named after the
+// job on the database registered under pgxKey; replicas that miss the lock
+// skip the run.
+func New(id string, pgxKey string) *scheduler {
+	return &scheduler{id: id, pgxKey: pgxKey}
+}
+
+func (s *scheduler) ID() string {
+	return s.id
+}
+
+func (s *scheduler) InitFlags() {}
+
+func (s *scheduler) Activate(sc sctx.ServiceContext) error {
+	s.logger = sctx.GlobalLogger().GetLogger(s.id)
+	s.pool = sc.MustGet(s.pgxKey).(pgxc.PgxComp).GetConn()
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	return nil
+}
+
+func (s *scheduler) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	return nil
+}
+
+func (s *scheduler) Schedule(job Job) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(job.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.runLocked(job); err != nil {
+					s.logger.Errorf("run %s: %v", job.Name, err)
+				}
+			}
+		}
+	}()
+}
+
+// runLocked runs the job while holding its advisory lock. The lock belongs to
+// the session, so it is taken and released on one pooled connection and goes
+// away on its own if that connection dies.
+func (s *scheduler) runLocked(job Job) error {
+	conn, err := s.pool.Acquire(s.ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	key := lockKey(job.Name)
+
+	var locked bool
+	if err := conn.QueryRow(s.ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked); err != nil {
+		return err
+	}
+	if !locked {
+		return nil
+	}
+	defer func() {
+		// Unlock with a fresh context so a stopping service still frees the lock
+		if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", key); err != nil {
+			s.logger.Errorf("unlock %s: %v", job.Name, err)
+		}
+	}()
+
+	return job.Run(s.ctx)
+}
+
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+
+	return int64(h.Sum64())
+}
@@ -56,7 +56,8 @@ func (j *jwtProvider) Generate(data tokenprovider.TokenPayload, expiry int) (tok
 		Payload: common.TokenPayload{
 			UserId:   data.GetUserId(),
 			Email:    data.GetEmail(),
-			SubToken: data.GetSubToken()},
+			SubToken: data.GetSubToken(),
+			Role:     data.GetRole()},
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Unix(now.Local().Add(time.Second*time.Duration(expiry)).Unix(), 0)),
 			IssuedAt:  jwt.NewNumericDate(time.Unix(now.Local().Unix(), 0)),
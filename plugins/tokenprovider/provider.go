@@ -14,6 +14,7 @@ type TokenPayload interface {
 	GetUserId() int32
 	GetSubToken() string
 	GetEmail() string
+	GetRole() string
 }
 
 type Token interface {
@@ -0,0 +1,3 @@
+-- AlterTable
+ALTER TABLE "orders" ADD COLUMN     "cancel_reason" TEXT,
+ADD COLUMN     "cancelled_at" TIMESTAMP(3);
@@ -0,0 +1,20 @@
+-- CreateTable
+CREATE TABLE "order_events" (
+    "id" SERIAL NOT NULL,
+    "order_id" INTEGER NOT NULL,
+    "event_type" TEXT NOT NULL,
+    "from_status" TEXT,
+    "to_status" TEXT,
+    "actor_id" INTEGER,
+    "actor_role" TEXT NOT NULL,
+    "metadata" JSONB NOT NULL DEFAULT '{}',
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "order_events_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "order_events_order_id_created_at_idx" ON "order_events"("order_id", "created_at");
+
+-- AddForeignKey
+ALTER TABLE "order_events" ADD CONSTRAINT "order_events_order_id_fkey" FOREIGN KEY ("order_id") REFERENCES "orders"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
@@ -0,0 +1,28 @@
+-- AlterTable
+ALTER TABLE "orders" ADD COLUMN     "payment_intent_id" TEXT,
+ADD COLUMN     "payment_provider" TEXT;
+
+-- AlterTable
+ALTER TABLE "refunds" ADD COLUMN     "provider_refund_id" TEXT;
+
+-- CreateTable
+CREATE TABLE "payment_webhook_events" (
+    "id" SERIAL NOT NULL,
+    "provider" TEXT NOT NULL,
+    "event_id" TEXT NOT NULL,
+    "event_type" TEXT NOT NULL,
+    "order_id" INTEGER NOT NULL,
+    "payload" JSONB NOT NULL,
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "payment_webhook_events_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "orders_payment_intent_id_key" ON "orders"("payment_intent_id");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "payment_webhook_events_provider_event_id_key" ON "payment_webhook_events"("provider", "event_id");
+
+-- AddForeignKey
+ALTER TABLE "payment_webhook_events" ADD CONSTRAINT "payment_webhook_events_order_id_fkey" FOREIGN KEY ("order_id") REFERENCES "orders"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
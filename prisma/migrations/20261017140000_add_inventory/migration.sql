@@ -0,0 +1,16 @@
+-- CreateTable
+CREATE TABLE "inventory" (
+    "product_id" INTEGER NOT NULL,
+    "on_hand" INTEGER NOT NULL DEFAULT 0,
+    "reserved" INTEGER NOT NULL DEFAULT 0,
+    "updated_at" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "inventory_pkey" PRIMARY KEY ("product_id")
+);
+
+-- AddForeignKey
+ALTER TABLE "inventory" ADD CONSTRAINT "inventory_product_id_fkey" FOREIGN KEY ("product_id") REFERENCES "products"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- Existing products start out of stock until they are counted
+INSERT INTO "inventory" ("product_id", "on_hand", "reserved", "updated_at")
+SELECT "id", 0, 0, CURRENT_TIMESTAMP FROM "products";
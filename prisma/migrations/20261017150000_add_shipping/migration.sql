@@ -0,0 +1,5 @@
+-- AlterTable
+ALTER TABLE "products" ADD COLUMN     "weight" INTEGER NOT NULL DEFAULT 0;
+
+-- AlterTable
+ALTER TABLE "orders" ADD COLUMN     "shipping_amount" DOUBLE PRECISION NOT NULL DEFAULT 0;
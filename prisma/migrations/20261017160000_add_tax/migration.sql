@@ -0,0 +1,7 @@
+-- AlterTable
+ALTER TABLE "orders" ADD COLUMN     "tax_amount" DOUBLE PRECISION NOT NULL DEFAULT 0,
+ADD COLUMN     "tax_inclusive" BOOLEAN NOT NULL DEFAULT false;
+
+-- AlterTable
+ALTER TABLE "order_items" ADD COLUMN     "tax_rate" DOUBLE PRECISION NOT NULL DEFAULT 0,
+ADD COLUMN     "tax_amount" DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,44 @@
+-- Amounts move from DOUBLE PRECISION to BIGINT minor units (cents), each next
+-- to the currency it is expressed in. Existing rows are rounded half away from
+-- zero, which is what ROUND does for NUMERIC.
+
+-- AlterTable
+ALTER TABLE "products" ALTER COLUMN "price" SET DATA TYPE BIGINT USING ROUND("price"::NUMERIC * 100),
+ADD COLUMN     "currency" TEXT NOT NULL DEFAULT 'USD';
+
+-- AlterTable
+ALTER TABLE "cart_items" ALTER COLUMN "price" SET DATA TYPE BIGINT USING ROUND("price"::NUMERIC * 100),
+ADD COLUMN     "currency" TEXT NOT NULL DEFAULT 'USD';
+
+-- AlterTable
+ALTER TABLE "orders" ALTER COLUMN "total_amount" SET DATA TYPE BIGINT USING ROUND("total_amount"::NUMERIC * 100),
+ALTER COLUMN "shipping_amount" SET DATA TYPE BIGINT USING ROUND("shipping_amount"::NUMERIC * 100),
+ALTER COLUMN "tax_amount" SET DATA TYPE BIGINT USING ROUND("tax_amount"::NUMERIC * 100),
+ALTER COLUMN "discount_amount" SET DATA TYPE BIGINT USING ROUND("discount_amount"::NUMERIC * 100),
+ADD COLUMN     "currency" TEXT NOT NULL DEFAULT 'USD';
+
+-- AlterTable
+ALTER TABLE "order_items" ALTER COLUMN "price" SET DATA TYPE BIGINT USING ROUND("price"::NUMERIC * 100),
+ALTER COLUMN "tax_amount" SET DATA TYPE BIGINT USING ROUND("tax_amount"::NUMERIC * 100),
+ALTER COLUMN "discount_amount" SET DATA TYPE BIGINT USING ROUND("discount_amount"::NUMERIC * 100),
+ADD COLUMN     "currency" TEXT NOT NULL DEFAULT 'USD';
+
+-- AlterTable
+ALTER TABLE "refunds" ALTER COLUMN "amount" SET DATA TYPE BIGINT USING ROUND("amount"::NUMERIC * 100),
+ADD COLUMN     "currency" TEXT NOT NULL DEFAULT 'USD';
+
+-- AlterTable
+ALTER TABLE "refund_items" ALTER COLUMN "amount" SET DATA TYPE BIGINT USING ROUND("amount"::NUMERIC * 100),
+ADD COLUMN     "currency" TEXT NOT NULL DEFAULT 'USD';
+
+-- AlterTable
+-- The value of a fixed amount coupon moves to "amount", "value" is a percentage only
+ALTER TABLE "coupons" ADD COLUMN     "amount" BIGINT NOT NULL DEFAULT 0,
+ADD COLUMN     "currency" TEXT NOT NULL DEFAULT 'USD',
+ALTER COLUMN "min_spend" SET DATA TYPE BIGINT USING ROUND("min_spend"::NUMERIC * 100);
+
+UPDATE "coupons" SET "amount" = ROUND("value"::NUMERIC * 100), "value" = 0 WHERE "type" = 'FIXED_AMOUNT';
+
+-- AlterTable
+ALTER TABLE "coupon_redemptions" ALTER COLUMN "discount_amount" SET DATA TYPE BIGINT USING ROUND("discount_amount"::NUMERIC * 100),
+ADD COLUMN     "currency" TEXT NOT NULL DEFAULT 'USD';
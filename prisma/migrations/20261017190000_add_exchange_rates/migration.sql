@@ -0,0 +1,34 @@
+-- Order items keep the product price they were converted from. Every existing
+-- order was placed in its products' currency, so the converted price is it.
+
+-- AlterTable
+ALTER TABLE "orders" ADD COLUMN     "exchange_rate_id" INTEGER,
+ADD COLUMN     "exchange_rate" DECIMAL(20,10) NOT NULL DEFAULT 1;
+
+-- AlterTable
+ALTER TABLE "order_items" ADD COLUMN     "product_price" BIGINT NOT NULL DEFAULT 0,
+ADD COLUMN     "product_currency" TEXT NOT NULL DEFAULT 'USD',
+ADD COLUMN     "exchange_rate_id" INTEGER,
+ADD COLUMN     "exchange_rate" DECIMAL(20,10) NOT NULL DEFAULT 1;
+
+UPDATE "order_items" SET "product_price" = "price", "product_currency" = "currency";
+
+-- CreateTable
+CREATE TABLE "exchange_rates" (
+    "id" SERIAL NOT NULL,
+    "currency" TEXT NOT NULL,
+    "rate" DECIMAL(20,10) NOT NULL,
+    "source" TEXT NOT NULL,
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "exchange_rates_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "exchange_rates_currency_created_at_idx" ON "exchange_rates"("currency", "created_at");
+
+-- AddForeignKey
+ALTER TABLE "orders" ADD CONSTRAINT "orders_exchange_rate_id_fkey" FOREIGN KEY ("exchange_rate_id") REFERENCES "exchange_rates"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "order_items" ADD CONSTRAINT "order_items_exchange_rate_id_fkey" FOREIGN KEY ("exchange_rate_id") REFERENCES "exchange_rates"("id") ON DELETE SET NULL ON UPDATE CASCADE;
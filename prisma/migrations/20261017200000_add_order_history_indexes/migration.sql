@@ -0,0 +1,5 @@
+-- CreateIndex
+CREATE INDEX "orders_user_id_created_at_idx" ON "orders"("user_id", "created_at");
+
+-- CreateIndex
+CREATE INDEX "order_items_order_id_idx" ON "order_items"("order_id");
@@ -0,0 +1,31 @@
+-- CreateTable
+CREATE TABLE "invoices" (
+    "id" SERIAL NOT NULL,
+    "order_id" INTEGER NOT NULL,
+    "year" INTEGER NOT NULL,
+    "sequence" INTEGER NOT NULL,
+    "number" TEXT NOT NULL,
+    "issued_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "invoices_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "invoice_counters" (
+    "year" INTEGER NOT NULL,
+    "last_sequence" INTEGER NOT NULL DEFAULT 0,
+
+    CONSTRAINT "invoice_counters_pkey" PRIMARY KEY ("year")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "invoices_order_id_key" ON "invoices"("order_id");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "invoices_number_key" ON "invoices"("number");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "invoices_year_sequence_key" ON "invoices"("year", "sequence");
+
+-- AddForeignKey
+ALTER TABLE "invoices" ADD CONSTRAINT "invoices_order_id_fkey" FOREIGN KEY ("order_id") REFERENCES "orders"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
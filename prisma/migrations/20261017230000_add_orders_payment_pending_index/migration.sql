@@ -0,0 +1,2 @@
+-- CreateIndex
+CREATE INDEX "orders_status_payment_status_created_at_idx" ON "orders"("status", "payment_status", "created_at");
@@ -0,0 +1,18 @@
+-- Order items keep the product as it was when the order was placed, so a
+-- renamed or deleted product does not change order history. Existing items
+-- take the product as it is now.
+
+-- AlterTable
+ALTER TABLE "order_items" ADD COLUMN     "product_name" TEXT NOT NULL DEFAULT '',
+ADD COLUMN     "product_description" TEXT,
+ADD COLUMN     "category_id" INTEGER,
+ADD COLUMN     "category_name" TEXT;
+
+UPDATE "order_items"
+SET "product_name" = "products"."name",
+    "product_description" = "products"."description",
+    "category_id" = "products"."category_id",
+    "category_name" = "categories"."name"
+FROM "products"
+LEFT JOIN "categories" ON "categories"."id" = "products"."category_id"
+WHERE "products"."id" = "order_items"."product_id";
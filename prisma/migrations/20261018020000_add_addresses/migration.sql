@@ -0,0 +1,42 @@
+-- Users keep an address book to check out with. Orders copy the addresses
+-- they were placed with and gain a billing address, which for existing
+-- orders is their shipping address.
+
+-- AlterTable
+ALTER TABLE "orders" ADD COLUMN     "billing_address" TEXT NOT NULL DEFAULT '',
+ADD COLUMN     "billing_city" TEXT NOT NULL DEFAULT '',
+ADD COLUMN     "billing_country" TEXT NOT NULL DEFAULT '',
+ADD COLUMN     "billing_zip" TEXT NOT NULL DEFAULT '';
+
+UPDATE "orders"
+SET "billing_address" = "shipping_address",
+    "billing_city" = "shipping_city",
+    "billing_country" = "shipping_country",
+    "billing_zip" = "shipping_zip";
+
+-- CreateTable
+CREATE TABLE "addresses" (
+    "id" SERIAL NOT NULL,
+    "user_id" INTEGER NOT NULL,
+    "label" TEXT,
+    "address" TEXT NOT NULL,
+    "city" TEXT NOT NULL,
+    "country" TEXT NOT NULL,
+    "zip" TEXT NOT NULL,
+    "is_default_shipping" BOOLEAN NOT NULL DEFAULT false,
+    "is_default_billing" BOOLEAN NOT NULL DEFAULT false,
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "addresses_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "addresses_user_id_idx" ON "addresses"("user_id");
+
+-- A user has at most one default shipping and one default billing address
+CREATE UNIQUE INDEX "addresses_user_id_default_shipping_key" ON "addresses"("user_id") WHERE "is_default_shipping";
+CREATE UNIQUE INDEX "addresses_user_id_default_billing_key" ON "addresses"("user_id") WHERE "is_default_billing";
+
+-- AddForeignKey
+ALTER TABLE "addresses" ADD CONSTRAINT "addresses_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,8 @@
+-- DropIndex
+DROP INDEX "orders_user_id_created_at_idx";
+
+-- CreateIndex
+CREATE INDEX "orders_user_id_created_at_id_idx" ON "orders"("user_id", "created_at", "id");
+
+-- CreateIndex
+CREATE INDEX "orders_user_id_currency_total_amount_id_idx" ON "orders"("user_id", "currency", "total_amount", "id");
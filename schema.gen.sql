@@ -3,8 +3,10 @@ CREATE TABLE "products" (
     "id" SERIAL NOT NULL,
     "name" TEXT NOT NULL,
     "description" TEXT,
-    "price" DOUBLE PRECISION NOT NULL,
+    "price" BIGINT NOT NULL,
+    "weight" INTEGER NOT NULL DEFAULT 0,
     "category_id" INTEGER NOT NULL,
+    "currency" TEXT NOT NULL DEFAULT 'USD',
     "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
     "updated_at" TIMESTAMP(3) NOT NULL,
 
@@ -40,7 +42,8 @@ CREATE TABLE "cart_items" (
     "user_id" INTEGER NOT NULL,
     "product_id" INTEGER NOT NULL,
     "quantity" INTEGER NOT NULL,
-    "price" DOUBLE PRECISION NOT NULL,
+    "price" BIGINT NOT NULL,
+    "currency" TEXT NOT NULL DEFAULT 'USD',
     "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
     "updated_at" TIMESTAMP(3) NOT NULL,
 
@@ -53,11 +56,27 @@ CREATE TABLE "orders" (
     "user_id" INTEGER NOT NULL,
     "status" TEXT NOT NULL DEFAULT 'PENDING',
     "payment_status" TEXT NOT NULL DEFAULT 'PENDING',
-    "total_amount" DOUBLE PRECISION NOT NULL,
+    "total_amount" BIGINT NOT NULL,
     "shipping_address" TEXT NOT NULL,
     "shipping_city" TEXT NOT NULL,
     "shipping_country" TEXT NOT NULL,
     "shipping_zip" TEXT NOT NULL,
+    "cancel_reason" TEXT,
+    "cancelled_at" TIMESTAMP(3),
+    "payment_provider" TEXT,
+    "payment_intent_id" TEXT,
+    "shipping_amount" BIGINT NOT NULL DEFAULT 0,
+    "tax_amount" BIGINT NOT NULL DEFAULT 0,
+    "tax_inclusive" BOOLEAN NOT NULL DEFAULT false,
+    "discount_amount" BIGINT NOT NULL DEFAULT 0,
+    "coupon_code" TEXT,
+    "currency" TEXT NOT NULL DEFAULT 'USD',
+    "exchange_rate_id" INTEGER,
+    "exchange_rate" DECIMAL(20,10) NOT NULL DEFAULT 1,
+    "billing_address" TEXT NOT NULL DEFAULT '',
+    "billing_city" TEXT NOT NULL DEFAULT '',
+    "billing_country" TEXT NOT NULL DEFAULT '',
+    "billing_zip" TEXT NOT NULL DEFAULT '',
     "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
     "updated_at" TIMESTAMP(3) NOT NULL,
 
@@ -70,13 +89,245 @@ CREATE TABLE "order_items" (
     "order_id" INTEGER NOT NULL,
     "product_id" INTEGER NOT NULL,
     "quantity" INTEGER NOT NULL,
-    "price" DOUBLE PRECISION NOT NULL,
+    "price" BIGINT NOT NULL,
+    "tax_rate" DOUBLE PRECISION NOT NULL DEFAULT 0,
+    "tax_amount" BIGINT NOT NULL DEFAULT 0,
+    "discount_amount" BIGINT NOT NULL DEFAULT 0,
+    "currency" TEXT NOT NULL DEFAULT 'USD',
+    "product_price" BIGINT NOT NULL DEFAULT 0,
+    "product_currency" TEXT NOT NULL DEFAULT 'USD',
+    "exchange_rate_id" INTEGER,
+    "exchange_rate" DECIMAL(20,10) NOT NULL DEFAULT 1,
+    "product_name" TEXT NOT NULL DEFAULT '',
+    "product_description" TEXT,
+    "category_id" INTEGER,
+    "category_name" TEXT,
     "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
     "updated_at" TIMESTAMP(3) NOT NULL,
 
     CONSTRAINT "order_items_pkey" PRIMARY KEY ("id")
 );
 
+-- CreateTable
+CREATE TABLE "refunds" (
+    "id" SERIAL NOT NULL,
+    "order_id" INTEGER NOT NULL,
+    "user_id" INTEGER NOT NULL,
+    "status" TEXT NOT NULL DEFAULT 'REQUESTED',
+    "amount" BIGINT NOT NULL,
+    "reason" TEXT NOT NULL,
+    "approved_at" TIMESTAMP(3),
+    "processed_at" TIMESTAMP(3),
+    "rejected_at" TIMESTAMP(3),
+    "provider_refund_id" TEXT,
+    "currency" TEXT NOT NULL DEFAULT 'USD',
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "refunds_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "refund_items" (
+    "id" SERIAL NOT NULL,
+    "refund_id" INTEGER NOT NULL,
+    "order_item_id" INTEGER NOT NULL,
+    "quantity" INTEGER NOT NULL,
+    "amount" BIGINT NOT NULL,
+    "currency" TEXT NOT NULL DEFAULT 'USD',
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "refund_items_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "order_events" (
+    "id" SERIAL NOT NULL,
+    "order_id" INTEGER NOT NULL,
+    "event_type" TEXT NOT NULL,
+    "from_status" TEXT,
+    "to_status" TEXT,
+    "actor_id" INTEGER,
+    "actor_role" TEXT NOT NULL,
+    "metadata" JSONB NOT NULL DEFAULT '{}',
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "order_events_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "payment_webhook_events" (
+    "id" SERIAL NOT NULL,
+    "provider" TEXT NOT NULL,
+    "event_id" TEXT NOT NULL,
+    "event_type" TEXT NOT NULL,
+    "order_id" INTEGER NOT NULL,
+    "payload" JSONB NOT NULL,
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "payment_webhook_events_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "idempotency_keys" (
+    "user_id" INTEGER NOT NULL,
+    "key" TEXT NOT NULL,
+    "fingerprint" TEXT NOT NULL,
+    "status_code" INTEGER,
+    "content_type" TEXT,
+    "response_body" BYTEA,
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "expires_at" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "idempotency_keys_pkey" PRIMARY KEY ("user_id","key")
+);
+
+-- CreateTable
+CREATE TABLE "inventory" (
+    "product_id" INTEGER NOT NULL,
+    "on_hand" INTEGER NOT NULL DEFAULT 0,
+    "reserved" INTEGER NOT NULL DEFAULT 0,
+    "updated_at" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "inventory_pkey" PRIMARY KEY ("product_id")
+);
+
+-- CreateTable
+CREATE TABLE "coupons" (
+    "id" SERIAL NOT NULL,
+    "code" TEXT NOT NULL,
+    "type" TEXT NOT NULL,
+    "value" DOUBLE PRECISION NOT NULL DEFAULT 0,
+    "buy_quantity" INTEGER NOT NULL DEFAULT 0,
+    "get_quantity" INTEGER NOT NULL DEFAULT 0,
+    "min_spend" BIGINT NOT NULL DEFAULT 0,
+    "product_ids" INTEGER[],
+    "category_ids" INTEGER[],
+    "usage_limit" INTEGER,
+    "per_user_limit" INTEGER,
+    "used_count" INTEGER NOT NULL DEFAULT 0,
+    "starts_at" TIMESTAMP(3),
+    "ends_at" TIMESTAMP(3),
+    "active" BOOLEAN NOT NULL DEFAULT true,
+    "amount" BIGINT NOT NULL DEFAULT 0,
+    "currency" TEXT NOT NULL DEFAULT 'USD',
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "coupons_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "coupon_redemptions" (
+    "id" SERIAL NOT NULL,
+    "coupon_id" INTEGER NOT NULL,
+    "user_id" INTEGER NOT NULL,
+    "order_id" INTEGER NOT NULL,
+    "discount_amount" BIGINT NOT NULL,
+    "currency" TEXT NOT NULL DEFAULT 'USD',
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "coupon_redemptions_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "cart_coupons" (
+    "user_id" INTEGER NOT NULL,
+    "coupon_id" INTEGER NOT NULL,
+    "applied_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "cart_coupons_pkey" PRIMARY KEY ("user_id")
+);
+
+-- CreateTable
+CREATE TABLE "exchange_rates" (
+    "id" SERIAL NOT NULL,
+    "currency" TEXT NOT NULL,
+    "rate" DECIMAL(20,10) NOT NULL,
+    "source" TEXT NOT NULL,
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "exchange_rates_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "invoices" (
+    "id" SERIAL NOT NULL,
+    "order_id" INTEGER NOT NULL,
+    "year" INTEGER NOT NULL,
+    "sequence" INTEGER NOT NULL,
+    "number" TEXT NOT NULL,
+    "issued_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "invoices_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "invoice_counters" (
+    "year" INTEGER NOT NULL,
+    "last_sequence" INTEGER NOT NULL DEFAULT 0,
+
+    CONSTRAINT "invoice_counters_pkey" PRIMARY KEY ("year")
+);
+
+-- CreateTable
+CREATE TABLE "shipments" (
+    "id" SERIAL NOT NULL,
+    "order_id" INTEGER NOT NULL,
+    "status" TEXT NOT NULL DEFAULT 'PENDING',
+    "carrier" TEXT NOT NULL,
+    "tracking_number" TEXT,
+    "shipped_at" TIMESTAMP(3),
+    "delivered_at" TIMESTAMP(3),
+    "cancelled_at" TIMESTAMP(3),
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "shipments_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "shipment_items" (
+    "id" SERIAL NOT NULL,
+    "shipment_id" INTEGER NOT NULL,
+    "order_item_id" INTEGER NOT NULL,
+    "quantity" INTEGER NOT NULL,
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "shipment_items_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "outbox" (
+    "id" BIGSERIAL NOT NULL,
+    "event_name" TEXT NOT NULL,
+    "payload" JSONB NOT NULL,
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "last_error" TEXT,
+    "next_attempt_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "published_at" TIMESTAMP(3),
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "outbox_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "addresses" (
+    "id" SERIAL NOT NULL,
+    "user_id" INTEGER NOT NULL,
+    "label" TEXT,
+    "address" TEXT NOT NULL,
+    "city" TEXT NOT NULL,
+    "country" TEXT NOT NULL,
+    "zip" TEXT NOT NULL,
+    "is_default_shipping" BOOLEAN NOT NULL DEFAULT false,
+    "is_default_billing" BOOLEAN NOT NULL DEFAULT false,
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "addresses_pkey" PRIMARY KEY ("id")
+);
+
 -- CreateIndex
 CREATE INDEX "products_category_id_idx" ON "products"("category_id");
 
@@ -92,6 +343,81 @@ CREATE INDEX "cart_items_user_id_idx" ON "cart_items"("user_id");
 -- CreateIndex
 CREATE UNIQUE INDEX "cart_items_user_id_product_id_key" ON "cart_items"("user_id", "product_id");
 
+-- CreateIndex
+CREATE INDEX "refunds_order_id_idx" ON "refunds"("order_id");
+
+-- CreateIndex
+CREATE INDEX "refund_items_refund_id_idx" ON "refund_items"("refund_id");
+
+-- CreateIndex
+CREATE INDEX "refund_items_order_item_id_idx" ON "refund_items"("order_item_id");
+
+-- CreateIndex
+CREATE INDEX "order_events_order_id_created_at_idx" ON "order_events"("order_id", "created_at");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "orders_payment_intent_id_key" ON "orders"("payment_intent_id");
+
+-- CreateIndex
+CREATE INDEX "orders_user_id_created_at_id_idx" ON "orders"("user_id", "created_at", "id");
+
+-- CreateIndex
+CREATE INDEX "orders_user_id_currency_total_amount_id_idx" ON "orders"("user_id", "currency", "total_amount", "id");
+
+-- CreateIndex
+CREATE INDEX "orders_status_payment_status_created_at_idx" ON "orders"("status", "payment_status", "created_at");
+
+-- CreateIndex
+CREATE INDEX "order_items_order_id_idx" ON "order_items"("order_id");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "payment_webhook_events_provider_event_id_key" ON "payment_webhook_events"("provider", "event_id");
+
+-- CreateIndex
+CREATE INDEX "idempotency_keys_expires_at_idx" ON "idempotency_keys"("expires_at");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "coupons_code_key" ON "coupons"("code");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "coupon_redemptions_order_id_key" ON "coupon_redemptions"("order_id");
+
+-- CreateIndex
+CREATE INDEX "coupon_redemptions_coupon_id_user_id_idx" ON "coupon_redemptions"("coupon_id", "user_id");
+
+-- CreateIndex
+CREATE INDEX "exchange_rates_currency_created_at_idx" ON "exchange_rates"("currency", "created_at");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "invoices_order_id_key" ON "invoices"("order_id");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "invoices_number_key" ON "invoices"("number");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "invoices_year_sequence_key" ON "invoices"("year", "sequence");
+
+-- CreateIndex
+CREATE INDEX "shipments_order_id_idx" ON "shipments"("order_id");
+
+-- CreateIndex
+CREATE INDEX "shipment_items_order_item_id_idx" ON "shipment_items"("order_item_id");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "shipment_items_shipment_id_order_item_id_key" ON "shipment_items"("shipment_id", "order_item_id");
+
+-- CreateIndex
+CREATE INDEX "outbox_published_at_next_attempt_at_idx" ON "outbox"("published_at", "next_attempt_at");
+
+-- CreateIndex
+CREATE INDEX "addresses_user_id_idx" ON "addresses"("user_id");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "addresses_user_id_default_shipping_key" ON "addresses"("user_id") WHERE "is_default_shipping";
+
+-- CreateIndex
+CREATE UNIQUE INDEX "addresses_user_id_default_billing_key" ON "addresses"("user_id") WHERE "is_default_billing";
+
 -- AddForeignKey
 ALTER TABLE "products" ADD CONSTRAINT "products_category_id_fkey" FOREIGN KEY ("category_id") REFERENCES "categories"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
 
@@ -104,3 +430,56 @@ ALTER TABLE "cart_items" ADD CONSTRAINT "cart_items_product_id_fkey" FOREIGN KEY
 -- AddForeignKey
 ALTER TABLE "order_items" ADD CONSTRAINT "order_items_order_id_fkey" FOREIGN KEY ("order_id") REFERENCES "orders"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
 
+-- AddForeignKey
+ALTER TABLE "refunds" ADD CONSTRAINT "refunds_order_id_fkey" FOREIGN KEY ("order_id") REFERENCES "orders"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "refund_items" ADD CONSTRAINT "refund_items_refund_id_fkey" FOREIGN KEY ("refund_id") REFERENCES "refunds"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "refund_items" ADD CONSTRAINT "refund_items_order_item_id_fkey" FOREIGN KEY ("order_item_id") REFERENCES "order_items"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "order_events" ADD CONSTRAINT "order_events_order_id_fkey" FOREIGN KEY ("order_id") REFERENCES "orders"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "payment_webhook_events" ADD CONSTRAINT "payment_webhook_events_order_id_fkey" FOREIGN KEY ("order_id") REFERENCES "orders"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "inventory" ADD CONSTRAINT "inventory_product_id_fkey" FOREIGN KEY ("product_id") REFERENCES "products"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "coupon_redemptions" ADD CONSTRAINT "coupon_redemptions_coupon_id_fkey" FOREIGN KEY ("coupon_id") REFERENCES "coupons"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "coupon_redemptions" ADD CONSTRAINT "coupon_redemptions_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "coupon_redemptions" ADD CONSTRAINT "coupon_redemptions_order_id_fkey" FOREIGN KEY ("order_id") REFERENCES "orders"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "cart_coupons" ADD CONSTRAINT "cart_coupons_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "cart_coupons" ADD CONSTRAINT "cart_coupons_coupon_id_fkey" FOREIGN KEY ("coupon_id") REFERENCES "coupons"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "orders" ADD CONSTRAINT "orders_exchange_rate_id_fkey" FOREIGN KEY ("exchange_rate_id") REFERENCES "exchange_rates"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "order_items" ADD CONSTRAINT "order_items_exchange_rate_id_fkey" FOREIGN KEY ("exchange_rate_id") REFERENCES "exchange_rates"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "invoices" ADD CONSTRAINT "invoices_order_id_fkey" FOREIGN KEY ("order_id") REFERENCES "orders"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "shipments" ADD CONSTRAINT "shipments_order_id_fkey" FOREIGN KEY ("order_id") REFERENCES "orders"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "shipment_items" ADD CONSTRAINT "shipment_items_shipment_id_fkey" FOREIGN KEY ("shipment_id") REFERENCES "shipments"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "shipment_items" ADD CONSTRAINT "shipment_items_order_item_id_fkey" FOREIGN KEY ("order_item_id") REFERENCES "order_items"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "addresses" ADD CONSTRAINT "addresses_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE ON UPDATE CASCADE;
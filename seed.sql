@@ -9,46 +9,50 @@ INSERT INTO categories (name, created_at, updated_at) VALUES
     ('Gaming', NOW(), NOW()),
     ('Cameras', NOW(), NOW());
 
--- Seed Products
+-- Seed Products (prices in cents)
 INSERT INTO products (name, description, price, category_id, created_at, updated_at) VALUES
     -- Smartphones
-    ('iPhone 15 Pro', 'Latest Apple iPhone with A17 Pro chip', 999.99, 1, NOW(), NOW()),
-    ('Samsung Galaxy S24', 'Flagship Android phone with AI features', 899.99, 1, NOW(), NOW()),
-    ('Google Pixel 8', 'Pure Android experience with amazing camera', 799.99, 1, NOW(), NOW()),
+    ('iPhone 15 Pro', 'Latest Apple iPhone with A17 Pro chip', 99999, 1, NOW(), NOW()),
+    ('Samsung Galaxy S24', 'Flagship Android phone with AI features', 89999, 1, NOW(), NOW()),
+    ('Google Pixel 8', 'Pure Android experience with amazing camera', 79999, 1, NOW(), NOW()),
 
     -- Laptops
-    ('MacBook Pro 14"', 'Professional laptop with M3 Pro chip', 1999.99, 2, NOW(), NOW()),
-    ('Dell XPS 15', 'Premium Windows laptop with OLED display', 1799.99, 2, NOW(), NOW()),
-    ('Lenovo ThinkPad X1', 'Business laptop with great keyboard', 1599.99, 2, NOW(), NOW()),
+    ('MacBook Pro 14"', 'Professional laptop with M3 Pro chip', 199999, 2, NOW(), NOW()),
+    ('Dell XPS 15', 'Premium Windows laptop with OLED display', 179999, 2, NOW(), NOW()),
+    ('Lenovo ThinkPad X1', 'Business laptop with great keyboard', 159999, 2, NOW(), NOW()),
 
     -- Tablets
-    ('iPad Pro 12.9"', 'Powerful tablet for professionals', 1099.99, 3, NOW(), NOW()),
-    ('Samsung Galaxy Tab S9', 'Premium Android tablet with S Pen', 849.99, 3, NOW(), NOW()),
+    ('iPad Pro 12.9"', 'Powerful tablet for professionals', 109999, 3, NOW(), NOW()),
+    ('Samsung Galaxy Tab S9', 'Premium Android tablet with S Pen', 84999, 3, NOW(), NOW()),
 
     -- Accessories
-    ('AirPods Pro', 'Wireless earbuds with noise cancellation', 249.99, 4, NOW(), NOW()),
-    ('Apple Watch Series 9', 'Advanced health and fitness tracking', 399.99, 4, NOW(), NOW()),
-    ('Samsung Galaxy Watch 6', 'Elegant smartwatch with health features', 299.99, 4, NOW(), NOW()),
+    ('AirPods Pro', 'Wireless earbuds with noise cancellation', 24999, 4, NOW(), NOW()),
+    ('Apple Watch Series 9', 'Advanced health and fitness tracking', 39999, 4, NOW(), NOW()),
+    ('Samsung Galaxy Watch 6', 'Elegant smartwatch with health features', 29999, 4, NOW(), NOW()),
 
     -- Smart Home
-    ('Amazon Echo Show', 'Smart display with Alexa', 129.99, 5, NOW(), NOW()),
-    ('Google Nest Hub', 'Smart home controller with display', 99.99, 5, NOW(), NOW()),
-    ('Philips Hue Starter Kit', 'Smart lighting system', 199.99, 5, NOW(), NOW()),
+    ('Amazon Echo Show', 'Smart display with Alexa', 12999, 5, NOW(), NOW()),
+    ('Google Nest Hub', 'Smart home controller with display', 9999, 5, NOW(), NOW()),
+    ('Philips Hue Starter Kit', 'Smart lighting system', 19999, 5, NOW(), NOW()),
 
     -- Audio
-    ('Sony WH-1000XM5', 'Premium noise-cancelling headphones', 399.99, 6, NOW(), NOW()),
-    ('Bose QuietComfort', 'Comfortable noise-cancelling headphones', 379.99, 6, NOW(), NOW()),
-    ('JBL Flip 6', 'Portable Bluetooth speaker', 129.99, 6, NOW(), NOW()),
+    ('Sony WH-1000XM5', 'Premium noise-cancelling headphones', 39999, 6, NOW(), NOW()),
+    ('Bose QuietComfort', 'Comfortable noise-cancelling headphones', 37999, 6, NOW(), NOW()),
+    ('JBL Flip 6', 'Portable Bluetooth speaker', 12999, 6, NOW(), NOW()),
 
     -- Gaming
-    ('PS5', 'Next-gen gaming console', 499.99, 7, NOW(), NOW()),
-    ('Xbox Series X', 'Powerful gaming console', 499.99, 7, NOW(), NOW()),
-    ('Nintendo Switch OLED', 'Hybrid gaming console', 349.99, 7, NOW(), NOW()),
+    ('PS5', 'Next-gen gaming console', 49999, 7, NOW(), NOW()),
+    ('Xbox Series X', 'Powerful gaming console', 49999, 7, NOW(), NOW()),
+    ('Nintendo Switch OLED', 'Hybrid gaming console', 34999, 7, NOW(), NOW()),
 
     -- Cameras
-    ('Sony A7 IV', 'Full-frame mirrorless camera', 2499.99, 8, NOW(), NOW()),
-    ('Canon EOS R6', 'Professional mirrorless camera', 2299.99, 8, NOW(), NOW()),
-    ('DJI Air 3', 'Premium consumer drone with 4K camera', 1999.99, 8, NOW(), NOW());
+    ('Sony A7 IV', 'Full-frame mirrorless camera', 249999, 8, NOW(), NOW()),
+    ('Canon EOS R6', 'Professional mirrorless camera', 229999, 8, NOW(), NOW()),
+    ('DJI Air 3', 'Premium consumer drone with 4K camera', 199999, 8, NOW(), NOW());
+
+-- Seed Inventory
+INSERT INTO inventory (product_id, on_hand, reserved, updated_at)
+SELECT id, 100, 0, NOW() FROM products;
 
 -- Example of how to verify the seed
 -- SELECT c.name as category, COUNT(p.id) as product_count
@@ -5,4 +5,21 @@ const (
 	KeyCompRedis = "redis"
 	KeyPgx       = "pgx"
 	KeyJwt       = "jwt"
+	KeyEventBus  = "eventbus"
+	KeyPayment   = "payment"
+	KeyScheduler = "scheduler"
+	// KeyOutboxBus delivers outbox events in process once they committed
+	KeyOutboxBus = "outbox-bus"
 )
+
+const (
+	RoleUser  = "USER"
+	RoleAdmin = "ADMIN"
+
+	// RoleSystem marks changes made by the application itself, never a user
+	RoleSystem = "SYSTEM"
+)
+
+// HeaderAcceptCurrency asks for prices in a currency, the currency query
+// parameter wins over it
+const HeaderAcceptCurrency = "Accept-Currency"
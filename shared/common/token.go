@@ -4,6 +4,7 @@ type TokenPayload struct {
 	UserId   int32  `json:"user_id"`
 	Email    string `json:"email"`
 	SubToken string `json:"sub_token"`
+	Role     string `json:"role"`
 }
 
 func (t TokenPayload) GetUserId() int32 {
@@ -17,3 +18,7 @@ func (t TokenPayload) GetSubToken() string {
 func (t TokenPayload) GetEmail() string {
 	return t.Email
 }
+
+func (t TokenPayload) GetRole() string {
+	return t.Role
+}
@@ -2,15 +2,24 @@ package config
 
 import (
 	"fmt"
+	"mallbots/shared/money"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Token  TokenConfig  `yaml:"token"`
-	Solver SolverConfig `yaml:"solver"`
+	Token    TokenConfig    `yaml:"token"`
+	Solver   SolverConfig   `yaml:"solver"`
+	Shipping ShippingConfig `yaml:"shipping"`
+	Tax      TaxConfig      `yaml:"tax"`
+	// OrderRules limit what can be put in a cart and ordered
+	OrderRules OrderRulesConfig `yaml:"order_rules"`
+	Invoice    InvoiceConfig    `yaml:"invoice"`
+	AutoCancel AutoCancelConfig `yaml:"auto_cancel"`
+	Outbox     OutboxConfig     `yaml:"outbox"`
 }
 
 type TokenConfig struct {
@@ -22,6 +31,60 @@ type SolverConfig struct {
 	SolverURL string `yaml:"solver_url"`
 }
 
+// ShippingConfig lists the zones orders can be shipped to. Zones are matched
+// top to bottom, so zip-prefix zones belong above country-wide ones.
+type ShippingConfig struct {
+	Zones []ShippingZoneConfig `yaml:"zones"`
+}
+
+type ShippingZoneConfig struct {
+	Name string `yaml:"name"`
+	// Countries are matched case-insensitively, "*" matches every country
+	Countries []string `yaml:"countries"`
+	// ZipPrefixes narrows the zone to some zip codes, empty matches all of them
+	ZipPrefixes []string `yaml:"zip_prefixes"`
+	// FreeAbove waives shipping once the subtotal reaches it, 0 disables it
+	FreeAbove money.Money        `yaml:"free_above"`
+	Rate      ShippingRateConfig `yaml:"rate"`
+}
+
+type ShippingRateConfig struct {
+	// Type is flat, weight or price
+	Type string `yaml:"type"`
+	// Amount is the fee charged by flat rates
+	Amount money.Money `yaml:"amount"`
+	// Tiers are checked in order against the order weight in grams (weight
+	// rates) or the subtotal (price rates)
+	Tiers []ShippingTierConfig `yaml:"tiers"`
+}
+
+// ShippingTierConfig charges Amount up to and including UpTo. A tier without
+// UpTo has no upper bound.
+type ShippingTierConfig struct {
+	UpTo   float64     `yaml:"up_to"`
+	Amount money.Money `yaml:"amount"`
+}
+
+// TaxConfig lists the tax rules by destination. Rules are matched top to
+// bottom like shipping zones; destinations without a rule are not taxed.
+type TaxConfig struct {
+	Rules []TaxRuleConfig `yaml:"rules"`
+}
+
+type TaxRuleConfig struct {
+	Name string `yaml:"name"`
+	// Country is matched case-insensitively, "*" matches every country
+	Country string `yaml:"country"`
+	// ZipPrefixes narrows the rule to a region, empty matches the whole country
+	ZipPrefixes []string `yaml:"zip_prefixes"`
+	// Rate is a percentage, 10 means 10%
+	Rate float64 `yaml:"rate"`
+	// CategoryRates overrides Rate for the products of a category
+	CategoryRates map[int32]float64 `yaml:"category_rates"`
+	// Inclusive means catalog prices already contain the tax
+	Inclusive bool `yaml:"inclusive"`
+}
+
 // LoadConfig reads and parses the YAML configuration file
 func LoadConfig(configPath string) (*Config, error) {
 	// If configPath is empty, use default path
@@ -48,3 +111,105 @@ func LoadConfig(configPath string) (*Config, error) {
 
 	return &config, nil
 }
+
+// OrderRulesConfig switches on the order business rules. A zero or empty
+// setting leaves its rule off.
+type OrderRulesConfig struct {
+	// MinimumAmounts sets the smallest subtotal accepted per destination
+	MinimumAmounts []MinimumAmountConfig `yaml:"minimum_amounts"`
+	// MaxQuantityPerProduct caps the units of any one product in an order
+	MaxQuantityPerProduct int32 `yaml:"max_quantity_per_product"`
+	// ProductMaxQuantities overrides MaxQuantityPerProduct for some products
+	ProductMaxQuantities map[int32]int32 `yaml:"product_max_quantities"`
+	// MaxQuantityPerOrder caps the units across the whole order
+	MaxQuantityPerOrder int32 `yaml:"max_quantity_per_order"`
+	// MaxOrdersPerDay caps the orders a customer places per UTC day,
+	// cancelled orders do not count
+	MaxOrdersPerDay int32 `yaml:"max_orders_per_day"`
+	// BlockedCountries are never shipped to, matched case-insensitively
+	BlockedCountries []string `yaml:"blocked_countries"`
+}
+
+type MinimumAmountConfig struct {
+	// Country is matched case-insensitively, "*" matches every country not
+	// listed on its own
+	Country string      `yaml:"country"`
+	Amount  money.Money `yaml:"amount"`
+}
+
+// InvoiceConfig describes the seller printed on invoices and how they look
+type InvoiceConfig struct {
+	// NumberPrefix starts every invoice number, INV when empty
+	NumberPrefix string `yaml:"number_prefix"`
+	// TemplateDir may hold invoice.html.tmpl and invoice.txt.tmpl to replace
+	// the built-in HTML and PDF layouts, config/invoices when empty
+	TemplateDir string       `yaml:"template_dir"`
+	Seller      SellerConfig `yaml:"seller"`
+}
+
+type SellerConfig struct {
+	Name    string `yaml:"name"`
+	Address string `yaml:"address"`
+	City    string `yaml:"city"`
+	Country string `yaml:"country"`
+	Zip     string `yaml:"zip"`
+	TaxID   string `yaml:"tax_id"`
+	Email   string `yaml:"email"`
+}
+
+// AutoCancelConfig cancels orders whose payment never arrives. Orders are
+// cancelled once they have waited longer than the TTL of their payment
+// provider, or the default TTL when their provider has none.
+type AutoCancelConfig struct {
+	// Interval is how often unpaid orders are looked for, 1m when empty
+	Interval time.Duration `yaml:"interval"`
+	// BatchSize caps the orders listed at once, 100 when empty. A run lists
+	// more only to get past the orders that failed to cancel.
+	BatchSize int32 `yaml:"batch_size"`
+	// TTL applies to orders without a provider TTL, 0 never cancels them
+	TTL time.Duration `yaml:"ttl"`
+	// ProviderTTLs sets the TTL of orders paying through a provider, keyed
+	// by provider name; 0 never cancels them
+	ProviderTTLs map[string]time.Duration `yaml:"provider_ttls"`
+}
+
+// TTLFor returns how long an order paying through provider may stay unpaid,
+// 0 when it is never cancelled
+func (c AutoCancelConfig) TTLFor(provider *string) time.Duration {
+	if provider != nil {
+		if ttl, ok := c.ProviderTTLs[*provider]; ok {
+			return ttl
+		}
+	}
+
+	return c.TTL
+}
+
+// OutboxConfig controls how the domain events recorded in the outbox reach
+// other systems. In-process subscribers always get them; the other sinks are
+// switched on by their settings.
+type OutboxConfig struct {
+	// PollInterval is how often the relay looks for new events, 1s when empty
+	PollInterval time.Duration `yaml:"poll_interval"`
+	// BatchSize caps the events read at once, 100 when empty
+	BatchSize int32 `yaml:"batch_size"`
+	// RetryBackoff is the wait after a failed delivery, doubled on every
+	// further failure up to MaxRetryBackoff; 1s and 10m when empty
+	RetryBackoff    time.Duration `yaml:"retry_backoff"`
+	MaxRetryBackoff time.Duration `yaml:"max_retry_backoff"`
+	// Lease is how long a batch stays with the relay delivering it before
+	// it is due again, 5m when empty
+	Lease time.Duration `yaml:"lease"`
+	// NotifyChannel sends events with Postgres NOTIFY, empty disables it
+	NotifyChannel string `yaml:"notify_channel"`
+	// Webhooks receive every event as a JSON POST
+	Webhooks []OutboxWebhookConfig `yaml:"webhooks"`
+}
+
+type OutboxWebhookConfig struct {
+	URL string `yaml:"url"`
+	// Secret signs the body with HMAC-SHA256, empty sends it unsigned
+	Secret string `yaml:"secret"`
+	// Timeout bounds a delivery, 10s when empty
+	Timeout time.Duration `yaml:"timeout"`
+}
@@ -11,6 +11,14 @@ var (
 	ErrCannotLogin       = errors.New("cannot login")
 )
 
+var (
+	// Idempotency errors
+	ErrInvalidIdempotencyKey        = errors.New("idempotency key must be between 1 and 255 characters")
+	ErrIdempotencyKeyReused         = errors.New("idempotency key was already used for a different request")
+	ErrIdempotentRequestInProgress  = errors.New("a request with this idempotency key is still being processed")
+	ErrCannotStoreIdempotentRequest = errors.New("cannot store idempotent request")
+)
+
 var (
 	// Order errors
 	ErrCartEmpty               = errors.New("cart is empty")
@@ -20,28 +28,100 @@ var (
 	ErrInvalidOrderStatus      = errors.New("invalid order status")
 	ErrInvalidStatusTransition = errors.New("invalid status transition")
 	ErrUnauthorizedOrderAccess = errors.New("unauthorized access to order")
+	ErrCannotRecordOrderEvent  = errors.New("cannot record order event")
+	ErrInvalidOrderFilter      = errors.New("invalid order filter")
+	ErrInvalidCursor           = errors.New("invalid pagination cursor")
 
 	// Payment errors
 	ErrInvalidPaymentStatus           = errors.New("invalid payment status")
 	ErrInvalidPaymentStatusTransition = errors.New("invalid payment status transition")
 	ErrPaymentFailed                  = errors.New("payment failed")
 	ErrPaymentAlreadyProcessed        = errors.New("payment already processed")
+	ErrOrderNotPayable                = errors.New("order cannot be paid in its current state")
+	ErrPaymentIntentNotFound          = errors.New("order has no payment intent")
+	ErrPaymentAmountMismatch          = errors.New("paid amount does not match the order total")
+	ErrInvalidWebhookSignature        = errors.New("invalid webhook signature")
+	ErrInvalidWebhookPayload          = errors.New("invalid webhook payload")
+	ErrCannotRecordWebhook            = errors.New("cannot record payment webhook")
+	ErrRefundNeedsRefundFlow          = errors.New("payments are refunded through the refund flow")
+
+	// Invoice errors
+	ErrInvoiceNotFound      = errors.New("invoice not found")
+	ErrInvoiceNotAvailable  = errors.New("invoices are only issued for paid orders")
+	ErrCannotIssueInvoice   = errors.New("cannot issue invoice")
+	ErrCannotRenderInvoice  = errors.New("cannot render invoice")
+	ErrInvoiceNotAcceptable = errors.New("invoice is only available as HTML or PDF")
+
+	// Shipment errors
+	ErrShipmentNotFound                = errors.New("shipment not found")
+	ErrCannotCreateShipment            = errors.New("cannot create shipment")
+	ErrCannotUpdateShipment            = errors.New("cannot update shipment")
+	ErrInvalidShipmentStatus           = errors.New("invalid shipment status")
+	ErrInvalidShipmentStatusTransition = errors.New("invalid shipment status transition")
+	ErrShipmentItemNotFound            = errors.New("shipment item does not belong to the order")
+	ErrShipmentQuantityExceeded        = errors.New("shipment quantity exceeds the quantity left to ship")
+	ErrOrderNotShippable               = errors.New("order cannot be shipped in its current state")
+	ErrStatusFollowsShipments          = errors.New("shipped statuses follow the order's shipments")
+
+	// Address errors
+	ErrAddressNotFound   = errors.New("address not found")
+	ErrCannotSaveAddress = errors.New("cannot save address")
 
 	// Shipping errors
 	ErrInvalidShippingAddress    = errors.New("invalid shipping address")
+	ErrInvalidBillingAddress     = errors.New("invalid billing address")
 	ErrInvalidShippingCountry    = errors.New("shipping not available in this country")
 	ErrShippingCalculationFailed = errors.New("failed to calculate shipping cost")
 
 	// Order Items errors
+	ErrProductNotFound        = errors.New("product not found")
 	ErrProductOutOfStock      = errors.New("product is out of stock")
 	ErrInvalidProductQuantity = errors.New("invalid product quantity")
 	ErrProductPriceChanged    = errors.New("product price has changed")
 	ErrCannotCreateOrderItems = errors.New("cannot create order items")
 
+	// Inventory errors
+	ErrInventoryNotFound     = errors.New("product has no inventory record")
+	ErrStockBelowReserved    = errors.New("stock on hand cannot drop below the reserved quantity")
+	ErrCannotUpdateInventory = errors.New("cannot update inventory")
+
 	// Business Logic errors
 	ErrOrderAlreadyCancelled        = errors.New("order is already cancelled")
 	ErrOrderNotCancellable          = errors.New("order cannot be cancelled at this stage")
 	ErrOrderNotRefundable           = errors.New("order is not eligible for refund")
 	ErrMinimumOrderAmountNotMet     = errors.New("minimum order amount not met")
 	ErrMaximumOrderQuantityExceeded = errors.New("maximum order quantity exceeded")
+	ErrMaximumDailyOrdersExceeded   = errors.New("maximum orders per day exceeded")
+
+	// Refund errors
+	ErrRefundNotFound                = errors.New("refund not found")
+	ErrCannotCreateRefund            = errors.New("cannot create refund")
+	ErrCannotUpdateRefund            = errors.New("cannot update refund")
+	ErrInvalidRefundRequest          = errors.New("refund must specify either items or an amount")
+	ErrRefundItemNotFound            = errors.New("refund item does not belong to the order")
+	ErrRefundExceedsPaidAmount       = errors.New("refund exceeds the amount paid")
+	ErrRefundQuantityExceeded        = errors.New("refund quantity exceeds the quantity ordered")
+	ErrInvalidRefundStatusTransition = errors.New("invalid refund status transition")
+
+	// Promotion errors
+	ErrCouponNotFound           = errors.New("coupon not found")
+	ErrCouponCodeTaken          = errors.New("coupon code is already taken")
+	ErrInvalidCoupon            = errors.New("invalid coupon definition")
+	ErrCouponNotActive          = errors.New("coupon is not active")
+	ErrCouponMinimumSpendNotMet = errors.New("minimum spend for coupon not met")
+	ErrCouponNotApplicable      = errors.New("coupon does not apply to the items in the cart")
+	ErrCouponUsageLimitReached  = errors.New("coupon usage limit reached")
+	ErrCouponUserLimitReached   = errors.New("coupon already used the maximum number of times")
+	ErrCouponRedemptionNotFound = errors.New("coupon redemption not found")
+	ErrCannotCreateCoupon       = errors.New("cannot create coupon")
+	ErrCannotRedeemCoupon       = errors.New("cannot redeem coupon")
+
+	// Money errors
+	ErrInvalidAmount   = errors.New("invalid amount")
+	ErrInvalidCurrency = errors.New("invalid currency")
+
+	// Exchange rate errors
+	ErrInvalidExchangeRate      = errors.New("invalid exchange rate")
+	ErrExchangeRateNotFound     = errors.New("exchange rate not found")
+	ErrCannotCreateExchangeRate = errors.New("cannot create exchange rate")
 )
@@ -47,6 +47,7 @@ func RequiredAuth(sc sctx.ServiceContext) fiber.Handler {
 		}
 
 		c.Context().SetUserValue("userId", payload.GetUserId())
+		c.Context().SetUserValue("role", payload.GetRole())
 		return c.Next()
 	}
 }
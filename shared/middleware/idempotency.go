@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"mallbots/plugins/idempotency"
+	"mallbots/shared/errorx"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/phathdt/service-context/core"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// Idempotency replays the stored response when a request is retried with the
+// same Idempotency-Key. Keys are scoped to the authenticated user, so it must
+// be registered after RequiredAuth. Requests without the header pass through.
+//
+// Only successful responses are stored. A failed or panicking request frees
+// the key again so the client can retry it.
+func Idempotency(store idempotency.Store) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			panic(core.ErrBadRequest.WithError(errorx.ErrInvalidIdempotencyKey.Error()))
+		}
+
+		userID, _ := c.Context().UserValue("userId").(int32)
+		ctx := c.Context()
+		fingerprint := requestFingerprint(c)
+
+		record, reserved, err := store.Reserve(ctx, userID, key, fingerprint)
+		if err != nil {
+			panic(core.ErrInternalServerError.
+				WithError(errorx.ErrCannotStoreIdempotentRequest.Error()).
+				WithDebug(err.Error()))
+		}
+
+		if !reserved {
+			return replay(c, record, fingerprint)
+		}
+
+		completed := false
+		defer func() {
+			if !completed {
+				_ = store.Release(ctx, userID, key)
+			}
+		}()
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status < fiber.StatusOK || status >= fiber.StatusMultipleChoices {
+			return nil
+		}
+
+		err = store.Complete(ctx, userID, key, &idempotency.Record{
+			Fingerprint: fingerprint,
+			StatusCode:  status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		})
+		if err != nil {
+			panic(core.ErrInternalServerError.
+				WithError(errorx.ErrCannotStoreIdempotentRequest.Error()).
+				WithDebug(err.Error()))
+		}
+		completed = true
+
+		return nil
+	}
+}
+
+func replay(c *fiber.Ctx, record *idempotency.Record, fingerprint string) error {
+	if record.Fingerprint != fingerprint {
+		panic(core.ErrConflict.WithError(errorx.ErrIdempotencyKeyReused.Error()))
+	}
+
+	if !record.Completed() {
+		panic(core.ErrConflict.WithError(errorx.ErrIdempotentRequestInProgress.Error()))
+	}
+
+	c.Set(IdempotentReplayedHeader, "true")
+	c.Set(fiber.HeaderContentType, record.ContentType)
+	return c.Status(record.StatusCode).Send(record.Body)
+}
+
+// requestFingerprint identifies a request by route and body, so a key reused
+// on another endpoint counts as a different request too
+func requestFingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.Path()))
+	hash.Write([]byte{0})
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
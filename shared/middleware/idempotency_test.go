@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mallbots/plugins/idempotency"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/phathdt/service-context/core"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]*idempotency.Record
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: map[string]*idempotency.Record{}}
+}
+
+func (s *memoryStore) Reserve(_ context.Context, userID int32, key, fingerprint string) (*idempotency.Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := recordID(userID, key)
+	if record, ok := s.records[id]; ok {
+		return record, false, nil
+	}
+
+	s.records[id] = &idempotency.Record{Fingerprint: fingerprint}
+	return s.records[id], true, nil
+}
+
+func (s *memoryStore) Complete(_ context.Context, userID int32, key string, record *idempotency.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[recordID(userID, key)] = record
+	return nil
+}
+
+func (s *memoryStore) Release(_ context.Context, userID int32, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, recordID(userID, key))
+	return nil
+}
+
+func recordID(userID int32, key string) string {
+	return fmt.Sprintf("%d:%s", userID, key)
+}
+
+func setupIdempotencyApp(store idempotency.Store, calls *int) *fiber.App {
+	app := fiber.New()
+
+	app.Use(func(c *fiber.Ctx) error {
+		defer func() {
+			if err := recover(); err != nil {
+				appErr := err.(CanGetStatusCode)
+				_ = c.Status(appErr.StatusCode()).JSON(appErr)
+			}
+		}()
+		return c.Next()
+	})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Context().SetUserValue("userId", int32(1))
+		return c.Next()
+	})
+
+	app.Post("/orders", Idempotency(store), func(c *fiber.Ctx) error {
+		*calls++
+		if strings.Contains(string(c.Body()), "fail") {
+			panic(core.ErrBadRequest.WithError("bad request"))
+		}
+		return c.Status(http.StatusCreated).JSON(fiber.Map{"order": *calls})
+	})
+
+	return app
+}
+
+func post(t *testing.T, app *fiber.App, key, body string) (*http.Response, string) {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(data)
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Run("Replays Stored Response", func(t *testing.T) {
+		calls := 0
+		app := setupIdempotencyApp(newMemoryStore(), &calls)
+
+		first, firstBody := post(t, app, "key-1", `{"a":1}`)
+		require.Equal(t, http.StatusCreated, first.StatusCode)
+
+		retry, retryBody := post(t, app, "key-1", `{"a":1}`)
+		require.Equal(t, http.StatusCreated, retry.StatusCode)
+		require.Equal(t, firstBody, retryBody)
+		require.Equal(t, "true", retry.Header.Get(IdempotentReplayedHeader))
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("Rejects Different Body", func(t *testing.T) {
+		calls := 0
+		app := setupIdempotencyApp(newMemoryStore(), &calls)
+
+		post(t, app, "key-1", `{"a":1}`)
+		resp, _ := post(t, app, "key-1", `{"a":2}`)
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("Rejects Request In Progress", func(t *testing.T) {
+		calls := 0
+		store := newMemoryStore()
+		app := setupIdempotencyApp(store, &calls)
+
+		_, _, err := store.Reserve(context.Background(), 1, "key-1", requestFingerprintFor(t, `{"a":1}`))
+		require.NoError(t, err)
+
+		resp, _ := post(t, app, "key-1", `{"a":1}`)
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+		require.Equal(t, 0, calls)
+	})
+
+	t.Run("Failed Request Releases Key", func(t *testing.T) {
+		calls := 0
+		app := setupIdempotencyApp(newMemoryStore(), &calls)
+
+		resp, _ := post(t, app, "key-1", `{"fail":true}`)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, _ = post(t, app, "key-1", `{"fail":true}`)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("Without Key Passes Through", func(t *testing.T) {
+		calls := 0
+		app := setupIdempotencyApp(newMemoryStore(), &calls)
+
+		post(t, app, "", `{"a":1}`)
+		post(t, app, "", `{"a":1}`)
+		require.Equal(t, 2, calls)
+	})
+}
+
+// requestFingerprintFor computes the fingerprint the middleware derives for a
+// POST /orders request with body
+func requestFingerprintFor(t *testing.T, body string) string {
+	var fingerprint string
+	probe := fiber.New()
+	probe.Post("/orders", func(c *fiber.Ctx) error {
+		fingerprint = requestFingerprint(c)
+		return nil
+	})
+
+	_, err := probe.Test(httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body)))
+	require.NoError(t, err)
+	return fingerprint
+}
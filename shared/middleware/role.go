@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/phathdt/service-context/core"
+)
+
+// RequiredRole only lets requests through when the authenticated user has one
+// of the given roles. It must be registered after RequiredAuth.
+func RequiredRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, _ := c.Context().UserValue("role").(string)
+
+		for _, r := range roles {
+			if r == role {
+				return c.Next()
+			}
+		}
+
+		panic(core.ErrForbidden.WithError("you do not have permission to perform this action"))
+	}
+}
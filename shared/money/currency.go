@@ -0,0 +1,59 @@
+package money
+
+import (
+	"mallbots/shared/errorx"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency code
+type Currency string
+
+const (
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+	GBP Currency = "GBP"
+	JPY Currency = "JPY"
+	VND Currency = "VND"
+	KRW Currency = "KRW"
+	BHD Currency = "BHD"
+	KWD Currency = "KWD"
+)
+
+// DefaultCurrency prices everything that does not name a currency
+const DefaultCurrency = USD
+
+// exponents lists the currencies whose minor unit is not a hundredth
+var exponents = map[Currency]int{
+	JPY: 0,
+	KRW: 0,
+	VND: 0,
+	BHD: 3,
+	KWD: 3,
+}
+
+// ParseCurrency normalises a three letter currency code
+func ParseCurrency(code string) (Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", errorx.ErrInvalidCurrency
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", errorx.ErrInvalidCurrency
+		}
+	}
+
+	return Currency(code), nil
+}
+
+// Exponent is the number of decimal places of the currency's minor unit
+func (c Currency) Exponent() int {
+	if exp, ok := exponents[c]; ok {
+		return exp
+	}
+	return 2
+}
+
+func (c Currency) String() string {
+	return string(c)
+}
@@ -0,0 +1,293 @@
+// Package money represents amounts exactly, as an integer number of minor
+// units (cents for USD) of a currency.
+//
+// Rounding rules: parsed amounts must fit the currency's minor unit exactly,
+// and operations that can produce fractions of a minor unit (Percent, Scale,
+// Ratio) round half away from zero. Allocate never rounds, it hands the
+// remainder to the largest share so the parts always add up to the whole.
+//
+// Amounts of different currencies never mix. Combining them is a programming
+// error and panics; the zero Money has no currency and adopts the other one.
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mallbots/shared/errorx"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Minor is an amount in minor units as stored in a BIGINT column. The
+// currency lives in a column of its own.
+type Minor int64
+
+// In attaches a currency to a stored amount
+func (m Minor) In(currency Currency) Money {
+	return New(int64(m), currency)
+}
+
+// Money is an exact amount of a currency
+type Money struct {
+	amount   int64
+	currency Currency
+}
+
+// New builds an amount from minor units
+func New(minor int64, currency Currency) Money {
+	return Money{amount: minor, currency: currency}
+}
+
+// Zero is nothing of the currency
+func Zero(currency Currency) Money {
+	return Money{currency: currency}
+}
+
+// Parse reads a decimal such as "12.30" or "-4". It fails when the amount has
+// more decimal places than the currency's minor unit.
+func Parse(s string, currency Currency) (Money, error) {
+	s = strings.TrimSpace(s)
+
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	exp := currency.Exponent()
+	if whole == "" && frac == "" || hasPoint && frac == "" || len(frac) > exp || !digits(whole) || !digits(frac) {
+		return Money{}, errorx.ErrInvalidAmount
+	}
+
+	minor := whole + frac + strings.Repeat("0", exp-len(frac))
+	amount, err := strconv.ParseInt(minor, 10, 64)
+	if err != nil {
+		return Money{}, errorx.ErrInvalidAmount
+	}
+	if negative {
+		amount = -amount
+	}
+
+	return New(amount, currency), nil
+}
+
+// MustParse is Parse for amounts known to be valid, such as constants in tests
+func MustParse(s string, currency Currency) Money {
+	m, err := Parse(s, currency)
+	if err != nil {
+		panic(fmt.Sprintf("money: cannot parse %q: %v", s, err))
+	}
+	return m
+}
+
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Minor returns the amount in minor units, ready to be stored
+func (m Money) Minor() Minor {
+	return Minor(m.amount)
+}
+
+func (m Money) Currency() Currency {
+	if m.currency == "" {
+		return DefaultCurrency
+	}
+	return m.currency
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+func (m Money) Add(o Money) Money {
+	return Money{amount: m.amount + o.amount, currency: m.common(o)}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{amount: m.amount - o.amount, currency: m.common(o)}
+}
+
+func (m Money) Neg() Money {
+	return Money{amount: -m.amount, currency: m.currency}
+}
+
+// Mul multiplies by a quantity
+func (m Money) Mul(quantity int64) Money {
+	return Money{amount: m.amount * quantity, currency: m.currency}
+}
+
+// Percent takes percent of the amount, rounded half away from zero
+func (m Money) Percent(percent float64) Money {
+	return m.Scale(percent / 100)
+}
+
+// Scale multiplies by factor, rounded half away from zero
+func (m Money) Scale(factor float64) Money {
+	return Money{amount: int64(math.Round(float64(m.amount) * factor)), currency: m.currency}
+}
+
+// Ratio multiplies by num/den exactly, rounded half away from zero
+func (m Money) Ratio(num, den int64) Money {
+	if den == 0 {
+		panic("money: ratio with a zero denominator")
+	}
+	if den < 0 {
+		num, den = -num, -den
+	}
+
+	product := m.amount * num
+	q, r := product/den, product%den
+	if r < 0 {
+		r = -r
+	}
+	if 2*r >= den {
+		if product < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+
+	return Money{amount: q, currency: m.currency}
+}
+
+// Allocate splits the amount in proportion to weights. The shares add up to
+// the amount exactly; the rounding remainder goes to the largest weight.
+func (m Money) Allocate(weights []int64) []Money {
+	shares := make([]Money, len(weights))
+	var total int64
+	largest := -1
+	for i, w := range weights {
+		shares[i] = Zero(m.currency)
+		total += w
+		if w > 0 && (largest < 0 || w > weights[largest]) {
+			largest = i
+		}
+	}
+	if total == 0 {
+		return shares
+	}
+
+	var spread int64
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		shares[i].amount = m.amount * w / total
+		spread += shares[i].amount
+	}
+	shares[largest].amount += m.amount - spread
+
+	return shares
+}
+
+// Cmp returns -1, 0 or 1 as m is less than, equal to or greater than o
+func (m Money) Cmp(o Money) int {
+	m.common(o)
+	switch {
+	case m.amount < o.amount:
+		return -1
+	case m.amount > o.amount:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (m Money) LessThan(o Money) bool {
+	return m.Cmp(o) < 0
+}
+
+func (m Money) GreaterThan(o Money) bool {
+	return m.Cmp(o) > 0
+}
+
+// Min returns the smaller of two amounts
+func Min(a, b Money) Money {
+	if b.LessThan(a) {
+		return b
+	}
+	return a
+}
+
+// Sum adds up amounts of one currency
+func Sum(currency Currency, amounts ...Money) Money {
+	total := Zero(currency)
+	for _, amount := range amounts {
+		total = total.Add(amount)
+	}
+	return total
+}
+
+// common is the currency two amounts share
+func (m Money) common(o Money) Currency {
+	switch {
+	case m.currency == o.currency || o.currency == "":
+		return m.currency
+	case m.currency == "":
+		return o.currency
+	default:
+		panic(fmt.Sprintf("money: cannot combine %s and %s", m.currency, o.currency))
+	}
+}
+
+// String formats the amount as a plain decimal, "12.30"
+func (m Money) String() string {
+	exp := m.Currency().Exponent()
+
+	amount := m.amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	s := strconv.FormatInt(amount, 10)
+	if exp == 0 {
+		return sign + s
+	}
+	if len(s) <= exp {
+		s = strings.Repeat("0", exp-len(s)+1) + s
+	}
+
+	return sign + s[:len(s)-exp] + "." + s[len(s)-exp:]
+}
+
+// MarshalJSON encodes the amount as a decimal string so clients never see a
+// binary float
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON accepts a decimal string or a number in the default currency
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	return m.UnmarshalText(bytes.Trim(data, `"`))
+}
+
+// UnmarshalText reads a decimal in the default currency, used by the config
+func (m *Money) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text), DefaultCurrency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
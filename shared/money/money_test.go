@@ -0,0 +1,182 @@
+package money
+
+import (
+	"encoding/json"
+	"mallbots/shared/errorx"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		currency Currency
+		expected int64
+		err      bool
+	}{
+		{name: "whole amount", input: "12", currency: USD, expected: 1200},
+		{name: "cents", input: "12.34", currency: USD, expected: 1234},
+		{name: "single decimal", input: "0.5", currency: USD, expected: 50},
+		{name: "negative", input: "-3.05", currency: USD, expected: -305},
+		{name: "zero decimal currency", input: "25000", currency: VND, expected: 25000},
+		{name: "three decimal currency", input: "1.234", currency: KWD, expected: 1234},
+		{name: "too precise", input: "0.001", currency: USD, err: true},
+		{name: "decimals on zero decimal currency", input: "1.5", currency: JPY, err: true},
+		{name: "empty", input: "", currency: USD, err: true},
+		{name: "trailing point", input: "1.", currency: USD, err: true},
+		{name: "not a number", input: "1e3", currency: USD, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse(tt.input, tt.currency)
+			if tt.err {
+				assert.ErrorIs(t, err, errorx.ErrInvalidAmount)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, New(tt.expected, tt.currency), m)
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	assert.Equal(t, "12.34", New(1234, USD).String())
+	assert.Equal(t, "0.05", New(5, USD).String())
+	assert.Equal(t, "-0.50", New(-50, USD).String())
+	assert.Equal(t, "25000", New(25000, VND).String())
+	assert.Equal(t, "0.007", New(7, BHD).String())
+}
+
+func TestArithmetic(t *testing.T) {
+	price := MustParse("19.99", USD)
+
+	assert.Equal(t, MustParse("59.97", USD), price.Mul(3))
+	assert.Equal(t, MustParse("20.09", USD), price.Add(New(10, USD)))
+	assert.Equal(t, MustParse("19.89", USD), price.Sub(New(10, USD)))
+	assert.Equal(t, price, price.Add(Money{}), "the zero value adopts the other currency")
+
+	t.Run("Float Drift Is Gone", func(t *testing.T) {
+		total := Zero(USD)
+		for i := 0; i < 10; i++ {
+			total = total.Add(MustParse("0.10", USD))
+		}
+		assert.Equal(t, MustParse("1.00", USD), total)
+	})
+
+	t.Run("Mixed Currencies Panic", func(t *testing.T) {
+		assert.Panics(t, func() { price.Add(New(1, EUR)) })
+	})
+}
+
+func TestRounding(t *testing.T) {
+	tests := []struct {
+		name     string
+		got      Money
+		expected int64
+	}{
+		{name: "percent rounds half up", got: New(125, USD).Percent(10), expected: 13},
+		{name: "percent rounds down", got: New(124, USD).Percent(10), expected: 12},
+		{name: "negative rounds away from zero", got: New(-125, USD).Percent(10), expected: -13},
+		{name: "ratio rounds half up", got: New(100, USD).Ratio(1, 8), expected: 13},
+		{name: "ratio exact", got: New(900, USD).Ratio(2, 3), expected: 600},
+		{name: "negative ratio", got: New(-100, USD).Ratio(1, 8), expected: -13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, int64(tt.got.Minor()))
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	shares := New(1000, USD).Allocate([]int64{1, 1, 1})
+	assert.Equal(t, []Money{New(334, USD), New(333, USD), New(333, USD)}, shares)
+
+	shares = New(500, USD).Allocate([]int64{3000, 0, 1000})
+	assert.Equal(t, []Money{New(375, USD), New(0, USD), New(125, USD)}, shares)
+
+	shares = New(500, USD).Allocate([]int64{0, 0})
+	assert.Equal(t, []Money{Zero(USD), Zero(USD)}, shares)
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Price Money `json:"price"`
+	}{Price: MustParse("9.90", USD)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price":"9.90"}`, string(data))
+
+	var decoded struct {
+		Price Money `json:"price"`
+		Fee   Money `json:"fee"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"price":"9.90","fee":1.5}`), &decoded))
+	assert.Equal(t, New(990, DefaultCurrency), decoded.Price)
+	assert.Equal(t, New(150, DefaultCurrency), decoded.Fee)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"price":"9.999"}`), &decoded))
+}
+
+func TestYAML(t *testing.T) {
+	var cfg struct {
+		Amount Money `yaml:"amount"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte("amount: 5.99"), &cfg))
+	assert.Equal(t, New(599, DefaultCurrency), cfg.Amount)
+}
+
+func TestParseCurrency(t *testing.T) {
+	c, err := ParseCurrency(" eur ")
+	require.NoError(t, err)
+	assert.Equal(t, EUR, c)
+
+	_, err = ParseCurrency("EURO")
+	assert.ErrorIs(t, err, errorx.ErrInvalidCurrency)
+	assert.Equal(t, 2, USD.Exponent())
+	assert.Equal(t, 0, VND.Exponent())
+}
+
+func TestParseRate(t *testing.T) {
+	r, err := ParseRate("0.921500")
+	require.NoError(t, err)
+	assert.Equal(t, "0.9215", r.String())
+
+	r, err = ParseRate("1.000")
+	require.NoError(t, err)
+	assert.Equal(t, Rate{}, r, "1 is the zero rate")
+
+	for _, invalid := range []string{"", "0", "-1.2", "abc", "1e3", "1/3"} {
+		_, err := ParseRate(invalid)
+		assert.ErrorIs(t, err, errorx.ErrInvalidExchangeRate, invalid)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	eur := MustParseRate("0.92")
+	vnd := MustParseRate("25000")
+
+	assert.Equal(t, New(920, EUR), New(1000, USD).Convert(EUR, Rate{}, eur))
+	assert.Equal(t, New(1000, USD), New(920, EUR).Convert(USD, eur, Rate{}))
+	assert.Equal(t, New(250000, VND), New(1000, USD).Convert(VND, Rate{}, vnd))
+	assert.Equal(t, New(271739, VND), New(1000, EUR).Convert(VND, eur, vnd), "cross rates go through the base")
+	assert.Equal(t, New(1, USD), New(125, VND).Convert(USD, vnd, Rate{}), "rounds half away from zero")
+
+	same := New(1234, EUR)
+	assert.Equal(t, same, same.Convert(EUR, eur, eur))
+}
+
+func TestRateScan(t *testing.T) {
+	var r Rate
+	require.NoError(t, r.Scan("0.9200000000"))
+	assert.Equal(t, MustParseRate("0.92"), r)
+
+	value, err := r.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "0.92", value)
+}
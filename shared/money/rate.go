@@ -0,0 +1,132 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"mallbots/shared/errorx"
+	"math/big"
+	"strings"
+)
+
+// RateScale is the number of decimal places a rate keeps, as in the
+// NUMERIC(20,10) columns rates are stored in
+const RateScale = 10
+
+// Rate is an exchange rate: how many units of a currency one unit of the
+// base currency buys. The zero Rate is 1.
+type Rate struct {
+	// value is the rate as a normalised decimal, empty for 1
+	value string
+}
+
+// ParseRate reads a positive decimal such as "0.9215"
+func ParseRate(s string) (Rate, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || r.Sign() <= 0 || strings.ContainsAny(s, "eE/") {
+		return Rate{}, errorx.ErrInvalidExchangeRate
+	}
+
+	value := strings.TrimRight(r.FloatString(RateScale), "0")
+	value = strings.TrimSuffix(value, ".")
+	if value == "0" {
+		return Rate{}, errorx.ErrInvalidExchangeRate
+	}
+	if value == "1" {
+		return Rate{}, nil
+	}
+
+	return Rate{value: value}, nil
+}
+
+// MustParseRate is ParseRate for rates known to be valid
+func MustParseRate(s string) Rate {
+	r, err := ParseRate(s)
+	if err != nil {
+		panic(fmt.Sprintf("money: cannot parse rate %q: %v", s, err))
+	}
+	return r
+}
+
+func (r Rate) rat() *big.Rat {
+	if r.value == "" {
+		return big.NewRat(1, 1)
+	}
+	v, _ := new(big.Rat).SetString(r.value)
+	return v
+}
+
+func (r Rate) String() string {
+	if r.value == "" {
+		return "1"
+	}
+	return r.value
+}
+
+// Convert changes the amount into currency to. fromRate and toRate are the
+// rates of the amount's currency and of to against the same base currency.
+// The result is rounded half away from zero.
+func (m Money) Convert(to Currency, fromRate, toRate Rate) Money {
+	if m.Currency() == to {
+		return m
+	}
+
+	// amount / 10^from * toRate / fromRate * 10^to
+	num := new(big.Int).Mul(big.NewInt(m.amount), pow10(to.Exponent()))
+	den := pow10(m.Currency().Exponent())
+	value := new(big.Rat).SetFrac(num, den)
+	value.Mul(value, toRate.rat())
+	value.Quo(value, fromRate.rat())
+
+	return Money{amount: roundRat(value), currency: to}
+}
+
+func pow10(exp int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+}
+
+// roundRat rounds half away from zero
+func roundRat(r *big.Rat) int64 {
+	q, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() != 0 && new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		if r.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q.Int64()
+}
+
+// MarshalJSON encodes the rate as a decimal string
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// Scan reads a NUMERIC column
+func (r *Rate) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case nil:
+		*r = Rate{}
+		return nil
+	default:
+		return fmt.Errorf("money: cannot scan %T into a rate", src)
+	}
+
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Value writes the rate to a NUMERIC column
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}